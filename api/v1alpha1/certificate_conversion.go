@@ -0,0 +1,23 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Hub marks v1alpha1 as the conversion hub for Certificate. Other API versions
+// implement sigs.k8s.io/controller-runtime/pkg/conversion.Convertible by
+// converting to and from this version, so objects stored as v1alpha1 keep working
+// when new versions are served.
+func (*Certificate) Hub() {}
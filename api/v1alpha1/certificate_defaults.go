@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// DefaultClusterIssuerName is the ClusterIssuer used when ClusterIssuerName is not set.
+	DefaultClusterIssuerName = "letsencrypt-prod"
+
+	// DefaultFallbackAfter is how long issuance must keep failing before switching to the
+	// fallback issuer when FallbackAfter is not set.
+	DefaultFallbackAfter = time.Hour
+
+	// DefaultAWSCredentialType is the AWS credential type used when AWS.CredentialType is not set.
+	DefaultAWSCredentialType = "assume-role"
+
+	// DefaultCertDataKey is the TLS Secret key of the certificate when CertDataKey is not set.
+	DefaultCertDataKey = "tls.crt"
+
+	// DefaultKeyDataKey is the TLS Secret key of the private key when KeyDataKey is not set.
+	DefaultKeyDataKey = "tls.key"
+
+	// DefaultCertificateNameSuffix is appended to the name of a Certificate to name its
+	// cert-manager Certificate unless the operator is configured with another suffix.
+	DefaultCertificateNameSuffix = "-cert"
+
+	// DefaultSecretNameSuffix is appended to the name of a Certificate to name its TLS Secret
+	// unless the operator is configured with another suffix.
+	DefaultSecretNameSuffix = "-tls"
+)
+
+// EffectiveSpec returns a copy of the spec with the defaults the operator applies at
+// runtime resolved, so it shows exactly which issuer, providers, and targets are used.
+// Remote cluster Secrets are named like the TLS Secret with DefaultSecretNameSuffix, use
+// EffectiveSpecWithSecretNameSuffix for operators configured with another suffix.
+func (c *Certificate) EffectiveSpec() CertificateSpec {
+	return c.EffectiveSpecWithSecretNameSuffix(DefaultSecretNameSuffix)
+}
+
+// EffectiveSpecWithSecretNameSuffix is EffectiveSpec for an operator that appends
+// secretNameSuffix to the name of a Certificate to name its TLS Secret.
+func (c *Certificate) EffectiveSpecWithSecretNameSuffix(secretNameSuffix string) CertificateSpec {
+	spec := *c.Spec.DeepCopy()
+
+	if spec.IssuerKind == "" {
+		spec.IssuerKind = IssuerKindClusterIssuer
+	}
+	if spec.ClusterIssuerName == "" {
+		spec.ClusterIssuerName = DefaultClusterIssuerName
+	}
+
+	if spec.FallbackClusterIssuerName != "" && spec.FallbackAfter == nil {
+		spec.FallbackAfter = &metav1.Duration{Duration: DefaultFallbackAfter}
+	}
+
+	// Cloudflare is enabled by default once credentials are configured
+	if spec.CloudflareEnabled == nil {
+		spec.CloudflareEnabled = ptr.To(spec.CloudflareSecretRef != "")
+	}
+	if spec.CloudflareBundle == "" {
+		spec.CloudflareBundle = BundleFullChain
+	}
+
+	if spec.CertDataKey == "" {
+		spec.CertDataKey = DefaultCertDataKey
+	}
+	if spec.KeyDataKey == "" {
+		spec.KeyDataKey = DefaultKeyDataKey
+	}
+
+	if spec.AWS != nil {
+		if spec.AWS.CredentialType == "" {
+			spec.AWS.CredentialType = DefaultAWSCredentialType
+		}
+		if spec.AWS.Bundle == "" {
+			spec.AWS.Bundle = BundleFullChain
+		}
+	}
+
+	if spec.S3 != nil {
+		if spec.S3.Prefix == "" {
+			spec.S3.Prefix = c.Namespace + "/" + c.Name
+		}
+		if spec.S3.CredentialType == "" {
+			spec.S3.CredentialType = DefaultAWSCredentialType
+		}
+	}
+
+	if spec.DisableFinalizer == nil {
+		spec.DisableFinalizer = ptr.To(false)
+	}
+	if spec.UploadsPaused == nil {
+		spec.UploadsPaused = ptr.To(false)
+	}
+
+	for i := range spec.RemoteClusters {
+		if spec.RemoteClusters[i].Namespace == "" {
+			spec.RemoteClusters[i].Namespace = c.Namespace
+		}
+		if spec.RemoteClusters[i].SecretName == "" {
+			spec.RemoteClusters[i].SecretName = c.Name + secretNameSuffix
+		}
+	}
+	return spec
+}
@@ -23,7 +23,20 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// InstanceLabel assigns a Certificate to the operator instance started with the same
+// --instance-id. Operators without an instance ID reconcile the Certificates without it.
+const InstanceLabel = "certificate.println.kr/instance"
+
+// ApprovedAnnotation set to "true" approves the upload of a Certificate with
+// spec.requireApproval.
+const ApprovedAnnotation = "certificate.println.kr/approved"
+
 // CertificateSpec defines the desired state of Certificate.
+// +kubebuilder:validation:XValidation:rule="!has(self.issuerKind) || self.issuerKind != 'Issuer' || (has(self.issuerName) && size(self.issuerName) > 0)",message="issuerName is required when issuerKind is Issuer"
+// +kubebuilder:validation:XValidation:rule="!has(self.cloudflareEnabled) || !self.cloudflareEnabled || (has(self.cloudflareSecretRef) && size(self.cloudflareSecretRef) > 0)",message="cloudflareSecretRef is required when cloudflareEnabled is true"
+// +kubebuilder:validation:XValidation:rule="!has(self.akamaiEnabled) || !self.akamaiEnabled || (has(self.akamaiSecretRef) && size(self.akamaiSecretRef) > 0 && has(self.akamaiEnrollmentID))",message="akamaiSecretRef and akamaiEnrollmentID are required when akamaiEnabled is true"
+// +kubebuilder:validation:XValidation:rule="!has(self.awsAssumeRoleARNs) || size(self.awsAssumeRoleARNs) == 0 || has(self.aws)",message="aws is required when awsAssumeRoleARNs is set"
+// +kubebuilder:validation:XValidation:rule="(has(self.certDataKey) ? self.certDataKey : 'tls.crt') != (has(self.keyDataKey) ? self.keyDataKey : 'tls.key')",message="certDataKey and keyDataKey must differ"
 type CertificateSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -37,6 +50,78 @@ type CertificateSpec struct {
 	// +kubebuilder:default="letsencrypt-prod"
 	ClusterIssuerName string `json:"clusterIssuerName,omitempty"`
 
+	// IssuerKind selects whether the certificate is issued by the ClusterIssuer in ClusterIssuerName
+	// or by the namespaced Issuer in IssuerName. Changing it, or the issuer name, reissues the certificate.
+	// +optional
+	// +kubebuilder:default="ClusterIssuer"
+	IssuerKind IssuerKind `json:"issuerKind,omitempty"`
+
+	// IssuerName is the name of the cert-manager Issuer in the Certificate's namespace.
+	// Required if IssuerKind is Issuer.
+	// +optional
+	IssuerName string `json:"issuerName,omitempty"`
+
+	// PrivateKeyRotationPolicy controls whether cert-manager generates a new private key when the
+	// certificate is renewed (Always) or reuses the existing key (Never). Defaults to cert-manager's
+	// default. Providers re-import the certificate with whichever key is in the TLS Secret.
+	// +optional
+	PrivateKeyRotationPolicy PrivateKeyRotationPolicy `json:"privateKeyRotationPolicy,omitempty"`
+
+	// RevisionHistoryLimit is how many CertificateRequests cert-manager keeps for the
+	// certificate, older ones are garbage-collected. Defaults to cert-manager's default.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// Subject sets the X.509 subject fields of the certificate, e.g. for enterprise CAs that
+	// require an organization. Changing it reissues the certificate.
+	// +optional
+	Subject *X509Subject `json:"subject,omitempty"`
+
+	// AdditionalOutputFormats are extra formats cert-manager writes to the TLS Secret next to
+	// tls.crt and tls.key: tls-combined.pem for CombinedPEM and key.der for DER.
+	// Requires cert-manager v1.7 or later.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=2
+	AdditionalOutputFormats []AdditionalOutputFormat `json:"additionalOutputFormats,omitempty"`
+
+	// CertificateLabels are set on the cert-manager Certificate, e.g. cost or owner labels.
+	// Changing them updates the cert-manager Certificate; labels removed here are removed
+	// from it. The app.kubernetes.io/managed-by label is set by the operator.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="!('app.kubernetes.io/managed-by' in self)",message="app.kubernetes.io/managed-by is set by the operator"
+	CertificateLabels map[string]string `json:"certificateLabels,omitempty"`
+
+	// CertificateAnnotations are set on the cert-manager Certificate. Changing them updates
+	// the cert-manager Certificate; annotations removed here are removed from it.
+	// +optional
+	CertificateAnnotations map[string]string `json:"certificateAnnotations,omitempty"`
+
+	// ShadowClusterIssuerName is an additional ClusterIssuer to test issuance against, e.g. a
+	// staging issuer before an issuer migration. A second cert-manager Certificate is created
+	// for it and its readiness is reported in status, but it is never uploaded to providers.
+	// +optional
+	ShadowClusterIssuerName string `json:"shadowClusterIssuerName,omitempty"`
+
+	// FallbackClusterIssuerName is a ClusterIssuer to switch to when issuance against the
+	// primary issuer keeps failing, e.g. during a Let's Encrypt outage. The cert-manager
+	// Certificate stays on the fallback issuer until this field is changed or removed.
+	// +optional
+	FallbackClusterIssuerName string `json:"fallbackClusterIssuerName,omitempty"`
+
+	// FallbackAfter is how long issuance against the primary issuer must keep failing before
+	// switching to FallbackClusterIssuerName. Defaults to 1h.
+	// +optional
+	FallbackAfter *metav1.Duration `json:"fallbackAfter,omitempty"`
+
+	// IssuanceTimeout is how long to wait for cert-manager to issue the certificate before
+	// setting the Timeout condition and emitting a warning event, e.g. to alert on a
+	// stuck ACME challenge. The operator keeps waiting after the timeout. Unset waits
+	// without a timeout.
+	// +optional
+	IssuanceTimeout *metav1.Duration `json:"issuanceTimeout,omitempty"`
+
 	// CloudflareSecretRef is the name of the Secret containing Cloudflare credentials (api-token).
 	// +optional
 	CloudflareSecretRef string `json:"cloudflareSecretRef,omitempty"`
@@ -51,11 +136,166 @@ type CertificateSpec struct {
 	// +optional
 	CloudflareEnabled *bool `json:"cloudflareEnabled,omitempty"`
 
+	// CloudflareBundle controls which parts of the certificate bundle are uploaded to Cloudflare.
+	// Defaults to "full-chain".
+	// +optional
+	CloudflareBundle BundleType `json:"cloudflareBundle,omitempty"`
+
+	// AkamaiEnabled uploads certificates to the third-party Akamai CPS enrollment
+	// AkamaiEnrollmentID. CPS keeps the private key of an enrollment, so each issuance starts
+	// a renewal change, the issuer signs the CSRs CPS generates for it through cert-manager
+	// CertificateRequests, and those certificates are uploaded into the change.
+	// +optional
+	AkamaiEnabled bool `json:"akamaiEnabled,omitempty"`
+
+	// AkamaiEnrollmentID is the ID of the third-party Akamai CPS enrollment the certificate
+	// is uploaded to. Required if AkamaiEnabled is true.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AkamaiEnrollmentID int64 `json:"akamaiEnrollmentID,omitempty"`
+
+	// AkamaiSecretRef is the name of the Secret containing the Akamai EdgeGrid credentials
+	// (host, client-token, client-secret, and access-token). Required if AkamaiEnabled is true.
+	// +optional
+	AkamaiSecretRef string `json:"akamaiSecretRef,omitempty"`
+
+	// Priority orders reconciles when the controller has a backlog. Certificates with a
+	// higher priority are reconciled first. Defaults to 0; negative values are allowed.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// PKCS12PasswordSecretRef is the name of the Secret containing the password (password)
+	// of a PKCS#12 keystore (keystore.p12 or tls.p12) in the TLS Secret. The keystore is
+	// only used when the TLS Secret has no certificate and key under CertDataKey/KeyDataKey.
+	// Defaults to an empty password.
+	// +optional
+	PKCS12PasswordSecretRef string `json:"pkcs12PasswordSecretRef,omitempty"`
+
+	// TrustedCASecretRef is the name of the Secret containing a PEM CA bundle (ca.crt) the
+	// issued certificate must chain to. Uploads are skipped while the chain doesn't verify
+	// against it, e.g. when a misconfigured issuer signs with an unexpected CA.
+	// +optional
+	TrustedCASecretRef string `json:"trustedCASecretRef,omitempty"`
+
+	// DNSCheck holds uploads back until spec.domain resolves as expected, e.g. when records
+	// are only pointed at the endpoint serving the certificate after issuance. No DNS lookups
+	// are made when unset.
+	// +optional
+	DNSCheck *DNSCheck `json:"dnsCheck,omitempty"`
+
+	// CertDataKey is the key of the PEM certificate in the TLS Secret, for Secrets written by
+	// tools other than cert-manager. Defaults to "tls.crt".
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	CertDataKey string `json:"certDataKey,omitempty"`
+
+	// KeyDataKey is the key of the PEM private key in the TLS Secret. Defaults to "tls.key".
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	KeyDataKey string `json:"keyDataKey,omitempty"`
+
+	// KeySecretRef is the name of a Secret in the Certificate's namespace holding the PEM
+	// private key under KeyDataKey, for pipelines that deliver the key separately from the
+	// certificate. The TLS Secret then only needs the certificate under CertDataKey. Uploads
+	// wait until both Secrets are present and the key matches the certificate.
+	// +optional
+	KeySecretRef string `json:"keySecretRef,omitempty"`
+
+	// DisableFinalizer stops the operator from adding its finalizer, so deleting the Certificate is
+	// never blocked. Uploaded certificates and replicated Secrets are then not cleaned up
+	// automatically. Defaults to false.
+	// +optional
+	DisableFinalizer *bool `json:"disableFinalizer,omitempty"`
+
+	// UploadsPaused stops uploads to every provider and replication to remote clusters while
+	// cert-manager keeps issuing and renewing the certificate, e.g. during a provider migration.
+	// Once unpaused, the latest certificate is uploaded. Defaults to false.
+	// +optional
+	UploadsPaused *bool `json:"uploadsPaused,omitempty"`
+
+	// RequireApproval withholds uploads to every provider and replication to remote clusters
+	// until the certificate.println.kr/approved annotation is set to "true". The cert-manager
+	// Certificate is still created and issued. The PendingApproval condition reports an
+	// upload waiting for approval. Defaults to false.
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// CredentialsNamespace is the namespace of the Secrets referenced by CloudflareSecretRef,
+	// AkamaiSecretRef, AWS.SecretRef, and S3.SecretRef. Overrides the operator's --credentials-namespace setting.
+	// Only the Certificate's own namespace or the operator's --credentials-namespace are
+	// allowed, other namespaces are ignored. Defaults to the Certificate's namespace.
+	// +optional
+	CredentialsNamespace string `json:"credentialsNamespace,omitempty"`
+
 	// AWS contains AWS-specific configuration.
 	// +optional
 	AWS *AWS `json:"aws,omitempty"`
+
+	// AWSAssumeRoleARNs are IAM roles in other AWS accounts to import the certificate into.
+	// Each role is assumed via STS using the credentials configured in AWS, which is required.
+	// +optional
+	// +kubebuilder:validation:items:Pattern=`^arn:aws[a-z-]*:iam::[0-9]{12}:role/.+$`
+	AWSAssumeRoleARNs []string `json:"awsAssumeRoleARNs,omitempty"`
+
+	// ProviderTags are tags set on the certificate in AWS ACM, in every account it is imported
+	// into, next to the ManagedBy and Domain tags the operator sets. Changing them updates the
+	// tags without waiting for a renewal; tags not listed here are removed.
+	// +optional
+	// +kubebuilder:validation:MaxProperties=48
+	// +kubebuilder:validation:XValidation:rule="self.all(k, size(k) <= 128 && size(self[k]) <= 256)",message="tag keys must be at most 128 and values at most 256 characters"
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('aws:') && k != 'ManagedBy' && k != 'Domain')",message="tag keys must not start with aws: or be ManagedBy or Domain"
+	ProviderTags map[string]string `json:"providerTags,omitempty"`
+
+	// RemoteClusters are other Kubernetes clusters, e.g. edge clusters, the TLS Secret is replicated to.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	RemoteClusters []RemoteCluster `json:"remoteClusters,omitempty"`
+
+	// S3 configures writing the certificate, private key, and chain as PEM files to an S3 bucket.
+	// +optional
+	S3 *S3 `json:"s3,omitempty"`
+}
+
+// DNSCheck is what spec.domain must resolve to before the certificate is uploaded. With
+// neither field set, the domain only has to resolve. Wildcard domains are not checked.
+type DNSCheck struct {
+	// ExpectedAddresses are IP addresses the domain must resolve to, every one of them.
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:MinLength=1
+	ExpectedAddresses []string `json:"expectedAddresses,omitempty"`
+
+	// ExpectedCNAME is the canonical name the domain must resolve through, e.g.
+	// "lb.example.net", compared case-insensitively.
+	// +optional
+	ExpectedCNAME string `json:"expectedCNAME,omitempty"`
 }
 
+// RemoteCluster is a Kubernetes cluster the TLS Secret is replicated to.
+type RemoteCluster struct {
+	// Name identifies the cluster in status.
+	Name string `json:"name"`
+
+	// KubeconfigSecretRef is the name of the Secret containing the kubeconfig (kubeconfig) used to
+	// reach the cluster. It is read from the same namespace as the provider credentials.
+	KubeconfigSecretRef string `json:"kubeconfigSecretRef"`
+
+	// Namespace is the namespace of the replicated Secret in the cluster.
+	// Defaults to the Certificate's namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SecretName is the name of the replicated Secret in the cluster.
+	// Defaults to the name of the TLS Secret, "{certificate-name}-tls" unless the operator
+	// is configured with another suffix.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.credentialType) || self.credentialType != 'access-key' || (has(self.secretRef) && size(self.secretRef) > 0)",message="secretRef is required when credentialType is access-key"
 type AWS struct {
 	// CredentialType is the type of AWS credentials to use.
 	// +kubebuilder:default="assume-role"
@@ -64,8 +304,195 @@ type AWS struct {
 	// SecretRef is the name of the Secret containing AWS credentials (access-key-id, secret-access-key, region).
 	// +optional
 	SecretRef string `json:"secretRef,omitempty"`
+
+	// Bundle controls which parts of the certificate bundle are imported into AWS ACM.
+	// Defaults to "full-chain".
+	// +optional
+	Bundle BundleType `json:"bundle,omitempty"`
+
+	// ChainMode controls how the bundle is passed to ACM on import. "inline" imports the
+	// bundle as the certificate, "separate" imports the leaf as the certificate and the
+	// intermediates as the certificate chain. Defaults to "inline".
+	// +optional
+	ChainMode AWSChainMode `json:"chainMode,omitempty"`
+
+	// PrivateCA marks the certificate as issued by a private CA whose chain does not build
+	// to a public root. With the separate chain mode, a certificate without intermediates
+	// is then imported without a chain instead of being rejected.
+	// +optional
+	PrivateCA bool `json:"privateCA,omitempty"`
+
+	// SkipUnchangedReimport reads the certificate imported under status.awsCertificateARN
+	// back before a re-import and skips the re-import when it already holds the same
+	// certificates, compared by fingerprint. Tags are still synced.
+	// +optional
+	SkipUnchangedReimport bool `json:"skipUnchangedReimport,omitempty"`
 }
 
+// AWSChainMode describes how the certificate chain is passed to ACM on import.
+// +kubebuilder:validation:Enum=inline;separate
+type AWSChainMode string
+
+const (
+	// AWSChainModeInline imports the bundle as the certificate.
+	AWSChainModeInline AWSChainMode = "inline"
+
+	// AWSChainModeSeparate imports the leaf as the certificate and the intermediates as
+	// the certificate chain.
+	AWSChainModeSeparate AWSChainMode = "separate"
+)
+
+// S3 is an S3 bucket the certificate is written to as cert.pem, key.pem, and chain.pem.
+// +kubebuilder:validation:XValidation:rule="!has(self.credentialType) || self.credentialType != 'access-key' || (has(self.secretRef) && size(self.secretRef) > 0)",message="secretRef is required when credentialType is access-key"
+// +kubebuilder:validation:XValidation:rule="!has(self.kmsKeyID) || (has(self.serverSideEncryption) && self.serverSideEncryption == 'aws:kms')",message="kmsKeyID requires serverSideEncryption aws:kms"
+type S3 struct {
+	// Bucket is the name of the S3 bucket.
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// Prefix is the key prefix of the objects.
+	// Defaults to "{certificate-namespace}/{certificate-name}".
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Region is the region of the bucket. Defaults to the region of the credentials.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// CredentialType is the type of AWS credentials to use.
+	// +kubebuilder:default="assume-role"
+	CredentialType string `json:"credentialType,omitempty"`
+
+	// SecretRef is the name of the Secret containing AWS credentials (access-key-id, secret-access-key, region).
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// ServerSideEncryption encrypts the objects at rest. Defaults to the bucket's default encryption.
+	// +optional
+	ServerSideEncryption S3ServerSideEncryption `json:"serverSideEncryption,omitempty"`
+
+	// KMSKeyID is the KMS key used with aws:kms encryption. Defaults to the AWS managed key.
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+}
+
+// S3ServerSideEncryption is the server-side encryption of the objects written to S3.
+// +kubebuilder:validation:Enum=AES256;"aws:kms"
+type S3ServerSideEncryption string
+
+const (
+	// S3ServerSideEncryptionAES256 encrypts the objects with S3 managed keys (SSE-S3).
+	S3ServerSideEncryptionAES256 S3ServerSideEncryption = "AES256"
+
+	// S3ServerSideEncryptionKMS encrypts the objects with a KMS key (SSE-KMS).
+	S3ServerSideEncryptionKMS S3ServerSideEncryption = "aws:kms"
+)
+
+// X509Subject is the X.509 subject of the certificate. Lengths follow the RFC 5280 upper bounds.
+type X509Subject struct {
+	// Organizations (O) of the subject.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=64
+	Organizations []string `json:"organizations,omitempty"`
+
+	// OrganizationalUnits (OU) of the subject.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=64
+	OrganizationalUnits []string `json:"organizationalUnits,omitempty"`
+
+	// Countries (C) of the subject as ISO 3166-1 alpha-2 codes, e.g. "DE".
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:items:Pattern=`^[A-Z]{2}$`
+	Countries []string `json:"countries,omitempty"`
+
+	// Provinces (ST) of the subject.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=128
+	Provinces []string `json:"provinces,omitempty"`
+
+	// Localities (L) of the subject.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=128
+	Localities []string `json:"localities,omitempty"`
+
+	// StreetAddresses of the subject.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=128
+	StreetAddresses []string `json:"streetAddresses,omitempty"`
+
+	// PostalCodes of the subject.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=40
+	PostalCodes []string `json:"postalCodes,omitempty"`
+
+	// SerialNumber of the subject.
+	// +optional
+	// +kubebuilder:validation:MaxLength=64
+	SerialNumber string `json:"serialNumber,omitempty"`
+}
+
+// IssuerKind is the kind of cert-manager issuer that issues the certificate.
+// +kubebuilder:validation:Enum=ClusterIssuer;Issuer
+type IssuerKind string
+
+const (
+	// IssuerKindClusterIssuer issues the certificate with a cluster-scoped ClusterIssuer.
+	IssuerKindClusterIssuer IssuerKind = "ClusterIssuer"
+
+	// IssuerKindIssuer issues the certificate with an Issuer in the Certificate's namespace.
+	IssuerKindIssuer IssuerKind = "Issuer"
+)
+
+// PrivateKeyRotationPolicy is the cert-manager private key rotation policy.
+// +kubebuilder:validation:Enum=Never;Always
+type PrivateKeyRotationPolicy string
+
+const (
+	// PrivateKeyRotationPolicyNever reuses the existing private key on renewal.
+	PrivateKeyRotationPolicyNever PrivateKeyRotationPolicy = "Never"
+
+	// PrivateKeyRotationPolicyAlways generates a new private key on every renewal.
+	PrivateKeyRotationPolicyAlways PrivateKeyRotationPolicy = "Always"
+)
+
+// AdditionalOutputFormat is an additional cert-manager output format of the TLS Secret.
+// +kubebuilder:validation:Enum=CombinedPEM;DER
+type AdditionalOutputFormat string
+
+const (
+	// AdditionalOutputFormatCombinedPEM writes the private key followed by the certificate
+	// chain to tls-combined.pem.
+	AdditionalOutputFormatCombinedPEM AdditionalOutputFormat = "CombinedPEM"
+
+	// AdditionalOutputFormatDER writes the DER-encoded private key to key.der.
+	AdditionalOutputFormatDER AdditionalOutputFormat = "DER"
+)
+
+// BundleType describes how the certificate bundle is assembled before upload.
+// +kubebuilder:validation:Enum=leaf-only;full-chain
+type BundleType string
+
+const (
+	// BundleLeafOnly uploads only the leaf certificate without intermediates.
+	BundleLeafOnly BundleType = "leaf-only"
+
+	// BundleFullChain uploads the certificate as issued, including intermediates.
+	BundleFullChain BundleType = "full-chain"
+)
+
 // CertificateStatus defines the observed state of Certificate.
 type CertificateStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -74,6 +501,19 @@ type CertificateStatus struct {
 	// CertificateRef references the created Certificate.
 	CertificateRef string `json:"certificateRef,omitempty"`
 
+	// SecretName is the name of the TLS Secret cert-manager issues the certificate into.
+	// Changes to this Secret trigger a reconcile of the Certificate.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// ShadowCertificateRef references the cert-manager Certificate created for ShadowClusterIssuerName.
+	// +optional
+	ShadowCertificateRef string `json:"shadowCertificateRef,omitempty"`
+
+	// ShadowReady is true when the shadow cert-manager Certificate has been issued.
+	// +optional
+	ShadowReady bool `json:"shadowReady,omitempty"`
+
 	// CloudflareUploaded is true if the certificate has been uploaded to Cloudflare.
 	CloudflareUploaded bool `json:"cloudflareUploaded,omitempty"`
 
@@ -86,18 +526,379 @@ type CertificateStatus struct {
 	// CloudflareCertificateID is the ID of the certificate in Cloudflare.
 	CloudflareCertificateID string `json:"cloudflareCertificateID,omitempty"`
 
-	// LastUploadedCertHash is the SHA256 hash of the last uploaded certificate.
-	// Used to detect certificate renewals.
+	// S3Uploaded is true if the certificate has been written to the S3 bucket.
+	// +optional
+	S3Uploaded bool `json:"s3Uploaded,omitempty"`
+
+	// S3ObjectKeys are the keys of the objects written to the S3 bucket.
+	// +optional
+	S3ObjectKeys []string `json:"s3ObjectKeys,omitempty"`
+
+	// Providers reports the uploads to each provider, keyed by provider name: akamai, aws,
+	// cloudflare, or s3. The imports through AWSAssumeRoleARNs are keyed aws:<account ID>.
+	// The per-provider fields above, such as AWSUploaded and
+	// CloudflareCertificateID, are derived from it and kept for compatibility.
+	// +optional
+	Providers map[string]ProviderStatus `json:"providers,omitempty"`
+
+	// LastUploadedCertHash is the SHA256 hash of the DER encoding of the last uploaded leaf
+	// certificate. Used to detect certificate renewals.
 	// +optional
 	LastUploadedCertHash string `json:"lastUploadedCertHash,omitempty"`
 
+	// LastUploadedChainFingerprint is the SHA256 fingerprint of every certificate in the last
+	// uploaded chain. Only the fingerprint is stored, never the PEM, so status stays small
+	// regardless of chain length. Used to detect intermediate rotations.
+	// +optional
+	LastUploadedChainFingerprint string `json:"lastUploadedChainFingerprint,omitempty"`
+
+	// LastUploadedSpecHash is the SHA256 hash of the spec fields that shape the last upload
+	// without being part of the certificate, such as providerTags and bundle types. Used to
+	// update providers when they change between renewals.
+	// +optional
+	LastUploadedSpecHash string `json:"lastUploadedSpecHash,omitempty"`
+
 	// LastUploadedTime is the timestamp of the last successful upload to cloud providers.
 	// +optional
 	LastUploadedTime *metav1.Time `json:"lastUploadedTime,omitempty"`
+
+	// NotAfter is when the certificate in the TLS Secret expires.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// IssuanceDetail describes the current cert-manager issuance progress while the
+	// certificate is not yet issued, e.g. "pending http01 challenge for example.com".
+	// +optional
+	IssuanceDetail string `json:"issuanceDetail,omitempty"`
+
+	// IssuanceStartedAt is when the operator started waiting for cert-manager to issue the
+	// certificate, spec.issuanceTimeout counts from it. It is cleared once the certificate
+	// is issued.
+	// +optional
+	IssuanceStartedAt *metav1.Time `json:"issuanceStartedAt,omitempty"`
+
+	// IssuanceFailingSince is when the operator first saw the latest issuance attempt fail.
+	// It is cleared once cert-manager issues the certificate.
+	// +optional
+	IssuanceFailingSince *metav1.Time `json:"issuanceFailingSince,omitempty"`
+
+	// ActiveIssuer is the issuer of the cert-manager Certificate as kind/name, e.g.
+	// ClusterIssuer/letsencrypt-prod. It names FallbackClusterIssuerName once the
+	// Certificate switched to it.
+	// +optional
+	ActiveIssuer string `json:"activeIssuer,omitempty"`
+
+	// ManagedByVersion is the version of the operator that last reconciled the Certificate
+	// successfully.
+	// +optional
+	ManagedByVersion string `json:"managedByVersion,omitempty"`
+
+	// LastReconcileDuration is how long the operator took to process the Certificate in the
+	// reconcile at LastReconcileTime, e.g. "1.25s". To keep status writes down, it is only
+	// recorded with other status changes or when the recorded one is a few minutes old.
+	// +optional
+	LastReconcileDuration string `json:"lastReconcileDuration,omitempty"`
+
+	// LastReconcileTime is when the reconcile of LastReconcileDuration finished.
+	// +optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// AWSAccountCertificateARNs maps AWS account IDs to the certificate ARN imported
+	// into that account through AWSAssumeRoleARNs.
+	// +optional
+	AWSAccountCertificateARNs map[string]string `json:"awsAccountCertificateARNs,omitempty"`
+
+	// RemoteClusters reports the replication of the TLS Secret to each of spec.remoteClusters.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	RemoteClusters []RemoteClusterStatus `json:"remoteClusters,omitempty"`
+
+	// ProviderCircuitBreakers track the providers whose latest uploads failed. A provider
+	// whose breaker is Open isn't uploaded to until its cooldown has passed.
+	// +optional
+	// +listType=map
+	// +listMapKey=provider
+	ProviderCircuitBreakers []ProviderCircuitBreaker `json:"providerCircuitBreakers,omitempty"`
+
+	// Finalization is the outcome of the latest cleanup of the provider resources after the
+	// Certificate was deleted. Failed entries are what holds the deletion.
+	// +optional
+	Finalization *FinalizationStatus `json:"finalization,omitempty"`
+
+	// Conditions are the latest observations of the Certificate's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ConditionProvidersConfigured is False when a provider is enabled in the spec but can't
+	// be uploaded to, e.g. cloudflareEnabled without cloudflareSecretRef. The message lists
+	// every misconfiguration.
+	ConditionProvidersConfigured = "ProvidersConfigured"
+
+	// ReasonConfigured is the ProvidersConfigured reason when every enabled provider is configured.
+	ReasonConfigured = "Configured"
+
+	// ReasonMisconfigured is the ProvidersConfigured reason when an enabled provider is misconfigured.
+	ReasonMisconfigured = "Misconfigured"
+
+	// ConditionSANMismatch is True when the issued leaf certificate's SANs don't include
+	// spec.domain. Uploads are skipped until a matching certificate is issued.
+	ConditionSANMismatch = "SANMismatch"
+
+	// ReasonDomainNotInSANs is the SANMismatch reason when spec.domain is missing from the SANs.
+	ReasonDomainNotInSANs = "DomainNotInSANs"
+
+	// ReasonSANsMatch is the SANMismatch reason when the SANs include spec.domain.
+	ReasonSANsMatch = "SANsMatch"
+
+	// ConditionProvidersDisabled is True when uploads to a provider the Certificate uses are
+	// disabled by the operator configuration. The message lists the disabled providers. The
+	// condition is removed once all of them are enabled again.
+	ConditionProvidersDisabled = "ProvidersDisabled"
+
+	// ReasonAdministrativelyDisabled is the ProvidersDisabled reason when a provider is disabled.
+	ReasonAdministrativelyDisabled = "AdministrativelyDisabled"
+
+	// ConditionVerificationFailed is True when the certificate read back from a provider after
+	// an upload differs from the uploaded one. It is only set when upload verification is
+	// enabled in the operator configuration.
+	ConditionVerificationFailed = "VerificationFailed"
+
+	// ReasonMismatch is the VerificationFailed reason when a provider's copy differs.
+	ReasonMismatch = "Mismatch"
+
+	// ReasonVerified is the VerificationFailed reason when every verified copy matches.
+	ReasonVerified = "Verified"
+
+	// ConditionChainVerificationFailed is True when the issued certificate doesn't chain to the
+	// CA in spec.trustedCASecretRef. Uploads are skipped until a trusted certificate is issued.
+	// The condition is removed when spec.trustedCASecretRef is unset.
+	ConditionChainVerificationFailed = "ChainVerificationFailed"
+
+	// ReasonUntrustedChain is the ChainVerificationFailed reason when the chain doesn't verify
+	// or the trusted CA can't be read.
+	ReasonUntrustedChain = "UntrustedChain"
+
+	// ReasonChainTrusted is the ChainVerificationFailed reason when the chain verifies.
+	ReasonChainTrusted = "ChainTrusted"
+
+	// ConditionSecretConflict is True when another cert-manager Certificate issues into the
+	// Certificate's TLS Secret. The cert-manager Certificate is neither created nor updated
+	// until the other one is removed, the condition is then removed.
+	ConditionSecretConflict = "SecretConflict"
+
+	// ReasonSecretInUse is the SecretConflict reason when another cert-manager Certificate
+	// targets the TLS Secret.
+	ReasonSecretInUse = "SecretInUse"
+
+	// ConditionNameConflict is True when a cert-manager Certificate or Secret the operator
+	// didn't create already has the name of the Certificate's cert-manager Certificate or TLS
+	// Secret. Nothing is created or updated until it is removed or renamed, the condition is
+	// then removed.
+	ConditionNameConflict = "NameConflict"
+
+	// ReasonUnmanagedResource is the NameConflict reason when the resource with the name
+	// wasn't created by the operator for the Certificate.
+	ReasonUnmanagedResource = "UnmanagedResource"
+
+	// ConditionTLSSecretTypeMismatch is True when the TLS Secret holds tls.crt and tls.key but
+	// isn't of type kubernetes.io/tls, which ingress controllers require. Uploads are not
+	// affected. The condition is removed once the Secret has the right type.
+	ConditionTLSSecretTypeMismatch = "TLSSecretTypeMismatch"
+
+	// ReasonNotKubernetesTLS is the TLSSecretTypeMismatch reason when the TLS Secret has
+	// another type, e.g. Opaque.
+	ReasonNotKubernetesTLS = "NotKubernetesTLS"
+
+	// ConditionDNSNotReady is True when spec.domain doesn't resolve as spec.dnsCheck expects.
+	// Uploads are skipped until it does. The condition is removed when spec.dnsCheck is unset.
+	ConditionDNSNotReady = "DNSNotReady"
+
+	// ReasonDNSMismatch is the DNSNotReady reason when the domain doesn't resolve, or not
+	// to the expected addresses or canonical name.
+	ReasonDNSMismatch = "DNSMismatch"
+
+	// ReasonDNSResolved is the DNSNotReady reason when the domain resolves as expected.
+	ReasonDNSResolved = "DNSResolved"
+
+	// ConditionPendingApproval is True while the upload of a Certificate with
+	// spec.requireApproval waits for the certificate.println.kr/approved annotation. The
+	// condition is removed when spec.requireApproval is unset.
+	ConditionPendingApproval = "PendingApproval"
+
+	// ReasonAwaitingApproval is the PendingApproval reason when the upload isn't approved.
+	ReasonAwaitingApproval = "AwaitingApproval"
+
+	// ReasonApproved is the PendingApproval reason when the upload is approved.
+	ReasonApproved = "Approved"
+
+	// ConditionTimeout is True when cert-manager hasn't issued the certificate within
+	// spec.issuanceTimeout of status.issuanceStartedAt. The condition is removed once the
+	// certificate is issued or spec.issuanceTimeout is unset.
+	ConditionTimeout = "Timeout"
+
+	// ReasonIssuanceTimedOut is the Timeout reason when issuance took longer than
+	// spec.issuanceTimeout.
+	ReasonIssuanceTimedOut = "IssuanceTimedOut"
+)
+
+// RemoteClusterStatus is the replication state of the TLS Secret in a remote cluster.
+type RemoteClusterStatus struct {
+	// Name is the name of the cluster in spec.remoteClusters.
+	Name string `json:"name"`
+
+	// SecretRef references the replicated Secret as "namespace/name".
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// Synced is true when the remote Secret holds the current certificate.
+	Synced bool `json:"synced"`
+
+	// LastSyncedTime is the timestamp of the last successful replication.
+	// +optional
+	LastSyncedTime *metav1.Time `json:"lastSyncedTime,omitempty"`
+
+	// Error is the last replication error, empty when Synced.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// CircuitBreakerState is the state of a provider's upload circuit breaker.
+// +kubebuilder:validation:Enum=Closed;Open;HalfOpen
+type CircuitBreakerState string
+
+const (
+	// CircuitBreakerClosed uploads to the provider, counting consecutive failures.
+	CircuitBreakerClosed CircuitBreakerState = "Closed"
+
+	// CircuitBreakerOpen stops uploads to the provider until the cooldown has passed.
+	CircuitBreakerOpen CircuitBreakerState = "Open"
+
+	// CircuitBreakerHalfOpen tries a single upload to the provider after the cooldown. The
+	// breaker closes when it succeeds and opens again when it fails.
+	CircuitBreakerHalfOpen CircuitBreakerState = "HalfOpen"
+)
+
+// ProviderCircuitBreaker is the upload circuit breaker of a provider. Breakers are removed
+// once an upload to their provider succeeds.
+type ProviderCircuitBreaker struct {
+	// Provider is the name of the provider: aws, cloudflare, or s3.
+	Provider string `json:"provider"`
+
+	// State is the state of the breaker.
+	State CircuitBreakerState `json:"state"`
+
+	// ConsecutiveFailures is the number of uploads to the provider that failed in a row.
+	ConsecutiveFailures int32 `json:"consecutiveFailures"`
+
+	// OpenedAt is when the breaker last opened.
+	// +optional
+	OpenedAt *metav1.Time `json:"openedAt,omitempty"`
+
+	// LastError is the error of the latest failed upload.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// ObservedGeneration is the generation of the Certificate the failures were counted at.
+	// A spec change resets the breaker, so a fix is tried without waiting for the cooldown.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ProviderStatus reports the uploads of the certificate to a provider.
+type ProviderStatus struct {
+	// Uploaded is true when the current certificate has been uploaded to the provider.
+	// +optional
+	Uploaded bool `json:"uploaded,omitempty"`
+
+	// Identifier identifies the certificate at the provider, e.g. the ACM ARN, the
+	// Cloudflare certificate ID, or the pending Akamai CPS change the operator started.
+	// +optional
+	Identifier string `json:"identifier,omitempty"`
+
+	// LastUploadedTime is when the certificate was last uploaded to the provider.
+	// +optional
+	LastUploadedTime *metav1.Time `json:"lastUploadedTime,omitempty"`
+
+	// LastError is the error of the latest failed upload to the provider. It is cleared
+	// once an upload succeeds.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// ReuploadPending is true when uploads to the provider were disabled by the operator,
+	// which may have skipped renewals. The certificate is uploaded to the provider again once
+	// it is enabled.
+	// +optional
+	ReuploadPending bool `json:"reuploadPending,omitempty"`
+
+	// ACM is the state AWS ACM reports for the certificate, read after each import. Only set
+	// for the aws provider.
+	// +optional
+	ACM *ACMCertificateStatus `json:"acm,omitempty"`
+}
+
+// ACMCertificateStatus is the state AWS ACM reports for an imported certificate. ACM
+// doesn't renew imported certificates, the operator re-imports them once cert-manager
+// renews them.
+type ACMCertificateStatus struct {
+	// Status is the ACM status of the certificate, e.g. ISSUED or EXPIRED.
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// Type is the ACM type of the certificate, IMPORTED for certificates the operator uploads.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// RenewalEligibility is whether ACM can renew the certificate, INELIGIBLE for imported
+	// certificates.
+	// +optional
+	RenewalEligibility string `json:"renewalEligibility,omitempty"`
+
+	// InUseByCount is the number of AWS resources using the certificate, e.g. load balancers
+	// or CloudFront distributions.
+	// +optional
+	InUseByCount int32 `json:"inUseByCount,omitempty"`
+
+	// ImportedAt is when the certificate was last imported into ACM.
+	// +optional
+	ImportedAt *metav1.Time `json:"importedAt,omitempty"`
+
+	// FailureReason is why ACM rejected the certificate, set when Status is FAILED.
+	// +optional
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+// FinalizationStatus is the outcome of the cleanup of the provider resources during deletion.
+// Resources are named provider/identifier, e.g. cloudflare/023e105f4ecef8ad9ca31a8372d0c353.
+type FinalizationStatus struct {
+	// Deleted are the provider resources that were deleted.
+	// +optional
+	Deleted []string `json:"deleted,omitempty"`
+
+	// Failed are the provider resources that couldn't be deleted. The cleanup is retried
+	// until the list is empty.
+	// +optional
+	Failed []CleanupFailure `json:"failed,omitempty"`
+}
+
+// CleanupFailure is a provider resource that couldn't be deleted.
+type CleanupFailure struct {
+	// Resource is the provider resource as provider/identifier.
+	Resource string `json:"resource"`
+
+	// Error is why the deletion failed.
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Operator Version",type=string,JSONPath=`.status.managedByVersion`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Certificate is the Schema for the certificates API.
 type Certificate struct {
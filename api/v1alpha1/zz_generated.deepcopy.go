@@ -21,9 +21,29 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ACMCertificateStatus) DeepCopyInto(out *ACMCertificateStatus) {
+	*out = *in
+	if in.ImportedAt != nil {
+		in, out := &in.ImportedAt, &out.ImportedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACMCertificateStatus.
+func (in *ACMCertificateStatus) DeepCopy() *ACMCertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ACMCertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWS) DeepCopyInto(out *AWS) {
 	*out = *in
@@ -101,16 +121,92 @@ func (in *CertificateList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
 	*out = *in
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Subject != nil {
+		in, out := &in.Subject, &out.Subject
+		*out = new(X509Subject)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalOutputFormats != nil {
+		in, out := &in.AdditionalOutputFormats, &out.AdditionalOutputFormats
+		*out = make([]AdditionalOutputFormat, len(*in))
+		copy(*out, *in)
+	}
+	if in.CertificateLabels != nil {
+		in, out := &in.CertificateLabels, &out.CertificateLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CertificateAnnotations != nil {
+		in, out := &in.CertificateAnnotations, &out.CertificateAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FallbackAfter != nil {
+		in, out := &in.FallbackAfter, &out.FallbackAfter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.IssuanceTimeout != nil {
+		in, out := &in.IssuanceTimeout, &out.IssuanceTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.CloudflareEnabled != nil {
 		in, out := &in.CloudflareEnabled, &out.CloudflareEnabled
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DNSCheck != nil {
+		in, out := &in.DNSCheck, &out.DNSCheck
+		*out = new(DNSCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisableFinalizer != nil {
+		in, out := &in.DisableFinalizer, &out.DisableFinalizer
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UploadsPaused != nil {
+		in, out := &in.UploadsPaused, &out.UploadsPaused
+		*out = new(bool)
+		**out = **in
+	}
 	if in.AWS != nil {
 		in, out := &in.AWS, &out.AWS
 		*out = new(AWS)
 		**out = **in
 	}
+	if in.AWSAssumeRoleARNs != nil {
+		in, out := &in.AWSAssumeRoleARNs, &out.AWSAssumeRoleARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProviderTags != nil {
+		in, out := &in.ProviderTags, &out.ProviderTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]RemoteCluster, len(*in))
+		copy(*out, *in)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSpec.
@@ -126,10 +222,71 @@ func (in *CertificateSpec) DeepCopy() *CertificateSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
 	*out = *in
+	if in.S3ObjectKeys != nil {
+		in, out := &in.S3ObjectKeys, &out.S3ObjectKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make(map[string]ProviderStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.LastUploadedTime != nil {
 		in, out := &in.LastUploadedTime, &out.LastUploadedTime
 		*out = (*in).DeepCopy()
 	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.IssuanceStartedAt != nil {
+		in, out := &in.IssuanceStartedAt, &out.IssuanceStartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.IssuanceFailingSince != nil {
+		in, out := &in.IssuanceFailingSince, &out.IssuanceFailingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AWSAccountCertificateARNs != nil {
+		in, out := &in.AWSAccountCertificateARNs, &out.AWSAccountCertificateARNs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]RemoteClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProviderCircuitBreakers != nil {
+		in, out := &in.ProviderCircuitBreakers, &out.ProviderCircuitBreakers
+		*out = make([]ProviderCircuitBreaker, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Finalization != nil {
+		in, out := &in.Finalization, &out.Finalization
+		*out = new(FinalizationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
@@ -141,3 +298,205 @@ func (in *CertificateStatus) DeepCopy() *CertificateStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupFailure) DeepCopyInto(out *CleanupFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupFailure.
+func (in *CleanupFailure) DeepCopy() *CleanupFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSCheck) DeepCopyInto(out *DNSCheck) {
+	*out = *in
+	if in.ExpectedAddresses != nil {
+		in, out := &in.ExpectedAddresses, &out.ExpectedAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSCheck.
+func (in *DNSCheck) DeepCopy() *DNSCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalizationStatus) DeepCopyInto(out *FinalizationStatus) {
+	*out = *in
+	if in.Deleted != nil {
+		in, out := &in.Deleted, &out.Deleted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make([]CleanupFailure, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalizationStatus.
+func (in *FinalizationStatus) DeepCopy() *FinalizationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalizationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderCircuitBreaker) DeepCopyInto(out *ProviderCircuitBreaker) {
+	*out = *in
+	if in.OpenedAt != nil {
+		in, out := &in.OpenedAt, &out.OpenedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderCircuitBreaker.
+func (in *ProviderCircuitBreaker) DeepCopy() *ProviderCircuitBreaker {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderCircuitBreaker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderStatus) DeepCopyInto(out *ProviderStatus) {
+	*out = *in
+	if in.LastUploadedTime != nil {
+		in, out := &in.LastUploadedTime, &out.LastUploadedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ACM != nil {
+		in, out := &in.ACM, &out.ACM
+		*out = new(ACMCertificateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderStatus.
+func (in *ProviderStatus) DeepCopy() *ProviderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
+func (in *RemoteCluster) DeepCopy() *RemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterStatus) DeepCopyInto(out *RemoteClusterStatus) {
+	*out = *in
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterStatus.
+func (in *RemoteClusterStatus) DeepCopy() *RemoteClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3) DeepCopyInto(out *S3) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3.
+func (in *S3) DeepCopy() *S3 {
+	if in == nil {
+		return nil
+	}
+	out := new(S3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *X509Subject) DeepCopyInto(out *X509Subject) {
+	*out = *in
+	if in.Organizations != nil {
+		in, out := &in.Organizations, &out.Organizations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrganizationalUnits != nil {
+		in, out := &in.OrganizationalUnits, &out.OrganizationalUnits
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Countries != nil {
+		in, out := &in.Countries, &out.Countries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Provinces != nil {
+		in, out := &in.Provinces, &out.Provinces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Localities != nil {
+		in, out := &in.Localities, &out.Localities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StreetAddresses != nil {
+		in, out := &in.StreetAddresses, &out.StreetAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostalCodes != nil {
+		in, out := &in.PostalCodes, &out.PostalCodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new X509Subject.
+func (in *X509Subject) DeepCopy() *X509Subject {
+	if in == nil {
+		return nil
+	}
+	out := new(X509Subject)
+	in.DeepCopyInto(out)
+	return out
+}
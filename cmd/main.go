@@ -19,8 +19,10 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -28,20 +30,34 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	acmev1 "github.com/cert-manager/cert-manager/pkg/apis/acme/v1"
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
 	"github.com/tae2089/certificate-operator/internal/api"
+	"github.com/tae2089/certificate-operator/internal/api/audit"
+	"github.com/tae2089/certificate-operator/internal/blackout"
+	"github.com/tae2089/certificate-operator/internal/config"
 	"github.com/tae2089/certificate-operator/internal/controller"
 	"github.com/tae2089/certificate-operator/internal/driver"
+	kubernetesdriver "github.com/tae2089/certificate-operator/internal/driver/kubernetes"
+	"github.com/tae2089/certificate-operator/internal/metricsauth"
+	"github.com/tae2089/certificate-operator/internal/reconcileonce"
+	"github.com/tae2089/certificate-operator/internal/version"
+	webhookv1alpha1 "github.com/tae2089/certificate-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -65,28 +81,38 @@ var (
 // @BasePath /
 // @schemes http https
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(certificatev1alpha1.AddToScheme(scheme))
 	utilruntime.Must(certmanagerv1.AddToScheme(scheme))
+	utilruntime.Must(acmev1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 // nolint:gocyclo
 func main() {
-	var metricsAddr string
+	if len(os.Args) > 1 && os.Args[1] == reconcileonce.CommandName {
+		if err := runReconcileOnce(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
-	var enableAPIServer bool
-	var apiServerPort string
+	var configFile string
+	operatorConfig := config.NewOperatorConfig()
 	var tlsOpts []func(*tls.Config)
-	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
-		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
@@ -102,10 +128,9 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
-	flag.BoolVar(&enableAPIServer, "enable-api-server", true,
-		"Enable the REST API server for Certificate CRUD operations")
-	flag.StringVar(&apiServerPort, "api-server-port", "8080",
-		"The port on which the REST API server will listen")
+	flag.StringVar(&configFile, "config", "",
+		"Path to a YAML operator config file. Flags set on the command line override values from the file.")
+	operatorConfig.BindFlags(flag.CommandLine)
 	opts := zap.Options{
 		Development: true,
 	}
@@ -114,6 +139,17 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if configFile != "" {
+		if err := operatorConfig.LoadFile(configFile, flag.CommandLine); err != nil {
+			setupLog.Error(err, "unable to load operator config", "path", configFile)
+			os.Exit(1)
+		}
+	}
+	if err := operatorConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid operator config")
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -163,7 +199,7 @@ func main() {
 	// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/metrics/server
 	// - https://book.kubebuilder.io/reference/metrics.html
 	metricsServerOptions := metricsserver.Options{
-		BindAddress:   metricsAddr,
+		BindAddress:   operatorConfig.Metrics.BindAddress,
 		SecureServing: secureMetrics,
 		TLSOpts:       tlsOpts,
 	}
@@ -176,6 +212,18 @@ func main() {
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
 
+	// A static bearer token replaces the authn/authz filter, for scrapers outside the cluster
+	if operatorConfig.Metrics.BearerTokenFile != "" {
+		token, err := metricsauth.ReadBearerToken(operatorConfig.Metrics.BearerTokenFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load metrics bearer token")
+			os.Exit(1)
+		}
+		setupLog.Info("Protecting the metrics endpoint with a bearer token",
+			"metrics-bearer-token-file", operatorConfig.Metrics.BearerTokenFile)
+		metricsServerOptions.FilterProvider = metricsauth.BearerTokenFilterProvider(token)
+	}
+
 	// If the certificate is not specified, controller-runtime will automatically
 	// generate self-signed certificates for the metrics server. While convenient for development and testing,
 	// this setup is not recommended for production.
@@ -203,13 +251,21 @@ func main() {
 		})
 	}
 
+	// Each operator instance elects its own leader
+	leaderElectionID := "4a2b0970.println.kr"
+	if operatorConfig.InstanceID != "" {
+		leaderElectionID = operatorConfig.InstanceID + "." + leaderElectionID
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "4a2b0970.println.kr",
+		LeaderElectionID:       leaderElectionID,
+		// Leave room for in-flight uploads to finish within the provider shutdown grace period
+		GracefulShutdownTimeout: ptr.To(operatorConfig.Providers.ShutdownGracePeriod.Duration + 5*time.Second),
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -226,15 +282,82 @@ func main() {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+	// Fail fast on clusters without a compatible cert-manager instead of in every reconcile
+	if err := checkCertManagerAPI(mgr.GetConfig()); err != nil {
+		setupLog.Error(err, "cert-manager API check failed")
+		os.Exit(1)
+	}
+
+	uploadBlackout, err := blackout.NewSchedule(operatorConfig.Providers.UploadBlackout.Windows,
+		operatorConfig.Providers.UploadBlackout.TimeZone)
+	if err != nil {
+		setupLog.Error(err, "invalid upload blackout windows")
+		os.Exit(1)
+	}
 
+	reconcileTracker := controller.NewReconcileTracker(operatorConfig.Controller.ReconcileLagThreshold.Duration)
+	eventRecorder := mgr.GetEventRecorderFor("certificate-controller")
+	certificateManager := driver.NewCertificateManager(mgr.GetClient(), mgr.GetScheme(),
+		driver.WithCredentialsNamespace(operatorConfig.CredentialsNamespace),
+		driver.WithInstanceID(operatorConfig.InstanceID),
+		driver.WithManagedBy(operatorConfig.ManagedBy),
+		driver.WithNameSuffixes(operatorConfig.CertificateNameSuffix, operatorConfig.SecretNameSuffix),
+		driver.WithMaxRetries(operatorConfig.Providers.MaxRetries),
+		driver.WithShutdownGracePeriod(operatorConfig.Providers.ShutdownGracePeriod.Duration),
+		driver.WithMaxConcurrentUploads(operatorConfig.Providers.MaxConcurrentUploads),
+		driver.WithDisabledProviders(operatorConfig.Providers.Disabled),
+		driver.WithRenewalUploadWindow(operatorConfig.Providers.RenewalUploadWindow.Duration),
+		driver.WithUploadVerification(operatorConfig.Providers.VerifyUploads),
+		driver.WithDisabledUploadCleanup(operatorConfig.Providers.DeleteDisabledUploads),
+		driver.WithDefaultAWSRegion(operatorConfig.Providers.DefaultAWSRegion),
+		driver.WithCircuitBreaker(operatorConfig.Providers.CircuitBreakerThreshold,
+			operatorConfig.Providers.CircuitBreakerCooldown.Duration),
+		driver.WithTagLabelPrefix(operatorConfig.Providers.TagLabelPrefix),
+		driver.WithDNSResolver(operatorConfig.Controller.DNSResolver),
+		driver.WithUploadBlackout(uploadBlackout),
+		driver.WithEventRecorder(eventRecorder),
+	)
+	// Lets in-flight uploads finish when the operator shuts down
+	if err := mgr.Add(certificateManager); err != nil {
+		setupLog.Error(err, "unable to add certificate manager to manager")
+		os.Exit(1)
+	}
 	if err := (&controller.CertificateReconciler{
-		Client:  mgr.GetClient(),
-		Scheme:  mgr.GetScheme(),
-		Manager: driver.NewCertificateManager(mgr.GetClient(), mgr.GetScheme()),
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Manager:                 certificateManager,
+		FinalizeRetryInterval:   operatorConfig.Controller.FinalizeRetryInterval.Duration,
+		Tracker:                 reconcileTracker,
+		Recorder:                eventRecorder,
+		CredentialsNamespace:    operatorConfig.CredentialsNamespace,
+		MaxConcurrentReconciles: operatorConfig.Controller.MaxConcurrentReconciles,
+		Version:                 version.Version,
+		InstanceID:              operatorConfig.InstanceID,
+		APIReader:               mgr.GetAPIReader(),
+		SecretNameSuffix:        operatorConfig.SecretNameSuffix,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Certificate")
 		os.Exit(1)
 	}
+	if operatorConfig.Controller.WatchIngresses {
+		if err := (&controller.IngressReconciler{
+			Client:     mgr.GetClient(),
+			Scheme:     mgr.GetScheme(),
+			InstanceID: operatorConfig.InstanceID,
+			Recorder:   mgr.GetEventRecorderFor("ingress-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Ingress")
+			os.Exit(1)
+		}
+	}
+	// The conversion and validating webhooks need serving certificates, so they are only enabled when
+	// deployed with config/webhook (see config/default/manager_webhook_patch.yaml)
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err := webhookv1alpha1.SetupCertificateWebhookWithManager(mgr, operatorConfig.CredentialsNamespace); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Certificate")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -257,6 +380,11 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	// A stalled reconcile loop makes the operator unready, without restarting it mid-upload
+	if err := mgr.AddReadyzCheck("reconcile-lag", reconcileTracker.Check); err != nil {
+		setupLog.Error(err, "unable to set up reconcile lag ready check")
+		os.Exit(1)
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
@@ -266,20 +394,133 @@ func main() {
 	ctx := ctrl.SetupSignalHandler()
 
 	// Start API server if enabled
-	if enableAPIServer {
-		setupLog.Info("API server is enabled, starting API server", "port", apiServerPort)
+	if operatorConfig.APIServer.Enabled {
+		setupLog.Info("API server is enabled, starting API server", "port", operatorConfig.APIServer.Port,
+			"auditSink", operatorConfig.APIServer.Audit.Sink, "listCacheTTL", operatorConfig.APIServer.ListCacheTTL.Duration,
+			"adminEndpoints", operatorConfig.APIServer.AdminTokenFile != "")
+
+		auditSink, err := audit.NewSink(operatorConfig.APIServer.Audit.Sink, operatorConfig.APIServer.Audit.File)
+		if err != nil {
+			setupLog.Error(err, "unable to create API audit sink")
+			os.Exit(1)
+		}
+
+		trustedProxies, err := audit.ParseTrustedProxies(operatorConfig.APIServer.TrustedProxies)
+		if err != nil {
+			setupLog.Error(err, "invalid API trusted proxies")
+			os.Exit(1)
+		}
+
+		adminToken := ""
+		if operatorConfig.APIServer.AdminTokenFile != "" {
+			adminToken, err = metricsauth.ReadBearerToken(operatorConfig.APIServer.AdminTokenFile)
+			if err != nil {
+				setupLog.Error(err, "unable to load API admin token")
+				os.Exit(1)
+			}
+		}
+
+		// The manager's client reads from the informer cache, which can't be watched
+		watchClient, err := client.NewWithWatch(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+		if err != nil {
+			setupLog.Error(err, "unable to create API watch client")
+			os.Exit(1)
+		}
 
 		// Run API server in background goroutine
 		go func() {
-			if err := api.StartAPIServer(ctx, mgr.GetClient(), apiServerPort); err != nil {
+			if err := api.StartAPIServer(ctx, mgr.GetClient(), mgr.GetAPIReader(), watchClient, certificateManager,
+				operatorConfig.APIServer.Port, auditSink, trustedProxies, operatorConfig.APIServer.ListCacheTTL.Duration, adminToken,
+				operatorConfig.CertificateNameSuffix, operatorConfig.SecretNameSuffix,
+				operatorConfig.APIServer.RequestTimeout.Duration, operatorConfig.APIServer.LongRequestTimeout.Duration); err != nil {
 				setupLog.Error(err, "API server error")
 			}
 		}()
 	}
 
-	setupLog.Info("starting manager")
+	setupLog.Info("starting manager", "version", version.Version)
 	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// runReconcileOnce processes a single Certificate with the operator settings as a dry run and
+// prints the status changes, without starting the controller manager
+func runReconcileOnce(args []string) error {
+	fs := flag.NewFlagSet(reconcileonce.CommandName, flag.ContinueOnError)
+	var configFile string
+	var opts reconcileonce.Options
+	operatorConfig := config.NewOperatorConfig()
+	fs.StringVar(&configFile, "config", "",
+		"Path to a YAML operator config file. Flags set on the command line override values from the file.")
+	opts.BindFlags(fs)
+	operatorConfig.BindFlags(fs)
+	ctrlconfig.RegisterFlags(fs)
+	zapOpts := zap.Options{
+		Development: true,
+	}
+	zapOpts.BindFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&zapOpts)))
+
+	if configFile != "" {
+		if err := operatorConfig.LoadFile(configFile, fs); err != nil {
+			return fmt.Errorf("unable to load operator config: %w", err)
+		}
+	}
+	if err := operatorConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid operator config: %w", err)
+	}
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("unable to load kubeconfig: %w", err)
+	}
+	if err := checkCertManagerAPI(restConfig); err != nil {
+		return err
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("unable to create client: %w", err)
+	}
+
+	uploadBlackout, err := blackout.NewSchedule(operatorConfig.Providers.UploadBlackout.Windows,
+		operatorConfig.Providers.UploadBlackout.TimeZone)
+	if err != nil {
+		return fmt.Errorf("invalid upload blackout windows: %w", err)
+	}
+
+	certificateManager := driver.NewCertificateManager(k8sClient, scheme,
+		driver.WithCredentialsNamespace(operatorConfig.CredentialsNamespace),
+		driver.WithInstanceID(operatorConfig.InstanceID),
+		driver.WithManagedBy(operatorConfig.ManagedBy),
+		driver.WithNameSuffixes(operatorConfig.CertificateNameSuffix, operatorConfig.SecretNameSuffix),
+		driver.WithMaxRetries(operatorConfig.Providers.MaxRetries),
+		driver.WithDisabledProviders(operatorConfig.Providers.Disabled),
+		driver.WithRenewalUploadWindow(operatorConfig.Providers.RenewalUploadWindow.Duration),
+		driver.WithUploadVerification(operatorConfig.Providers.VerifyUploads),
+		driver.WithDisabledUploadCleanup(operatorConfig.Providers.DeleteDisabledUploads),
+		driver.WithDefaultAWSRegion(operatorConfig.Providers.DefaultAWSRegion),
+		driver.WithCircuitBreaker(operatorConfig.Providers.CircuitBreakerThreshold,
+			operatorConfig.Providers.CircuitBreakerCooldown.Duration),
+		driver.WithTagLabelPrefix(operatorConfig.Providers.TagLabelPrefix),
+		driver.WithDNSResolver(operatorConfig.Controller.DNSResolver),
+		driver.WithUploadBlackout(uploadBlackout),
+		driver.WithDryRun(),
+	)
+	return reconcileonce.Run(ctrl.SetupSignalHandler(), k8sClient, certificateManager, opts, os.Stdout)
+}
+
+// checkCertManagerAPI checks that the cluster serves the cert-manager API versions the
+// operator was built against
+func checkCertManagerAPI(restConfig *rest.Config) error {
+	dc, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create discovery client: %w", err)
+	}
+	return kubernetesdriver.CheckCertManagerAPI(dc)
+}
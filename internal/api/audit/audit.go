@@ -0,0 +1,341 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var (
+	auditLog = ctrl.Log.WithName("api-audit")
+)
+
+const (
+	// SinkNone disables auditing
+	SinkNone = "none"
+
+	// SinkLog writes audit entries to the operator log
+	SinkLog = "log"
+
+	// SinkFile appends audit entries as JSON lines to a file
+	SinkFile = "file"
+
+	// OutcomeSuccess is recorded for mutations that returned a 2xx or 3xx status
+	OutcomeSuccess = "success"
+
+	// OutcomeFailure is recorded for mutations that returned an error status or panicked
+	OutcomeFailure = "failure"
+
+	// AnonymousPrincipal is recorded when the request carries no verified identity
+	AnonymousPrincipal = "anonymous"
+
+	// AdminTokenPrincipal is recorded for requests authenticated with the admin bearer token
+	AdminTokenPrincipal = "admin-token"
+
+	// remoteUserHeader carries the user authenticated by a fronting proxy
+	remoteUserHeader = "X-Remote-User"
+
+	// principalKey is the gin context key of the principal set by authentication middleware
+	principalKey = "audit.principal"
+
+	// maxBodySize caps the request and error response bytes the middleware keeps
+	maxBodySize = 1 << 20
+)
+
+// Event is a single audit record for a mutating API request
+type Event struct {
+	Time      time.Time `json:"time"`
+	Principal string    `json:"principal"`
+	SourceIP  string    `json:"sourceIP"`
+	Action    string    `json:"action"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Resource  string    `json:"resource"`
+	Status    int       `json:"status"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Sink stores audit entries
+type Sink interface {
+	Record(ctx context.Context, event Event) error
+}
+
+// LogSink writes audit entries to a logger
+type LogSink struct {
+	Logger logr.Logger
+}
+
+// Record logs the event
+func (s LogSink) Record(_ context.Context, event Event) error {
+	s.Logger.Info("API audit",
+		"principal", event.Principal,
+		"sourceIP", event.SourceIP,
+		"action", event.Action,
+		"resource", event.Resource,
+		"status", event.Status,
+		"outcome", event.Outcome,
+		"error", event.Error)
+	return nil
+}
+
+// FileSink appends audit entries as JSON lines to a file
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens path for appending, creating it if needed
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Record appends the event to the file
+func (s *FileSink) Record(_ context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(line)
+	return err
+}
+
+// Close closes the audit file
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// NewSink creates the sink of the given kind. The path is only used by SinkFile.
+// It returns a nil Sink for SinkNone.
+func NewSink(kind, path string) (Sink, error) {
+	switch kind {
+	case SinkNone:
+		return nil, nil
+	case SinkLog:
+		return LogSink{Logger: auditLog}, nil
+	case SinkFile:
+		return NewFileSink(path)
+	default:
+		return nil, fmt.Errorf("unsupported audit sink %q (supported sinks: %s, %s, %s)", kind, SinkNone, SinkLog, SinkFile)
+	}
+}
+
+// SetPrincipal records the principal an authentication middleware verified for the request
+func SetPrincipal(c *gin.Context, principal string) {
+	c.Set(principalKey, principal)
+}
+
+// ParseTrustedProxies parses IP addresses and CIDR ranges
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if addr, err := netip.ParseAddr(value); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, expected an IP address or CIDR range", value)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Middleware records an audit event for every mutating request, including failed ones.
+// Read-only requests are not audited. Sink errors are logged and never change the response.
+// The X-Remote-User header is only trusted on requests from trustedProxies.
+func Middleware(sink Sink, trustedProxies []netip.Prefix) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutation(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		body := readBody(c.Request)
+		writer := &errorCapturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		event := Event{
+			Time:     time.Now().UTC(),
+			SourceIP: c.ClientIP(),
+			Method:   c.Request.Method,
+			Path:     c.Request.URL.Path,
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				event.Principal = principal(c, trustedProxies)
+				event.Action, event.Resource = describe(c, body)
+				event.Status = http.StatusInternalServerError
+				event.Outcome = OutcomeFailure
+				event.Error = fmt.Sprint(r)
+				record(c, sink, event)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		// Authentication middleware of the route sets the principal it verified
+		event.Principal = principal(c, trustedProxies)
+		event.Action, event.Resource = describe(c, body)
+		event.Status = writer.Status()
+		event.Outcome = OutcomeSuccess
+		if event.Status >= http.StatusBadRequest {
+			event.Outcome = OutcomeFailure
+			event.Error = writer.errorMessage()
+		}
+		record(c, sink, event)
+	}
+}
+
+// record stores event, logging sink failures
+func record(c *gin.Context, sink Sink, event Event) {
+	if err := sink.Record(c.Request.Context(), event); err != nil {
+		auditLog.Error(err, "Failed to record audit event", "action", event.Action, "resource", event.Resource)
+	}
+}
+
+// isMutation reports whether method changes state
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// principal returns the identity of the caller: the principal verified by authentication
+// middleware, the X-Remote-User header set by a trusted authenticating proxy, or
+// AnonymousPrincipal. HTTP basic auth users aren't verified by the API, so they are ignored.
+func principal(c *gin.Context, trustedProxies []netip.Prefix) string {
+	if user := c.GetString(principalKey); user != "" {
+		return user
+	}
+	if user := c.GetHeader(remoteUserHeader); user != "" && fromTrustedProxy(c, trustedProxies) {
+		return user
+	}
+	return AnonymousPrincipal
+}
+
+// fromTrustedProxy reports whether the request was sent directly by one of trustedProxies
+func fromTrustedProxy(c *gin.Context, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(c.RemoteIP())
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBody returns up to maxBodySize bytes of the request body and restores it for the handler
+func readBody(req *http.Request) []byte {
+	if req.Body == nil {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(req.Body, maxBodySize))
+	req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+	return body
+}
+
+// describe returns the action and the affected resource of a request.
+// Resources are "certificates/{namespace}/{name}", the label selector for batch deletes, or
+// "certificates/{namespace}?source={source}" for syncs.
+func describe(c *gin.Context, body []byte) (string, string) {
+	// Custom methods on collections, e.g. POST /certificates:sync, take the namespace and
+	// the source they change from the body
+	if collection, collectionAction, ok := strings.Cut(c.Param("collection"), ":"); ok {
+		var ref struct {
+			Namespace string `json:"namespace"`
+			Source    string `json:"source"`
+		}
+		_ = json.Unmarshal(body, &ref)
+		return collectionAction, fmt.Sprintf("%s/%s?source=%s", collection, ref.Namespace, ref.Source)
+	}
+
+	namespace := c.Param("namespace")
+	name, customAction, _ := strings.Cut(c.Param("name"), ":")
+
+	if namespace == "" && name == "" {
+		// Create takes the name and namespace from the body
+		var ref struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		}
+		if err := json.Unmarshal(body, &ref); err == nil {
+			namespace, name = ref.Namespace, ref.Name
+		}
+	}
+
+	resource := "certificates"
+	switch {
+	case name != "":
+		resource = fmt.Sprintf("certificates/%s/%s", namespace, name)
+	case c.Query("labelSelector") != "":
+		resource = "certificates?labelSelector=" + c.Query("labelSelector")
+	}
+
+	if customAction != "" {
+		return customAction, resource
+	}
+	switch c.Request.Method {
+	case http.MethodPost:
+		return "create", resource
+	case http.MethodPut, http.MethodPatch:
+		return "update", resource
+	case http.MethodDelete:
+		return "delete", resource
+	}
+	return strings.ToLower(c.Request.Method), resource
+}
+
+// errorCapturingWriter keeps the body of error responses for the audit event
+type errorCapturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write captures the body when the response is an error
+func (w *errorCapturingWriter) Write(data []byte) (int, error) {
+	if w.Status() >= http.StatusBadRequest && w.body.Len() < maxBodySize {
+		w.body.Write(data[:min(len(data), maxBodySize-w.body.Len())])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// errorMessage returns the "error" field of a JSON error response, or the raw body
+func (w *errorCapturingWriter) errorMessage() string {
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(w.body.Bytes(), &resp); err == nil && resp.Error != "" {
+		return resp.Error
+	}
+	return strings.TrimSpace(w.body.String())
+}
@@ -0,0 +1,230 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// recordingSink keeps every entry in memory.
+type recordingSink struct {
+	mu      sync.Mutex
+	entries []Event
+	err     error
+}
+
+func (s *recordingSink) Record(_ context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, event)
+	return s.err
+}
+
+var _ = Describe("Middleware", func() {
+	var (
+		sink   *recordingSink
+		engine *gin.Engine
+	)
+
+	BeforeEach(func() {
+		sink = &recordingSink{}
+		engine = gin.New()
+		engine.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}))
+		// httptest requests come from 192.0.2.1
+		trustedProxies, err := ParseTrustedProxies([]string{"192.0.2.1"})
+		Expect(err).NotTo(HaveOccurred())
+		v1 := engine.Group("/api/v1", Middleware(sink, trustedProxies))
+		v1.POST("/certificates", func(c *gin.Context) {
+			var req map[string]any
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusCreated, req)
+		})
+		v1.GET("/certificates", func(c *gin.Context) { c.JSON(http.StatusOK, []string{}) })
+		v1.DELETE("/certificates", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+		v1.PUT("/namespaces/:namespace/certificates/:name", func(c *gin.Context) {
+			c.JSON(http.StatusNotFound, gin.H{"error": `certificates "missing" not found`})
+		})
+		v1.DELETE("/namespaces/:namespace/certificates/:name", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		v1.POST("/namespaces/:namespace/certificates/:name", func(c *gin.Context) { panic("boom") })
+		v1.POST("/:collection", func(c *gin.Context) {
+			SetPrincipal(c, AdminTokenPrincipal)
+			c.JSON(http.StatusOK, gin.H{"results": []string{}})
+		})
+	})
+
+	perform := func(method, path, body string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	It("should record a create with the resource from the body", func() {
+		recorder := perform(http.MethodPost, "/api/v1/certificates", `{"name":"example","namespace":"default"}`,
+			"X-Remote-User", "alice")
+		Expect(recorder.Code).To(Equal(http.StatusCreated))
+		Expect(recorder.Body.String()).To(ContainSubstring(`"name":"example"`))
+
+		Expect(sink.entries).To(HaveLen(1))
+		entry := sink.entries[0]
+		Expect(entry.Principal).To(Equal("alice"))
+		Expect(entry.Action).To(Equal("create"))
+		Expect(entry.Resource).To(Equal("certificates/default/example"))
+		Expect(entry.Status).To(Equal(http.StatusCreated))
+		Expect(entry.Outcome).To(Equal(OutcomeSuccess))
+		Expect(entry.Error).To(BeEmpty())
+		Expect(entry.Time).NotTo(BeZero())
+	})
+
+	It("should not trust X-Remote-User from other clients", func() {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/certificates",
+			bytes.NewBufferString(`{"name":"example","namespace":"default"}`))
+		req.RemoteAddr = "203.0.113.7:4321"
+		req.Header.Set("X-Remote-User", "alice")
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+		Expect(recorder.Code).To(Equal(http.StatusCreated))
+
+		Expect(sink.entries).To(HaveLen(1))
+		Expect(sink.entries[0].Principal).To(Equal(AnonymousPrincipal))
+		Expect(sink.entries[0].SourceIP).To(Equal("203.0.113.7"))
+	})
+
+	It("should record failed mutations with the error and ignore unverified basic auth users", func() {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/namespaces/default/certificates/missing", nil)
+		req.SetBasicAuth("bob", "secret")
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+
+		Expect(sink.entries).To(HaveLen(1))
+		entry := sink.entries[0]
+		Expect(entry.Principal).To(Equal(AnonymousPrincipal))
+		Expect(entry.Action).To(Equal("update"))
+		Expect(entry.Resource).To(Equal("certificates/default/missing"))
+		Expect(entry.Outcome).To(Equal(OutcomeFailure))
+		Expect(entry.Error).To(Equal(`certificates "missing" not found`))
+	})
+
+	It("should record custom actions and panics", func() {
+		recorder := perform(http.MethodPost, "/api/v1/namespaces/default/certificates/example:resetUploadStatus", "")
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+
+		Expect(sink.entries).To(HaveLen(1))
+		entry := sink.entries[0]
+		Expect(entry.Principal).To(Equal(AnonymousPrincipal))
+		Expect(entry.Action).To(Equal("resetUploadStatus"))
+		Expect(entry.Resource).To(Equal("certificates/default/example"))
+		Expect(entry.Outcome).To(Equal(OutcomeFailure))
+		Expect(entry.Error).To(Equal("boom"))
+	})
+
+	It("should record a sync with its namespace and source", func() {
+		recorder := perform(http.MethodPost, "/api/v1/certificates:sync",
+			`{"namespace":"team-a","source":"infra-certificates","certificates":[{"name":"example"}]}`)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		Expect(sink.entries).To(HaveLen(1))
+		entry := sink.entries[0]
+		Expect(entry.Principal).To(Equal(AdminTokenPrincipal))
+		Expect(entry.Action).To(Equal("sync"))
+		Expect(entry.Resource).To(Equal("certificates/team-a?source=infra-certificates"))
+		Expect(entry.Path).To(Equal("/api/v1/certificates:sync"))
+		Expect(entry.Outcome).To(Equal(OutcomeSuccess))
+	})
+
+	It("should record one entry per mutation and none for reads", func() {
+		perform(http.MethodGet, "/api/v1/certificates", "")
+		perform(http.MethodDelete, "/api/v1/certificates?labelSelector=env%3Dtest", "")
+		perform(http.MethodDelete, "/api/v1/namespaces/default/certificates/example", "")
+		perform(http.MethodPost, "/api/v1/certificates", `not json`)
+
+		Expect(sink.entries).To(HaveLen(3))
+		Expect(sink.entries[0].Action).To(Equal("delete"))
+		Expect(sink.entries[0].Resource).To(Equal("certificates?labelSelector=env=test"))
+		Expect(sink.entries[1].Resource).To(Equal("certificates/default/example"))
+		Expect(sink.entries[1].Status).To(Equal(http.StatusNoContent))
+		Expect(sink.entries[2].Resource).To(Equal("certificates"))
+		Expect(sink.entries[2].Outcome).To(Equal(OutcomeFailure))
+	})
+
+	It("should not fail the request when the sink fails", func() {
+		sink.err = errors.New("disk full")
+		recorder := perform(http.MethodDelete, "/api/v1/namespaces/default/certificates/example", "")
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+	})
+})
+
+var _ = Describe("ParseTrustedProxies", func() {
+	It("should accept IP addresses and CIDR ranges", func() {
+		prefixes, err := ParseTrustedProxies([]string{"10.0.0.1", "10.1.0.0/16", "fd00::/8"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prefixes).To(HaveLen(3))
+		Expect(prefixes[0].String()).To(Equal("10.0.0.1/32"))
+	})
+
+	It("should reject hostnames", func() {
+		_, err := ParseTrustedProxies([]string{"proxy.example.com"})
+		Expect(err).To(MatchError(ContainSubstring(`invalid trusted proxy "proxy.example.com"`)))
+	})
+})
+
+var _ = Describe("FileSink", func() {
+	It("should append one JSON line per entry", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		Expect(os.WriteFile(path, []byte(`{"action":"existing"}`+"\n"), 0o600)).To(Succeed())
+
+		sink, err := NewSink(SinkFile, path)
+		Expect(err).NotTo(HaveOccurred())
+		fileSink := sink.(*FileSink)
+		Expect(fileSink.Record(context.Background(), Event{Action: "create", Resource: "certificates/default/a"})).To(Succeed())
+		Expect(fileSink.Record(context.Background(), Event{Action: "delete", Resource: "certificates/default/a"})).To(Succeed())
+		Expect(fileSink.Close()).To(Succeed())
+
+		file, err := os.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close() //nolint:errcheck
+
+		var actions []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var event Event
+			Expect(json.Unmarshal(scanner.Bytes(), &event)).To(Succeed())
+			actions = append(actions, event.Action)
+		}
+		Expect(actions).To(Equal([]string{"existing", "create", "delete"}))
+	})
+})
+
+var _ = Describe("NewSink", func() {
+	It("should return no sink when auditing is disabled", func() {
+		sink, err := NewSink(SinkNone, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sink).To(BeNil())
+	})
+
+	It("should reject unknown sinks", func() {
+		_, err := NewSink("syslog", "")
+		Expect(err).To(MatchError(ContainSubstring("unsupported audit sink")))
+	})
+})
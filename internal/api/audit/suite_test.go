@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAudit(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Audit Suite")
+}
+
+var _ = BeforeSuite(func() {
+	gin.SetMode(gin.TestMode)
+})
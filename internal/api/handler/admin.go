@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/api/audit"
+)
+
+// CloudResourceEntry lists the provider resources the operator manages for a Certificate
+type CloudResourceEntry struct {
+	Namespace         string `json:"namespace" example:"default"`
+	Name              string `json:"name" example:"example-cert"`
+	Domain            string `json:"domain" example:"example.com"`
+	AWSCertificateARN string `json:"awsCertificateARN,omitempty" example:"arn:aws:acm:us-east-1:123456789012:certificate/abc"`
+	// AWSAccountCertificateARNs maps AWS account IDs to the ARN imported through awsAssumeRoleARNs
+	AWSAccountCertificateARNs map[string]string `json:"awsAccountCertificateARNs,omitempty"`
+	CloudflareCertificateID   string            `json:"cloudflareCertificateID,omitempty" example:"2458ce5a-0c35-4c7f-82c7-8e9487d3ff60"`
+	S3ObjectKeys              []string          `json:"s3ObjectKeys,omitempty"`
+	// AkamaiChange is the Akamai CPS change the operator started, while it waits for the certificate
+	AkamaiChange string `json:"akamaiChange,omitempty" example:"/cps/v2/enrollments/1234/changes/5678"`
+	// LastUploadedTime is when the certificate was last uploaded, empty until it is
+	LastUploadedTime string `json:"lastUploadedTime,omitempty" example:"2025-10-03T00:00:00Z"`
+	// RemoteClusters are the remote clusters the TLS Secret is replicated to
+	RemoteClusters []RemoteClusterResource `json:"remoteClusters,omitempty"`
+}
+
+// RemoteClusterResource is the replication of a Certificate's TLS Secret to a remote cluster
+type RemoteClusterResource struct {
+	Name string `json:"name" example:"prod-eu"`
+	// LastSyncedTime is when the Secret was last replicated, empty until it is
+	LastSyncedTime string `json:"lastSyncedTime,omitempty" example:"2025-10-03T00:00:00Z"`
+}
+
+// newCloudResourceEntry returns the provider resources of cert recorded in its status
+func newCloudResourceEntry(cert *certificatev1alpha1.Certificate) CloudResourceEntry {
+	entry := CloudResourceEntry{
+		Namespace:                 cert.Namespace,
+		Name:                      cert.Name,
+		Domain:                    cert.Spec.Domain,
+		AWSCertificateARN:         cert.Status.AWSCertificateARN,
+		AWSAccountCertificateARNs: cert.Status.AWSAccountCertificateARNs,
+		CloudflareCertificateID:   cert.Status.CloudflareCertificateID,
+		S3ObjectKeys:              cert.Status.S3ObjectKeys,
+		AkamaiChange:              cert.Status.Providers["akamai"].Identifier,
+		LastUploadedTime:          formatTime(cert.Status.LastUploadedTime),
+	}
+	for _, cluster := range cert.Status.RemoteClusters {
+		entry.RemoteClusters = append(entry.RemoteClusters, RemoteClusterResource{
+			Name:           cluster.Name,
+			LastSyncedTime: formatTime(cluster.LastSyncedTime),
+		})
+	}
+	return entry
+}
+
+// ListCloudResources godoc
+// @Summary List the provider resources of every Certificate
+// @Description List the AWS ARNs, Cloudflare IDs, S3 object keys, Akamai CPS changes, remote clusters, and upload times recorded in the status of every Certificate across all namespaces, for audits of the provider resources. Requires the admin bearer token.
+// @Tags admin
+// @Produce json,yaml
+// @Security BearerAuth
+// @Success 200 {array} CloudResourceEntry
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/cloud-resources [get]
+func (h *CertificateHandler) ListCloudResources(c *gin.Context) {
+	entries := []CloudResourceEntry{}
+	continueToken := ""
+	for {
+		certList := &certificatev1alpha1.CertificateList{}
+		if err := h.listPage(c, certList, continueToken); err != nil {
+			respondKubernetesError(c, err)
+			return
+		}
+		for i := range certList.Items {
+			entries = append(entries, newCloudResourceEntry(&certList.Items[i]))
+		}
+
+		continueToken = certList.Continue
+		if h.APIReader == nil || continueToken == "" {
+			break
+		}
+	}
+
+	respond(c, http.StatusOK, entries)
+}
+
+// ProviderHealth summarizes the uploads to a provider across all Certificates
+type ProviderHealth struct {
+	Provider string `json:"provider" example:"aws"`
+	// Certificates is the number of Certificates that upload to the provider
+	Certificates int `json:"certificates" example:"12"`
+	// FailingCertificates is the number of Certificates whose latest upload to the provider failed
+	FailingCertificates int `json:"failingCertificates" example:"1"`
+	// RecentFailures is the number of uploads that failed in a row, summed over the Certificates
+	RecentFailures int32 `json:"recentFailures" example:"3"`
+	// OpenCircuitBreakers is the number of Certificates whose uploads to the provider are paused
+	OpenCircuitBreakers int `json:"openCircuitBreakers" example:"0"`
+	// LastSuccessTime is the latest successful upload to the provider, empty until there is one
+	LastSuccessTime string `json:"lastSuccessTime,omitempty" example:"2025-10-03T00:00:00Z"`
+	// LastError is the error of the failed upload whose circuit breaker opened last, or of
+	// the first failing Certificate when no breaker is open
+	LastError string `json:"lastError,omitempty" example:"ThrottlingException: Rate exceeded"`
+	// LastErrorCertificate is the namespace/name of the Certificate LastError belongs to
+	LastErrorCertificate string `json:"lastErrorCertificate,omitempty" example:"default/example-cert"`
+}
+
+// providerHealthAggregate accumulates the ProviderHealth of a provider
+type providerHealthAggregate struct {
+	ProviderHealth
+	lastSuccess   *metav1.Time
+	lastErrorOpen *metav1.Time
+}
+
+// add counts the upload status and circuit breaker of a Certificate, either may be nil
+func (a *providerHealthAggregate) add(
+	cert *certificatev1alpha1.Certificate,
+	status *certificatev1alpha1.ProviderStatus,
+	breaker *certificatev1alpha1.ProviderCircuitBreaker,
+) {
+	a.Certificates++
+
+	lastError := ""
+	if status != nil {
+		lastError = status.LastError
+		if status.LastUploadedTime != nil && (a.lastSuccess == nil || a.lastSuccess.Before(status.LastUploadedTime)) {
+			a.lastSuccess = status.LastUploadedTime
+		}
+	}
+	var openedAt *metav1.Time
+	if breaker != nil {
+		a.RecentFailures += breaker.ConsecutiveFailures
+		if breaker.State == certificatev1alpha1.CircuitBreakerOpen {
+			a.OpenCircuitBreakers++
+			openedAt = breaker.OpenedAt
+		}
+		if lastError == "" {
+			lastError = breaker.LastError
+		}
+	}
+	if lastError == "" {
+		return
+	}
+
+	a.FailingCertificates++
+	if a.LastError == "" || (openedAt != nil && (a.lastErrorOpen == nil || a.lastErrorOpen.Before(openedAt))) {
+		a.LastError = lastError
+		a.LastErrorCertificate = cert.Namespace + "/" + cert.Name
+		a.lastErrorOpen = openedAt
+	}
+}
+
+// providerHealth aggregates the upload status and circuit breakers of certs by provider,
+// sorted by provider name
+func providerHealth(certs []certificatev1alpha1.Certificate) []ProviderHealth {
+	aggregates := map[string]*providerHealthAggregate{}
+	aggregate := func(provider string) *providerHealthAggregate {
+		if aggregates[provider] == nil {
+			aggregates[provider] = &providerHealthAggregate{ProviderHealth: ProviderHealth{Provider: provider}}
+		}
+		return aggregates[provider]
+	}
+
+	for i := range certs {
+		cert := &certs[i]
+		breakers := map[string]*certificatev1alpha1.ProviderCircuitBreaker{}
+		for j := range cert.Status.ProviderCircuitBreakers {
+			breakers[cert.Status.ProviderCircuitBreakers[j].Provider] = &cert.Status.ProviderCircuitBreakers[j]
+		}
+		for provider, status := range cert.Status.Providers {
+			aggregate(provider).add(cert, &status, breakers[provider])
+			delete(breakers, provider)
+		}
+		// A provider whose first upload keeps failing has a breaker but no upload status yet
+		for provider, breaker := range breakers {
+			aggregate(provider).add(cert, nil, breaker)
+		}
+	}
+
+	health := make([]ProviderHealth, 0, len(aggregates))
+	for _, a := range aggregates {
+		a.LastSuccessTime = formatTime(a.lastSuccess)
+		health = append(health, a.ProviderHealth)
+	}
+	slices.SortFunc(health, func(a, b ProviderHealth) int {
+		return strings.Compare(a.Provider, b.Provider)
+	})
+	return health
+}
+
+// GetProviderHealth godoc
+// @Summary Get the health of every provider
+// @Description Aggregate the upload status and circuit breakers of every Certificate across all namespaces by provider: the latest successful upload, the failing Certificates and recent failures, and the latest error. Requires the admin bearer token.
+// @Tags admin
+// @Produce json,yaml
+// @Security BearerAuth
+// @Success 200 {array} ProviderHealth
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/providers/health [get]
+func (h *CertificateHandler) GetProviderHealth(c *gin.Context) {
+	var certs []certificatev1alpha1.Certificate
+	continueToken := ""
+	for {
+		certList := &certificatev1alpha1.CertificateList{}
+		if err := h.listPage(c, certList, continueToken); err != nil {
+			respondKubernetesError(c, err)
+			return
+		}
+		certs = append(certs, certList.Items...)
+
+		continueToken = certList.Continue
+		if h.APIReader == nil || continueToken == "" {
+			break
+		}
+	}
+
+	respond(c, http.StatusOK, providerHealth(certs))
+}
+
+// BearerTokenAuth returns a middleware that rejects requests whose Authorization header
+// doesn't carry token. Accepted requests are audited as audit.AdminTokenPrincipal.
+func BearerTokenAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized,
+				newErrorResponse(ErrorCodeUnauthorized, "a valid bearer token is required"))
+			return
+		}
+		audit.SetPrincipal(c, audit.AdminTokenPrincipal)
+		c.Next()
+	}
+}
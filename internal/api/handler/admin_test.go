@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+var _ = Describe("Cloud resources admin endpoint", func() {
+	const path = "/api/v1/admin/cloud-resources"
+
+	var (
+		engine *gin.Engine
+		reader *pagingReader
+	)
+
+	BeforeEach(func() {
+		uploaded := newTestCertificate("default", "uploaded", nil)
+		uploaded.Status = certificatev1alpha1.CertificateStatus{
+			AWSUploaded:       true,
+			AWSCertificateARN: "arn:aws:acm:us-east-1:111111111111:certificate/abc",
+			AWSAccountCertificateARNs: map[string]string{
+				"222222222222": "arn:aws:acm:us-east-1:222222222222:certificate/def",
+			},
+			CloudflareUploaded:      true,
+			CloudflareCertificateID: "cf-123",
+			S3Uploaded:              true,
+			S3ObjectKeys:            []string{"certs/uploaded.pem"},
+			LastUploadedTime:        &metav1.Time{Time: time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)},
+			RemoteClusters: []certificatev1alpha1.RemoteClusterStatus{
+				{Name: "edge", Synced: true, LastSyncedTime: &metav1.Time{Time: time.Date(2025, 10, 3, 12, 1, 0, 0, time.UTC)}},
+			},
+		}
+
+		k8sClient := newFakeClient(
+			uploaded,
+			newTestCertificate("team", "pending", nil),
+			newTestCertificate("default", "other", nil),
+		)
+		reader = &pagingReader{Reader: k8sClient, pageSize: 2}
+		h := NewCertificateHandler(k8sClient, reader, 0)
+		engine = gin.New()
+		engine.GET(path, BearerTokenAuth("s3cr3t"), h.ListCloudResources)
+	})
+
+	It("should list the provider resources of every Certificate across namespaces", func() {
+		recorder := performRequest(engine, http.MethodGet, path, nil, "Authorization", "Bearer s3cr3t")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(reader.pages).To(Equal(2))
+
+		var entries []CloudResourceEntry
+		decodeJSON(recorder, &entries)
+		Expect(entries).To(ConsistOf(
+			CloudResourceEntry{
+				Namespace:         "default",
+				Name:              "uploaded",
+				Domain:            "uploaded.example.com",
+				AWSCertificateARN: "arn:aws:acm:us-east-1:111111111111:certificate/abc",
+				AWSAccountCertificateARNs: map[string]string{
+					"222222222222": "arn:aws:acm:us-east-1:222222222222:certificate/def",
+				},
+				CloudflareCertificateID: "cf-123",
+				S3ObjectKeys:            []string{"certs/uploaded.pem"},
+				LastUploadedTime:        "2025-10-03T12:00:00Z",
+				RemoteClusters: []RemoteClusterResource{
+					{Name: "edge", LastSyncedTime: "2025-10-03T12:01:00Z"},
+				},
+			},
+			CloudResourceEntry{Namespace: "team", Name: "pending", Domain: "pending.example.com"},
+			CloudResourceEntry{Namespace: "default", Name: "other", Domain: "other.example.com"},
+		))
+	})
+
+	It("should reject requests without a valid bearer token", func() {
+		for _, headers := range [][]string{nil, {"Authorization", "Bearer wrong"}, {"Authorization", "s3cr3t"}} {
+			recorder := performRequest(engine, http.MethodGet, path, nil, headers...)
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Header().Get("WWW-Authenticate")).To(HavePrefix("Bearer"))
+
+			var response ErrorResponse
+			decodeJSON(recorder, &response)
+			Expect(response.Code).To(Equal(ErrorCodeUnauthorized))
+		}
+		Expect(reader.pages).To(BeZero())
+	})
+
+	It("should return the upstream error when listing a page fails", func() {
+		reader.failFrom = 2
+		recorder := performRequest(engine, http.MethodGet, path, nil, "Authorization", "Bearer s3cr3t")
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+
+		var response ErrorResponse
+		decodeJSON(recorder, &response)
+		Expect(response.Code).To(Equal(ErrorCodeUpstreamError))
+	})
+})
+
+var _ = Describe("Provider health admin endpoint", func() {
+	const path = "/api/v1/admin/providers/health"
+
+	var (
+		engine *gin.Engine
+		reader *pagingReader
+	)
+
+	at := func(hour int) *metav1.Time {
+		return &metav1.Time{Time: time.Date(2025, 10, 3, hour, 0, 0, 0, time.UTC)}
+	}
+
+	BeforeEach(func() {
+		healthy := newTestCertificate("default", "healthy", nil)
+		healthy.Status.Providers = map[string]certificatev1alpha1.ProviderStatus{
+			"aws":        {Uploaded: true, LastUploadedTime: at(10)},
+			"cloudflare": {Uploaded: true, LastUploadedTime: at(11)},
+		}
+
+		throttled := newTestCertificate("default", "throttled", nil)
+		throttled.Status.Providers = map[string]certificatev1alpha1.ProviderStatus{
+			"aws":        {LastUploadedTime: at(12), LastError: "ThrottlingException: Rate exceeded"},
+			"cloudflare": {Uploaded: true, LastUploadedTime: at(9)},
+		}
+		throttled.Status.ProviderCircuitBreakers = []certificatev1alpha1.ProviderCircuitBreaker{{
+			Provider:            "aws",
+			State:               certificatev1alpha1.CircuitBreakerClosed,
+			ConsecutiveFailures: 2,
+			LastError:           "ThrottlingException: Rate exceeded",
+		}}
+
+		denied := newTestCertificate("team", "denied", nil)
+		denied.Status.ProviderCircuitBreakers = []certificatev1alpha1.ProviderCircuitBreaker{{
+			Provider:            "aws",
+			State:               certificatev1alpha1.CircuitBreakerOpen,
+			ConsecutiveFailures: 5,
+			OpenedAt:            at(8),
+			LastError:           "AccessDeniedException: not authorized",
+		}}
+
+		k8sClient := newFakeClient(healthy, throttled, denied)
+		reader = &pagingReader{Reader: k8sClient, pageSize: 2}
+		h := NewCertificateHandler(k8sClient, reader, 0)
+		engine = gin.New()
+		engine.GET(path, BearerTokenAuth("s3cr3t"), h.GetProviderHealth)
+	})
+
+	It("should aggregate the provider status of every Certificate across namespaces", func() {
+		recorder := performRequest(engine, http.MethodGet, path, nil, "Authorization", "Bearer s3cr3t")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(reader.pages).To(Equal(2))
+
+		var health []ProviderHealth
+		decodeJSON(recorder, &health)
+		Expect(health).To(Equal([]ProviderHealth{
+			{
+				Provider:             "aws",
+				Certificates:         3,
+				FailingCertificates:  2,
+				RecentFailures:       7,
+				OpenCircuitBreakers:  1,
+				LastSuccessTime:      "2025-10-03T12:00:00Z",
+				LastError:            "AccessDeniedException: not authorized",
+				LastErrorCertificate: "team/denied",
+			},
+			{
+				Provider:        "cloudflare",
+				Certificates:    2,
+				LastSuccessTime: "2025-10-03T11:00:00Z",
+			},
+		}))
+	})
+
+	It("should report the first failing Certificate's error when no breaker is open", func() {
+		k8sClient := newFakeClient(
+			&certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"},
+				Status: certificatev1alpha1.CertificateStatus{Providers: map[string]certificatev1alpha1.ProviderStatus{
+					"s3": {LastError: "NoSuchBucket"},
+				}},
+			},
+			&certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"},
+				Status: certificatev1alpha1.CertificateStatus{Providers: map[string]certificatev1alpha1.ProviderStatus{
+					"s3": {LastError: "AccessDenied"},
+				}},
+			},
+		)
+		engine = gin.New()
+		engine.GET(path, BearerTokenAuth("s3cr3t"), NewCertificateHandler(k8sClient, nil, 0).GetProviderHealth)
+
+		recorder := performRequest(engine, http.MethodGet, path, nil, "Authorization", "Bearer s3cr3t")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var health []ProviderHealth
+		decodeJSON(recorder, &health)
+		Expect(health).To(Equal([]ProviderHealth{{
+			Provider:             "s3",
+			Certificates:         2,
+			FailingCertificates:  2,
+			LastError:            "NoSuchBucket",
+			LastErrorCertificate: "default/a",
+		}}))
+	})
+
+	It("should reject requests without a valid bearer token", func() {
+		recorder := performRequest(engine, http.MethodGet, path, nil, "Authorization", "Bearer wrong")
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		Expect(reader.pages).To(BeZero())
+	})
+
+	It("should return the upstream error when listing a page fails", func() {
+		reader.failFrom = 2
+		recorder := performRequest(engine, http.MethodGet, path, nil, "Authorization", "Bearer s3cr3t")
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+
+		var response ErrorResponse
+		decodeJSON(recorder, &response)
+		Expect(response.Code).To(Equal(ErrorCodeUpstreamError))
+	})
+})
@@ -2,11 +2,19 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	"github.com/gin-gonic/gin"
 	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -14,12 +22,36 @@ import (
 // CertificateHandler handles HTTP requests for Certificate resources
 type CertificateHandler struct {
 	Client client.Client
+
+	// APIReader reads directly from the API server. When set, streamed lists are
+	// paginated instead of read from the cache in one piece.
+	APIReader client.Reader
+
+	// Watcher streams Certificate changes for WatchCertificates, which is not supported
+	// when it is nil
+	Watcher client.WithWatch
+
+	// Planner plans spec changes for DiffCertificate, which is not supported when it is nil
+	Planner SpecChangePlanner
+
+	// CertificateNameSuffix and SecretNameSuffix are the suffixes the operator appends to the
+	// name of a Certificate to name its cert-manager Certificate and TLS Secret
+	CertificateNameSuffix string
+	SecretNameSuffix      string
+
+	// listCache caches list responses, nil when disabled
+	listCache *listCache
 }
 
-// NewCertificateHandler creates a new CertificateHandler
-func NewCertificateHandler(k8sClient client.Client) *CertificateHandler {
+// NewCertificateHandler creates a new CertificateHandler.
+// List responses are cached for listCacheTTL, 0 disables the cache.
+func NewCertificateHandler(k8sClient client.Client, apiReader client.Reader, listCacheTTL time.Duration) *CertificateHandler {
 	return &CertificateHandler{
-		Client: k8sClient,
+		Client:                k8sClient,
+		APIReader:             apiReader,
+		CertificateNameSuffix: certificatev1alpha1.DefaultCertificateNameSuffix,
+		SecretNameSuffix:      certificatev1alpha1.DefaultSecretNameSuffix,
+		listCache:             newListCache(listCacheTTL),
 	}
 }
 
@@ -53,14 +85,47 @@ type CertificateStatusResponse struct {
 	CertificateRef     string `json:"certificateRef,omitempty"`
 	CloudflareUploaded bool   `json:"cloudflareUploaded"`
 	AWSUploaded        bool   `json:"awsUploaded"`
+	S3Uploaded         bool   `json:"s3Uploaded"`
 	LastUploadedTime   string `json:"lastUploadedTime,omitempty"`
+
+	// Ready and IssuanceMessage reflect the Ready condition of the cert-manager Certificate.
+	// They are only returned when getting a single Certificate.
+	Ready           *bool  `json:"ready,omitempty"`
+	IssuanceMessage string `json:"issuanceMessage,omitempty" example:"Certificate is up to date and has not expired"`
+}
+
+// BatchDeleteResult represents the outcome of deleting a single Certificate
+type BatchDeleteResult struct {
+	Name      string `json:"name" example:"example-cert"`
+	Namespace string `json:"namespace" example:"default"`
+	Deleted   bool   `json:"deleted"`
+	Error     string `json:"error,omitempty"`
 }
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error string `json:"error" example:"resource not found"`
+// BatchDeleteResponse represents the response of a batch delete
+type BatchDeleteResponse struct {
+	DryRun  bool                `json:"dryRun"`
+	Results []BatchDeleteResult `json:"results"`
 }
 
+// resetUploadStatusAction is the custom method that clears the upload status of a Certificate
+const resetUploadStatusAction = "resetUploadStatus"
+
+const (
+	// idempotencyKeyHeader carries the client-chosen key that makes retried creates safe
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotentReplayedHeader is set on the response to a create that returned the
+	// Certificate created by an earlier request with the same idempotency key
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	// idempotencyKeyAnnotation records the idempotency key of the create request on the Certificate
+	idempotencyKeyAnnotation = "certificate.println.kr/idempotency-key"
+
+	// maxIdempotencyKeyLength bounds the idempotency key stored in the annotation
+	maxIdempotencyKeyLength = 255
+)
+
 // convertToResponse converts a Certificate to CertificateResponse
 func convertToResponse(cert *certificatev1alpha1.Certificate) CertificateResponse {
 	var lastUploadedTime string
@@ -78,6 +143,7 @@ func convertToResponse(cert *certificatev1alpha1.Certificate) CertificateRespons
 			CertificateRef:     cert.Status.CertificateRef,
 			CloudflareUploaded: cert.Status.CloudflareUploaded,
 			AWSUploaded:        cert.Status.AWSUploaded,
+			S3Uploaded:         cert.Status.S3Uploaded,
 			LastUploadedTime:   lastUploadedTime,
 		},
 	}
@@ -90,14 +156,25 @@ func convertToResponse(cert *certificatev1alpha1.Certificate) CertificateRespons
 // @Accept json
 // @Produce json
 // @Param certificate body CreateCertificateRequest true "Certificate to create"
+// @Param Idempotency-Key header string false "Returns the Certificate created by an earlier request with the same key instead of a conflict"
 // @Success 201 {object} CertificateResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/certificates [post]
 func (h *CertificateHandler) CreateCertificate(c *gin.Context) {
 	var req CreateCertificateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidSpec, err.Error()))
+		return
+	}
+
+	idempotencyKey := c.GetHeader(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest,
+			fmt.Sprintf("%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength)))
 		return
 	}
 
@@ -108,53 +185,162 @@ func (h *CertificateHandler) CreateCertificate(c *gin.Context) {
 		},
 		Spec: req.Spec,
 	}
+	if idempotencyKey != "" {
+		cert.Annotations = map[string]string{idempotencyKeyAnnotation: idempotencyKey}
+	}
 
-	if err := h.Client.Create(context.Background(), cert); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.Client.Create(c.Request.Context(), cert); err != nil {
+		// A retry of a create that succeeded returns the Certificate it created
+		if idempotencyKey != "" && apierrors.IsAlreadyExists(err) {
+			existing, getErr := h.getCreatedCertificate(c.Request.Context(), cert)
+			if getErr == nil && existing.Annotations[idempotencyKeyAnnotation] == idempotencyKey {
+				c.Header(idempotentReplayedHeader, "true")
+				c.JSON(http.StatusCreated, convertToResponse(existing))
+				return
+			}
+		}
+		c.JSON(kubernetesErrorResponse(err))
 		return
 	}
+	h.listCache.invalidate()
 
 	c.JSON(http.StatusCreated, convertToResponse(cert))
 }
 
+// getCreatedCertificate reads the Certificate with the name of cert from the API server
+// when an APIReader is configured, the cache may not have seen a Certificate created moments ago
+func (h *CertificateHandler) getCreatedCertificate(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+) (*certificatev1alpha1.Certificate, error) {
+	var reader client.Reader = h.Client
+	if h.APIReader != nil {
+		reader = h.APIReader
+	}
+	existing := &certificatev1alpha1.Certificate{}
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(cert), existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
 // ListCertificates godoc
 // @Summary List all Certificates
-// @Description Get a list of all Certificate resources across all namespaces
+// @Description Get a list of all Certificate resources across all namespaces. With Accept: application/x-ndjson the list is streamed as one Certificate per line.
 // @Tags certificates
-// @Produce json
+// @Produce json,yaml,x-ndjson
 // @Success 200 {array} CertificateResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/certificates [get]
 func (h *CertificateHandler) ListCertificates(c *gin.Context) {
+	if wantsNDJSON(c) {
+		h.streamCertificates(c)
+		return
+	}
+	h.listCertificates(c, "")
+}
+
+// DeleteCertificates godoc
+// @Summary Delete Certificates by label selector
+// @Description Delete all Certificate resources across all namespaces matching a label selector
+// @Tags certificates
+// @Produce json
+// @Param labelSelector query string true "Label selector (e.g. env=test)"
+// @Param dryRun query bool false "Return the matching Certificates without deleting them"
+// @Success 200 {object} BatchDeleteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/certificates [delete]
+func (h *CertificateHandler) DeleteCertificates(c *gin.Context) {
+	// Require a selector to avoid accidentally deleting every Certificate
+	selectorParam := c.Query("labelSelector")
+	if selectorParam == "" {
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest, "labelSelector query parameter is required"))
+		return
+	}
+
+	selector, err := labels.Parse(selectorParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest, err.Error()))
+		return
+	}
+	if selector.Empty() {
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest, "labelSelector must not be empty"))
+		return
+	}
+
+	dryRun := false
+	if dryRunParam := c.Query("dryRun"); dryRunParam != "" {
+		dryRun, err = strconv.ParseBool(dryRunParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest, "dryRun must be a boolean"))
+			return
+		}
+	}
+
 	certList := &certificatev1alpha1.CertificateList{}
-	if err := h.Client.List(context.Background(), certList); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.Client.List(c.Request.Context(), certList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		c.JSON(kubernetesErrorResponse(err))
 		return
 	}
 
-	responses := make([]CertificateResponse, 0, len(certList.Items))
-	for _, cert := range certList.Items {
-		responses = append(responses, convertToResponse(&cert))
+	response := BatchDeleteResponse{
+		DryRun:  dryRun,
+		Results: make([]BatchDeleteResult, 0, len(certList.Items)),
+	}
+	for i := range certList.Items {
+		cert := &certList.Items[i]
+		result := BatchDeleteResult{
+			Name:      cert.Name,
+			Namespace: cert.Namespace,
+		}
+
+		if !dryRun {
+			if err := h.Client.Delete(c.Request.Context(), cert); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Deleted = true
+			}
+		}
+		response.Results = append(response.Results, result)
+	}
+	if !dryRun {
+		h.listCache.invalidate()
 	}
 
-	c.JSON(http.StatusOK, responses)
+	c.JSON(http.StatusOK, response)
 }
 
 // ListCertificatesInNamespace godoc
 // @Summary List Certificates in a namespace
-// @Description Get a list of Certificate resources in a specific namespace
+// @Description Get a list of Certificate resources in a specific namespace. With Accept: application/x-ndjson the list is streamed as one Certificate per line.
 // @Tags certificates
-// @Produce json
+// @Produce json,yaml,x-ndjson
 // @Param namespace path string true "Namespace"
 // @Success 200 {array} CertificateResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/namespaces/{namespace}/certificates [get]
 func (h *CertificateHandler) ListCertificatesInNamespace(c *gin.Context) {
 	namespace := c.Param("namespace")
+	if wantsNDJSON(c) {
+		h.streamCertificates(c, client.InNamespace(namespace))
+		return
+	}
+	h.listCertificates(c, namespace)
+}
+
+// listCertificates responds with the Certificates in namespace, or in all namespaces when
+// empty, from the list cache when it holds a fresh response
+func (h *CertificateHandler) listCertificates(c *gin.Context, namespace string) {
+	key := listCacheKey(namespace)
+	if responses, ok := h.listCache.get(key); ok {
+		respond(c, http.StatusOK, responses)
+		return
+	}
 
 	certList := &certificatev1alpha1.CertificateList{}
-	if err := h.Client.List(context.Background(), certList, client.InNamespace(namespace)); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.Client.List(c.Request.Context(), certList, client.InNamespace(namespace)); err != nil {
+		respondKubernetesError(c, err)
 		return
 	}
 
@@ -162,15 +348,16 @@ func (h *CertificateHandler) ListCertificatesInNamespace(c *gin.Context) {
 	for _, cert := range certList.Items {
 		responses = append(responses, convertToResponse(&cert))
 	}
+	h.listCache.set(key, responses)
 
-	c.JSON(http.StatusOK, responses)
+	respond(c, http.StatusOK, responses)
 }
 
 // GetCertificate godoc
 // @Summary Get a Certificate
 // @Description Get a specific Certificate resource by name and namespace
 // @Tags certificates
-// @Produce json
+// @Produce json,yaml
 // @Param namespace path string true "Namespace"
 // @Param name path string true "Certificate name"
 // @Success 200 {object} CertificateResponse
@@ -182,15 +369,77 @@ func (h *CertificateHandler) GetCertificate(c *gin.Context) {
 	name := c.Param("name")
 
 	cert := &certificatev1alpha1.Certificate{}
-	if err := h.Client.Get(context.Background(), types.NamespacedName{
+	if err := h.Client.Get(c.Request.Context(), types.NamespacedName{
 		Namespace: namespace,
 		Name:      name,
 	}, cert); err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		respondKubernetesError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, convertToResponse(cert))
+	response := convertToResponse(cert)
+	ready, message, err := h.issuanceStatus(c.Request.Context(), cert)
+	if err != nil {
+		respondKubernetesError(c, err)
+		return
+	}
+	response.Status.Ready = &ready
+	response.Status.IssuanceMessage = message
+
+	respond(c, http.StatusOK, response)
+}
+
+// issuanceStatus reports whether the cert-manager Certificate of cert is ready and the
+// message of its Ready condition. A cert-manager Certificate that doesn't exist yet is
+// reported as not ready.
+func (h *CertificateHandler) issuanceStatus(ctx context.Context, cert *certificatev1alpha1.Certificate) (bool, string, error) {
+	name := cert.Status.CertificateRef
+	if name == "" {
+		// Not reconciled yet, the operator names it after the Certificate
+		name = cert.Name + h.CertificateNameSuffix
+	}
+
+	cmCert := &certmanagerv1.Certificate{}
+	err := h.Client.Get(ctx, types.NamespacedName{Namespace: cert.Namespace, Name: name}, cmCert)
+	if apierrors.IsNotFound(err) {
+		return false, fmt.Sprintf("cert-manager Certificate %s doesn't exist yet", name), nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+
+	for _, cond := range cmCert.Status.Conditions {
+		if cond.Type == certmanagerv1.CertificateConditionReady {
+			return cond.Status == cmmeta.ConditionTrue, cond.Message, nil
+		}
+	}
+	return false, fmt.Sprintf("cert-manager Certificate %s has no Ready condition yet", name), nil
+}
+
+// GetEffectiveSpec godoc
+// @Summary Get the effective spec of a Certificate
+// @Description Get the spec of a Certificate with the defaults the operator applies at runtime resolved, e.g. the ClusterIssuer, bundle types, and provider enabled flags
+// @Tags certificates
+// @Produce json,yaml
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Certificate name"
+// @Success 200 {object} certificatev1alpha1.CertificateSpec
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/namespaces/{namespace}/certificates/{name}/effective-spec [get]
+func (h *CertificateHandler) GetEffectiveSpec(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	cert := &certificatev1alpha1.Certificate{}
+	if err := h.Client.Get(c.Request.Context(), types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}, cert); err != nil {
+		respondKubernetesError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, cert.EffectiveSpecWithSecretNameSuffix(h.SecretNameSuffix))
 }
 
 // UpdateCertificate godoc
@@ -204,7 +453,10 @@ func (h *CertificateHandler) GetCertificate(c *gin.Context) {
 // @Param certificate body UpdateCertificateRequest true "Certificate updates"
 // @Success 200 {object} CertificateResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/namespaces/{namespace}/certificates/{name} [put]
 func (h *CertificateHandler) UpdateCertificate(c *gin.Context) {
@@ -213,25 +465,26 @@ func (h *CertificateHandler) UpdateCertificate(c *gin.Context) {
 
 	var req UpdateCertificateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidSpec, err.Error()))
 		return
 	}
 
 	cert := &certificatev1alpha1.Certificate{}
-	if err := h.Client.Get(context.Background(), types.NamespacedName{
+	if err := h.Client.Get(c.Request.Context(), types.NamespacedName{
 		Namespace: namespace,
 		Name:      name,
 	}, cert); err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		c.JSON(kubernetesErrorResponse(err))
 		return
 	}
 
 	// Update spec with the provided spec
 	cert.Spec = req.Spec
-	if err := h.Client.Update(context.Background(), cert); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.Client.Update(c.Request.Context(), cert); err != nil {
+		c.JSON(kubernetesErrorResponse(err))
 		return
 	}
+	h.listCache.invalidate()
 
 	c.JSON(http.StatusOK, convertToResponse(cert))
 }
@@ -252,18 +505,88 @@ func (h *CertificateHandler) DeleteCertificate(c *gin.Context) {
 	name := c.Param("name")
 
 	cert := &certificatev1alpha1.Certificate{}
-	if err := h.Client.Get(context.Background(), types.NamespacedName{
+	if err := h.Client.Get(c.Request.Context(), types.NamespacedName{
 		Namespace: namespace,
 		Name:      name,
 	}, cert); err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		c.JSON(kubernetesErrorResponse(err))
 		return
 	}
 
-	if err := h.Client.Delete(context.Background(), cert); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.Client.Delete(c.Request.Context(), cert); err != nil {
+		c.JSON(kubernetesErrorResponse(err))
 		return
 	}
+	h.listCache.invalidate()
 
 	c.Status(http.StatusNoContent)
 }
+
+// CertificateAction dispatches custom methods addressed as "{name}:{action}".
+// Kubernetes names cannot contain a colon, so the first colon separates the action.
+func (h *CertificateHandler) CertificateAction(c *gin.Context) {
+	name, action, _ := strings.Cut(c.Param("name"), ":")
+
+	switch action {
+	case resetUploadStatusAction:
+		h.ResetUploadStatus(c, c.Param("namespace"), name)
+	case diffAction:
+		h.DiffCertificate(c, c.Param("namespace"), name)
+	default:
+		c.JSON(http.StatusNotFound, newErrorResponse(ErrorCodeNotFound, fmt.Sprintf("unknown certificate action %q", action)))
+	}
+}
+
+// CollectionAction dispatches custom methods on collections addressed as
+// "{collection}:{action}", e.g. POST /api/v1/certificates:sync
+func (h *CertificateHandler) CollectionAction(c *gin.Context) {
+	collection, action, _ := strings.Cut(c.Param("collection"), ":")
+
+	switch {
+	case collection == "certificates" && action == syncAction:
+		h.SyncCertificates(c)
+	default:
+		c.JSON(http.StatusNotFound, newErrorResponse(ErrorCodeNotFound,
+			fmt.Sprintf("unknown action %q on %q", action, collection)))
+	}
+}
+
+// ResetUploadStatus godoc
+// @Summary Reset the upload status of a Certificate
+// @Description Clear the Cloudflare and AWS upload flags, their identifiers, and the last uploaded hash so the next reconcile uploads the certificate again
+// @Tags certificates
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Certificate name"
+// @Success 200 {object} certificatev1alpha1.CertificateStatus
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/namespaces/{namespace}/certificates/{name}:resetUploadStatus [post]
+func (h *CertificateHandler) ResetUploadStatus(c *gin.Context, namespace, name string) {
+	cert := &certificatev1alpha1.Certificate{}
+	if err := h.Client.Get(c.Request.Context(), types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}, cert); err != nil {
+		c.JSON(kubernetesErrorResponse(err))
+		return
+	}
+
+	cert.Status.CloudflareUploaded = false
+	cert.Status.CloudflareCertificateID = ""
+	cert.Status.AWSUploaded = false
+	cert.Status.AWSCertificateARN = ""
+	cert.Status.S3Uploaded = false
+	cert.Status.LastUploadedCertHash = ""
+	cert.Status.LastUploadedChainFingerprint = ""
+	cert.Status.LastUploadedSpecHash = ""
+	cert.Status.Providers = nil
+	cert.Status.ProviderCircuitBreakers = nil
+	if err := h.Client.Status().Update(c.Request.Context(), cert); err != nil {
+		c.JSON(kubernetesErrorResponse(err))
+		return
+	}
+	h.listCache.invalidate()
+
+	c.JSON(http.StatusOK, cert.Status)
+}
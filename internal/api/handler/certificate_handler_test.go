@@ -0,0 +1,630 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/yaml"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// newTestCertificate returns a Certificate with the given labels.
+func newTestCertificate(namespace, name string, certLabels map[string]string) *certificatev1alpha1.Certificate {
+	return &certificatev1alpha1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    certLabels,
+		},
+		Spec: certificatev1alpha1.CertificateSpec{
+			Domain: name + ".example.com",
+		},
+	}
+}
+
+// pagingReader serves Certificate lists in pages of pageSize, using the offset of the next
+// page as the continue token. When failFrom is set, listing that page and every later one fails.
+type pagingReader struct {
+	client.Reader
+	pageSize int
+	failFrom int
+	pages    int
+}
+
+func (r *pagingReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	r.pages++
+	if r.failFrom > 0 && r.pages >= r.failFrom {
+		return errors.New("etcdserver: request timed out")
+	}
+
+	listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+	Expect(listOpts.Limit).To(BeNumerically(">", 0))
+
+	if err := r.Reader.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	certList := list.(*certificatev1alpha1.CertificateList)
+	offset := 0
+	if listOpts.Continue != "" {
+		offset, _ = strconv.Atoi(listOpts.Continue)
+	}
+	all := certList.Items
+	end := min(offset+r.pageSize, len(all))
+	certList.Items = all[offset:end]
+	certList.Continue = ""
+	if end < len(all) {
+		certList.Continue = strconv.Itoa(end)
+	}
+	return nil
+}
+
+// decodeNDJSON decodes every line of a newline-delimited JSON response
+func decodeNDJSON[T any](body []byte) []T {
+	var items []T
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		var item T
+		Expect(json.Unmarshal(scanner.Bytes(), &item)).To(Succeed(), scanner.Text())
+		items = append(items, item)
+	}
+	return items
+}
+
+var _ = Describe("CertificateHandler", func() {
+	var (
+		k8sClient client.Client
+		h         *CertificateHandler
+		engine    *gin.Engine
+	)
+
+	BeforeEach(func() {
+		k8sClient = newFakeClient(
+			newTestCertificate("default", "test-a", map[string]string{"env": "test"}),
+			newTestCertificate("team", "test-b", map[string]string{"env": "test"}),
+			newTestCertificate("default", "prod", map[string]string{"env": "prod"}),
+		)
+
+		h = NewCertificateHandler(k8sClient, nil, 0)
+		engine = gin.New()
+		engine.GET("/api/v1/certificates", h.ListCertificates)
+		engine.POST("/api/v1/certificates", h.CreateCertificate)
+		engine.DELETE("/api/v1/certificates", h.DeleteCertificates)
+		engine.GET("/api/v1/namespaces/:namespace/certificates", h.ListCertificatesInNamespace)
+		engine.GET("/api/v1/namespaces/:namespace/certificates/:name", h.GetCertificate)
+		engine.GET("/api/v1/namespaces/:namespace/certificates/:name/effective-spec", h.GetEffectiveSpec)
+		engine.POST("/api/v1/namespaces/:namespace/certificates/:name", h.CertificateAction)
+	})
+
+	Context("When negotiating the response format", func() {
+		It("should return JSON by default", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/prod", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+
+			var response CertificateResponse
+			decodeJSON(recorder, &response)
+			Expect(response.Name).To(Equal("prod"))
+		})
+
+		It("should return YAML for a single Certificate when requested", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/prod", nil,
+				"Accept", "application/yaml")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("application/yaml"))
+
+			var response CertificateResponse
+			Expect(yaml.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Name).To(Equal("prod"))
+			Expect(response.Spec.Domain).To(Equal("prod.example.com"))
+			Expect(recorder.Body.String()).To(ContainSubstring("domain: prod.example.com"))
+		})
+
+		It("should return YAML lists for the x-yaml media type", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates", nil,
+				"Accept", "application/x-yaml")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("application/yaml"))
+
+			var responses []CertificateResponse
+			Expect(yaml.Unmarshal(recorder.Body.Bytes(), &responses)).To(Succeed())
+			Expect(responses).To(HaveLen(2))
+		})
+
+		It("should prefer the first acceptable format listed by the client", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates", nil,
+				"Accept", "application/yaml, application/json")
+			Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("application/yaml"))
+
+			recorder = performRequest(engine, http.MethodGet, "/api/v1/certificates", nil,
+				"Accept", "text/html")
+			Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+		})
+
+		It("should return errors as YAML when requested", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/missing", nil,
+				"Accept", "application/yaml")
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+			Expect(recorder.Body.String()).To(ContainSubstring("code: NOT_FOUND\n"))
+			Expect(recorder.Body.String()).To(ContainSubstring("error: "))
+		})
+	})
+
+	Context("When creating with an idempotency key", func() {
+		create := func(name, key string) *httptest.ResponseRecorder {
+			var headers []string
+			if key != "" {
+				headers = []string{"Idempotency-Key", key}
+			}
+			return performRequest(engine, http.MethodPost, "/api/v1/certificates", CreateCertificateRequest{
+				Name:      name,
+				Namespace: "default",
+				Spec:      certificatev1alpha1.CertificateSpec{Domain: name + ".example.com"},
+			}, headers...)
+		}
+
+		It("should record the key and return the created Certificate on a retry with the same key", func() {
+			recorder := create("api", "key-1")
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			Expect(recorder.Header().Get("Idempotent-Replayed")).To(BeEmpty())
+
+			cert := &certificatev1alpha1.Certificate{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "api"}, cert)).To(Succeed())
+			Expect(cert.Annotations).To(HaveKeyWithValue(idempotencyKeyAnnotation, "key-1"))
+
+			recorder = create("api", "key-1")
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+			Expect(recorder.Header().Get("Idempotent-Replayed")).To(Equal("true"))
+			var response CertificateResponse
+			decodeJSON(recorder, &response)
+			Expect(response.Name).To(Equal("api"))
+			Expect(response.Spec.Domain).To(Equal("api.example.com"))
+
+			certList := &certificatev1alpha1.CertificateList{}
+			Expect(k8sClient.List(context.Background(), certList, client.InNamespace("default"))).To(Succeed())
+			Expect(certList.Items).To(HaveLen(3))
+		})
+
+		It("should return a conflict for a different key or no key", func() {
+			Expect(create("api", "key-1").Code).To(Equal(http.StatusCreated))
+
+			recorder := create("api", "key-2")
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+			Expect(recorder.Header().Get("Idempotent-Replayed")).To(BeEmpty())
+
+			Expect(create("api", "").Code).To(Equal(http.StatusConflict))
+
+			By("not replaying Certificates created without a key")
+			Expect(create("prod", "key-1").Code).To(Equal(http.StatusConflict))
+		})
+
+		It("should reject overly long keys", func() {
+			recorder := create("api", strings.Repeat("k", 256))
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "api"}, &certificatev1alpha1.Certificate{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When the API server rejects a create", func() {
+		var createErr error
+
+		create := func(name string) (int, ErrorResponse) {
+			k8sClient := fake.NewClientBuilder().
+				WithScheme(testScheme).
+				WithObjects(newTestCertificate("default", "prod", nil)).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if createErr != nil {
+							return createErr
+						}
+						return c.Create(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			engine := gin.New()
+			engine.POST("/api/v1/certificates", NewCertificateHandler(k8sClient, nil, 0).CreateCertificate)
+
+			recorder := performRequest(engine, http.MethodPost, "/api/v1/certificates", CreateCertificateRequest{
+				Name:      name,
+				Namespace: "default",
+				Spec:      certificatev1alpha1.CertificateSpec{Domain: name + ".example.com"},
+			})
+			var response ErrorResponse
+			decodeJSON(recorder, &response)
+			return recorder.Code, response
+		}
+
+		BeforeEach(func() {
+			createErr = nil
+		})
+
+		It("should return a conflict for a Certificate that already exists", func() {
+			status, response := create("prod")
+			Expect(status).To(Equal(http.StatusConflict))
+			Expect(response.Code).To(Equal(ErrorCodeAlreadyExists))
+			Expect(response.Error).To(ContainSubstring(`"prod" already exists`))
+		})
+
+		It("should return forbidden when the operator may not create the Certificate", func() {
+			createErr = apierrors.NewForbidden(certificatev1alpha1.GroupVersion.WithResource("certificates").GroupResource(),
+				"api", errors.New("namespace is restricted"))
+
+			status, response := create("api")
+			Expect(status).To(Equal(http.StatusForbidden))
+			Expect(response.Code).To(Equal(ErrorCodeForbidden))
+			Expect(response.Error).To(ContainSubstring("namespace is restricted"))
+		})
+
+		It("should return unprocessable entity for a Certificate the validation rejects", func() {
+			createErr = apierrors.NewInvalid(certificatev1alpha1.GroupVersion.WithKind("Certificate").GroupKind(), "api",
+				field.ErrorList{field.Required(field.NewPath("spec", "domain"), "")})
+
+			status, response := create("api")
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			Expect(response.Code).To(Equal(ErrorCodeInvalidSpec))
+			Expect(response.Details).To(ConsistOf(ErrorDetail{Field: "spec.domain", Message: "Required value"}))
+		})
+	})
+
+	Context("When batch deleting by label selector", func() {
+		It("should reject a request without a selector", func() {
+			recorder := performRequest(engine, http.MethodDelete, "/api/v1/certificates", nil)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reject an invalid selector", func() {
+			recorder := performRequest(engine, http.MethodDelete, "/api/v1/certificates?labelSelector=env%3D%3D%3D", nil)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should delete only the matching Certificates", func() {
+			recorder := performRequest(engine, http.MethodDelete, "/api/v1/certificates?labelSelector=env%3Dtest", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var response BatchDeleteResponse
+			decodeJSON(recorder, &response)
+			Expect(response.DryRun).To(BeFalse())
+			Expect(response.Results).To(ConsistOf(
+				BatchDeleteResult{Name: "test-a", Namespace: "default", Deleted: true},
+				BatchDeleteResult{Name: "test-b", Namespace: "team", Deleted: true},
+			))
+
+			err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "test-a"}, &certificatev1alpha1.Certificate{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "prod"}, &certificatev1alpha1.Certificate{})).To(Succeed())
+		})
+
+		It("should only report the matching Certificates on a dry run", func() {
+			recorder := performRequest(engine, http.MethodDelete, "/api/v1/certificates?labelSelector=env%3Dtest&dryRun=true", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var response BatchDeleteResponse
+			decodeJSON(recorder, &response)
+			Expect(response.DryRun).To(BeTrue())
+			Expect(response.Results).To(HaveLen(2))
+			for _, result := range response.Results {
+				Expect(result.Deleted).To(BeFalse())
+			}
+
+			certList := &certificatev1alpha1.CertificateList{}
+			Expect(k8sClient.List(context.Background(), certList)).To(Succeed())
+			Expect(certList.Items).To(HaveLen(3))
+		})
+	})
+
+	Context("When resetting the upload status", func() {
+		BeforeEach(func() {
+			cert := &certificatev1alpha1.Certificate{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "prod"}, cert)).To(Succeed())
+			cert.Status = certificatev1alpha1.CertificateStatus{
+				CertificateRef:          "prod-cert",
+				CloudflareUploaded:      true,
+				CloudflareCertificateID: "cf-id",
+				AWSUploaded:             true,
+				AWSCertificateARN:       "arn:aws:acm:us-east-1:123456789012:certificate/deleted",
+				LastUploadedCertHash:    "hash",
+				Providers: map[string]certificatev1alpha1.ProviderStatus{
+					"cloudflare": {Uploaded: true, Identifier: "cf-id"},
+					"aws":        {Uploaded: true, Identifier: "arn:aws:acm:us-east-1:123456789012:certificate/deleted"},
+				},
+				ProviderCircuitBreakers: []certificatev1alpha1.ProviderCircuitBreaker{{
+					Provider:            "aws",
+					State:               certificatev1alpha1.CircuitBreakerOpen,
+					ConsecutiveFailures: 5,
+				}},
+			}
+			Expect(k8sClient.Status().Update(context.Background(), cert)).To(Succeed())
+		})
+
+		It("should clear the upload fields and return the updated status", func() {
+			recorder := performRequest(engine, http.MethodPost, "/api/v1/namespaces/default/certificates/prod:resetUploadStatus", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var status certificatev1alpha1.CertificateStatus
+			decodeJSON(recorder, &status)
+			Expect(status).To(Equal(certificatev1alpha1.CertificateStatus{CertificateRef: "prod-cert"}))
+
+			cert := &certificatev1alpha1.Certificate{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "prod"}, cert)).To(Succeed())
+			Expect(cert.Status.CloudflareUploaded).To(BeFalse())
+			Expect(cert.Status.CloudflareCertificateID).To(BeEmpty())
+			Expect(cert.Status.AWSUploaded).To(BeFalse())
+			Expect(cert.Status.AWSCertificateARN).To(BeEmpty())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+			Expect(cert.Status.Providers).To(BeEmpty())
+			Expect(cert.Status.ProviderCircuitBreakers).To(BeEmpty())
+			Expect(cert.Status.CertificateRef).To(Equal("prod-cert"))
+		})
+
+		It("should return not found for a missing Certificate", func() {
+			recorder := performRequest(engine, http.MethodPost, "/api/v1/namespaces/default/certificates/missing:resetUploadStatus", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should reject an unknown action", func() {
+			recorder := performRequest(engine, http.MethodPost, "/api/v1/namespaces/default/certificates/prod:explode", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("When getting the issuance status", func() {
+		newCMCertificate := func(name string, conditions ...certmanagerv1.CertificateCondition) *certmanagerv1.Certificate {
+			return &certmanagerv1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Status:     certmanagerv1.CertificateStatus{Conditions: conditions},
+			}
+		}
+
+		getStatus := func(name string) CertificateStatusResponse {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/"+name, nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			var response CertificateResponse
+			decodeJSON(recorder, &response)
+			return response.Status
+		}
+
+		It("should report a ready cert-manager Certificate", func() {
+			Expect(k8sClient.Create(context.Background(), newCMCertificate("test-a-cert", certmanagerv1.CertificateCondition{
+				Type:    certmanagerv1.CertificateConditionReady,
+				Status:  cmmeta.ConditionTrue,
+				Message: "Certificate is up to date and has not expired",
+			}))).To(Succeed())
+
+			status := getStatus("test-a")
+			Expect(status.Ready).To(Equal(ptr.To(true)))
+			Expect(status.IssuanceMessage).To(Equal("Certificate is up to date and has not expired"))
+		})
+
+		It("should report a pending issuance", func() {
+			Expect(k8sClient.Create(context.Background(), newCMCertificate("test-a-cert", certmanagerv1.CertificateCondition{
+				Type:    certmanagerv1.CertificateConditionReady,
+				Status:  cmmeta.ConditionFalse,
+				Message: "Issuing certificate as Secret does not exist",
+			}))).To(Succeed())
+
+			status := getStatus("test-a")
+			Expect(status.Ready).To(Equal(ptr.To(false)))
+			Expect(status.IssuanceMessage).To(Equal("Issuing certificate as Secret does not exist"))
+		})
+
+		It("should read the cert-manager Certificate named in the status", func() {
+			cert := &certificatev1alpha1.Certificate{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "prod"}, cert)).To(Succeed())
+			cert.Status.CertificateRef = "prod-custom"
+			Expect(k8sClient.Status().Update(context.Background(), cert)).To(Succeed())
+			Expect(k8sClient.Create(context.Background(), newCMCertificate("prod-custom"))).To(Succeed())
+
+			status := getStatus("prod")
+			Expect(status.Ready).To(Equal(ptr.To(false)))
+			Expect(status.IssuanceMessage).To(ContainSubstring("prod-custom has no Ready condition yet"))
+		})
+
+		It("should report a cert-manager Certificate that doesn't exist yet as not ready", func() {
+			status := getStatus("test-a")
+			Expect(status.Ready).To(Equal(ptr.To(false)))
+			Expect(status.IssuanceMessage).To(ContainSubstring("test-a-cert doesn't exist yet"))
+		})
+
+		It("should name a cert-manager Certificate that wasn't reconciled yet with the configured suffix", func() {
+			h.CertificateNameSuffix = "-operator-cert"
+			Expect(k8sClient.Create(context.Background(), newCMCertificate("test-a-operator-cert", certmanagerv1.CertificateCondition{
+				Type:   certmanagerv1.CertificateConditionReady,
+				Status: cmmeta.ConditionTrue,
+			}))).To(Succeed())
+
+			status := getStatus("test-a")
+			Expect(status.Ready).To(Equal(ptr.To(true)))
+		})
+
+		It("should not report readiness in lists", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			var responses []CertificateResponse
+			decodeJSON(recorder, &responses)
+			Expect(responses).NotTo(BeEmpty())
+			for _, response := range responses {
+				Expect(response.Status.Ready).To(BeNil())
+			}
+		})
+	})
+
+	Context("When getting the effective spec", func() {
+		It("should resolve the runtime defaults of the raw spec", func() {
+			cert := newTestCertificate("default", "edge", nil)
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			cert.Spec.RemoteClusters = []certificatev1alpha1.RemoteCluster{
+				{Name: "edge-eu", KubeconfigSecretRef: "edge-eu-kubeconfig"},
+				{Name: "edge-us", KubeconfigSecretRef: "edge-us-kubeconfig", Namespace: "ingress", SecretName: "custom-tls"},
+			}
+			Expect(k8sClient.Create(context.Background(), cert)).To(Succeed())
+
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/edge/effective-spec", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var spec certificatev1alpha1.CertificateSpec
+			decodeJSON(recorder, &spec)
+
+			raw := cert.Spec
+			Expect(raw.IssuerKind).To(BeEmpty())
+			Expect(raw.ClusterIssuerName).To(BeEmpty())
+			Expect(raw.CloudflareEnabled).To(BeNil())
+
+			Expect(spec.Domain).To(Equal(raw.Domain))
+			Expect(spec.CloudflareSecretRef).To(Equal(raw.CloudflareSecretRef))
+			Expect(spec.IssuerKind).To(Equal(certificatev1alpha1.IssuerKindClusterIssuer))
+			Expect(spec.ClusterIssuerName).To(Equal(certificatev1alpha1.DefaultClusterIssuerName))
+			Expect(spec.CloudflareEnabled).To(Equal(ptr.To(true)))
+			Expect(spec.CloudflareBundle).To(Equal(certificatev1alpha1.BundleFullChain))
+			Expect(spec.AWS).To(Equal(&certificatev1alpha1.AWS{
+				CredentialType: certificatev1alpha1.DefaultAWSCredentialType,
+				Bundle:         certificatev1alpha1.BundleFullChain,
+			}))
+			Expect(spec.DisableFinalizer).To(Equal(ptr.To(false)))
+			Expect(spec.RemoteClusters).To(Equal([]certificatev1alpha1.RemoteCluster{
+				{Name: "edge-eu", KubeconfigSecretRef: "edge-eu-kubeconfig", Namespace: "default", SecretName: "edge-tls"},
+				{Name: "edge-us", KubeconfigSecretRef: "edge-us-kubeconfig", Namespace: "ingress", SecretName: "custom-tls"},
+			}))
+		})
+
+		It("should name remote cluster Secrets with the configured suffix", func() {
+			h.SecretNameSuffix = "-operator-tls"
+			cert := newTestCertificate("default", "edge", nil)
+			cert.Spec.RemoteClusters = []certificatev1alpha1.RemoteCluster{
+				{Name: "edge-eu", KubeconfigSecretRef: "edge-eu-kubeconfig"},
+			}
+			Expect(k8sClient.Create(context.Background(), cert)).To(Succeed())
+
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/edge/effective-spec", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var spec certificatev1alpha1.CertificateSpec
+			decodeJSON(recorder, &spec)
+			Expect(spec.RemoteClusters).To(HaveLen(1))
+			Expect(spec.RemoteClusters[0].SecretName).To(Equal("edge-operator-tls"))
+		})
+
+		It("should keep explicitly set values", func() {
+			cert := newTestCertificate("default", "explicit", nil)
+			cert.Spec.IssuerKind = certificatev1alpha1.IssuerKindIssuer
+			cert.Spec.IssuerName = "team-issuer"
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.CloudflareEnabled = ptr.To(false)
+			cert.Spec.CloudflareBundle = certificatev1alpha1.BundleLeafOnly
+			Expect(k8sClient.Create(context.Background(), cert)).To(Succeed())
+
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/explicit/effective-spec", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var spec certificatev1alpha1.CertificateSpec
+			decodeJSON(recorder, &spec)
+			Expect(spec.IssuerKind).To(Equal(certificatev1alpha1.IssuerKindIssuer))
+			Expect(spec.IssuerName).To(Equal("team-issuer"))
+			Expect(spec.CloudflareEnabled).To(Equal(ptr.To(false)))
+			Expect(spec.CloudflareBundle).To(Equal(certificatev1alpha1.BundleLeafOnly))
+			Expect(spec.AWS).To(BeNil())
+		})
+
+		It("should report Cloudflare as disabled without credentials", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/prod/effective-spec", nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var spec certificatev1alpha1.CertificateSpec
+			decodeJSON(recorder, &spec)
+			Expect(spec.CloudflareEnabled).To(Equal(ptr.To(false)))
+		})
+
+		It("should return not found for a missing Certificate", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/missing/effective-spec", nil)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("When streaming lists as NDJSON", func() {
+		var reader *pagingReader
+
+		BeforeEach(func() {
+			reader = &pagingReader{Reader: k8sClient, pageSize: 2}
+			h := NewCertificateHandler(k8sClient, reader, 0)
+			engine = gin.New()
+			engine.GET("/api/v1/certificates", h.ListCertificates)
+			engine.GET("/api/v1/namespaces/:namespace/certificates", h.ListCertificatesInNamespace)
+		})
+
+		It("should write one Certificate per line across every page", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates", nil,
+				"Accept", "application/x-ndjson")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/x-ndjson"))
+			Expect(recorder.Flushed).To(BeTrue())
+			Expect(reader.pages).To(Equal(2))
+
+			Expect(bytes.Count(recorder.Body.Bytes(), []byte("\n"))).To(Equal(3))
+			responses := decodeNDJSON[CertificateResponse](recorder.Body.Bytes())
+			names := make([]string, 0, len(responses))
+			for _, response := range responses {
+				names = append(names, response.Namespace+"/"+response.Name)
+			}
+			Expect(names).To(ConsistOf("default/test-a", "team/test-b", "default/prod"))
+		})
+
+		It("should stream only the Certificates in the namespace", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/team/certificates", nil,
+				"Accept", "application/x-ndjson")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			responses := decodeNDJSON[CertificateResponse](recorder.Body.Bytes())
+			Expect(responses).To(HaveLen(1))
+			Expect(responses[0].Name).To(Equal("test-b"))
+		})
+
+		It("should end the stream with an error line when a later page fails", func() {
+			reader.failFrom = 2
+
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates", nil,
+				"Accept", "application/x-ndjson")
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			lines := decodeNDJSON[map[string]any](recorder.Body.Bytes())
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[2]).To(HaveKeyWithValue("error", ContainSubstring("request timed out")))
+		})
+
+		It("should return an error response when the first page fails", func() {
+			reader.failFrom = 1
+
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates", nil,
+				"Accept", "application/x-ndjson")
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+
+		It("should keep returning a JSON array by default", func() {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates", nil)
+			Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+
+			var responses []CertificateResponse
+			decodeJSON(recorder, &responses)
+			Expect(responses).To(HaveLen(3))
+		})
+	})
+})
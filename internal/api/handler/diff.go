@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver"
+)
+
+// diffAction is the custom method that previews the changes of a new Certificate spec
+const diffAction = "diff"
+
+// SpecChangePlanner plans what replacing the spec of a Certificate would change, without
+// changing anything
+type SpecChangePlanner interface {
+	PlanSpecChange(cert *certificatev1alpha1.Certificate, candidate certificatev1alpha1.CertificateSpec) driver.SpecChangePlan
+}
+
+// CertificateFieldChange is a field of the cert-manager Certificate that would change
+type CertificateFieldChange struct {
+	// Field is the path of the field in the cert-manager Certificate
+	Field     string `json:"field" example:"spec.dnsNames"`
+	Current   any    `json:"current,omitempty"`
+	Candidate any    `json:"candidate,omitempty"`
+}
+
+// CertificateDiffResponse describes what replacing the spec of a Certificate would change
+type CertificateDiffResponse struct {
+	// CertificateChanges are the changes to the cert-manager Certificate
+	CertificateChanges []CertificateFieldChange `json:"certificateChanges"`
+	// Reissue is true when cert-manager would issue a new certificate
+	Reissue bool `json:"reissue"`
+	// ReuploadProviders are the providers the certificate would be uploaded to again
+	ReuploadProviders []string `json:"reuploadProviders" example:"cloudflare,aws"`
+	// UploadsPaused is true when the re-uploads wait until spec.uploadsPaused is unset
+	UploadsPaused bool `json:"uploadsPaused"`
+}
+
+// DiffCertificate godoc
+// @Summary Preview a Certificate spec change
+// @Description Compare a candidate spec with the current one and return the changes to the cert-manager Certificate, whether it would be reissued, and which providers would be uploaded to again. The candidate is validated with a server-side dry run; nothing is changed.
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Param namespace path string true "Namespace"
+// @Param name path string true "Certificate name"
+// @Param request body UpdateCertificateRequest true "Candidate spec"
+// @Success 200 {object} CertificateDiffResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Router /api/v1/namespaces/{namespace}/certificates/{name}:diff [post]
+func (h *CertificateHandler) DiffCertificate(c *gin.Context, namespace, name string) {
+	if h.Planner == nil {
+		c.JSON(http.StatusNotImplemented, newErrorResponse(ErrorCodeInternalError, "previewing Certificate changes is not supported"))
+		return
+	}
+
+	var req UpdateCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidSpec, err.Error()))
+		return
+	}
+
+	cert := &certificatev1alpha1.Certificate{}
+	if err := h.Client.Get(c.Request.Context(), types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}, cert); err != nil {
+		c.JSON(kubernetesErrorResponse(err))
+		return
+	}
+
+	// The dry run validates the candidate and applies the defaults the stored spec would get
+	candidate := cert.DeepCopy()
+	candidate.Spec = req.Spec
+	if err := h.Client.Update(c.Request.Context(), candidate, client.DryRunAll); err != nil {
+		c.JSON(kubernetesErrorResponse(err))
+		return
+	}
+
+	plan := h.Planner.PlanSpecChange(cert, candidate.Spec)
+	response := CertificateDiffResponse{
+		CertificateChanges: make([]CertificateFieldChange, 0, len(plan.CertificateChanges)),
+		Reissue:            plan.Reissue,
+		ReuploadProviders:  append([]string{}, plan.ReuploadProviders...),
+		UploadsPaused:      plan.UploadsPaused,
+	}
+	for _, change := range plan.CertificateChanges {
+		response.CertificateChanges = append(response.CertificateChanges, CertificateFieldChange{
+			Field:     change.Field,
+			Current:   change.Current,
+			Candidate: change.Candidate,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
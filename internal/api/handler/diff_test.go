@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver"
+)
+
+var _ = Describe("DiffCertificate", func() {
+	var (
+		engine    *gin.Engine
+		h         *CertificateHandler
+		k8sClient client.Client
+	)
+
+	BeforeEach(func() {
+		cert := newTestCertificate("default", "prod", nil)
+		cert.Spec.ClusterIssuerName = "letsencrypt"
+		cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+		cert.Spec.CloudflareZoneID = "zone"
+		cert.Spec.S3 = &certificatev1alpha1.S3{Bucket: "certificates"}
+		cert.Status.CertificateRef = "prod-cert"
+		cert.Status.LastUploadedCertHash = "uploaded"
+		k8sClient = newFakeClient(cert)
+
+		h = NewCertificateHandler(k8sClient, nil, 0)
+		h.Planner = driver.NewCertificateManager(k8sClient, testScheme)
+		engine = gin.New()
+		engine.POST("/api/v1/namespaces/:namespace/certificates/:name", h.CertificateAction)
+	})
+
+	// candidate returns the spec of the stored Certificate changed by mutate
+	candidate := func(mutate func(*certificatev1alpha1.CertificateSpec)) UpdateCertificateRequest {
+		cert := &certificatev1alpha1.Certificate{}
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "prod"}, cert)).To(Succeed())
+		mutate(&cert.Spec)
+		return UpdateCertificateRequest{Spec: cert.Spec}
+	}
+
+	diff := func(body any) CertificateDiffResponse {
+		recorder := performRequest(engine, http.MethodPost, "/api/v1/namespaces/default/certificates/prod:diff", body)
+		Expect(recorder.Code).To(Equal(http.StatusOK), recorder.Body.String())
+		var response CertificateDiffResponse
+		decodeJSON(recorder, &response)
+		return response
+	}
+
+	It("should report no changes for the current spec", func() {
+		Expect(diff(candidate(func(*certificatev1alpha1.CertificateSpec) {}))).To(Equal(CertificateDiffResponse{
+			CertificateChanges: []CertificateFieldChange{},
+			ReuploadProviders:  []string{},
+		}))
+	})
+
+	It("should report the reissue and re-uploads of a domain and issuer change", func() {
+		response := diff(candidate(func(spec *certificatev1alpha1.CertificateSpec) {
+			spec.Domain = "www.example.com"
+			spec.ClusterIssuerName = "zerossl"
+		}))
+		Expect(response).To(Equal(CertificateDiffResponse{
+			CertificateChanges: []CertificateFieldChange{
+				{Field: "spec.dnsNames", Current: []any{"prod.example.com"}, Candidate: []any{"www.example.com"}},
+				{Field: "spec.issuerRef", Current: "ClusterIssuer/letsencrypt", Candidate: "ClusterIssuer/zerossl"},
+			},
+			Reissue:           true,
+			ReuploadProviders: []string{"cloudflare", "s3"},
+		}))
+	})
+
+	It("should report cert-manager changes that don't reissue", func() {
+		response := diff(candidate(func(spec *certificatev1alpha1.CertificateSpec) {
+			spec.CertificateLabels = map[string]string{"team": "web"}
+			spec.PrivateKeyRotationPolicy = certificatev1alpha1.PrivateKeyRotationPolicyAlways
+		}))
+		Expect(response.CertificateChanges).To(Equal([]CertificateFieldChange{
+			{Field: "metadata.labels", Candidate: map[string]any{"team": "web"}},
+			{Field: "spec.privateKey.rotationPolicy", Current: "", Candidate: "Always"},
+		}))
+		Expect(response.Reissue).To(BeFalse())
+		Expect(response.ReuploadProviders).To(BeEmpty())
+	})
+
+	It("should not change the Certificate", func() {
+		diff(candidate(func(spec *certificatev1alpha1.CertificateSpec) {
+			spec.Domain = "www.example.com"
+		}))
+
+		cert := &certificatev1alpha1.Certificate{}
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "prod"}, cert)).To(Succeed())
+		Expect(cert.Spec.Domain).To(Equal("prod.example.com"))
+		Expect(cert.Status.LastUploadedCertHash).To(Equal("uploaded"))
+	})
+
+	It("should return 404 for a missing Certificate", func() {
+		recorder := performRequest(engine, http.MethodPost, "/api/v1/namespaces/default/certificates/missing:diff",
+			UpdateCertificateRequest{Spec: certificatev1alpha1.CertificateSpec{Domain: "missing.example.com"}})
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should reject a malformed request", func() {
+		recorder := performRequest(engine, http.MethodPost, "/api/v1/namespaces/default/certificates/prod:diff", "spec")
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should return 501 without a planner", func() {
+		h.Planner = nil
+		recorder := performRequest(engine, http.MethodPost, "/api/v1/namespaces/default/certificates/prod:diff",
+			candidate(func(*certificatev1alpha1.CertificateSpec) {}))
+		Expect(recorder.Code).To(Equal(http.StatusNotImplemented))
+	})
+})
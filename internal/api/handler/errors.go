@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Error codes of ErrorResponse, so clients can tell errors with the same HTTP status apart
+const (
+	// ErrorCodeNotFound is returned when the Certificate or action doesn't exist
+	ErrorCodeNotFound = "NOT_FOUND"
+
+	// ErrorCodeAlreadyExists is returned when creating a Certificate that already exists
+	ErrorCodeAlreadyExists = "ALREADY_EXISTS"
+
+	// ErrorCodeConflict is returned when the Certificate was modified concurrently
+	ErrorCodeConflict = "CONFLICT"
+
+	// ErrorCodeInvalidSpec is returned when the request body is malformed or the API server
+	// rejects the Certificate, e.g. because of the CRD validation
+	ErrorCodeInvalidSpec = "INVALID_SPEC"
+
+	// ErrorCodeInvalidRequest is returned for invalid query parameters
+	ErrorCodeInvalidRequest = "INVALID_REQUEST"
+
+	// ErrorCodeUnauthorized is returned when an admin request lacks a valid bearer token
+	ErrorCodeUnauthorized = "UNAUTHORIZED"
+
+	// ErrorCodeForbidden is returned when the API server denies the operator's service account
+	// the request, e.g. because RBAC doesn't allow it in the namespace
+	ErrorCodeForbidden = "FORBIDDEN"
+
+	// ErrorCodeTimeout is returned when the request didn't complete within its route's timeout
+	ErrorCodeTimeout = "TIMEOUT"
+
+	// ErrorCodeUpstreamError is returned when the Kubernetes API server fails the request
+	ErrorCodeUpstreamError = "UPSTREAM_ERROR"
+
+	// ErrorCodeInternalError is returned when the response can't be encoded
+	ErrorCodeInternalError = "INTERNAL_ERROR"
+)
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	// Code is a machine-readable error code, e.g. NOT_FOUND
+	Code  string `json:"code" example:"NOT_FOUND"`
+	Error string `json:"error" example:"resource not found"`
+	// Details lists the individual problems, e.g. each field rejected by the validation
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorDetail is a single problem of an ErrorResponse
+type ErrorDetail struct {
+	Field   string `json:"field,omitempty" example:"spec.domain"`
+	Message string `json:"message" example:"Required value"`
+}
+
+// newErrorResponse returns an ErrorResponse without details
+func newErrorResponse(code, message string) ErrorResponse {
+	return ErrorResponse{Code: code, Error: message}
+}
+
+// kubernetesErrorResponse maps an error of the Kubernetes API to the HTTP status and
+// ErrorResponse to return. The causes of a rejected Certificate are returned as details.
+func kubernetesErrorResponse(err error) (int, ErrorResponse) {
+	switch {
+	case apierrors.IsNotFound(err):
+		return http.StatusNotFound, newErrorResponse(ErrorCodeNotFound, err.Error())
+	case apierrors.IsAlreadyExists(err):
+		return http.StatusConflict, newErrorResponse(ErrorCodeAlreadyExists, err.Error())
+	case apierrors.IsConflict(err):
+		return http.StatusConflict, newErrorResponse(ErrorCodeConflict, err.Error())
+	case apierrors.IsForbidden(err):
+		return http.StatusForbidden, newErrorResponse(ErrorCodeForbidden, err.Error())
+	case apierrors.IsInvalid(err):
+		return http.StatusUnprocessableEntity, invalidSpecResponse(err)
+	case apierrors.IsBadRequest(err):
+		return http.StatusBadRequest, invalidSpecResponse(err)
+	default:
+		return http.StatusInternalServerError, newErrorResponse(ErrorCodeUpstreamError, err.Error())
+	}
+}
+
+// invalidSpecResponse returns the INVALID_SPEC ErrorResponse for a Certificate the API server
+// rejected, with a detail for each rejected field
+func invalidSpecResponse(err error) ErrorResponse {
+	response := newErrorResponse(ErrorCodeInvalidSpec, err.Error())
+	if status, ok := err.(apierrors.APIStatus); ok && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			response.Details = append(response.Details, ErrorDetail{Field: cause.Field, Message: cause.Message})
+		}
+	}
+	return response
+}
+
+// respondKubernetesError writes the ErrorResponse for an error of the Kubernetes API in the
+// format negotiated with the client
+func respondKubernetesError(c *gin.Context, err error) {
+	status, response := kubernetesErrorResponse(err)
+	respond(c, status, response)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+var _ = Describe("Error responses", func() {
+	var (
+		engine  *gin.Engine
+		funcs   interceptor.Funcs
+		request func(method, path string, body any) ErrorResponse
+	)
+
+	BeforeEach(func() {
+		funcs = interceptor.Funcs{}
+		request = func(method, path string, body any) ErrorResponse {
+			k8sClient := fake.NewClientBuilder().
+				WithScheme(testScheme).
+				WithObjects(newTestCertificate("default", "prod", nil)).
+				WithStatusSubresource(&certificatev1alpha1.Certificate{}).
+				WithInterceptorFuncs(funcs).
+				Build()
+			h := NewCertificateHandler(k8sClient, nil, 0)
+			engine = gin.New()
+			engine.POST("/api/v1/certificates", h.CreateCertificate)
+			engine.GET("/api/v1/certificates", h.ListCertificates)
+			engine.DELETE("/api/v1/certificates", h.DeleteCertificates)
+			engine.GET("/api/v1/certificates/export", h.ExportCertificates)
+			engine.GET("/api/v1/namespaces/:namespace/certificates/:name", h.GetCertificate)
+			engine.PUT("/api/v1/namespaces/:namespace/certificates/:name", h.UpdateCertificate)
+			engine.DELETE("/api/v1/namespaces/:namespace/certificates/:name", h.DeleteCertificate)
+			engine.POST("/api/v1/namespaces/:namespace/certificates/:name", h.CertificateAction)
+
+			recorder := performRequest(engine, method, path, body)
+			var response ErrorResponse
+			decodeJSON(recorder, &response)
+			Expect(response.Error).NotTo(BeEmpty())
+			Expect(recorder.Code).To(BeNumerically(">=", http.StatusBadRequest))
+			return response
+		}
+	})
+
+	validSpec := certificatev1alpha1.CertificateSpec{Domain: "new.example.com"}
+
+	It("should report a missing Certificate as NOT_FOUND", func() {
+		Expect(request(http.MethodGet, "/api/v1/namespaces/default/certificates/missing", nil).Code).To(Equal(ErrorCodeNotFound))
+		Expect(request(http.MethodPut, "/api/v1/namespaces/default/certificates/missing",
+			UpdateCertificateRequest{Spec: validSpec}).Code).To(Equal(ErrorCodeNotFound))
+		Expect(request(http.MethodDelete, "/api/v1/namespaces/default/certificates/missing", nil).Code).To(Equal(ErrorCodeNotFound))
+		Expect(request(http.MethodPost, "/api/v1/namespaces/default/certificates/missing:resetUploadStatus", nil).Code).
+			To(Equal(ErrorCodeNotFound))
+	})
+
+	It("should report an unknown action as NOT_FOUND", func() {
+		Expect(request(http.MethodPost, "/api/v1/namespaces/default/certificates/prod:explode", nil).Code).To(Equal(ErrorCodeNotFound))
+	})
+
+	It("should report a malformed body as INVALID_SPEC", func() {
+		Expect(request(http.MethodPost, "/api/v1/certificates", map[string]string{"name": "new"}).Code).To(Equal(ErrorCodeInvalidSpec))
+		Expect(request(http.MethodPut, "/api/v1/namespaces/default/certificates/prod", "not a spec").Code).To(Equal(ErrorCodeInvalidSpec))
+	})
+
+	It("should report a Certificate rejected by the API server as INVALID_SPEC with details", func() {
+		funcs.Create = func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			return apierrors.NewInvalid(schema.GroupKind{Group: "certificate.println.kr", Kind: "Certificate"}, obj.GetName(),
+				field.ErrorList{field.Required(field.NewPath("spec", "domain"), "")})
+		}
+
+		response := request(http.MethodPost, "/api/v1/certificates",
+			CreateCertificateRequest{Name: "new", Namespace: "default", Spec: validSpec})
+		Expect(response.Code).To(Equal(ErrorCodeInvalidSpec))
+		Expect(response.Details).To(ConsistOf(ErrorDetail{Field: "spec.domain", Message: "Required value"}))
+	})
+
+	It("should report an existing Certificate as ALREADY_EXISTS", func() {
+		Expect(request(http.MethodPost, "/api/v1/certificates",
+			CreateCertificateRequest{Name: "prod", Namespace: "default", Spec: validSpec}).Code).To(Equal(ErrorCodeAlreadyExists))
+	})
+
+	It("should report invalid query parameters as INVALID_REQUEST", func() {
+		Expect(request(http.MethodDelete, "/api/v1/certificates", nil).Code).To(Equal(ErrorCodeInvalidRequest))
+		Expect(request(http.MethodDelete, "/api/v1/certificates?labelSelector=env%3D%3D%3D", nil).Code).To(Equal(ErrorCodeInvalidRequest))
+		Expect(request(http.MethodDelete, "/api/v1/certificates?labelSelector=env%3Dtest&dryRun=maybe", nil).Code).
+			To(Equal(ErrorCodeInvalidRequest))
+		Expect(request(http.MethodGet, "/api/v1/certificates/export?format=xlsx", nil).Code).To(Equal(ErrorCodeInvalidRequest))
+	})
+
+	It("should report a failing API server as UPSTREAM_ERROR", func() {
+		funcs.List = func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+			return errors.New("etcdserver: request timed out")
+		}
+		funcs.Get = func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return errors.New("etcdserver: request timed out")
+		}
+
+		Expect(request(http.MethodGet, "/api/v1/certificates", nil).Code).To(Equal(ErrorCodeUpstreamError))
+		Expect(request(http.MethodGet, "/api/v1/certificates/export", nil).Code).To(Equal(ErrorCodeUpstreamError))
+		Expect(request(http.MethodDelete, "/api/v1/certificates?labelSelector=env%3Dtest", nil).Code).To(Equal(ErrorCodeUpstreamError))
+		Expect(request(http.MethodGet, "/api/v1/namespaces/default/certificates/prod", nil).Code).To(Equal(ErrorCodeUpstreamError))
+	})
+})
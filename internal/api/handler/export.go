@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// exportFormatCSV exports the inventory as CSV, one Certificate per row
+	exportFormatCSV = "csv"
+
+	// exportFormatJSON exports the inventory as a JSON array of CertificateInventoryEntry
+	exportFormatJSON = "json"
+
+	// csvContentType is the content type of CSV exports
+	csvContentType = "text/csv; charset=utf-8"
+)
+
+// exportColumns are the CSV header columns, in the order of CertificateInventoryEntry.csvRow
+var exportColumns = []string{"namespace", "name", "domain", "notAfter", "providers", "lastUploadedTime"}
+
+// CertificateInventoryEntry is a Certificate in the inventory export
+type CertificateInventoryEntry struct {
+	Namespace string `json:"namespace" example:"default"`
+	Name      string `json:"name" example:"example-cert"`
+	Domain    string `json:"domain" example:"example.com"`
+	// NotAfter is when the certificate expires, empty until it is issued
+	NotAfter string `json:"notAfter,omitempty" example:"2026-01-01T00:00:00Z"`
+	// Providers are the providers the certificate is uploaded to
+	Providers        []string `json:"providers" example:"cloudflare,aws"`
+	LastUploadedTime string   `json:"lastUploadedTime,omitempty" example:"2025-10-03T00:00:00Z"`
+}
+
+// newInventoryEntry converts a Certificate to its inventory entry
+func newInventoryEntry(cert *certificatev1alpha1.Certificate) CertificateInventoryEntry {
+	providers := []string{}
+	if cert.Status.CloudflareUploaded {
+		providers = append(providers, "cloudflare")
+	}
+	if cert.Status.AWSUploaded {
+		providers = append(providers, "aws")
+	}
+	if cert.Status.Providers["akamai"].Uploaded {
+		providers = append(providers, "akamai")
+	}
+	if cert.Status.S3Uploaded {
+		providers = append(providers, "s3")
+	}
+	for _, cluster := range cert.Status.RemoteClusters {
+		if cluster.Synced {
+			providers = append(providers, "remote-cluster")
+			break
+		}
+	}
+
+	return CertificateInventoryEntry{
+		Namespace:        cert.Namespace,
+		Name:             cert.Name,
+		Domain:           cert.Spec.Domain,
+		NotAfter:         formatTime(cert.Status.NotAfter),
+		Providers:        providers,
+		LastUploadedTime: formatTime(cert.Status.LastUploadedTime),
+	}
+}
+
+// csvRow returns the entry in the order of exportColumns, with providers separated by ";"
+func (e CertificateInventoryEntry) csvRow() []string {
+	return []string{e.Namespace, e.Name, e.Domain, e.NotAfter, strings.Join(e.Providers, ";"), e.LastUploadedTime}
+}
+
+// formatTime formats t as RFC 3339, or returns an empty string when it is not set
+func formatTime(t *metav1.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ExportCertificates godoc
+// @Summary Export the Certificate inventory
+// @Description Export every Certificate across all namespaces with its domain, expiry, the providers it is uploaded to, and the last upload time. The export is streamed page by page. A failure after the first page ends the response early, leaving the JSON array unterminated.
+// @Tags certificates
+// @Produce text/csv,json
+// @Param format query string false "Export format, csv (default) or json"
+// @Success 200 {array} CertificateInventoryEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/certificates/export [get]
+func (h *CertificateHandler) ExportCertificates(c *gin.Context) {
+	format := c.DefaultQuery("format", exportFormatCSV)
+	var exporter inventoryExporter
+	switch format {
+	case exportFormatCSV:
+		exporter = &csvExporter{writer: csv.NewWriter(c.Writer)}
+	case exportFormatJSON:
+		exporter = &jsonExporter{c: c}
+	default:
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest,
+			fmt.Sprintf("unsupported format %q (supported formats: %s, %s)", format, exportFormatCSV, exportFormatJSON)))
+		return
+	}
+
+	ctx := c.Request.Context()
+	controller := http.NewResponseController(c.Writer)
+
+	continueToken := ""
+	for {
+		certList := &certificatev1alpha1.CertificateList{}
+		if err := h.listPage(c, certList, continueToken); err != nil {
+			if !c.Writer.Written() {
+				c.JSON(kubernetesErrorResponse(err))
+				return
+			}
+			// The status is already sent, the response ends early
+			_ = c.Error(err)
+			return
+		}
+
+		if !c.Writer.Written() {
+			c.Header("Content-Type", exporter.contentType())
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="certificates.%s"`, format))
+			c.Status(http.StatusOK)
+			if err := exporter.begin(); err != nil {
+				return
+			}
+		}
+		// Not every writer supports deadlines, e.g. test recorders
+		_ = controller.SetWriteDeadline(time.Now().Add(streamPageWriteTimeout))
+
+		for i := range certList.Items {
+			if err := exporter.write(newInventoryEntry(&certList.Items[i])); err != nil {
+				// The client went away
+				return
+			}
+		}
+		if err := exporter.flush(); err != nil {
+			return
+		}
+		c.Writer.Flush()
+
+		continueToken = certList.Continue
+		if h.APIReader == nil || continueToken == "" || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if err := exporter.end(); err == nil {
+		c.Writer.Flush()
+	}
+}
+
+// inventoryExporter encodes inventory entries in an export format
+type inventoryExporter interface {
+	contentType() string
+	// begin writes what precedes the first entry
+	begin() error
+	write(entry CertificateInventoryEntry) error
+	// flush writes buffered entries to the response
+	flush() error
+	// end writes what follows the last entry
+	end() error
+}
+
+// csvExporter writes a header row and one row per entry
+type csvExporter struct {
+	writer *csv.Writer
+}
+
+func (e *csvExporter) contentType() string { return csvContentType }
+
+func (e *csvExporter) begin() error { return e.writer.Write(exportColumns) }
+
+func (e *csvExporter) write(entry CertificateInventoryEntry) error {
+	return e.writer.Write(entry.csvRow())
+}
+
+func (e *csvExporter) flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvExporter) end() error { return e.flush() }
+
+// jsonExporter writes the entries as a JSON array
+type jsonExporter struct {
+	c       *gin.Context
+	entries int
+}
+
+func (e *jsonExporter) contentType() string { return gin.MIMEJSON + "; charset=utf-8" }
+
+func (e *jsonExporter) begin() error {
+	_, err := e.c.Writer.WriteString("[")
+	return err
+}
+
+func (e *jsonExporter) write(entry CertificateInventoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if e.entries > 0 {
+		if _, err := e.c.Writer.WriteString(","); err != nil {
+			return err
+		}
+	}
+	e.entries++
+	_, err = e.c.Writer.Write(data)
+	return err
+}
+
+func (e *jsonExporter) flush() error { return nil }
+
+func (e *jsonExporter) end() error {
+	_, err := e.c.Writer.WriteString("]\n")
+	return err
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+var _ = Describe("Inventory export", func() {
+	var (
+		engine *gin.Engine
+		reader *pagingReader
+	)
+
+	BeforeEach(func() {
+		uploaded := newTestCertificate("default", "uploaded", nil)
+		uploaded.Status = certificatev1alpha1.CertificateStatus{
+			CloudflareUploaded: true,
+			AWSUploaded:        true,
+			NotAfter:           &metav1.Time{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+			LastUploadedTime:   &metav1.Time{Time: time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)},
+			RemoteClusters: []certificatev1alpha1.RemoteClusterStatus{
+				{Name: "edge", Synced: true},
+			},
+		}
+
+		k8sClient := newFakeClient(
+			uploaded,
+			newTestCertificate("team", "pending", nil),
+			newTestCertificate("default", "other", nil),
+		)
+		reader = &pagingReader{Reader: k8sClient, pageSize: 2}
+		h := NewCertificateHandler(k8sClient, reader, 0)
+		engine = gin.New()
+		engine.GET("/api/v1/certificates/export", h.ExportCertificates)
+	})
+
+	It("should export every Certificate as CSV by default", func() {
+		recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates/export", nil)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("text/csv"))
+		Expect(recorder.Header().Get("Content-Disposition")).To(ContainSubstring(`filename="certificates.csv"`))
+		Expect(recorder.Flushed).To(BeTrue())
+		Expect(reader.pages).To(Equal(2))
+
+		records, err := csv.NewReader(bytes.NewReader(recorder.Body.Bytes())).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(4))
+		Expect(records[0]).To(Equal([]string{"namespace", "name", "domain", "notAfter", "providers", "lastUploadedTime"}))
+		Expect(records[1:]).To(ContainElement([]string{
+			"default", "uploaded", "uploaded.example.com", "2026-01-02T03:04:05Z",
+			"cloudflare;aws;remote-cluster", "2025-10-03T12:00:00Z",
+		}))
+		Expect(records[1:]).To(ContainElement([]string{"team", "pending", "pending.example.com", "", "", ""}))
+	})
+
+	It("should export every Certificate as a JSON array", func() {
+		recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates/export?format=json", nil)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+
+		var entries []CertificateInventoryEntry
+		decodeJSON(recorder, &entries)
+		Expect(entries).To(HaveLen(3))
+		Expect(entries).To(ContainElement(CertificateInventoryEntry{
+			Namespace:        "default",
+			Name:             "uploaded",
+			Domain:           "uploaded.example.com",
+			NotAfter:         "2026-01-02T03:04:05Z",
+			Providers:        []string{"cloudflare", "aws", "remote-cluster"},
+			LastUploadedTime: "2025-10-03T12:00:00Z",
+		}))
+	})
+
+	It("should reject an unsupported format", func() {
+		recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates/export?format=xlsx", nil)
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should return an error response when the first page fails", func() {
+		reader.failFrom = 1
+
+		recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates/export", nil)
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+	})
+})
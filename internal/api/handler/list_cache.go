@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"sync"
+	"time"
+)
+
+// listCache holds Certificate list responses for a short TTL, so dashboards polling the
+// list endpoints don't each read every Certificate. Writes through the API invalidate it;
+// changes made by the controller or kubectl show up once the TTL expires.
+// A nil listCache caches nothing.
+type listCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]listCacheEntry
+}
+
+// listCacheEntry is a cached list response
+type listCacheEntry struct {
+	responses []CertificateResponse
+	expires   time.Time
+}
+
+// newListCache returns a listCache keeping responses for ttl, or nil when ttl is not positive
+func newListCache(ttl time.Duration) *listCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &listCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]listCacheEntry),
+	}
+}
+
+// get returns the cached responses for key unless they expired
+func (c *listCache) get(key string) ([]CertificateResponse, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.responses, true
+}
+
+// set caches the responses for key
+func (c *listCache) set(key string, responses []CertificateResponse) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop expired entries so namespaces that are no longer polled don't accumulate
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = listCacheEntry{responses: responses, expires: now.Add(c.ttl)}
+}
+
+// invalidate drops every cached response. A write in one namespace also changes the list
+// across all namespaces, so all entries are dropped.
+func (c *listCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// listCacheKey returns the cache key of the list of namespace, empty for all namespaces
+func listCacheKey(namespace string) string {
+	return "namespace=" + namespace
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+var _ = Describe("List response cache", func() {
+	var (
+		engine *gin.Engine
+		h      *CertificateHandler
+		lists  int
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		lists = 0
+		now = time.Now()
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithObjects(
+				newTestCertificate("default", "prod", nil),
+				newTestCertificate("team", "staging", nil),
+			).
+			WithStatusSubresource(&certificatev1alpha1.Certificate{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					lists++
+					return c.List(ctx, list, opts...)
+				},
+			}).
+			Build()
+
+		h = NewCertificateHandler(k8sClient, nil, time.Minute)
+		h.listCache.now = func() time.Time { return now }
+		engine = gin.New()
+		engine.POST("/api/v1/certificates", h.CreateCertificate)
+		engine.GET("/api/v1/certificates", h.ListCertificates)
+		engine.GET("/api/v1/namespaces/:namespace/certificates", h.ListCertificatesInNamespace)
+	})
+
+	list := func(path string) []CertificateResponse {
+		recorder := performRequest(engine, http.MethodGet, path, nil)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		var responses []CertificateResponse
+		decodeJSON(recorder, &responses)
+		return responses
+	}
+
+	It("should serve repeated lists from the cache until the TTL expires", func() {
+		Expect(list("/api/v1/certificates")).To(HaveLen(2))
+		Expect(list("/api/v1/certificates")).To(HaveLen(2))
+		Expect(lists).To(Equal(1))
+
+		By("keying the cache by namespace")
+		Expect(list("/api/v1/namespaces/default/certificates")).To(HaveLen(1))
+		Expect(list("/api/v1/namespaces/default/certificates")).To(HaveLen(1))
+		Expect(lists).To(Equal(2))
+
+		By("listing again once the TTL expired")
+		now = now.Add(time.Minute)
+		Expect(list("/api/v1/certificates")).To(HaveLen(2))
+		Expect(lists).To(Equal(3))
+	})
+
+	It("should invalidate the cache after a create", func() {
+		Expect(list("/api/v1/certificates")).To(HaveLen(2))
+		Expect(list("/api/v1/namespaces/default/certificates")).To(HaveLen(1))
+
+		recorder := performRequest(engine, http.MethodPost, "/api/v1/certificates", CreateCertificateRequest{
+			Name:      "api",
+			Namespace: "default",
+			Spec:      certificatev1alpha1.CertificateSpec{Domain: "api.example.com"},
+		})
+		Expect(recorder.Code).To(Equal(http.StatusCreated))
+
+		Expect(list("/api/v1/certificates")).To(HaveLen(3))
+		Expect(list("/api/v1/namespaces/default/certificates")).To(HaveLen(2))
+		Expect(lists).To(Equal(4))
+	})
+
+	It("should not cache streamed lists", func() {
+		for range 2 {
+			recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates", nil, "Accept", ndjsonContentType)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		}
+		Expect(lists).To(Equal(2))
+	})
+
+	It("should cache nothing when the TTL is 0", func() {
+		Expect(newListCache(0)).To(BeNil())
+		var disabled *listCache
+		disabled.set("key", []CertificateResponse{{Name: "prod"}})
+		_, ok := disabled.get("key")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/yaml"
+)
+
+// yamlContentType is the content type of YAML responses
+const yamlContentType = "application/yaml; charset=utf-8"
+
+// respond writes obj as YAML when the client asks for it with the Accept header,
+// and as JSON otherwise. YAML is marshaled through the JSON field names so both
+// formats share the same schema.
+func respond(c *gin.Context, status int, obj any) {
+	switch c.NegotiateFormat(gin.MIMEJSON, gin.MIMEYAML2, gin.MIMEYAML) {
+	case gin.MIMEYAML2, gin.MIMEYAML:
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, newErrorResponse(ErrorCodeInternalError, err.Error()))
+			return
+		}
+		c.Data(status, yamlContentType, data)
+	default:
+		c.JSON(status, obj)
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ndjsonContentType is the content type of newline-delimited JSON list responses
+	ndjsonContentType = "application/x-ndjson"
+
+	// streamPageSize is how many Certificates are read from the API server per page
+	// while streaming a list
+	streamPageSize = 500
+
+	// streamPageWriteTimeout is how long writing a single page may take. The server's
+	// write timeout is extended by this much per page so long streams aren't cut off.
+	streamPageWriteTimeout = 15 * time.Second
+)
+
+// wantsNDJSON reports whether the client asked for a newline-delimited JSON stream
+func wantsNDJSON(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, gin.MIMEYAML2, gin.MIMEYAML, ndjsonContentType) == ndjsonContentType
+}
+
+// streamCertificates writes the Certificates matching opts as one JSON object per line,
+// flushing after every page, so the response is never buffered as a whole. With an
+// APIReader the list is read from the API server page by page; the cached Client can't
+// paginate and returns the list in one page. A failure before the first line is returned
+// as a regular error response; later failures end the stream with an ErrorResponse line.
+func (h *CertificateHandler) streamCertificates(c *gin.Context, opts ...client.ListOption) {
+	ctx := c.Request.Context()
+	encoder := json.NewEncoder(c.Writer)
+	controller := http.NewResponseController(c.Writer)
+
+	continueToken := ""
+	for {
+		certList := &certificatev1alpha1.CertificateList{}
+		if err := h.listPage(c, certList, continueToken, opts...); err != nil {
+			if !c.Writer.Written() {
+				c.JSON(kubernetesErrorResponse(err))
+				return
+			}
+			_, response := kubernetesErrorResponse(err)
+			_ = encoder.Encode(response)
+			return
+		}
+
+		if !c.Writer.Written() {
+			c.Header("Content-Type", ndjsonContentType)
+			c.Status(http.StatusOK)
+		}
+		// Not every writer supports deadlines, e.g. test recorders
+		_ = controller.SetWriteDeadline(time.Now().Add(streamPageWriteTimeout))
+
+		for i := range certList.Items {
+			if err := encoder.Encode(convertToResponse(&certList.Items[i])); err != nil {
+				// The client went away
+				return
+			}
+		}
+		c.Writer.Flush()
+
+		continueToken = certList.Continue
+		if h.APIReader == nil || continueToken == "" || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// listPage reads the page of Certificates after continueToken, or all of them from the
+// cached Client when no APIReader is configured
+func (h *CertificateHandler) listPage(
+	c *gin.Context,
+	certList *certificatev1alpha1.CertificateList,
+	continueToken string,
+	opts ...client.ListOption,
+) error {
+	if h.APIReader == nil {
+		return h.Client.List(c.Request.Context(), certList, opts...)
+	}
+	pageOpts := append([]client.ListOption{client.Limit(streamPageSize), client.Continue(continueToken)}, opts...)
+	return h.APIReader.List(c.Request.Context(), certList, pageOpts...)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// The handler tests run the Gin handlers against controller-runtime's fake client.
+
+var testScheme = runtime.NewScheme()
+
+func TestHandler(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Handler Suite")
+}
+
+var _ = BeforeSuite(func() {
+	gin.SetMode(gin.TestMode)
+
+	utilruntime.Must(clientgoscheme.AddToScheme(testScheme))
+	utilruntime.Must(certificatev1alpha1.AddToScheme(testScheme))
+	utilruntime.Must(certmanagerv1.AddToScheme(testScheme))
+})
+
+// newFakeClient returns a fake client seeded with the given objects.
+func newFakeClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&certificatev1alpha1.Certificate{}).
+		Build()
+}
+
+// performRequest sends a request to the engine and returns the recorded response.
+func performRequest(engine http.Handler, method, path string, body any, headers ...string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		Expect(err).NotTo(HaveOccurred())
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// decodeJSON unmarshals the response body into out.
+func decodeJSON(recorder *httptest.ResponseRecorder, out any) {
+	Expect(json.Unmarshal(recorder.Body.Bytes(), out)).To(Succeed())
+}
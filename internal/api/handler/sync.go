@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncAction is the custom method on the certificate collection that converges the
+// Certificates of a source to a declared set
+const syncAction = "sync"
+
+// syncSourceLabel is set on the Certificates created by SyncCertificates to the source of
+// the sync. A sync only updates and prunes the Certificates of its own source.
+const syncSourceLabel = "certificate.println.kr/sync-source"
+
+// Actions of a SyncCertificateResult
+const (
+	syncActionCreate    = "create"
+	syncActionUpdate    = "update"
+	syncActionUnchanged = "unchanged"
+	syncActionDelete    = "delete"
+)
+
+// SyncCertificatesRequest is the desired set of Certificates of a source in a namespace
+type SyncCertificatesRequest struct {
+	Namespace string `json:"namespace" binding:"required" example:"default"`
+	// Source identifies the set, e.g. the Git repository the Certificates are declared in.
+	// Certificates of other sources, or created otherwise, are neither changed nor pruned.
+	Source string `json:"source" binding:"required" example:"infra-certificates"`
+	// Certificates must be set, so a request that lost the list doesn't prune the source
+	Certificates []SyncCertificateSpec `json:"certificates" binding:"required"`
+	// AllowEmpty must be set to declare no Certificates, which deletes every Certificate of
+	// the source
+	AllowEmpty bool `json:"allowEmpty"`
+	// DryRun validates the changes with the API server without applying them
+	DryRun bool `json:"dryRun"`
+}
+
+// SyncCertificateSpec is a desired Certificate of a SyncCertificatesRequest
+type SyncCertificateSpec struct {
+	Name string                              `json:"name" example:"example-cert"`
+	Spec certificatev1alpha1.CertificateSpec `json:"spec"`
+}
+
+// SyncCertificateResult is the change a sync applied to a single Certificate
+type SyncCertificateResult struct {
+	Name string `json:"name" example:"example-cert"`
+	// Action is create, update, unchanged, or delete
+	Action string `json:"action" example:"update"`
+	// Error is why the action failed, the Certificate is left as it was
+	Error string `json:"error,omitempty"`
+}
+
+// SyncCertificatesResponse lists the changes of a sync, the desired Certificates in the
+// order of the request followed by the pruned ones
+type SyncCertificatesResponse struct {
+	DryRun  bool                    `json:"dryRun"`
+	Results []SyncCertificateResult `json:"results"`
+}
+
+// SyncCertificates godoc
+// @Summary Converge the Certificates of a source to a declared set
+// @Description Create the declared Certificates that don't exist, update the ones whose spec differs, and delete the Certificates of the source that are no longer declared, within one namespace. Declaring no Certificates requires allowEmpty. Meant for webhooks of CI systems in GitOps workflows. Only Certificates created by a sync of the same source are updated or deleted. Requires the admin bearer token.
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sync body SyncCertificatesRequest true "Declared Certificates"
+// @Success 200 {object} SyncCertificatesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/certificates:sync [post]
+func (h *CertificateHandler) SyncCertificates(c *gin.Context) {
+	var req SyncCertificatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidSpec, err.Error()))
+		return
+	}
+	if errs := validation.IsValidLabelValue(req.Source); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest,
+			fmt.Sprintf("invalid source %q: %s", req.Source, errs[0])))
+		return
+	}
+	if len(req.Certificates) == 0 && !req.AllowEmpty {
+		c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest,
+			"no certificates are declared, set allowEmpty to delete every Certificate of the source"))
+		return
+	}
+	declared := make(map[string]bool, len(req.Certificates))
+	for _, desired := range req.Certificates {
+		if desired.Name == "" {
+			c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest, "every certificate needs a name"))
+			return
+		}
+		if declared[desired.Name] {
+			c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest,
+				fmt.Sprintf("certificate %q is declared more than once", desired.Name)))
+			return
+		}
+		declared[desired.Name] = true
+	}
+
+	ctx := c.Request.Context()
+	existing := &certificatev1alpha1.CertificateList{}
+	if err := h.Client.List(ctx, existing, client.InNamespace(req.Namespace),
+		client.MatchingLabels{syncSourceLabel: req.Source}); err != nil {
+		c.JSON(kubernetesErrorResponse(err))
+		return
+	}
+
+	var dryRun []string
+	if req.DryRun {
+		dryRun = []string{metav1.DryRunAll}
+	}
+
+	response := SyncCertificatesResponse{
+		DryRun:  req.DryRun,
+		Results: make([]SyncCertificateResult, 0, len(req.Certificates)),
+	}
+	changed := false
+	for _, desired := range req.Certificates {
+		result := h.syncCertificate(ctx, req, desired, dryRun)
+		changed = changed || (result.Error == "" && result.Action != syncActionUnchanged)
+		response.Results = append(response.Results, result)
+	}
+
+	for i := range existing.Items {
+		cert := &existing.Items[i]
+		if declared[cert.Name] {
+			continue
+		}
+		result := SyncCertificateResult{Name: cert.Name, Action: syncActionDelete}
+		if err := h.Client.Delete(ctx, cert, &client.DeleteOptions{DryRun: dryRun}); client.IgnoreNotFound(err) != nil {
+			result.Error = err.Error()
+		} else {
+			changed = true
+		}
+		response.Results = append(response.Results, result)
+	}
+	if changed && !req.DryRun {
+		h.listCache.invalidate()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// syncCertificate creates the desired Certificate or updates its spec. A Certificate with
+// the name that wasn't created by a sync of the source is left alone.
+func (h *CertificateHandler) syncCertificate(
+	ctx context.Context,
+	req SyncCertificatesRequest,
+	desired SyncCertificateSpec,
+	dryRun []string,
+) SyncCertificateResult {
+	result := SyncCertificateResult{Name: desired.Name}
+
+	cert := &certificatev1alpha1.Certificate{}
+	err := h.Client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: desired.Name}, cert)
+	if apierrors.IsNotFound(err) {
+		result.Action = syncActionCreate
+		cert = &certificatev1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      desired.Name,
+				Namespace: req.Namespace,
+				Labels:    map[string]string{syncSourceLabel: req.Source},
+			},
+			Spec: desired.Spec,
+		}
+		if err := h.Client.Create(ctx, cert, &client.CreateOptions{DryRun: dryRun}); err != nil {
+			result.Error = err.Error()
+		}
+		return result
+	}
+	if err != nil {
+		result.Action = syncActionUpdate
+		result.Error = err.Error()
+		return result
+	}
+
+	if source := cert.Labels[syncSourceLabel]; source != req.Source {
+		result.Action = syncActionUpdate
+		if source == "" {
+			result.Error = "certificate exists and was not created by a sync"
+		} else {
+			result.Error = fmt.Sprintf("certificate exists and is synced from source %q", source)
+		}
+		return result
+	}
+
+	// The dry run applies the defaults the stored spec got, so fields left to their
+	// defaults in the request don't count as changes
+	result.Action = syncActionUpdate
+	candidate := cert.DeepCopy()
+	candidate.Spec = desired.Spec
+	if err := h.Client.Update(ctx, candidate, client.DryRunAll); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if equality.Semantic.DeepEqual(cert.Spec, candidate.Spec) {
+		result.Action = syncActionUnchanged
+		return result
+	}
+
+	cert.Spec = desired.Spec
+	if err := h.Client.Update(ctx, cert, &client.UpdateOptions{DryRun: dryRun}); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
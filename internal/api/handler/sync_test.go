@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+var _ = Describe("SyncCertificates", func() {
+	const token = "sync-token"
+
+	var (
+		engine    *gin.Engine
+		k8sClient client.Client
+	)
+
+	serve := func(c client.Client) {
+		k8sClient = c
+		h := NewCertificateHandler(k8sClient, nil, 0)
+		engine = gin.New()
+		engine.POST("/api/v1/certificates", h.CreateCertificate)
+		engine.POST("/api/v1/:collection", BearerTokenAuth(token), h.CollectionAction)
+	}
+
+	setup := func(objs ...client.Object) {
+		serve(newFakeClient(objs...))
+	}
+
+	// setupDefaulting serves a client that applies CRD defaults on updates like the API
+	// server, which the fake client doesn't
+	setupDefaulting := func(objs ...client.Object) {
+		serve(fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&certificatev1alpha1.Certificate{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					if cert, ok := obj.(*certificatev1alpha1.Certificate); ok {
+						if cert.Spec.IssuerKind == "" {
+							cert.Spec.IssuerKind = certificatev1alpha1.IssuerKindClusterIssuer
+						}
+						if cert.Spec.ClusterIssuerName == "" {
+							cert.Spec.ClusterIssuerName = certificatev1alpha1.DefaultClusterIssuerName
+						}
+						if cert.Spec.AWS != nil && cert.Spec.AWS.CredentialType == "" {
+							cert.Spec.AWS.CredentialType = certificatev1alpha1.DefaultAWSCredentialType
+						}
+					}
+					return c.Update(ctx, obj, opts...)
+				},
+			}).
+			Build())
+	}
+
+	// synced returns a Certificate created by an earlier sync of source
+	synced := func(name, source string) *certificatev1alpha1.Certificate {
+		return newTestCertificate("default", name, map[string]string{syncSourceLabel: source})
+	}
+
+	declare := func(name, domain string) SyncCertificateSpec {
+		return SyncCertificateSpec{Name: name, Spec: certificatev1alpha1.CertificateSpec{Domain: domain}}
+	}
+
+	sync := func(req SyncCertificatesRequest) SyncCertificatesResponse {
+		recorder := performRequest(engine, http.MethodPost, "/api/v1/certificates:sync", req, "Authorization", "Bearer "+token)
+		Expect(recorder.Code).To(Equal(http.StatusOK), recorder.Body.String())
+		var response SyncCertificatesResponse
+		decodeJSON(recorder, &response)
+		return response
+	}
+
+	getCertificate := func(name string) (*certificatev1alpha1.Certificate, error) {
+		cert := &certificatev1alpha1.Certificate{}
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, cert)
+		return cert, err
+	}
+
+	It("should create, update, and prune the Certificates of the source", func() {
+		changed := synced("api", "infra")
+		setup(changed, synced("web", "infra"), synced("removed", "infra"))
+
+		response := sync(SyncCertificatesRequest{
+			Namespace: "default",
+			Source:    "infra",
+			Certificates: []SyncCertificateSpec{
+				declare("new", "new.example.com"),
+				declare("api", "api-v2.example.com"),
+				declare("web", "web.example.com"),
+			},
+		})
+		Expect(response).To(Equal(SyncCertificatesResponse{Results: []SyncCertificateResult{
+			{Name: "new", Action: "create"},
+			{Name: "api", Action: "update"},
+			{Name: "web", Action: "unchanged"},
+			{Name: "removed", Action: "delete"},
+		}}))
+
+		created, err := getCertificate("new")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.Spec.Domain).To(Equal("new.example.com"))
+		Expect(created.Labels).To(HaveKeyWithValue(syncSourceLabel, "infra"))
+		updated, err := getCertificate("api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.Spec.Domain).To(Equal("api-v2.example.com"))
+		_, err = getCertificate("removed")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("reporting nothing to change once converged")
+		response = sync(SyncCertificatesRequest{
+			Namespace: "default",
+			Source:    "infra",
+			Certificates: []SyncCertificateSpec{
+				declare("new", "new.example.com"),
+				declare("api", "api-v2.example.com"),
+				declare("web", "web.example.com"),
+			},
+		})
+		for _, result := range response.Results {
+			Expect(result.Action).To(Equal("unchanged"), result.Name)
+		}
+	})
+
+	It("should leave Certificates of other sources and created otherwise alone", func() {
+		manual := newTestCertificate("default", "manual", nil)
+		staging := newTestCertificate("staging", "elsewhere", map[string]string{syncSourceLabel: "infra"})
+		setup(manual, synced("other", "platform"), staging)
+
+		response := sync(SyncCertificatesRequest{
+			Namespace:    "default",
+			Source:       "infra",
+			Certificates: []SyncCertificateSpec{declare("manual", "changed.example.com"), declare("other", "changed.example.com")},
+		})
+		Expect(response.Results).To(Equal([]SyncCertificateResult{
+			{Name: "manual", Action: "update", Error: "certificate exists and was not created by a sync"},
+			{Name: "other", Action: "update", Error: `certificate exists and is synced from source "platform"`},
+		}))
+
+		for _, name := range []string{"manual", "other"} {
+			cert, err := getCertificate(name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Spec.Domain).To(Equal(name + ".example.com"))
+		}
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "staging", Name: "elsewhere"},
+			&certificatev1alpha1.Certificate{})).To(Succeed())
+	})
+
+	It("should report the changes without applying them in a dry run", func() {
+		setup(synced("api", "infra"), synced("removed", "infra"))
+
+		response := sync(SyncCertificatesRequest{
+			Namespace:    "default",
+			Source:       "infra",
+			DryRun:       true,
+			Certificates: []SyncCertificateSpec{declare("new", "new.example.com"), declare("api", "api-v2.example.com")},
+		})
+		Expect(response).To(Equal(SyncCertificatesResponse{DryRun: true, Results: []SyncCertificateResult{
+			{Name: "new", Action: "create"},
+			{Name: "api", Action: "update"},
+			{Name: "removed", Action: "delete"},
+		}}))
+
+		_, err := getCertificate("new")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		api, err := getCertificate("api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(api.Spec.Domain).To(Equal("api.example.com"))
+		_, err = getCertificate("removed")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not update Certificates for fields left to their defaults", func() {
+		stored := synced("api", "infra")
+		stored.Spec.IssuerKind = certificatev1alpha1.IssuerKindClusterIssuer
+		stored.Spec.ClusterIssuerName = certificatev1alpha1.DefaultClusterIssuerName
+		stored.Spec.AWS = &certificatev1alpha1.AWS{CredentialType: certificatev1alpha1.DefaultAWSCredentialType}
+		setupDefaulting(stored)
+
+		withAWS := func(domain string) SyncCertificateSpec {
+			desired := declare("api", domain)
+			desired.Spec.AWS = &certificatev1alpha1.AWS{}
+			return desired
+		}
+
+		response := sync(SyncCertificatesRequest{
+			Namespace:    "default",
+			Source:       "infra",
+			Certificates: []SyncCertificateSpec{withAWS("api.example.com")},
+		})
+		Expect(response.Results).To(Equal([]SyncCertificateResult{{Name: "api", Action: "unchanged"}}))
+
+		By("still updating Certificates whose other fields changed")
+		response = sync(SyncCertificatesRequest{
+			Namespace:    "default",
+			Source:       "infra",
+			Certificates: []SyncCertificateSpec{withAWS("api-v2.example.com")},
+		})
+		Expect(response.Results).To(Equal([]SyncCertificateResult{{Name: "api", Action: "update"}}))
+
+		updated, err := getCertificate("api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.Spec.Domain).To(Equal("api-v2.example.com"))
+		Expect(updated.Spec.ClusterIssuerName).To(Equal(certificatev1alpha1.DefaultClusterIssuerName))
+		Expect(updated.Spec.AWS.CredentialType).To(Equal(certificatev1alpha1.DefaultAWSCredentialType))
+	})
+
+	It("should prune every Certificate of the source only when allowed to", func() {
+		setup(synced("api", "infra"))
+
+		response := sync(SyncCertificatesRequest{
+			Namespace:    "default",
+			Source:       "infra",
+			Certificates: []SyncCertificateSpec{},
+			AllowEmpty:   true,
+		})
+		Expect(response.Results).To(Equal([]SyncCertificateResult{{Name: "api", Action: "delete"}}))
+
+		_, err := getCertificate("api")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should require the bearer token", func() {
+		setup()
+		recorder := performRequest(engine, http.MethodPost, "/api/v1/certificates:sync",
+			SyncCertificatesRequest{Namespace: "default", Source: "infra"})
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should not find unknown collection actions", func() {
+		setup()
+		recorder := performRequest(engine, http.MethodPost, "/api/v1/certificates:apply",
+			SyncCertificatesRequest{Namespace: "default", Source: "infra"}, "Authorization", "Bearer "+token)
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should keep serving the create endpoint next to the sync endpoint", func() {
+		setup()
+		recorder := performRequest(engine, http.MethodPost, "/api/v1/certificates", CreateCertificateRequest{
+			Name:      "plain",
+			Namespace: "default",
+			Spec:      certificatev1alpha1.CertificateSpec{Domain: "plain.example.com"},
+		})
+		Expect(recorder.Code).To(Equal(http.StatusCreated), recorder.Body.String())
+	})
+
+	DescribeTable("should reject invalid requests",
+		func(req SyncCertificatesRequest) {
+			setup(synced("api", "infra"))
+			recorder := performRequest(engine, http.MethodPost, "/api/v1/certificates:sync", req, "Authorization", "Bearer "+token)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest), recorder.Body.String())
+			_, err := getCertificate("api")
+			Expect(err).NotTo(HaveOccurred())
+		},
+		Entry("without a source", SyncCertificatesRequest{Namespace: "default", Certificates: []SyncCertificateSpec{}}),
+		Entry("without certificates", SyncCertificatesRequest{Namespace: "default", Source: "infra"}),
+		Entry("with no certificates declared", SyncCertificatesRequest{
+			Namespace: "default", Source: "infra", Certificates: []SyncCertificateSpec{},
+		}),
+		Entry("with an invalid source", SyncCertificatesRequest{
+			Namespace: "default", Source: "git@example.com:infra", Certificates: []SyncCertificateSpec{declare("api", "api.example.com")},
+		}),
+		Entry("with an unnamed certificate", SyncCertificatesRequest{
+			Namespace: "default", Source: "infra", Certificates: []SyncCertificateSpec{declare("", "example.com")},
+		}),
+		Entry("with a certificate declared twice", SyncCertificatesRequest{
+			Namespace: "default", Source: "infra",
+			Certificates: []SyncCertificateSpec{declare("api", "api.example.com"), declare("api", "api.example.com")},
+		}),
+	)
+})
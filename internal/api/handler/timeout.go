@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// timeoutWriteGrace is how long after a request's timeout the server still accepts the
+// write of the timeout response
+const timeoutWriteGrace = 5 * time.Second
+
+// RequestTimeout returns a middleware that bounds a request to timeout, 0 for no bound.
+// The request context is cancelled once the timeout passes, so calls to the API server
+// return early. Writes after the timeout are discarded, and a request whose response
+// wasn't started by then gets a 503 TIMEOUT error instead. Streams started before the
+// timeout end without their remaining lines.
+func RequestTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		// The server's write timeout may be shorter or longer than the route's. Not every
+		// writer supports deadlines, e.g. test recorders.
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout + timeoutWriteGrace))
+
+		writer := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if ctx.Err() == nil || writer.Written() {
+			return
+		}
+		// Headers the handler set for its own response don't apply to the error
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		respond(c, http.StatusServiceUnavailable,
+			newErrorResponse(ErrorCodeTimeout, "the request did not complete within "+timeout.String()))
+	}
+}
+
+// timeoutWriter discards the writes of a handler once ctx is done
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+// WriteHeader records the status unless the timeout passed
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.ctx.Err() == nil {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// WriteHeaderNow sends the status unless the timeout passed
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.ctx.Err() == nil {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Write writes data unless the timeout passed
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes s unless the timeout passed
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush flushes the written data unless the timeout passed
+func (w *timeoutWriter) Flush() {
+	if w.ctx.Err() == nil {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (w *timeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
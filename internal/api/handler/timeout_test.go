@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("RequestTimeout", func() {
+	const timeout = 50 * time.Millisecond
+
+	var engine *gin.Engine
+
+	BeforeEach(func() {
+		engine = gin.New()
+	})
+
+	// expectTimedOut checks that the request got the TIMEOUT error well before the slow
+	// handler would have finished
+	expectTimedOut := func(recorder *httptest.ResponseRecorder, elapsed time.Duration) {
+		Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+		var response ErrorResponse
+		decodeJSON(recorder, &response)
+		Expect(response.Code).To(Equal(ErrorCodeTimeout))
+		Expect(response.Error).To(ContainSubstring("50ms"))
+		Expect(elapsed).To(BeNumerically("<", time.Second))
+	}
+
+	It("should cut off a handler waiting on the request context at the timeout", func() {
+		engine.GET("/slow", RequestTimeout(timeout), func(c *gin.Context) {
+			select {
+			case <-c.Request.Context().Done():
+				c.JSON(http.StatusInternalServerError, newErrorResponse(ErrorCodeUpstreamError, c.Request.Context().Err().Error()))
+			case <-time.After(10 * time.Second):
+				c.JSON(http.StatusOK, gin.H{"status": "done"})
+			}
+		})
+
+		start := time.Now()
+		recorder := performRequest(engine, http.MethodGet, "/slow", nil)
+		expectTimedOut(recorder, time.Since(start))
+		Expect(time.Since(start)).To(BeNumerically(">=", timeout))
+	})
+
+	It("should discard the response of a handler that ignores the request context", func() {
+		engine.GET("/slow", RequestTimeout(timeout), func(c *gin.Context) {
+			time.Sleep(2 * timeout)
+			c.Header("Content-Type", ndjsonContentType)
+			c.JSON(http.StatusOK, gin.H{"status": "done"})
+		})
+
+		start := time.Now()
+		recorder := performRequest(engine, http.MethodGet, "/slow", nil)
+		expectTimedOut(recorder, time.Since(start))
+		Expect(recorder.Body.String()).NotTo(ContainSubstring("done"))
+	})
+
+	It("should end a stream started before the timeout without the later writes", func() {
+		engine.GET("/stream", RequestTimeout(timeout), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+			_, _ = c.Writer.WriteString("first\n")
+			c.Writer.Flush()
+			<-c.Request.Context().Done()
+			_, err := c.Writer.WriteString("second\n")
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+
+		recorder := performRequest(engine, http.MethodGet, "/stream", nil)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(Equal("first\n"))
+	})
+
+	It("should pass responses within the timeout through", func() {
+		engine.GET("/fast", RequestTimeout(timeout), func(c *gin.Context) {
+			c.JSON(http.StatusCreated, gin.H{"status": "done"})
+		})
+
+		recorder := performRequest(engine, http.MethodGet, "/fast", nil)
+		Expect(recorder.Code).To(Equal(http.StatusCreated))
+		Expect(recorder.Body.String()).To(ContainSubstring("done"))
+	})
+
+	It("should not bound requests when the timeout is 0", func() {
+		engine.GET("/slow", RequestTimeout(0), func(c *gin.Context) {
+			_, hasDeadline := c.Request.Context().Deadline()
+			Expect(hasDeadline).To(BeFalse())
+			c.JSON(http.StatusOK, gin.H{"status": "done"})
+		})
+
+		Expect(performRequest(engine, http.MethodGet, "/slow", nil).Code).To(Equal(http.StatusOK))
+	})
+
+	It("should bound the API server calls of the Certificate handlers", func() {
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithObjects(newTestCertificate("default", "prod", nil)).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, _ client.WithWatch, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			}).
+			Build()
+		h := NewCertificateHandler(k8sClient, nil, 0)
+		engine.GET("/api/v1/namespaces/:namespace/certificates/:name", RequestTimeout(timeout), h.GetCertificate)
+
+		start := time.Now()
+		recorder := performRequest(engine, http.MethodGet, "/api/v1/namespaces/default/certificates/prod", nil)
+		expectTimedOut(recorder, time.Since(start))
+	})
+
+	It("should extend the server's write timeout to the route's timeout", func() {
+		engine.GET("/slow", RequestTimeout(time.Second), func(c *gin.Context) {
+			time.Sleep(200 * time.Millisecond)
+			c.JSON(http.StatusOK, gin.H{"status": "done"})
+		})
+		server := httptest.NewUnstartedServer(engine)
+		server.Config.WriteTimeout = 50 * time.Millisecond
+		server.Start()
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/slow")
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = resp.Body.Close() }()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(ContainSubstring("done"))
+	})
+})
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// watchKeepAliveInterval is how often an SSE comment is sent on an idle watch, so proxies
+// don't close the connection
+const watchKeepAliveInterval = 30 * time.Second
+
+// CertificateEvent is a change to a Certificate streamed by WatchCertificates
+type CertificateEvent struct {
+	// Type is ADDED, MODIFIED, or DELETED
+	Type        string              `json:"type" example:"MODIFIED"`
+	Certificate CertificateResponse `json:"certificate"`
+}
+
+// WatchCertificates godoc
+// @Summary Watch Certificates
+// @Description Stream the changes to Certificates across all namespaces as Server-Sent Events. Each event is named after the change, ADDED, MODIFIED, or DELETED, and its data is a CertificateEvent. Certificates that exist when the watch starts are sent as ADDED. The stream ends with an ERROR event carrying an ErrorResponse when the watch fails; clients should reconnect when the stream ends.
+// @Tags certificates
+// @Produce text/event-stream
+// @Param namespace query string false "Only watch the Certificates in this namespace"
+// @Param labelSelector query string false "Label selector (e.g. env=test)"
+// @Success 200 {object} CertificateEvent
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Router /api/v1/certificates/watch [get]
+func (h *CertificateHandler) WatchCertificates(c *gin.Context) {
+	if h.Watcher == nil {
+		c.JSON(http.StatusNotImplemented, newErrorResponse(ErrorCodeInternalError, "watching Certificates is not supported"))
+		return
+	}
+
+	var opts []client.ListOption
+	if namespace := c.Query("namespace"); namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if selectorParam := c.Query("labelSelector"); selectorParam != "" {
+		selector, err := labels.Parse(selectorParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, newErrorResponse(ErrorCodeInvalidRequest, err.Error()))
+			return
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	// The request context is cancelled when the client disconnects or the server shuts down
+	ctx := c.Request.Context()
+	watcher, err := h.Watcher.Watch(ctx, &certificatev1alpha1.CertificateList{}, opts...)
+	if err != nil {
+		c.JSON(kubernetesErrorResponse(err))
+		return
+	}
+	defer watcher.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keep reverse proxies such as nginx from buffering the events
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	// The stream outlives the server's write timeout. Not every writer supports deadlines,
+	// e.g. test recorders.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(watchKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				// The API server ended the watch, e.g. after its timeout
+				return
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+				cert, ok := event.Object.(*certificatev1alpha1.Certificate)
+				if !ok {
+					continue
+				}
+				c.SSEvent(string(event.Type), CertificateEvent{
+					Type:        string(event.Type),
+					Certificate: convertToResponse(cert),
+				})
+				c.Writer.Flush()
+			case watch.Error:
+				_, response := kubernetesErrorResponse(apierrors.FromObject(event.Object))
+				c.SSEvent("ERROR", response)
+				c.Writer.Flush()
+				return
+			}
+		}
+	}
+}
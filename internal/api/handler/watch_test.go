@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// stopRecordingWatch records whether the watch was stopped
+type stopRecordingWatch struct {
+	watch.Interface
+	stopped *atomic.Bool
+}
+
+func (w stopRecordingWatch) Stop() {
+	w.stopped.Store(true)
+	w.Interface.Stop()
+}
+
+// sseEvent is a Server-Sent Event read from a stream
+type sseEvent struct {
+	name string
+	data string
+}
+
+// readSSEvent reads the next event from an SSE stream, skipping comments
+func readSSEvent(reader *bufio.Reader) sseEvent {
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		Expect(err).NotTo(HaveOccurred())
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if event.name != "" || event.data != "" {
+				return event
+			}
+		case strings.HasPrefix(line, "event:"):
+			event.name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			event.data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+}
+
+var _ = Describe("Certificate watch", func() {
+	var (
+		k8sClient client.WithWatch
+		server    *httptest.Server
+		stopped   atomic.Bool
+	)
+
+	BeforeEach(func() {
+		stopped.Store(false)
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithStatusSubresource(&certificatev1alpha1.Certificate{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+					w, err := c.Watch(ctx, list, opts...)
+					if err != nil {
+						return nil, err
+					}
+					return stopRecordingWatch{Interface: w, stopped: &stopped}, nil
+				},
+			}).
+			Build()
+
+		h := NewCertificateHandler(k8sClient, nil, 0)
+		h.Watcher = k8sClient
+		engine := gin.New()
+		engine.GET("/api/v1/certificates/watch", h.WatchCertificates)
+		server = httptest.NewServer(engine)
+		DeferCleanup(server.Close)
+	})
+
+	connect := func(ctx context.Context, query string) *http.Response {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/certificates/watch"+query, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := server.Client().Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		return resp
+	}
+
+	It("should stream Certificate changes as Server-Sent Events", func(ctx SpecContext) {
+		resp := connect(ctx, "?namespace=default")
+		defer func() { _ = resp.Body.Close() }()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/event-stream"))
+		reader := bufio.NewReader(resp.Body)
+
+		By("sending a created Certificate")
+		Expect(k8sClient.Create(ctx, newTestCertificate("default", "api", map[string]string{"env": "prod"}))).To(Succeed())
+		event := readSSEvent(reader)
+		Expect(event.name).To(Equal("ADDED"))
+		var payload CertificateEvent
+		Expect(json.Unmarshal([]byte(event.data), &payload)).To(Succeed())
+		Expect(payload.Type).To(Equal("ADDED"))
+		Expect(payload.Certificate.Name).To(Equal("api"))
+		Expect(payload.Certificate.Namespace).To(Equal("default"))
+		Expect(payload.Certificate.Spec.Domain).To(Equal("api.example.com"))
+
+		By("sending a deleted Certificate")
+		Expect(k8sClient.Delete(ctx, newTestCertificate("default", "api", nil))).To(Succeed())
+		event = readSSEvent(reader)
+		Expect(event.name).To(Equal("DELETED"))
+	}, SpecTimeout(10*time.Second))
+
+	It("should stop the watch when the client disconnects", func(ctx SpecContext) {
+		connectCtx, disconnect := context.WithCancel(ctx)
+		resp := connect(connectCtx, "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		disconnect()
+		_ = resp.Body.Close()
+		Eventually(stopped.Load).Should(BeTrue())
+	}, SpecTimeout(10*time.Second))
+
+	It("should reject an invalid label selector", func(ctx SpecContext) {
+		resp := connect(ctx, "?labelSelector=env%3D%3D%3D")
+		defer func() { _ = resp.Body.Close() }()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should not be supported without a watcher", func() {
+		engine := gin.New()
+		engine.GET("/api/v1/certificates/watch", NewCertificateHandler(k8sClient, nil, 0).WatchCertificates)
+		recorder := performRequest(engine, http.MethodGet, "/api/v1/certificates/watch", nil)
+		Expect(recorder.Code).To(Equal(http.StatusNotImplemented))
+	})
+})
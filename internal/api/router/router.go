@@ -1,7 +1,11 @@
 package router
 
 import (
+	"net/netip"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/tae2089/certificate-operator/internal/api/audit"
 	"github.com/tae2089/certificate-operator/internal/api/handler"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -9,12 +13,40 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-// SetupRouter creates and configures the Gin router
-func SetupRouter(k8sClient client.Client) *gin.Engine {
+// SetupRouter creates and configures the Gin router.
+// Streamed lists are paginated through apiReader, which may be nil to read them from k8sClient.
+// Mutating API requests are recorded to auditSink unless it is nil. Only trustedProxies may
+// set the X-Remote-User and X-Forwarded-For headers recorded as principal and source IP.
+// List responses are cached for listCacheTTL, 0 disables the cache.
+// Certificate changes are watched through watcher, which may be nil to disable the watch endpoint.
+// Spec changes are previewed through planner, which may be nil to disable the diff endpoint.
+// The admin and sync endpoints require adminToken as bearer token and are disabled when it is empty.
+// Names of cert-manager Certificates and TLS Secrets are derived with certificateNameSuffix and
+// secretNameSuffix, which may be empty for the defaults.
+// Requests for a single Certificate are bounded by requestTimeout, and the ones that read or
+// change every Certificate by longRequestTimeout; 0 disables a timeout. Watches aren't bounded.
+func SetupRouter(
+	k8sClient client.Client,
+	apiReader client.Reader,
+	watcher client.WithWatch,
+	planner handler.SpecChangePlanner,
+	auditSink audit.Sink,
+	trustedProxies []netip.Prefix,
+	listCacheTTL time.Duration,
+	adminToken string,
+	certificateNameSuffix, secretNameSuffix string,
+	requestTimeout, longRequestTimeout time.Duration,
+) *gin.Engine {
 	// Set Gin to release mode for production
 	// gin.SetMode(gin.ReleaseMode)
 
 	router := gin.Default()
+	proxies := make([]string, 0, len(trustedProxies))
+	for _, prefix := range trustedProxies {
+		proxies = append(proxies, prefix.String())
+	}
+	// Parsed prefixes are always accepted
+	_ = router.SetTrustedProxies(proxies)
 
 	// Health check endpoint
 	router.GET("/healthz", func(c *gin.Context) {
@@ -27,16 +59,33 @@ func SetupRouter(k8sClient client.Client) *gin.Engine {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Create handlers
-	certHandler := handler.NewCertificateHandler(k8sClient)
+	certHandler := handler.NewCertificateHandler(k8sClient, apiReader, listCacheTTL)
+	certHandler.Watcher = watcher
+	certHandler.Planner = planner
+	if certificateNameSuffix != "" {
+		certHandler.CertificateNameSuffix = certificateNameSuffix
+	}
+	if secretNameSuffix != "" {
+		certHandler.SecretNameSuffix = secretNameSuffix
+	}
+
+	timeout := handler.RequestTimeout(requestTimeout)
+	longTimeout := handler.RequestTimeout(longRequestTimeout)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	if auditSink != nil {
+		v1.Use(audit.Middleware(auditSink, trustedProxies))
+	}
 	{
 		// Certificate routes
 		certificates := v1.Group("/certificates")
 		{
-			certificates.POST("", certHandler.CreateCertificate)
-			certificates.GET("", certHandler.ListCertificates)
+			certificates.POST("", timeout, certHandler.CreateCertificate)
+			certificates.GET("", longTimeout, certHandler.ListCertificates)
+			certificates.DELETE("", longTimeout, certHandler.DeleteCertificates)
+			certificates.GET("/export", longTimeout, certHandler.ExportCertificates)
+			certificates.GET("/watch", certHandler.WatchCertificates)
 		}
 
 		// Namespaced certificate routes
@@ -44,12 +93,27 @@ func SetupRouter(k8sClient client.Client) *gin.Engine {
 		{
 			namespaceCerts := namespaces.Group("/:namespace/certificates")
 			{
-				namespaceCerts.GET("", certHandler.ListCertificatesInNamespace)
-				namespaceCerts.GET("/:name", certHandler.GetCertificate)
-				namespaceCerts.PUT("/:name", certHandler.UpdateCertificate)
-				namespaceCerts.DELETE("/:name", certHandler.DeleteCertificate)
+				namespaceCerts.GET("", longTimeout, certHandler.ListCertificatesInNamespace)
+				namespaceCerts.GET("/:name", timeout, certHandler.GetCertificate)
+				namespaceCerts.GET("/:name/effective-spec", timeout, certHandler.GetEffectiveSpec)
+				namespaceCerts.PUT("/:name", timeout, certHandler.UpdateCertificate)
+				namespaceCerts.DELETE("/:name", timeout, certHandler.DeleteCertificate)
+				// Custom methods, e.g. POST /{name}:resetUploadStatus or /{name}:diff
+				namespaceCerts.POST("/:name", timeout, certHandler.CertificateAction)
 			}
 		}
+
+		// Admin routes, protected by the admin bearer token
+		if adminToken != "" {
+			admin := v1.Group("/admin", handler.BearerTokenAuth(adminToken), longTimeout)
+			{
+				admin.GET("/cloud-resources", certHandler.ListCloudResources)
+				admin.GET("/providers/health", certHandler.GetProviderHealth)
+			}
+
+			// Custom methods on collections, e.g. POST /certificates:sync
+			v1.POST("/:collection", handler.BearerTokenAuth(adminToken), longTimeout, certHandler.CollectionAction)
+		}
 	}
 
 	return router
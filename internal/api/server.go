@@ -3,9 +3,13 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
 	"time"
 
+	"github.com/tae2089/certificate-operator/internal/api/audit"
+	"github.com/tae2089/certificate-operator/internal/api/handler"
 	"github.com/tae2089/certificate-operator/internal/api/router"
 	"golang.org/x/sync/errgroup"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -16,17 +20,48 @@ var (
 	apiLog = ctrl.Log.WithName("api-server")
 )
 
-// StartAPIServer starts the Gin API server using errgroup for proper error handling
-func StartAPIServer(ctx context.Context, k8sClient client.Client, port string) error {
-	r := router.SetupRouter(k8sClient)
+// StartAPIServer starts the Gin API server using errgroup for proper error handling.
+// Streamed lists are paginated through apiReader and mutating requests are recorded to
+// auditSink unless it is nil, trusting the X-Remote-User and X-Forwarded-For headers of
+// trustedProxies only. List responses are cached for listCacheTTL, 0 disables the cache.
+// Certificate changes are streamed through watcher, nil disables the watch endpoint.
+// Spec changes are previewed through planner, nil disables the diff endpoint.
+// The admin endpoints require adminToken and are disabled when it is empty.
+// Certificates are reported with the cert-manager Certificate and TLS Secret names the
+// operator derives with certificateNameSuffix and secretNameSuffix, empty for the defaults.
+// Requests are bounded by requestTimeout, or longRequestTimeout for the ones that read or
+// change every Certificate, 0 disables a timeout.
+func StartAPIServer(
+	ctx context.Context,
+	k8sClient client.Client,
+	apiReader client.Reader,
+	watcher client.WithWatch,
+	planner handler.SpecChangePlanner,
+	port string,
+	auditSink audit.Sink,
+	trustedProxies []netip.Prefix,
+	listCacheTTL time.Duration,
+	adminToken string,
+	certificateNameSuffix, secretNameSuffix string,
+	requestTimeout, longRequestTimeout time.Duration,
+) error {
+	r := router.SetupRouter(k8sClient, apiReader, watcher, planner, auditSink, trustedProxies, listCacheTTL, adminToken,
+		certificateNameSuffix, secretNameSuffix, requestTimeout, longRequestTimeout)
 
+	// Watch streams only end with their request, so cancel the requests once shutdown starts
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
+	// Routes with a timeout extend the write deadline to cover it
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", port),
 		Handler:      r,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
+		BaseContext:  func(net.Listener) context.Context { return requestCtx },
 	}
+	srv.RegisterOnShutdown(cancelRequests)
 
 	g, gCtx := errgroup.WithContext(ctx)
 
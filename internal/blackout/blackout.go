@@ -0,0 +1,217 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package blackout parses recurring blackout windows such as "Mon-Fri 09:00-18:00" and
+// tells whether a time falls into one of them.
+package blackout
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxMergedWindows bounds how many back-to-back windows End follows, so windows covering
+// the whole week don't loop forever
+const maxMergedWindows = 16
+
+// weekdays maps the accepted day names to their weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Window is a daily time range on a set of weekdays. A window whose end is before its start
+// runs past midnight into the next day, its weekdays are the days it starts on.
+type Window struct {
+	days        [7]bool
+	startMinute int
+	endMinute   int
+}
+
+// Parse parses a window written as "[days ]HH:MM-HH:MM", e.g. "Mon-Fri 09:00-18:00",
+// "Sat 00:00-24:00", or "22:00-06:00" for every day. days is a day name or a range of day
+// names, ranges may wrap around the week as in "Fri-Mon".
+func Parse(s string) (Window, error) {
+	var w Window
+	fields := strings.Fields(s)
+	var timeRange string
+	switch len(fields) {
+	case 1:
+		for day := range w.days {
+			w.days[day] = true
+		}
+		timeRange = fields[0]
+	case 2:
+		if err := w.parseDays(fields[0]); err != nil {
+			return Window{}, fmt.Errorf("invalid blackout window %q: %w", s, err)
+		}
+		timeRange = fields[1]
+	default:
+		return Window{}, fmt.Errorf("invalid blackout window %q, expected [days ]HH:MM-HH:MM", s)
+	}
+
+	start, end, ok := strings.Cut(timeRange, "-")
+	if !ok {
+		return Window{}, fmt.Errorf("invalid blackout window %q, expected [days ]HH:MM-HH:MM", s)
+	}
+	var err error
+	if w.startMinute, err = parseClock(start, false); err != nil {
+		return Window{}, fmt.Errorf("invalid blackout window %q: %w", s, err)
+	}
+	if w.endMinute, err = parseClock(end, true); err != nil {
+		return Window{}, fmt.Errorf("invalid blackout window %q: %w", s, err)
+	}
+	if w.startMinute == w.endMinute {
+		return Window{}, fmt.Errorf("invalid blackout window %q: start and end are the same", s)
+	}
+	return w, nil
+}
+
+// parseDays sets the days of a day name or a range of day names
+func (w *Window) parseDays(s string) error {
+	first, last, isRange := strings.Cut(strings.ToLower(s), "-")
+	from, ok := weekdays[first]
+	if !ok {
+		return fmt.Errorf("unknown day %q", first)
+	}
+	to := from
+	if isRange {
+		if to, ok = weekdays[last]; !ok {
+			return fmt.Errorf("unknown day %q", last)
+		}
+	}
+	for day := from; ; day = (day + 1) % 7 {
+		w.days[day] = true
+		if day == to {
+			return nil
+		}
+	}
+}
+
+// parseClock returns the minutes since midnight of HH:MM, allowing 24:00 as an end
+func parseClock(s string, end bool) (int, error) {
+	hours, minutes, ok := strings.Cut(s, ":")
+	if !ok || len(hours) != 2 || len(minutes) != 2 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	if h == 24 && m == 0 && end {
+		return 24 * 60, nil
+	}
+	if h < 0 || h > 23 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return h*60 + m, nil
+}
+
+// end returns when the occurrence of the window covering t ends, and false when t is outside it
+func (w Window) end(t time.Time) (time.Time, bool) {
+	// An occurrence running past midnight may have started the day before
+	for _, daysBack := range []int{0, 1} {
+		day := t.AddDate(0, 0, -daysBack)
+		if !w.days[day.Weekday()] {
+			continue
+		}
+		start := clockTime(day, w.startMinute)
+		end := clockTime(day, w.endMinute)
+		if w.endMinute < w.startMinute {
+			end = clockTime(day.AddDate(0, 0, 1), w.endMinute)
+		}
+		if !t.Before(start) && t.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// clockTime returns the time minute minutes into the day of t, in t's location
+func clockTime(t time.Time, minute int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, minute, 0, 0, t.Location())
+}
+
+// Schedule is a set of blackout windows in a time zone. A nil Schedule has no windows.
+type Schedule struct {
+	windows  []Window
+	location *time.Location
+}
+
+// NewSchedule parses windows in the IANA time zone timeZone, UTC when empty. It returns a
+// nil Schedule when there are no windows.
+func NewSchedule(windows []string, timeZone string) (*Schedule, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blackout time zone %q: %w", timeZone, err)
+	}
+	if len(windows) == 0 {
+		return nil, nil
+	}
+	schedule := &Schedule{location: location}
+	for _, s := range windows {
+		w, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		schedule.windows = append(schedule.windows, w)
+	}
+	return schedule, nil
+}
+
+// End returns when the blackout covering t ends, and false when t is outside every window.
+// Windows that overlap or follow each other without a gap count as one blackout.
+func (s *Schedule) End(t time.Time) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	t = t.In(s.location)
+
+	end, active := s.windowEnd(t)
+	if !active {
+		return time.Time{}, false
+	}
+	for range maxMergedWindows {
+		next, ok := s.windowEnd(end)
+		if !ok {
+			break
+		}
+		end = next
+	}
+	return end, true
+}
+
+// windowEnd returns the latest end of the windows covering t
+func (s *Schedule) windowEnd(t time.Time) (time.Time, bool) {
+	var latest time.Time
+	for _, w := range s.windows {
+		if end, ok := w.end(t); ok && end.After(latest) {
+			latest = end
+		}
+	}
+	return latest, !latest.IsZero()
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blackout
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Blackout windows", func() {
+	// Monday 2025-06-02
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2025, time.June, 2, hour, minute, 0, 0, time.UTC)
+	}
+
+	newSchedule := func(timeZone string, windows ...string) *Schedule {
+		schedule, err := NewSchedule(windows, timeZone)
+		Expect(err).NotTo(HaveOccurred())
+		return schedule
+	}
+
+	It("should cover the window on its days only", func() {
+		schedule := newSchedule("", "Mon-Fri 09:00-18:00")
+
+		end, ok := schedule.End(monday(9, 0))
+		Expect(ok).To(BeTrue())
+		Expect(end).To(Equal(monday(18, 0)))
+
+		_, ok = schedule.End(monday(8, 59))
+		Expect(ok).To(BeFalse())
+		_, ok = schedule.End(monday(18, 0))
+		Expect(ok).To(BeFalse())
+		_, ok = schedule.End(monday(12, 0).AddDate(0, 0, 5))
+		Expect(ok).To(BeFalse(), "Saturday")
+	})
+
+	It("should run windows past midnight into the next day", func() {
+		schedule := newSchedule("", "Sun 22:00-06:00")
+
+		end, ok := schedule.End(monday(5, 0))
+		Expect(ok).To(BeTrue())
+		Expect(end).To(Equal(monday(6, 0)))
+
+		end, ok = schedule.End(monday(23, 0).AddDate(0, 0, -1))
+		Expect(ok).To(BeTrue())
+		Expect(end).To(Equal(monday(6, 0)))
+
+		_, ok = schedule.End(monday(23, 0))
+		Expect(ok).To(BeFalse(), "the window starts on Sundays only")
+	})
+
+	It("should treat back-to-back windows as one blackout", func() {
+		schedule := newSchedule("", "Sat-Sun 00:00-24:00", "Mon 00:00-08:00")
+
+		end, ok := schedule.End(monday(10, 0).AddDate(0, 0, -2))
+		Expect(ok).To(BeTrue())
+		Expect(end).To(Equal(monday(8, 0)))
+	})
+
+	It("should stop following windows that cover the whole week", func() {
+		schedule := newSchedule("", "00:00-24:00")
+
+		end, ok := schedule.End(monday(10, 0))
+		Expect(ok).To(BeTrue())
+		Expect(end).To(BeTemporally(">", monday(10, 0)))
+	})
+
+	It("should interpret the windows in the time zone", func() {
+		schedule := newSchedule("Asia/Seoul", "Mon 09:00-18:00")
+
+		end, ok := schedule.End(monday(0, 30))
+		Expect(ok).To(BeTrue(), "09:30 in Seoul")
+		Expect(end).To(BeTemporally("==", monday(9, 0)))
+
+		_, ok = schedule.End(monday(10, 0))
+		Expect(ok).To(BeFalse(), "19:00 in Seoul")
+	})
+
+	It("should wrap day ranges around the week", func() {
+		schedule := newSchedule("", "Fri-Mon 12:00-13:00")
+
+		_, ok := schedule.End(monday(12, 30))
+		Expect(ok).To(BeTrue())
+		_, ok = schedule.End(monday(12, 30).AddDate(0, 0, 1))
+		Expect(ok).To(BeFalse(), "Tuesday")
+	})
+
+	It("should have no windows without any", func() {
+		schedule := newSchedule("Europe/Berlin")
+		Expect(schedule).To(BeNil())
+		_, ok := schedule.End(monday(12, 0))
+		Expect(ok).To(BeFalse())
+	})
+
+	DescribeTable("rejecting invalid windows",
+		func(window, message string) {
+			_, err := Parse(window)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("missing end", "Mon 09:00", "expected [days ]HH:MM-HH:MM"),
+		Entry("unknown day", "Monday 09:00-18:00", "unknown day"),
+		Entry("unknown range end", "Mon-Fr 09:00-18:00", "unknown day"),
+		Entry("hour out of range", "25:00-26:00", "invalid time"),
+		Entry("24:00 as start", "24:00-06:00", "invalid time"),
+		Entry("single-digit hour", "9:00-18:00", "invalid time"),
+		Entry("empty window", "09:00-09:00", "start and end are the same"),
+		Entry("extra fields", "Mon Tue 09:00-18:00", "expected [days ]HH:MM-HH:MM"),
+	)
+
+	It("should reject an unknown time zone", func() {
+		_, err := NewSchedule([]string{"09:00-18:00"}, "Mars/Olympus")
+		Expect(err).To(MatchError(ContainSubstring("invalid blackout time zone")))
+	})
+})
@@ -0,0 +1,558 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config loads the operator settings from an optional YAML file and command-line flags.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/api/audit"
+	"github.com/tae2089/certificate-operator/internal/blackout"
+)
+
+// OperatorConfig holds the operator settings. Values are read from the file passed
+// with --config; flags set explicitly on the command line override file values.
+type OperatorConfig struct {
+	// CredentialsNamespace is where Cloudflare/AWS credential Secrets are read from.
+	// Empty means each Certificate's own namespace.
+	CredentialsNamespace string `json:"credentialsNamespace,omitempty"`
+
+	// InstanceID limits the operator to the Certificates labeled certificate.println.kr/instance
+	// with this value, so several operators can run in one cluster. Empty means the
+	// Certificates without the label.
+	InstanceID string `json:"instanceID,omitempty"`
+
+	// ManagedBy is the app.kubernetes.io/managed-by label value of the cert-manager
+	// Certificates the operator creates. With an instance ID, the ID is appended as
+	// "<managedBy>-<instanceID>".
+	ManagedBy string `json:"managedBy,omitempty"`
+
+	// CertificateNameSuffix and SecretNameSuffix are appended to the name of a Certificate to
+	// name its cert-manager Certificate and TLS Secret. Changing them makes the operator issue
+	// new ones for every Certificate.
+	CertificateNameSuffix string `json:"certificateNameSuffix,omitempty"`
+	SecretNameSuffix      string `json:"secretNameSuffix,omitempty"`
+
+	// Controller configures the Certificate reconciler
+	Controller ControllerConfig `json:"controller"`
+
+	// Providers configures the cloud provider drivers
+	Providers ProvidersConfig `json:"providers"`
+
+	// APIServer configures the REST API server
+	APIServer APIServerConfig `json:"apiServer"`
+
+	// Metrics configures the metrics endpoint
+	Metrics MetricsConfig `json:"metrics"`
+}
+
+// ControllerConfig configures the Certificate reconciler
+type ControllerConfig struct {
+	// FinalizeRetryInterval is how long to wait before retrying a failed cloud cleanup
+	FinalizeRetryInterval metav1.Duration `json:"finalizeRetryInterval"`
+
+	// ReconcileLagThreshold reports unready when a reconcile has been running for this long.
+	// Failing reconciles return and don't count. Zero disables the check.
+	ReconcileLagThreshold metav1.Duration `json:"reconcileLagThreshold"`
+
+	// MaxConcurrentReconciles is how many Certificates are reconciled at the same time
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles"`
+
+	// WatchIngresses creates a Certificate for each TLS host of Ingresses annotated with
+	// certificate.println.kr/ingress-certificates
+	WatchIngresses bool `json:"watchIngresses"`
+
+	// DNSResolver is the host:port of the DNS server the spec.dnsCheck lookups of
+	// Certificates are sent to. Empty means the system resolver.
+	DNSResolver string `json:"dnsResolver,omitempty"`
+}
+
+// ProvidersConfig configures the cloud provider drivers
+type ProvidersConfig struct {
+	// MaxRetries is how many times an upload is retried on throttling or 5xx errors
+	MaxRetries int `json:"maxRetries"`
+
+	// ShutdownGracePeriod is how long in-flight uploads may run after shutdown starts
+	ShutdownGracePeriod metav1.Duration `json:"shutdownGracePeriod"`
+
+	// MaxConcurrentUploads caps the uploads in flight to each provider, keyed by provider name.
+	// Providers that are not listed are only limited by the concurrent reconciles.
+	MaxConcurrentUploads map[string]int `json:"maxConcurrentUploads,omitempty"`
+
+	// RenewalUploadWindow spreads the re-uploads of renewed certificates over this window, so
+	// certificates renewed in a batch don't hit provider rate limits. Zero disables it.
+	RenewalUploadWindow metav1.Duration `json:"renewalUploadWindow"`
+
+	// VerifyUploads reads each uploaded certificate back from AWS ACM and Cloudflare and
+	// compares it with the upload
+	VerifyUploads bool `json:"verifyUploads"`
+
+	// DeleteDisabledUploads deletes the Cloudflare certificate of a Certificate once its
+	// spec.cloudflareEnabled is set to false and clears the ID from its status
+	DeleteDisabledUploads bool `json:"deleteDisabledUploads"`
+
+	// DefaultAWSRegion is the region of AWS ACM and S3 requests when neither the credentials
+	// Secret nor the operator's environment sets one
+	DefaultAWSRegion string `json:"defaultAWSRegion,omitempty"`
+
+	// CircuitBreakerThreshold is the number of uploads to a provider that must fail in a row
+	// for a Certificate before uploads to it are paused for CircuitBreakerCooldown. 0 disables
+	// the circuit breaker.
+	CircuitBreakerThreshold int `json:"circuitBreakerThreshold"`
+
+	// CircuitBreakerCooldown is how long uploads to a provider are paused once its circuit
+	// breaker opened, before a single upload tests whether it recovered
+	CircuitBreakerCooldown metav1.Duration `json:"circuitBreakerCooldown"`
+
+	// TagLabelPrefix, when set, turns each label of a Certificate starting with it into a
+	// provider tag keyed by the rest of the label key. spec.providerTags take precedence.
+	TagLabelPrefix string `json:"tagLabelPrefix,omitempty"`
+
+	// UploadBlackout holds uploads back during recurring windows, e.g. business hours in
+	// which production changes are forbidden
+	UploadBlackout UploadBlackoutConfig `json:"uploadBlackout"`
+
+	// Disabled lists providers nothing is uploaded to, e.g. during a provider incident.
+	// Cleanup of deleted Certificates still runs against them.
+	Disabled []string `json:"disabled,omitempty"`
+}
+
+// UploadBlackoutConfig configures the windows in which nothing is uploaded to providers.
+// cert-manager keeps issuing and renewing certificates during them.
+type UploadBlackoutConfig struct {
+	// Windows are recurring windows written as "[days ]HH:MM-HH:MM", e.g. "Mon-Fri 09:00-18:00".
+	// A window whose end is before its start runs past midnight.
+	Windows []string `json:"windows,omitempty"`
+
+	// TimeZone is the IANA time zone of the windows, e.g. "Europe/Berlin". Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// DefaultManagedBy is the default managed-by label value of the cert-manager Certificates
+const DefaultManagedBy = "certificate-operator"
+
+// ProviderNames are the provider names accepted in ProvidersConfig.MaxConcurrentUploads
+// and ProvidersConfig.Disabled
+var ProviderNames = []string{"akamai", "aws", "cloudflare", "remote-cluster", "s3"}
+
+// APIServerConfig configures the REST API server
+type APIServerConfig struct {
+	// Enabled starts the REST API server
+	Enabled bool `json:"enabled"`
+
+	// Port is the port the REST API server listens on
+	Port string `json:"port"`
+
+	// Audit configures the audit log of mutating API requests
+	Audit AuditConfig `json:"audit"`
+
+	// ListCacheTTL is how long Certificate list responses are cached, so frequent polling
+	// doesn't read every Certificate each time. Writes through the API invalidate the cache.
+	// 0 disables it.
+	ListCacheTTL metav1.Duration `json:"listCacheTTL,omitempty"`
+
+	// AdminTokenFile is a file holding the bearer token required by the /api/v1/admin
+	// endpoints. The admin endpoints are disabled when it isn't set.
+	AdminTokenFile string `json:"adminTokenFile,omitempty"`
+
+	// RequestTimeout is how long a request for a single Certificate may take. 0 disables it.
+	RequestTimeout metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// LongRequestTimeout is how long the requests that read or change every Certificate
+	// may take: lists, exports, batch deletes, syncs, and the admin endpoints. Watches
+	// aren't bounded. 0 disables it.
+	LongRequestTimeout metav1.Duration `json:"longRequestTimeout,omitempty"`
+
+	// TrustedProxies are the IP addresses and CIDR ranges of the authenticating proxies in
+	// front of the API server. Only their X-Remote-User and X-Forwarded-For headers are
+	// trusted for the principal and source IP of audit entries. Empty trusts no proxy.
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
+}
+
+// MetricsConfig configures the metrics endpoint
+type MetricsConfig struct {
+	// BindAddress is the address the metrics endpoint binds to. "0" disables it.
+	BindAddress string `json:"bindAddress"`
+
+	// BearerTokenFile, when set, is a file holding the token scrapers must send as a
+	// bearer token. It replaces the Kubernetes authn/authz check of the secure endpoint.
+	BearerTokenFile string `json:"bearerTokenFile,omitempty"`
+}
+
+// AuditConfig configures where audit entries of mutating API requests are written
+type AuditConfig struct {
+	// Sink is one of "none", "log", or "file"
+	Sink string `json:"sink"`
+
+	// File is the path audit entries are appended to when Sink is "file"
+	File string `json:"file,omitempty"`
+}
+
+// NewOperatorConfig returns the configuration used when neither a file nor flags set a value
+func NewOperatorConfig() *OperatorConfig {
+	return &OperatorConfig{
+		ManagedBy:             DefaultManagedBy,
+		CertificateNameSuffix: certificatev1alpha1.DefaultCertificateNameSuffix,
+		SecretNameSuffix:      certificatev1alpha1.DefaultSecretNameSuffix,
+		Controller: ControllerConfig{
+			FinalizeRetryInterval:   metav1.Duration{Duration: 30 * time.Second},
+			ReconcileLagThreshold:   metav1.Duration{Duration: 15 * time.Minute},
+			MaxConcurrentReconciles: 1,
+		},
+		Providers: ProvidersConfig{
+			MaxRetries:              3,
+			ShutdownGracePeriod:     metav1.Duration{Duration: 25 * time.Second},
+			CircuitBreakerThreshold: 5,
+			CircuitBreakerCooldown:  metav1.Duration{Duration: 30 * time.Minute},
+		},
+		APIServer: APIServerConfig{
+			Enabled: true,
+			Port:    "8080",
+			Audit: AuditConfig{
+				Sink: "log",
+			},
+			RequestTimeout:     metav1.Duration{Duration: 15 * time.Second},
+			LongRequestTimeout: metav1.Duration{Duration: 5 * time.Minute},
+		},
+		Metrics: MetricsConfig{
+			BindAddress: "0",
+		},
+	}
+}
+
+// BindFlags registers the flags backed by this configuration, using its current values as defaults
+func (c *OperatorConfig) BindFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.APIServer.Enabled, "enable-api-server", c.APIServer.Enabled,
+		"Enable the REST API server for Certificate CRUD operations")
+	fs.StringVar(&c.APIServer.Port, "api-server-port", c.APIServer.Port,
+		"The port on which the REST API server will listen")
+	fs.StringVar(&c.APIServer.Audit.Sink, "api-audit-sink", c.APIServer.Audit.Sink,
+		"Where to record mutating REST API requests: none, log, or file")
+	fs.StringVar(&c.APIServer.Audit.File, "api-audit-file", c.APIServer.Audit.File,
+		"The file audit entries are appended to when --api-audit-sink=file")
+	fs.DurationVar(&c.APIServer.ListCacheTTL.Duration, "api-list-cache-ttl", c.APIServer.ListCacheTTL.Duration,
+		"How long REST API list responses are cached. Writes through the API invalidate the cache. Set to 0 to disable.")
+	fs.StringVar(&c.APIServer.AdminTokenFile, "api-admin-token-file", c.APIServer.AdminTokenFile,
+		"A file holding the bearer token required by the REST API admin endpoints. The admin endpoints are disabled without it.")
+	fs.DurationVar(&c.APIServer.RequestTimeout.Duration, "api-request-timeout", c.APIServer.RequestTimeout.Duration,
+		"How long a REST API request for a single Certificate may take. Set to 0 to disable.")
+	fs.DurationVar(&c.APIServer.LongRequestTimeout.Duration, "api-long-request-timeout",
+		c.APIServer.LongRequestTimeout.Duration,
+		"How long REST API lists, exports, batch deletes, syncs, and admin requests may take. Set to 0 to disable.")
+	fs.Var(&listValue{values: &c.APIServer.TrustedProxies}, "api-trusted-proxies",
+		"Comma-separated IP addresses and CIDR ranges of authenticating proxies whose X-Remote-User and X-Forwarded-For headers the REST API trusts")
+	fs.DurationVar(&c.Controller.FinalizeRetryInterval.Duration, "finalize-retry-interval",
+		c.Controller.FinalizeRetryInterval.Duration,
+		"How long to wait before retrying cloud cleanup when deleting a Certificate fails")
+	fs.DurationVar(&c.Controller.ReconcileLagThreshold.Duration, "reconcile-lag-threshold",
+		c.Controller.ReconcileLagThreshold.Duration,
+		"Report unready when a reconcile has been running for this long. Set to 0 to disable.")
+	fs.IntVar(&c.Controller.MaxConcurrentReconciles, "max-concurrent-reconciles", c.Controller.MaxConcurrentReconciles,
+		"How many Certificates are reconciled at the same time")
+	fs.BoolVar(&c.Controller.WatchIngresses, "watch-ingresses", c.Controller.WatchIngresses,
+		"Create a Certificate for each TLS host of Ingresses annotated with certificate.println.kr/ingress-certificates")
+	fs.StringVar(&c.Controller.DNSResolver, "dns-resolver", c.Controller.DNSResolver,
+		"The host:port of the DNS server used for the spec.dnsCheck of Certificates, e.g. 1.1.1.1:53. "+
+			"Defaults to the system resolver.")
+	fs.StringVar(&c.InstanceID, "instance-id", c.InstanceID,
+		"Only reconcile Certificates labeled certificate.println.kr/instance with this value. "+
+			"Defaults to the Certificates without the label.")
+	fs.StringVar(&c.ManagedBy, "managed-by", c.ManagedBy,
+		"The app.kubernetes.io/managed-by label value of the cert-manager Certificates the operator creates. "+
+			"With --instance-id, the instance ID is appended.")
+	fs.StringVar(&c.CertificateNameSuffix, "certificate-name-suffix", c.CertificateNameSuffix,
+		"Appended to the name of a Certificate to name its cert-manager Certificate")
+	fs.StringVar(&c.SecretNameSuffix, "secret-name-suffix", c.SecretNameSuffix,
+		"Appended to the name of a Certificate to name its TLS Secret")
+	fs.StringVar(&c.CredentialsNamespace, "credentials-namespace", c.CredentialsNamespace,
+		"Namespace holding the Cloudflare/AWS credential Secrets. Defaults to each Certificate's namespace.")
+	fs.IntVar(&c.Providers.MaxRetries, "provider-max-retries", c.Providers.MaxRetries,
+		"How many times a Cloudflare/AWS upload is retried with backoff on throttling or 5xx errors")
+	fs.DurationVar(&c.Providers.ShutdownGracePeriod.Duration, "provider-shutdown-grace-period",
+		c.Providers.ShutdownGracePeriod.Duration,
+		"How long in-flight Cloudflare/AWS uploads may run after the operator starts shutting down")
+	fs.Var(&uploadLimitsValue{limits: &c.Providers.MaxConcurrentUploads}, "provider-max-concurrent-uploads",
+		"Caps the uploads in flight per provider, e.g. aws=2,cloudflare=4. Providers: "+strings.Join(ProviderNames, ", "))
+	fs.DurationVar(&c.Providers.RenewalUploadWindow.Duration, "renewal-upload-window",
+		c.Providers.RenewalUploadWindow.Duration,
+		"Spreads the re-uploads of renewed certificates over this window. Set to 0 to upload them as soon as they are issued.")
+	fs.BoolVar(&c.Providers.VerifyUploads, "verify-uploads", c.Providers.VerifyUploads,
+		"Read each uploaded certificate back from AWS ACM and Cloudflare and report copies that differ "+
+			"in the VerificationFailed condition")
+	fs.BoolVar(&c.Providers.DeleteDisabledUploads, "delete-disabled-uploads", c.Providers.DeleteDisabledUploads,
+		"Delete the Cloudflare certificate of a Certificate once its spec.cloudflareEnabled is set to false, "+
+			"instead of leaving the last upload in place")
+	fs.StringVar(&c.Providers.DefaultAWSRegion, "default-aws-region", c.Providers.DefaultAWSRegion,
+		"The AWS region of ACM imports and S3 writes whose credentials Secret and environment set none")
+	fs.IntVar(&c.Providers.CircuitBreakerThreshold, "provider-circuit-breaker-threshold",
+		c.Providers.CircuitBreakerThreshold,
+		"Pause uploads to a provider after this many consecutive failures for a Certificate. Set to 0 to disable.")
+	fs.DurationVar(&c.Providers.CircuitBreakerCooldown.Duration, "provider-circuit-breaker-cooldown",
+		c.Providers.CircuitBreakerCooldown.Duration,
+		"How long uploads to a provider stay paused before a single upload tests whether it recovered")
+	fs.StringVar(&c.Providers.TagLabelPrefix, "provider-tag-label-prefix", c.Providers.TagLabelPrefix,
+		"Set the labels of a Certificate starting with this prefix, e.g. tags.example.com/, as provider tags "+
+			"keyed by the rest of the label key")
+	fs.Var(&listValue{values: &c.Providers.UploadBlackout.Windows}, "upload-blackout-windows",
+		"Comma-separated windows in which uploads to providers wait, e.g. \"Mon-Fri 09:00-18:00\". "+
+			"Issuance continues.")
+	fs.StringVar(&c.Providers.UploadBlackout.TimeZone, "upload-blackout-timezone", c.Providers.UploadBlackout.TimeZone,
+		"The IANA time zone of --upload-blackout-windows, e.g. Europe/Berlin. Defaults to UTC.")
+	fs.Var(&listValue{values: &c.Providers.Disabled}, "disabled-providers",
+		"Comma-separated providers nothing is uploaded to, e.g. aws. Cleanup still runs. Providers: "+
+			strings.Join(ProviderNames, ", "))
+	fs.StringVar(&c.Metrics.BindAddress, "metrics-bind-address", c.Metrics.BindAddress,
+		"The address the metrics endpoint binds to. "+
+			"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
+	fs.StringVar(&c.Metrics.BearerTokenFile, "metrics-bearer-token-file", c.Metrics.BearerTokenFile,
+		"A file holding the bearer token required to scrape the metrics endpoint. "+
+			"Replaces the Kubernetes authn/authz check of --metrics-secure.")
+}
+
+// LoadFile reads the YAML file at path into the configuration. Flags that were set
+// explicitly on fs keep their command-line values. Unknown fields are rejected.
+func (c *OperatorConfig) LoadFile(path string, fs *flag.FlagSet) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// Remember explicitly set flags, the file would otherwise overwrite them
+	setFlags := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = f.Value.String()
+	})
+
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	for name, value := range setFlags {
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("failed to apply flag --%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the configuration for invalid values
+func (c *OperatorConfig) Validate() error {
+	if c.CredentialsNamespace != "" {
+		if errs := validation.IsDNS1123Label(c.CredentialsNamespace); len(errs) > 0 {
+			return fmt.Errorf("invalid credentialsNamespace %q: %s", c.CredentialsNamespace, errs[0])
+		}
+	}
+	if c.ManagedBy == "" {
+		return fmt.Errorf("managedBy must not be empty")
+	}
+	if errs := validation.IsValidLabelValue(c.ManagedBy); len(errs) > 0 {
+		return fmt.Errorf("invalid managedBy %q: %s", c.ManagedBy, errs[0])
+	}
+	for _, name := range []struct{ field, suffix string }{
+		{"certificateNameSuffix", c.CertificateNameSuffix},
+		{"secretNameSuffix", c.SecretNameSuffix},
+	} {
+		field, suffix := name.field, name.suffix
+		if suffix == "" {
+			return fmt.Errorf("%s must not be empty", field)
+		}
+		// The suffix is appended to a Certificate name, which is a DNS subdomain itself
+		if errs := validation.IsDNS1123Subdomain("a" + suffix); len(errs) > 0 {
+			return fmt.Errorf("invalid %s %q: %s", field, suffix, errs[0])
+		}
+	}
+	if c.InstanceID != "" {
+		// The ID is also part of the managed-by label value, which is limited to 63 characters
+		if errs := validation.IsDNS1123Label(c.InstanceID); len(errs) > 0 {
+			return fmt.Errorf("invalid instanceID %q: %s", c.InstanceID, errs[0])
+		}
+		if maxLength := validation.LabelValueMaxLength - len(c.ManagedBy) - 1; len(c.InstanceID) > maxLength {
+			return fmt.Errorf("instanceID must be at most %d characters with managedBy %q", maxLength, c.ManagedBy)
+		}
+	}
+
+	if c.Controller.FinalizeRetryInterval.Duration <= 0 {
+		return fmt.Errorf("controller.finalizeRetryInterval must be positive")
+	}
+	if c.Controller.ReconcileLagThreshold.Duration < 0 {
+		return fmt.Errorf("controller.reconcileLagThreshold must not be negative")
+	}
+	if c.Controller.MaxConcurrentReconciles < 1 {
+		return fmt.Errorf("controller.maxConcurrentReconciles must be at least 1")
+	}
+	if c.Controller.DNSResolver != "" {
+		host, port, err := net.SplitHostPort(c.Controller.DNSResolver)
+		if err != nil || host == "" {
+			return fmt.Errorf("invalid controller.dnsResolver %q, expected host:port", c.Controller.DNSResolver)
+		}
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("invalid controller.dnsResolver port %q", port)
+		}
+	}
+
+	if c.Providers.MaxRetries < 0 {
+		return fmt.Errorf("providers.maxRetries must not be negative")
+	}
+	if c.Providers.ShutdownGracePeriod.Duration < 0 {
+		return fmt.Errorf("providers.shutdownGracePeriod must not be negative")
+	}
+	if c.Providers.RenewalUploadWindow.Duration < 0 {
+		return fmt.Errorf("providers.renewalUploadWindow must not be negative")
+	}
+	for _, provider := range slices.Sorted(maps.Keys(c.Providers.MaxConcurrentUploads)) {
+		if !slices.Contains(ProviderNames, provider) {
+			return fmt.Errorf("unknown provider %q in providers.maxConcurrentUploads (supported providers: %s)",
+				provider, strings.Join(ProviderNames, ", "))
+		}
+		if c.Providers.MaxConcurrentUploads[provider] < 1 {
+			return fmt.Errorf("providers.maxConcurrentUploads.%s must be at least 1", provider)
+		}
+	}
+	if c.Providers.CircuitBreakerThreshold < 0 {
+		return fmt.Errorf("providers.circuitBreakerThreshold must not be negative")
+	}
+	if c.Providers.CircuitBreakerThreshold > 0 && c.Providers.CircuitBreakerCooldown.Duration <= 0 {
+		return fmt.Errorf("providers.circuitBreakerCooldown must be positive")
+	}
+	if c.Providers.TagLabelPrefix != "" {
+		// The prefix must start a valid label key, e.g. tags.example.com/ or tag-
+		if errs := validation.IsQualifiedName(c.Providers.TagLabelPrefix + "x"); len(errs) > 0 {
+			return fmt.Errorf("invalid providers.tagLabelPrefix %q: %s", c.Providers.TagLabelPrefix, strings.Join(errs, ", "))
+		}
+	}
+	if _, err := blackout.NewSchedule(c.Providers.UploadBlackout.Windows, c.Providers.UploadBlackout.TimeZone); err != nil {
+		return fmt.Errorf("invalid providers.uploadBlackout: %w", err)
+	}
+	for _, provider := range c.Providers.Disabled {
+		if !slices.Contains(ProviderNames, provider) {
+			return fmt.Errorf("unknown provider %q in providers.disabled (supported providers: %s)",
+				provider, strings.Join(ProviderNames, ", "))
+		}
+	}
+
+	if c.Metrics.BindAddress == "" {
+		return fmt.Errorf("metrics.bindAddress must not be empty, use \"0\" to disable the metrics endpoint")
+	}
+
+	if c.APIServer.Enabled {
+		port, err := strconv.Atoi(c.APIServer.Port)
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("invalid apiServer.port %q", c.APIServer.Port)
+		}
+
+		switch c.APIServer.Audit.Sink {
+		case "none", "log":
+		case "file":
+			if c.APIServer.Audit.File == "" {
+				return fmt.Errorf("apiServer.audit.file is required when apiServer.audit.sink is \"file\"")
+			}
+		default:
+			return fmt.Errorf("invalid apiServer.audit.sink %q (supported sinks: none, log, file)", c.APIServer.Audit.Sink)
+		}
+		if c.APIServer.ListCacheTTL.Duration < 0 {
+			return fmt.Errorf("apiServer.listCacheTTL must not be negative")
+		}
+		if c.APIServer.RequestTimeout.Duration < 0 {
+			return fmt.Errorf("apiServer.requestTimeout must not be negative")
+		}
+		if c.APIServer.LongRequestTimeout.Duration < 0 {
+			return fmt.Errorf("apiServer.longRequestTimeout must not be negative")
+		}
+		if _, err := audit.ParseTrustedProxies(c.APIServer.TrustedProxies); err != nil {
+			return fmt.Errorf("apiServer.trustedProxies: %w", err)
+		}
+	}
+	return nil
+}
+
+// uploadLimitsValue is a flag.Value of comma-separated provider=limit pairs. Setting it
+// replaces all limits, so re-applying its String value restores the same limits.
+type uploadLimitsValue struct {
+	limits *map[string]int
+}
+
+// String returns the limits sorted by provider
+func (v *uploadLimitsValue) String() string {
+	if v.limits == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(*v.limits))
+	for _, provider := range slices.Sorted(maps.Keys(*v.limits)) {
+		pairs = append(pairs, provider+"="+strconv.Itoa((*v.limits)[provider]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set parses provider=limit pairs
+func (v *uploadLimitsValue) Set(value string) error {
+	limits := map[string]int{}
+	for pair := range strings.SplitSeq(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		provider, limit, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid provider limit %q, expected provider=limit", pair)
+		}
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return fmt.Errorf("invalid limit for provider %s: %w", provider, err)
+		}
+		limits[strings.TrimSpace(provider)] = n
+	}
+	*v.limits = limits
+	return nil
+}
+
+// listValue is a flag.Value of comma-separated values, e.g. provider names. Setting it
+// replaces the list, so re-applying its String value restores the same list.
+type listValue struct {
+	values *[]string
+}
+
+// String returns the values as listed
+func (v *listValue) String() string {
+	if v.values == nil {
+		return ""
+	}
+	return strings.Join(*v.values, ",")
+}
+
+// Set parses the values
+func (v *listValue) Set(value string) error {
+	var values []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	*v.values = values
+	return nil
+}
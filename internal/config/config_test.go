@@ -0,0 +1,242 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OperatorConfig", func() {
+	var (
+		cfg *OperatorConfig
+		fs  *flag.FlagSet
+	)
+
+	BeforeEach(func() {
+		cfg = NewOperatorConfig()
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		cfg.BindFlags(fs)
+	})
+
+	writeConfig := func(content string) string {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+
+	It("should use the defaults without a file or flags", func() {
+		Expect(fs.Parse(nil)).To(Succeed())
+		Expect(cfg.Validate()).To(Succeed())
+		Expect(cfg.APIServer.Enabled).To(BeTrue())
+		Expect(cfg.APIServer.Port).To(Equal("8080"))
+		Expect(cfg.Providers.MaxRetries).To(Equal(3))
+		Expect(cfg.Providers.ShutdownGracePeriod.Duration).To(Equal(25 * time.Second))
+		Expect(cfg.Controller.FinalizeRetryInterval.Duration).To(Equal(30 * time.Second))
+		Expect(cfg.APIServer.Audit.Sink).To(Equal("log"))
+		Expect(cfg.Metrics.BindAddress).To(Equal("0"))
+		Expect(cfg.Metrics.BearerTokenFile).To(BeEmpty())
+		Expect(cfg.Controller.MaxConcurrentReconciles).To(Equal(1))
+		Expect(cfg.Controller.WatchIngresses).To(BeFalse())
+		Expect(cfg.ManagedBy).To(Equal("certificate-operator"))
+		Expect(cfg.CertificateNameSuffix).To(Equal("-cert"))
+		Expect(cfg.SecretNameSuffix).To(Equal("-tls"))
+		Expect(cfg.Controller.DNSResolver).To(BeEmpty())
+		Expect(cfg.Providers.MaxConcurrentUploads).To(BeEmpty())
+		Expect(cfg.Providers.Disabled).To(BeEmpty())
+		Expect(cfg.Providers.RenewalUploadWindow.Duration).To(BeZero())
+		Expect(cfg.Providers.VerifyUploads).To(BeFalse())
+		Expect(cfg.Providers.DeleteDisabledUploads).To(BeFalse())
+		Expect(cfg.Providers.CircuitBreakerThreshold).To(Equal(5))
+		Expect(cfg.Providers.CircuitBreakerCooldown.Duration).To(Equal(30 * time.Minute))
+	})
+
+	It("should read per-provider upload limits from the file and the flag", func() {
+		path := writeConfig(`
+providers:
+  maxConcurrentUploads:
+    aws: 2
+    cloudflare: 8
+`)
+		Expect(fs.Parse(nil)).To(Succeed())
+		Expect(cfg.LoadFile(path, fs)).To(Succeed())
+		Expect(cfg.Validate()).To(Succeed())
+		Expect(cfg.Providers.MaxConcurrentUploads).To(Equal(map[string]int{"aws": 2, "cloudflare": 8}))
+
+		cfg = NewOperatorConfig()
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		cfg.BindFlags(fs)
+		Expect(fs.Parse([]string{"--provider-max-concurrent-uploads=cloudflare=4,aws=1"})).To(Succeed())
+		Expect(cfg.LoadFile(path, fs)).To(Succeed())
+		Expect(cfg.Providers.MaxConcurrentUploads).To(Equal(map[string]int{"aws": 1, "cloudflare": 4}))
+	})
+
+	It("should read disabled providers from the file and the flag", func() {
+		path := writeConfig(`
+providers:
+  disabled:
+  - aws
+`)
+		Expect(fs.Parse(nil)).To(Succeed())
+		Expect(cfg.LoadFile(path, fs)).To(Succeed())
+		Expect(cfg.Validate()).To(Succeed())
+		Expect(cfg.Providers.Disabled).To(Equal([]string{"aws"}))
+
+		cfg = NewOperatorConfig()
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		cfg.BindFlags(fs)
+		Expect(fs.Parse([]string{"--disabled-providers=cloudflare, s3"})).To(Succeed())
+		Expect(cfg.LoadFile(path, fs)).To(Succeed())
+		Expect(cfg.Providers.Disabled).To(Equal([]string{"cloudflare", "s3"}))
+	})
+
+	It("should read upload blackout windows from the file and the flags", func() {
+		path := writeConfig(`
+providers:
+  uploadBlackout:
+    windows:
+    - Mon-Fri 09:00-18:00
+    timeZone: Europe/Berlin
+`)
+		Expect(fs.Parse(nil)).To(Succeed())
+		Expect(cfg.LoadFile(path, fs)).To(Succeed())
+		Expect(cfg.Validate()).To(Succeed())
+		Expect(cfg.Providers.UploadBlackout.Windows).To(Equal([]string{"Mon-Fri 09:00-18:00"}))
+		Expect(cfg.Providers.UploadBlackout.TimeZone).To(Equal("Europe/Berlin"))
+
+		cfg = NewOperatorConfig()
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		cfg.BindFlags(fs)
+		Expect(fs.Parse([]string{"--upload-blackout-windows=Mon-Fri 09:00-18:00, Sat 10:00-12:00"})).To(Succeed())
+		Expect(cfg.LoadFile(path, fs)).To(Succeed())
+		Expect(cfg.Validate()).To(Succeed())
+		Expect(cfg.Providers.UploadBlackout.Windows).To(Equal([]string{"Mon-Fri 09:00-18:00", "Sat 10:00-12:00"}))
+	})
+
+	It("should reject a malformed upload limit flag", func() {
+		Expect(fs.Parse([]string{"--provider-max-concurrent-uploads=aws"})).To(MatchError(ContainSubstring("provider=limit")))
+	})
+
+	It("should parse the YAML file and keep defaults for unset fields", func() {
+		path := writeConfig(`
+credentialsNamespace: certificate-credentials
+controller:
+  finalizeRetryInterval: 1m
+providers:
+  maxRetries: 5
+apiServer:
+  port: "9090"
+  longRequestTimeout: 10m
+`)
+		Expect(fs.Parse(nil)).To(Succeed())
+		Expect(cfg.LoadFile(path, fs)).To(Succeed())
+		Expect(cfg.Validate()).To(Succeed())
+
+		Expect(cfg.CredentialsNamespace).To(Equal("certificate-credentials"))
+		Expect(cfg.Controller.FinalizeRetryInterval.Duration).To(Equal(time.Minute))
+		Expect(cfg.Controller.ReconcileLagThreshold.Duration).To(Equal(15 * time.Minute))
+		Expect(cfg.Providers.MaxRetries).To(Equal(5))
+		Expect(cfg.APIServer.Enabled).To(BeTrue())
+		Expect(cfg.APIServer.Port).To(Equal("9090"))
+		Expect(cfg.APIServer.RequestTimeout.Duration).To(Equal(15 * time.Second))
+		Expect(cfg.APIServer.LongRequestTimeout.Duration).To(Equal(10 * time.Minute))
+	})
+
+	It("should let explicitly set flags override file values", func() {
+		path := writeConfig(`
+credentialsNamespace: from-file
+providers:
+  maxRetries: 5
+apiServer:
+  enabled: true
+  port: "9090"
+`)
+		Expect(fs.Parse([]string{"--provider-max-retries=1", "--enable-api-server=false"})).To(Succeed())
+		Expect(cfg.LoadFile(path, fs)).To(Succeed())
+
+		Expect(cfg.Providers.MaxRetries).To(Equal(1))
+		Expect(cfg.APIServer.Enabled).To(BeFalse())
+		Expect(cfg.CredentialsNamespace).To(Equal("from-file"))
+		Expect(cfg.APIServer.Port).To(Equal("9090"))
+	})
+
+	It("should reject unknown fields", func() {
+		path := writeConfig("providers:\n  maxRetry: 5\n")
+		Expect(fs.Parse(nil)).To(Succeed())
+		Expect(cfg.LoadFile(path, fs)).To(MatchError(ContainSubstring("maxRetry")))
+	})
+
+	It("should fail when the file does not exist", func() {
+		Expect(cfg.LoadFile(filepath.Join(GinkgoT().TempDir(), "missing.yaml"), fs)).NotTo(Succeed())
+	})
+
+	DescribeTable("validation",
+		func(mutate func(*OperatorConfig), errSubstring string) {
+			mutate(cfg)
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring(errSubstring)))
+		},
+		Entry("invalid credentials namespace", func(c *OperatorConfig) { c.CredentialsNamespace = "Not_Valid" }, "credentialsNamespace"),
+		Entry("invalid instance ID", func(c *OperatorConfig) { c.InstanceID = "Team_A" }, "instanceID"),
+		Entry("too long instance ID", func(c *OperatorConfig) { c.InstanceID = strings.Repeat("a", 43) }, "instanceID"),
+		Entry("too long instance ID for the managed-by value", func(c *OperatorConfig) {
+			c.ManagedBy = strings.Repeat("m", 40)
+			c.InstanceID = strings.Repeat("a", 23)
+		}, "instanceID"),
+		Entry("empty managed-by value", func(c *OperatorConfig) { c.ManagedBy = "" }, "managedBy"),
+		Entry("invalid managed-by value", func(c *OperatorConfig) { c.ManagedBy = "platform certs" }, "managedBy"),
+		Entry("empty certificate name suffix", func(c *OperatorConfig) { c.CertificateNameSuffix = "" }, "certificateNameSuffix"),
+		Entry("invalid secret name suffix", func(c *OperatorConfig) { c.SecretNameSuffix = "_TLS" }, "secretNameSuffix"),
+		Entry("non-positive finalize retry interval", func(c *OperatorConfig) { c.Controller.FinalizeRetryInterval.Duration = 0 }, "finalizeRetryInterval"),
+		Entry("negative lag threshold", func(c *OperatorConfig) { c.Controller.ReconcileLagThreshold.Duration = -time.Second }, "reconcileLagThreshold"),
+		Entry("DNS resolver without a port", func(c *OperatorConfig) { c.Controller.DNSResolver = "1.1.1.1" }, "dnsResolver"),
+		Entry("DNS resolver with an invalid port", func(c *OperatorConfig) { c.Controller.DNSResolver = "1.1.1.1:dns" }, "dnsResolver"),
+		Entry("negative max retries", func(c *OperatorConfig) { c.Providers.MaxRetries = -1 }, "maxRetries"),
+		Entry("no concurrent reconciles", func(c *OperatorConfig) { c.Controller.MaxConcurrentReconciles = 0 }, "maxConcurrentReconciles"),
+		Entry("unknown upload limit provider", func(c *OperatorConfig) { c.Providers.MaxConcurrentUploads = map[string]int{"gcp": 1} }, "unknown provider"),
+		Entry("negative renewal upload window", func(c *OperatorConfig) { c.Providers.RenewalUploadWindow.Duration = -time.Minute }, "renewalUploadWindow"),
+		Entry("negative circuit breaker threshold", func(c *OperatorConfig) { c.Providers.CircuitBreakerThreshold = -1 }, "circuitBreakerThreshold"),
+		Entry("non-positive circuit breaker cooldown", func(c *OperatorConfig) { c.Providers.CircuitBreakerCooldown.Duration = 0 }, "circuitBreakerCooldown"),
+		Entry("invalid tag label prefix", func(c *OperatorConfig) { c.Providers.TagLabelPrefix = "tags_example.com/" }, "tagLabelPrefix"),
+		Entry("invalid upload blackout window", func(c *OperatorConfig) { c.Providers.UploadBlackout.Windows = []string{"Mon 9-18"} }, "uploadBlackout"),
+		Entry("unknown upload blackout time zone", func(c *OperatorConfig) { c.Providers.UploadBlackout.TimeZone = "Mars/Olympus" }, "uploadBlackout"),
+		Entry("unknown disabled provider", func(c *OperatorConfig) { c.Providers.Disabled = []string{"gcp"} }, "providers.disabled"),
+		Entry("non-positive upload limit", func(c *OperatorConfig) { c.Providers.MaxConcurrentUploads = map[string]int{"aws": 0} }, "maxConcurrentUploads.aws"),
+		Entry("negative shutdown grace period", func(c *OperatorConfig) { c.Providers.ShutdownGracePeriod.Duration = -time.Second }, "shutdownGracePeriod"),
+		Entry("invalid API server port", func(c *OperatorConfig) { c.APIServer.Port = "http" }, "apiServer.port"),
+		Entry("unknown audit sink", func(c *OperatorConfig) { c.APIServer.Audit.Sink = "syslog" }, "apiServer.audit.sink"),
+		Entry("file audit sink without a file", func(c *OperatorConfig) { c.APIServer.Audit.Sink = "file" }, "apiServer.audit.file"),
+		Entry("negative list cache TTL", func(c *OperatorConfig) { c.APIServer.ListCacheTTL.Duration = -time.Second }, "apiServer.listCacheTTL"),
+		Entry("negative request timeout", func(c *OperatorConfig) { c.APIServer.RequestTimeout.Duration = -time.Second }, "apiServer.requestTimeout"),
+		Entry("negative long request timeout", func(c *OperatorConfig) {
+			c.APIServer.LongRequestTimeout.Duration = -time.Second
+		}, "apiServer.longRequestTimeout"),
+		Entry("invalid trusted proxy", func(c *OperatorConfig) { c.APIServer.TrustedProxies = []string{"proxy"} }, "apiServer.trustedProxies"),
+		Entry("empty metrics bind address", func(c *OperatorConfig) { c.Metrics.BindAddress = "" }, "metrics.bindAddress"),
+	)
+
+	It("should not validate the port when the API server is disabled", func() {
+		cfg.APIServer.Enabled = false
+		cfg.APIServer.Port = ""
+		Expect(cfg.Validate()).To(Succeed())
+	})
+})
@@ -18,15 +18,23 @@ package controller
 
 import (
 	"context"
-	"strings"
+	"slices"
+	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -37,13 +45,67 @@ import (
 
 const (
 	certificateFinalizer = "certificate.println.kr/finalizer"
+
+	// deletionProtectionAnnotation set to "true" holds a deleted Certificate, and its cloud
+	// cleanup, until the annotation is removed
+	deletionProtectionAnnotation = "certificate.println.kr/deletion-protection"
+
+	// defaultFinalizeRetryInterval is used when FinalizeRetryInterval is not set
+	defaultFinalizeRetryInterval = 30 * time.Second
+
+	// reconcileDurationRefreshInterval is how old the recorded reconcile duration may get
+	// before it is written without other status changes
+	reconcileDurationRefreshInterval = 10 * time.Minute
 )
 
+// CertificateProcessor processes and finalizes Certificate resources.
+// It is implemented by driver.CertificateManager.
+type CertificateProcessor interface {
+	ProcessCertificate(ctx context.Context, cert *certificatev1alpha1.Certificate) (ctrl.Result, bool, error)
+	Finalize(ctx context.Context, cert *certificatev1alpha1.Certificate) error
+}
+
 // CertificateReconciler reconciles a Certificate object
 type CertificateReconciler struct {
 	client.Client
 	Scheme  *runtime.Scheme
-	Manager *driver.CertificateManager
+	Manager CertificateProcessor
+
+	// FinalizeRetryInterval is how long to wait before retrying a failed cloud cleanup
+	// during deletion. Defaults to 30 seconds.
+	FinalizeRetryInterval time.Duration
+
+	// Tracker records the reconciles in flight for the reconcile lag readiness check. Optional.
+	Tracker *ReconcileTracker
+
+	// Recorder emits events on Certificates, e.g. when deletion protection holds a deletion. Optional.
+	Recorder record.EventRecorder
+
+	// CredentialsNamespace is the operator credentials namespace the Manager reads credential
+	// Secrets from, empty for each Certificate's namespace. Used to map Secret changes to Certificates.
+	CredentialsNamespace string
+
+	// MaxConcurrentReconciles is how many Certificates are reconciled at the same time.
+	// Defaults to 1.
+	MaxConcurrentReconciles int
+
+	// Version is recorded in status.managedByVersion after a successful reconcile. Optional.
+	Version string
+
+	// InstanceID limits the reconciler to the Certificates whose instance label matches it.
+	// Empty reconciles the Certificates without the label.
+	InstanceID string
+
+	// APIReader reads Certificates from the API server when the cache may not have seen the
+	// latest status write yet, so the certificate isn't uploaded twice. Optional.
+	APIReader client.Reader
+
+	// SecretNameSuffix is appended to the name of a Certificate to name its TLS Secret, as the
+	// Manager is configured. Defaults to "-tls".
+	SecretNameSuffix string
+
+	// statusWrites tracks the latest status write of each Certificate
+	statusWrites statusWriteTracker
 }
 
 // +kubebuilder:rbac:groups=certificate.println.kr,resources=certificates,verbs=get;list;watch;create;update;patch;delete
@@ -51,25 +113,67 @@ type CertificateReconciler struct {
 // +kubebuilder:rbac:groups=certificate.println.kr,resources=certificates/finalizers,verbs=update
 // +kubebuilder:rbac:groups=cert-manager.io,resources=issuers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates/status,verbs=update;patch
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificaterequests,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=acme.cert-manager.io,resources=orders;challenges,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *CertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Tracker.Started(req.NamespacedName)
+	defer r.Tracker.Finished(req.NamespacedName)
+	return r.reconcile(ctx, req)
+}
+
+// reconcile performs a single reconciliation of a Certificate CR
+func (r *CertificateReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
+	start := time.Now()
 
 	var cert certificatev1alpha1.Certificate
 	if err := r.Get(ctx, req.NamespacedName, &cert); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.statusWrites.forget(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// The status written by the previous reconcile may not have reached the cache yet
+	if r.APIReader != nil && r.statusWrites.stale(&cert) {
+		log.V(1).Info("Cached Certificate predates the latest status write, reading it from the API server")
+		if err := r.APIReader.Get(ctx, req.NamespacedName, &cert); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		r.statusWrites.record(req.NamespacedName, cert.ResourceVersion)
+	}
+
+	// Requests for owned objects and Secrets bypass the instance predicate
+	if !matchesInstance(&cert, r.InstanceID) {
+		log.V(1).Info("Certificate belongs to another operator instance, skipping",
+			"instance", cert.Labels[certificatev1alpha1.InstanceLabel])
+		return ctrl.Result{}, nil
+	}
+
+	// Log this Certificate in more detail when requested by its annotation
+	ctx = withCertificateLogLevel(ctx, &cert)
+	log = logf.FromContext(ctx)
+
 	// Handle deletion
 	if !cert.DeletionTimestamp.IsZero() {
 		return r.handleDeletion(ctx, &cert)
 	}
 
-	// Ensure finalizer
-	if !controllerutil.ContainsFinalizer(&cert, certificateFinalizer) {
+	// Ensure finalizer, or drop it when cloud cleanup is managed externally.
+	// Deletion protection needs the finalizer to hold the deletion.
+	if finalizerDisabled(&cert) && !deletionProtected(&cert) {
+		if controllerutil.RemoveFinalizer(&cert, certificateFinalizer) {
+			if err := r.Update(ctx, &cert); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else if !controllerutil.ContainsFinalizer(&cert, certificateFinalizer) {
 		controllerutil.AddFinalizer(&cert, certificateFinalizer)
 		if err := r.Update(ctx, &cert); err != nil {
 			return ctrl.Result{}, err
@@ -77,20 +181,46 @@ func (r *CertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	// Process certificate using the manager
+	storedStatus := cert.Status.DeepCopy()
 	result, statusUpdated, err := r.Manager.ProcessCertificate(ctx, &cert)
 	if err != nil {
 		log.Error(err, "Failed to process certificate")
 		return ctrl.Result{}, err
 	}
 
+	// Record which operator version last processed the certificate
+	if r.Version != "" && cert.Status.ManagedByVersion != r.Version {
+		cert.Status.ManagedByVersion = r.Version
+		statusUpdated = true
+	}
+
 	// Update status if changed
 	if statusUpdated {
-		if err := r.Status().Update(ctx, &cert); err != nil {
-			log.Error(err, "Failed to update Certificate status")
+		if err := driver.BoundStatus(&cert.Status); err != nil {
+			log.Error(err, "Refusing to write oversized Certificate status")
 			return ctrl.Result{}, err
 		}
+		// Fields set back to their stored values need no write, which would still bump resourceVersion
+		if equality.Semantic.DeepEqual(storedStatus, &cert.Status) {
+			log.V(1).Info("Certificate status unchanged, skipping status update")
+			statusUpdated = false
+		}
 	}
 
+	// The reconcile duration rides along with other status writes, and only causes a write
+	// of its own once the recorded one is stale
+	now := time.Now()
+	if !statusUpdated && !reconcileDurationStale(&cert.Status, now) {
+		return result, nil
+	}
+	cert.Status.LastReconcileDuration = now.Sub(start).Round(time.Millisecond).String()
+	cert.Status.LastReconcileTime = &metav1.Time{Time: now}
+	if err := r.Status().Update(ctx, &cert); err != nil {
+		log.Error(err, "Failed to update Certificate status")
+		return ctrl.Result{}, err
+	}
+	r.statusWrites.record(req.NamespacedName, cert.ResourceVersion)
+
 	// Return result from manager (may include requeue)
 	return result, nil
 }
@@ -100,9 +230,47 @@ func (r *CertificateReconciler) handleDeletion(ctx context.Context, cert *certif
 	log := logf.FromContext(ctx)
 
 	if controllerutil.ContainsFinalizer(cert, certificateFinalizer) {
-		if err := r.Manager.Finalize(ctx, cert); err != nil {
-			log.Error(err, "Failed to finalize Certificate")
-			return ctrl.Result{}, err
+		// Keep the Certificate, and everything uploaded, until protection is lifted
+		if deletionProtected(cert) {
+			log.Info("Deletion protection is enabled, holding deletion until the annotation is removed",
+				"annotation", deletionProtectionAnnotation, "retryAfter", r.finalizeRetryInterval())
+			if r.Recorder != nil {
+				r.Recorder.Eventf(cert, corev1.EventTypeWarning, "DeletionProtected",
+					"Deletion is held until the %s annotation is removed", deletionProtectionAnnotation)
+			}
+			return ctrl.Result{RequeueAfter: r.finalizeRetryInterval()}, nil
+		}
+
+		// Cloud cleanup is managed externally, release the finalizer added before it was disabled
+		if finalizerDisabled(cert) {
+			log.Info("Finalizer is disabled, skipping cloud cleanup")
+			controllerutil.RemoveFinalizer(cert, certificateFinalizer)
+			return ctrl.Result{}, r.Update(ctx, cert)
+		}
+
+		storedFinalization := cert.Status.Finalization.DeepCopy()
+		finalizeErr := r.Manager.Finalize(ctx, cert)
+
+		// Record what was cleaned up, and what holds the deletion, before the finalizer is removed
+		if driver.FinalizationChanged(storedFinalization, cert.Status.Finalization) {
+			if err := driver.BoundStatus(&cert.Status); err != nil {
+				log.Error(err, "Refusing to write oversized Certificate status")
+				return ctrl.Result{}, err
+			}
+			if err := r.Status().Update(ctx, cert); err != nil {
+				log.Error(err, "Failed to record finalization summary")
+				return ctrl.Result{}, err
+			}
+		}
+
+		if err := finalizeErr; err != nil {
+			if driver.IsRetriable(err) {
+				log.Info("Cloud cleanup temporarily failed, retrying later",
+					"error", err.Error(), "retryAfter", r.finalizeRetryInterval())
+			} else {
+				log.Error(err, "Failed to finalize Certificate", "retryAfter", r.finalizeRetryInterval())
+			}
+			return ctrl.Result{RequeueAfter: r.finalizeRetryInterval()}, nil
 		}
 
 		controllerutil.RemoveFinalizer(cert, certificateFinalizer)
@@ -113,32 +281,120 @@ func (r *CertificateReconciler) handleDeletion(ctx context.Context, cert *certif
 	return ctrl.Result{}, nil
 }
 
-// findCertificateForSecret maps a Secret to its owning Certificate CR.
-// The Secret name follows the pattern "{certificate-name}-tls".
-func (r *CertificateReconciler) findCertificateForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
-	// Only process secrets that end with "-tls"
-	secretName := secret.GetName()
-	if !strings.HasSuffix(secretName, "-tls") {
+// finalizerDisabled reports whether spec.disableFinalizer is set
+func finalizerDisabled(cert *certificatev1alpha1.Certificate) bool {
+	return cert.Spec.DisableFinalizer != nil && *cert.Spec.DisableFinalizer
+}
+
+// deletionProtected reports whether the deletion protection annotation is set to true
+func deletionProtected(cert *certificatev1alpha1.Certificate) bool {
+	protected, err := strconv.ParseBool(cert.Annotations[deletionProtectionAnnotation])
+	return err == nil && protected
+}
+
+// reconcileDurationStale reports whether the recorded reconcile duration is missing or older
+// than reconcileDurationRefreshInterval
+func reconcileDurationStale(status *certificatev1alpha1.CertificateStatus, now time.Time) bool {
+	return status.LastReconcileTime == nil ||
+		now.Sub(status.LastReconcileTime.Time) >= reconcileDurationRefreshInterval
+}
+
+// finalizeRetryInterval returns the configured finalize retry interval or the default
+func (r *CertificateReconciler) finalizeRetryInterval() time.Duration {
+	if r.FinalizeRetryInterval > 0 {
+		return r.FinalizeRetryInterval
+	}
+	return defaultFinalizeRetryInterval
+}
+
+// secretNameSuffix returns the configured TLS Secret name suffix or the default
+func (r *CertificateReconciler) secretNameSuffix() string {
+	if r.SecretNameSuffix != "" {
+		return r.SecretNameSuffix
+	}
+	return certificatev1alpha1.DefaultSecretNameSuffix
+}
+
+// secretNameIndexKey indexes Certificates by the name of their TLS Secret
+const secretNameIndexKey = "status.secretName"
+
+// indexCertificateSecretName returns the TLS Secret name of a Certificate for the
+// secretNameIndexKey index. Certificates that were not reconciled yet are indexed by
+// "{certificate-name}{SecretNameSuffix}".
+func (r *CertificateReconciler) indexCertificateSecretName(obj client.Object) []string {
+	cert, ok := obj.(*certificatev1alpha1.Certificate)
+	if !ok {
 		return nil
 	}
+	if cert.Status.SecretName != "" {
+		return []string{cert.Status.SecretName}
+	}
+	return []string{cert.Name + r.secretNameSuffix()}
+}
 
-	// Extract certificate name by removing "-tls" suffix
-	certName := strings.TrimSuffix(secretName, "-tls")
+// credentialSecretIndexKey indexes Certificates by the "{namespace}/{name}" of the other
+// Secrets they read, such as provider credentials
+const credentialSecretIndexKey = "spec.credentialSecrets"
 
+// indexCertificateCredentialSecrets returns the Secrets a Certificate reads besides its TLS
+// Secret for the credentialSecretIndexKey index. Credential Secrets may live in another
+// namespace, so the keys include the namespace.
+func (r *CertificateReconciler) indexCertificateCredentialSecrets(obj client.Object) []string {
+	cert, ok := obj.(*certificatev1alpha1.Certificate)
+	if !ok {
+		return nil
+	}
+	secrets := driver.ReferencedSecrets(cert, r.CredentialsNamespace)
+	keys := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		keys = append(keys, secret.String())
+	}
+	return keys
+}
+
+// findCertificateForSecret maps a Secret to the Certificate CRs whose TLS Secret it is, looked
+// up through the secretNameIndexKey index, and to the Certificates that read it as a credential
+// Secret, looked up through the credentialSecretIndexKey index. Rotated credentials thereby
+// retry uploads that failed with the old ones.
+func (r *CertificateReconciler) findCertificateForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
 	log := logf.FromContext(ctx)
-	log.V(1).Info("Secret changed, triggering reconcile for Certificate",
-		"secret", secretName,
-		"certificate", certName,
-		"namespace", secret.GetNamespace())
 
-	return []reconcile.Request{
-		{
+	var certs certificatev1alpha1.CertificateList
+	if err := r.List(ctx, &certs,
+		client.InNamespace(secret.GetNamespace()),
+		client.MatchingFields{secretNameIndexKey: secret.GetName()},
+	); err != nil {
+		log.Error(err, "Failed to look up Certificates for Secret", "secret", secret.GetName())
+		return nil
+	}
+
+	var dependents certificatev1alpha1.CertificateList
+	if err := r.List(ctx, &dependents,
+		client.MatchingFields{credentialSecretIndexKey: client.ObjectKeyFromObject(secret).String()},
+	); err != nil {
+		log.Error(err, "Failed to look up Certificates for credential Secret", "secret", secret.GetName())
+	}
+
+	requests := make([]reconcile.Request, 0, len(certs.Items)+len(dependents.Items))
+	for _, cert := range append(certs.Items, dependents.Items...) {
+		request := reconcile.Request{
 			NamespacedName: types.NamespacedName{
-				Name:      certName,
-				Namespace: secret.GetNamespace(),
+				Name:      cert.Name,
+				Namespace: cert.Namespace,
 			},
-		},
+		}
+		if slices.Contains(requests, request) {
+			continue
+		}
+
+		log.V(1).Info("Secret changed, triggering reconcile for Certificate",
+			"secret", secret.GetName(),
+			"secretNamespace", secret.GetNamespace(),
+			"certificate", cert.Name,
+			"namespace", cert.Namespace)
+		requests = append(requests, request)
 	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -148,14 +404,28 @@ func (r *CertificateReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		r.Manager = driver.NewCertificateManager(r.Client, r.Scheme)
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &certificatev1alpha1.Certificate{},
+		secretNameIndexKey, r.indexCertificateSecretName); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &certificatev1alpha1.Certificate{},
+		credentialSecretIndexKey, r.indexCertificateCredentialSecrets); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&certificatev1alpha1.Certificate{}).
+		For(&certificatev1alpha1.Certificate{}, builder.WithPredicates(instancePredicate(r.InstanceID))).
 		Owns(&certmanagerv1.Issuer{}).
 		Owns(&certmanagerv1.Certificate{}).
 		Watches(
 			&corev1.Secret{},
-			handler.EnqueueRequestsFromMapFunc(r.findCertificateForSecret),
+			enqueueRequestsFromMapFuncAfter(r.findCertificateForSecret, secretEnqueueDelay),
 		).
 		Named("certificate").
+		WithOptions(controller.Options{
+			UsePriorityQueue:        ptr.To(true),
+			NewQueue:                newCertificateQueue(mgr.GetClient()),
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+		}).
 		Complete(r)
 }
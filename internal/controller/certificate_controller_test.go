@@ -18,11 +18,25 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,6 +45,73 @@ import (
 	"github.com/tae2089/certificate-operator/internal/driver"
 )
 
+// fakeProcessor is a CertificateProcessor stub for reconciler tests.
+type fakeProcessor struct {
+	processResult ctrl.Result
+	processErr    error
+	finalizeErr   error
+
+	// statusUpdated is reported from ProcessCertificate after updateStatus, when set, ran
+	statusUpdated bool
+	updateStatus  func(status *certificatev1alpha1.CertificateStatus)
+
+	// finalization, when set, is recorded in status by Finalize
+	finalization *certificatev1alpha1.FinalizationStatus
+
+	processCalls  int
+	finalizeCalls int
+
+	// process, when set, is called with the reconcile context
+	process func(ctx context.Context)
+}
+
+func (p *fakeProcessor) ProcessCertificate(ctx context.Context, cert *certificatev1alpha1.Certificate) (ctrl.Result, bool, error) {
+	p.processCalls++
+	if p.process != nil {
+		p.process(ctx)
+	}
+	if p.updateStatus != nil {
+		p.updateStatus(&cert.Status)
+	}
+	return p.processResult, p.statusUpdated, p.processErr
+}
+
+func (p *fakeProcessor) Finalize(_ context.Context, cert *certificatev1alpha1.Certificate) error {
+	p.finalizeCalls++
+	if p.finalization != nil {
+		cert.Status.Finalization = p.finalization.DeepCopy()
+	}
+	return p.finalizeErr
+}
+
+// newFakeReconciler builds a reconciler backed by a fake client seeded with objs.
+func newFakeReconciler(processor CertificateProcessor, objs ...client.Object) *CertificateReconciler {
+	r := &CertificateReconciler{Manager: processor}
+	r.Client = fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&certificatev1alpha1.Certificate{}).
+		WithIndex(&certificatev1alpha1.Certificate{}, secretNameIndexKey, r.indexCertificateSecretName).
+		WithIndex(&certificatev1alpha1.Certificate{}, credentialSecretIndexKey, r.indexCertificateCredentialSecrets).
+		Build()
+	r.Scheme = r.Client.Scheme()
+	return r
+}
+
+// newDeletingCertificate returns a Certificate that is marked for deletion.
+func newDeletingCertificate(name string) *certificatev1alpha1.Certificate {
+	now := metav1.Now()
+	return &certificatev1alpha1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{certificateFinalizer},
+		},
+		Spec: certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+	}
+}
+
 var _ = Describe("Certificate Controller", func() {
 	Context("When reconciling a resource", func() {
 		const resourceName = "test-resource"
@@ -87,4 +168,634 @@ var _ = Describe("Certificate Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When the manager reports a status update", func() {
+		newUploadedCertificate := func(name string) *certificatev1alpha1.Certificate {
+			return &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  "default",
+					Finalizers: []string{certificateFinalizer},
+				},
+				Spec: certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+				Status: certificatev1alpha1.CertificateStatus{
+					SecretName:        name + "-tls",
+					AWSUploaded:       true,
+					LastReconcileTime: ptr.To(metav1.Now()),
+				},
+			}
+		}
+
+		// reconcileResourceVersion reconciles cert and returns its resourceVersion before and after
+		reconcileResourceVersion := func(processor *fakeProcessor, cert *certificatev1alpha1.Certificate) (string, string) {
+			reconciler := newFakeReconciler(processor, cert)
+			key := client.ObjectKeyFromObject(cert)
+
+			before := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, before)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			after := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, after)).To(Succeed())
+			return before.ResourceVersion, after.ResourceVersion
+		}
+
+		It("should not write a status that did not change", func() {
+			processor := &fakeProcessor{
+				statusUpdated: true,
+				updateStatus: func(status *certificatev1alpha1.CertificateStatus) {
+					// Flipped and restored within the same reconcile
+					status.AWSUploaded = false
+					status.AWSUploaded = true
+				},
+			}
+
+			before, after := reconcileResourceVersion(processor, newUploadedCertificate("unchanged-status"))
+			Expect(after).To(Equal(before))
+		})
+
+		It("should write a status that changed", func() {
+			processor := &fakeProcessor{
+				statusUpdated: true,
+				updateStatus: func(status *certificatev1alpha1.CertificateStatus) {
+					status.CloudflareUploaded = true
+				},
+			}
+
+			before, after := reconcileResourceVersion(processor, newUploadedCertificate("changed-status"))
+			Expect(after).NotTo(Equal(before))
+		})
+	})
+
+	Context("When one issuance triggers both a Secret and a cert-manager Certificate event", func() {
+		key := types.NamespacedName{Namespace: "default", Name: "issued"}
+
+		newIssuedCertificate := func() *certificatev1alpha1.Certificate {
+			return &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+					Finalizers: []string{certificateFinalizer},
+				},
+				Spec: certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+				Status: certificatev1alpha1.CertificateStatus{
+					SecretName:        key.Name + "-tls",
+					LastReconcileTime: ptr.To(metav1.Now()),
+				},
+			}
+		}
+
+		It("should upload once when the second reconcile reads a stale cache", func() {
+			uploads := 0
+			processor := &fakeProcessor{
+				statusUpdated: true,
+				updateStatus: func(status *certificatev1alpha1.CertificateStatus) {
+					if status.LastUploadedCertHash != "issued-hash" {
+						uploads++
+						status.AWSUploaded = true
+						status.LastUploadedCertHash = "issued-hash"
+					}
+				},
+			}
+			reconciler := newFakeReconciler(processor, newIssuedCertificate())
+			apiServer := reconciler.Client.(client.WithWatch)
+
+			// The cache keeps serving the Certificate as it was before the first reconcile
+			cached := &certificatev1alpha1.Certificate{}
+			Expect(apiServer.Get(ctx, key, cached)).To(Succeed())
+			cacheSynced := false
+			reconciler.Client = interceptor.NewClient(apiServer, interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, k client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if cert, ok := obj.(*certificatev1alpha1.Certificate); ok && !cacheSynced {
+						cached.DeepCopyInto(cert)
+						return nil
+					}
+					return c.Get(ctx, k, obj, opts...)
+				},
+			})
+			apiReads := 0
+			reconciler.APIReader = interceptor.NewClient(apiServer, interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, k client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					apiReads++
+					return c.Get(ctx, k, obj, opts...)
+				},
+			})
+
+			for range 2 {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(processor.processCalls).To(Equal(2))
+			Expect(uploads).To(Equal(1))
+			Expect(apiReads).To(Equal(1))
+
+			By("reading from the cache again once it has caught up")
+			cacheSynced = true
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uploads).To(Equal(1))
+			Expect(apiReads).To(Equal(1))
+		})
+
+		It("should merge the delayed Secret reconcile into the cert-manager Certificate reconcile", func() {
+			reconciler := newFakeReconciler(&fakeProcessor{}, newIssuedCertificate())
+			queue := newCertificateQueue(reconciler.Client)("certificate",
+				workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			DeferCleanup(queue.ShutDown)
+
+			secretHandler := enqueueRequestsFromMapFuncAfter(reconciler.findCertificateForSecret, 100*time.Millisecond)
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: key.Name + "-tls", Namespace: key.Namespace}}
+			secretHandler.Update(ctx, event.UpdateEvent{ObjectOld: secret, ObjectNew: secret}, queue)
+			Expect(queue.Len()).To(BeZero())
+
+			// The cert-manager Certificate becoming ready enqueues the Certificate right away
+			queue.Add(reconcile.Request{NamespacedName: key})
+			Expect(queue.Len()).To(Equal(1))
+			item, _ := queue.Get()
+			Expect(item.NamespacedName).To(Equal(key))
+			queue.Done(item)
+
+			Consistently(queue.Len, 300*time.Millisecond, 20*time.Millisecond).Should(BeZero())
+		})
+	})
+
+	Context("When recording the reconcile duration", func() {
+		It("should populate the duration and time after a reconcile", func() {
+			cert := &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "timed", Namespace: "default", Finalizers: []string{certificateFinalizer}},
+				Spec:       certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+			}
+			processor := &fakeProcessor{process: func(context.Context) { time.Sleep(5 * time.Millisecond) }}
+			reconciler := newFakeReconciler(processor, cert)
+			key := client.ObjectKeyFromObject(cert)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			current := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+			Expect(current.Status.LastReconcileTime).NotTo(BeNil())
+			duration, err := time.ParseDuration(current.Status.LastReconcileDuration)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(duration).To(BeNumerically(">=", 5*time.Millisecond))
+
+			By("not writing a fresh duration on its own")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			again := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, again)).To(Succeed())
+			Expect(again.ResourceVersion).To(Equal(current.ResourceVersion))
+		})
+
+		It("should refresh a stale duration without other status changes", func() {
+			stale := metav1.NewTime(time.Now().Add(-reconcileDurationRefreshInterval))
+			cert := &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "default", Finalizers: []string{certificateFinalizer}},
+				Spec:       certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+				Status:     certificatev1alpha1.CertificateStatus{LastReconcileDuration: "1s", LastReconcileTime: &stale},
+			}
+			reconciler := newFakeReconciler(&fakeProcessor{}, cert)
+			key := client.ObjectKeyFromObject(cert)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			current := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+			Expect(current.Status.LastReconcileTime.Time).To(BeTemporally(">", stale.Time))
+			Expect(current.Status.LastReconcileDuration).NotTo(Equal("1s"))
+		})
+	})
+
+	Context("When recording the operator version", func() {
+		newVersionedCertificate := func(name string) *certificatev1alpha1.Certificate {
+			return &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  "default",
+					Finalizers: []string{certificateFinalizer},
+				},
+				Spec: certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+			}
+		}
+
+		// reconcileVersion reconciles cert with the operator version and returns the stored status
+		reconcileVersion := func(processor *fakeProcessor, cert *certificatev1alpha1.Certificate) (certificatev1alpha1.CertificateStatus, error) {
+			reconciler := newFakeReconciler(processor, cert)
+			reconciler.Version = "v1.2.3"
+			key := client.ObjectKeyFromObject(cert)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+
+			stored := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, stored)).To(Succeed())
+			return stored.Status, err
+		}
+
+		It("should set managedByVersion after a successful reconcile", func() {
+			status, err := reconcileVersion(&fakeProcessor{}, newVersionedCertificate("versioned"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.ManagedByVersion).To(Equal("v1.2.3"))
+		})
+
+		It("should keep the previous version when the reconcile fails", func() {
+			cert := newVersionedCertificate("versioned-failing")
+			cert.Status.ManagedByVersion = "v1.2.2"
+
+			status, err := reconcileVersion(&fakeProcessor{processErr: fmt.Errorf("upload failed")}, cert)
+			Expect(err).To(HaveOccurred())
+			Expect(status.ManagedByVersion).To(Equal("v1.2.2"))
+		})
+	})
+
+	Context("When finalizing a deleted resource fails", func() {
+		It("should requeue after the configured interval on a retriable error", func() {
+			cert := newDeletingCertificate("retriable-finalize")
+			processor := &fakeProcessor{
+				finalizeErr: driver.NewRetriableError(fmt.Errorf("rate limited")),
+			}
+			reconciler := newFakeReconciler(processor, cert)
+			reconciler.FinalizeRetryInterval = 2 * time.Minute
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(cert),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(2 * time.Minute))
+			Expect(processor.finalizeCalls).To(Equal(1))
+
+			By("keeping the finalizer until cleanup succeeds")
+			current := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cert), current)).To(Succeed())
+			Expect(current.Finalizers).To(ContainElement(certificateFinalizer))
+		})
+
+		It("should record the finalization summary in status", func() {
+			cert := newDeletingCertificate("summary-finalize")
+			processor := &fakeProcessor{
+				finalizeErr: fmt.Errorf("zone not found"),
+				finalization: &certificatev1alpha1.FinalizationStatus{
+					Deleted: []string{"aws/arn:aws:acm:us-east-1:123456789012:certificate/example"},
+					Failed:  []certificatev1alpha1.CleanupFailure{{Resource: "cloudflare/cf-id", Error: "zone not found"}},
+				},
+			}
+			reconciler := newFakeReconciler(processor, cert)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(cert),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			current := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cert), current)).To(Succeed())
+			Expect(current.Finalizers).To(ContainElement(certificateFinalizer))
+			Expect(current.Status.Finalization).To(Equal(processor.finalization))
+		})
+
+		It("should fall back to the default interval when none is configured", func() {
+			cert := newDeletingCertificate("default-finalize")
+			reconciler := newFakeReconciler(&fakeProcessor{finalizeErr: fmt.Errorf("access denied")}, cert)
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(cert),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(defaultFinalizeRetryInterval))
+		})
+	})
+
+	Context("When the finalizer is disabled", func() {
+		newFinalizerDisabledCertificate := func(name string) *certificatev1alpha1.Certificate {
+			return &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: certificatev1alpha1.CertificateSpec{
+					Domain:           "example.com",
+					DisableFinalizer: ptr.To(true),
+				},
+			}
+		}
+
+		It("should not add the finalizer and delete immediately", func() {
+			cert := newFinalizerDisabledCertificate("no-finalizer")
+			processor := &fakeProcessor{}
+			reconciler := newFakeReconciler(processor, cert)
+			key := client.ObjectKeyFromObject(cert)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processor.processCalls).To(Equal(1))
+
+			current := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+			Expect(current.Finalizers).To(BeEmpty())
+
+			By("deleting the Certificate")
+			Expect(reconciler.Delete(ctx, current)).To(Succeed())
+			Expect(errors.IsNotFound(reconciler.Get(ctx, key, current))).To(BeTrue())
+			Expect(processor.finalizeCalls).To(BeZero())
+		})
+
+		It("should remove a finalizer added before it was disabled", func() {
+			cert := newFinalizerDisabledCertificate("previously-finalized")
+			cert.Finalizers = []string{certificateFinalizer}
+			reconciler := newFakeReconciler(&fakeProcessor{}, cert)
+			key := client.ObjectKeyFromObject(cert)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			current := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+			Expect(current.Finalizers).To(BeEmpty())
+		})
+
+		It("should release a deleted Certificate without cloud cleanup", func() {
+			cert := newDeletingCertificate("disabled-finalize")
+			cert.Spec.DisableFinalizer = ptr.To(true)
+			processor := &fakeProcessor{finalizeErr: fmt.Errorf("should not be called")}
+			reconciler := newFakeReconciler(processor, cert)
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(cert),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(processor.finalizeCalls).To(BeZero())
+			Expect(errors.IsNotFound(reconciler.Get(ctx, client.ObjectKeyFromObject(cert), &certificatev1alpha1.Certificate{}))).To(BeTrue())
+		})
+	})
+
+	Context("When deletion protection is enabled", func() {
+		newProtectedCertificate := func(name string) *certificatev1alpha1.Certificate {
+			cert := newDeletingCertificate(name)
+			cert.Annotations = map[string]string{deletionProtectionAnnotation: "true"}
+			return cert
+		}
+
+		It("should hold the deletion and emit an event", func() {
+			cert := newProtectedCertificate("protected")
+			processor := &fakeProcessor{}
+			reconciler := newFakeReconciler(processor, cert)
+			recorder := record.NewFakeRecorder(10)
+			reconciler.Recorder = recorder
+			reconciler.FinalizeRetryInterval = time.Minute
+			key := client.ObjectKeyFromObject(cert)
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(processor.finalizeCalls).To(BeZero())
+			Expect(recorder.Events).To(Receive(ContainSubstring("DeletionProtected")))
+
+			current := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+			Expect(current.Finalizers).To(ContainElement(certificateFinalizer))
+
+			By("removing the annotation")
+			delete(current.Annotations, deletionProtectionAnnotation)
+			Expect(reconciler.Update(ctx, current)).To(Succeed())
+
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(processor.finalizeCalls).To(Equal(1))
+			Expect(errors.IsNotFound(reconciler.Get(ctx, key, current))).To(BeTrue())
+		})
+
+		It("should clean up when the annotation is not true", func() {
+			cert := newDeletingCertificate("unprotected")
+			cert.Annotations = map[string]string{deletionProtectionAnnotation: "false"}
+			processor := &fakeProcessor{}
+			reconciler := newFakeReconciler(processor, cert)
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(cert),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(processor.finalizeCalls).To(Equal(1))
+			Expect(errors.IsNotFound(reconciler.Get(ctx, client.ObjectKeyFromObject(cert), &certificatev1alpha1.Certificate{}))).To(BeTrue())
+		})
+
+		It("should keep the finalizer even when the finalizer is disabled", func() {
+			cert := &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "protected-no-finalizer",
+					Namespace:   "default",
+					Annotations: map[string]string{deletionProtectionAnnotation: "true"},
+				},
+				Spec: certificatev1alpha1.CertificateSpec{
+					Domain:           "example.com",
+					DisableFinalizer: ptr.To(true),
+				},
+			}
+			reconciler := newFakeReconciler(&fakeProcessor{}, cert)
+			key := client.ObjectKeyFromObject(cert)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			current := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+			Expect(current.Finalizers).To(ContainElement(certificateFinalizer))
+		})
+	})
+
+	Context("When a TLS Secret changes", func() {
+		newCertificate := func(namespace, name, secretName string) *certificatev1alpha1.Certificate {
+			return &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec:       certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+				Status:     certificatev1alpha1.CertificateStatus{SecretName: secretName},
+			}
+		}
+		newSecret := func(namespace, name string) *corev1.Secret {
+			return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		}
+
+		var reconciler *CertificateReconciler
+
+		BeforeEach(func() {
+			reconciler = newFakeReconciler(&fakeProcessor{},
+				newCertificate("default", "custom", "wildcard-example-com"),
+				newCertificate("default", "pending", ""),
+				newCertificate("team", "custom", "wildcard-example-com"),
+			)
+		})
+
+		It("should reconcile the Certificate with a custom-named Secret", func() {
+			Expect(reconciler.findCertificateForSecret(context.Background(), newSecret("default", "wildcard-example-com"))).
+				To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "custom"}}))
+		})
+
+		It("should fall back to the default Secret name before the first reconcile", func() {
+			Expect(reconciler.findCertificateForSecret(context.Background(), newSecret("default", "pending-tls"))).
+				To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "pending"}}))
+		})
+
+		It("should ignore Secrets that belong to no Certificate", func() {
+			Expect(reconciler.findCertificateForSecret(context.Background(), newSecret("default", "custom-tls"))).To(BeEmpty())
+			Expect(reconciler.findCertificateForSecret(context.Background(), newSecret("other", "wildcard-example-com"))).To(BeEmpty())
+		})
+	})
+
+	Context("When a credential Secret changes", func() {
+		var reconciler *CertificateReconciler
+
+		request := func(namespace, name string) reconcile.Request {
+			return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+		}
+		newSecret := func(namespace, name string) *corev1.Secret {
+			return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		}
+
+		BeforeEach(func() {
+			reconciler = newFakeReconciler(&fakeProcessor{},
+				&certificatev1alpha1.Certificate{
+					ObjectMeta: metav1.ObjectMeta{Name: "cloudflare", Namespace: "default"},
+					Spec: certificatev1alpha1.CertificateSpec{
+						Domain:              "example.com",
+						CloudflareSecretRef: "cloudflare-credentials",
+					},
+				},
+				&certificatev1alpha1.Certificate{
+					ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "default"},
+					Spec: certificatev1alpha1.CertificateSpec{
+						Domain:              "example.com",
+						CloudflareSecretRef: "cloudflare-credentials",
+						AWS:                 &certificatev1alpha1.AWS{CredentialType: "access-key", SecretRef: "aws-credentials"},
+					},
+				},
+				&certificatev1alpha1.Certificate{
+					ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "team"},
+					Spec: certificatev1alpha1.CertificateSpec{
+						Domain:               "example.com",
+						CredentialsNamespace: "credentials",
+						S3:                   &certificatev1alpha1.S3{Bucket: "certs", SecretRef: "aws-credentials"},
+					},
+				},
+			)
+		})
+
+		It("should reconcile every Certificate that reads the Secret", func() {
+			Expect(reconciler.findCertificateForSecret(context.Background(), newSecret("default", "cloudflare-credentials"))).
+				To(ConsistOf(request("default", "cloudflare"), request("default", "aws")))
+			Expect(reconciler.findCertificateForSecret(context.Background(), newSecret("default", "aws-credentials"))).
+				To(ConsistOf(request("default", "aws")))
+		})
+
+		It("should reconcile Certificates reading the Secret from another namespace", func() {
+			Expect(reconciler.findCertificateForSecret(context.Background(), newSecret("credentials", "aws-credentials"))).
+				To(ConsistOf(request("team", "shared")))
+		})
+
+		It("should ignore Secrets with the same name in other namespaces", func() {
+			Expect(reconciler.findCertificateForSecret(context.Background(), newSecret("team", "aws-credentials"))).To(BeEmpty())
+		})
+	})
+
+	Context("When running as a named operator instance", func() {
+		newInstanceCertificate := func(name, instance string) *certificatev1alpha1.Certificate {
+			cert := &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec:       certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+			}
+			if instance != "" {
+				cert.Labels = map[string]string{certificatev1alpha1.InstanceLabel: instance}
+			}
+			return cert
+		}
+
+		// reconcileInstance reconciles cert as the instance team-a and returns the processor
+		// and the stored Certificate
+		reconcileInstance := func(cert *certificatev1alpha1.Certificate) (*fakeProcessor, *certificatev1alpha1.Certificate) {
+			processor := &fakeProcessor{}
+			reconciler := newFakeReconciler(processor, cert)
+			reconciler.InstanceID = "team-a"
+			key := client.ObjectKeyFromObject(cert)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			current := &certificatev1alpha1.Certificate{}
+			Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+			return processor, current
+		}
+
+		It("should reconcile Certificates labeled for the instance", func() {
+			processor, current := reconcileInstance(newInstanceCertificate("team-a", "team-a"))
+			Expect(processor.processCalls).To(Equal(1))
+			Expect(current.Finalizers).To(ContainElement(certificateFinalizer))
+		})
+
+		It("should ignore Certificates of other instances", func() {
+			processor, current := reconcileInstance(newInstanceCertificate("team-b", "team-b"))
+			Expect(processor.processCalls).To(BeZero())
+			Expect(current.Finalizers).To(BeEmpty())
+
+			processor, current = reconcileInstance(newInstanceCertificate("unlabeled", ""))
+			Expect(processor.processCalls).To(BeZero())
+			Expect(current.Finalizers).To(BeEmpty())
+		})
+
+		It("should only pass events of the instance's Certificates", func() {
+			Expect(instancePredicate("team-a").Generic(event.GenericEvent{Object: newInstanceCertificate("a", "team-a")})).To(BeTrue())
+			Expect(instancePredicate("team-a").Generic(event.GenericEvent{Object: newInstanceCertificate("b", "team-b")})).To(BeFalse())
+			Expect(instancePredicate("").Generic(event.GenericEvent{Object: newInstanceCertificate("c", "")})).To(BeTrue())
+			Expect(instancePredicate("").Generic(event.GenericEvent{Object: newInstanceCertificate("d", "team-a")})).To(BeFalse())
+		})
+	})
+
+	Context("When a Certificate requests a higher log level", func() {
+		// reconcileLogs reconciles a Certificate with the given annotations against a logger
+		// at the global verbosity 0 and returns the logged messages
+		reconcileLogs := func(annotations map[string]string) []string {
+			cert := &certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "verbose", Namespace: "default", Annotations: annotations},
+				Spec:       certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+			}
+			processor := &fakeProcessor{
+				process: func(ctx context.Context) {
+					log := logf.FromContext(ctx)
+					log.Info("info")
+					log.V(1).Info("debug")
+					log.V(2).Info("trace")
+				},
+			}
+
+			var messages []string
+			logger := funcr.New(func(_, args string) { messages = append(messages, args) }, funcr.Options{Verbosity: 0})
+			ctx := logf.IntoContext(context.Background(), logger)
+
+			_, err := newFakeReconciler(processor, cert).Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "verbose", Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return messages
+		}
+
+		It("should only log at the global level without the annotation", func() {
+			messages := reconcileLogs(nil)
+			Expect(messages).To(ContainElement(ContainSubstring(`"msg"="info"`)))
+			Expect(messages).NotTo(ContainElement(ContainSubstring(`"msg"="debug"`)))
+		})
+
+		It("should log more detail for the annotated Certificate", func() {
+			messages := reconcileLogs(map[string]string{logLevelAnnotation: "1"})
+			Expect(messages).To(ContainElement(ContainSubstring(`"msg"="info"`)))
+			Expect(messages).To(ContainElement(ContainSubstring(`"msg"="debug"`)))
+			Expect(messages).NotTo(ContainElement(ContainSubstring(`"msg"="trace"`)))
+
+			messages = reconcileLogs(map[string]string{logLevelAnnotation: "2"})
+			Expect(messages).To(ContainElement(ContainSubstring(`"msg"="trace"`)))
+		})
+
+		It("should ignore an invalid level", func() {
+			messages := reconcileLogs(map[string]string{logLevelAnnotation: "verbose"})
+			Expect(messages).To(ContainElement(ContainSubstring("Ignoring invalid log level annotation")))
+			Expect(messages).NotTo(ContainElement(ContainSubstring(`"msg"="debug"`)))
+		})
+	})
 })
@@ -0,0 +1,209 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+const (
+	// ingressCertificatesAnnotation set to "true" on an Ingress creates a Certificate for
+	// each host of its TLS blocks
+	ingressCertificatesAnnotation = "certificate.println.kr/ingress-certificates"
+
+	// ingressClusterIssuerAnnotation sets the ClusterIssuer of the Certificates created for
+	// an Ingress, defaults to the Certificate's default ClusterIssuer
+	ingressClusterIssuerAnnotation = "certificate.println.kr/cluster-issuer"
+
+	// ingressLabel is set on the Certificates created for an Ingress to its name
+	ingressLabel = "certificate.println.kr/ingress"
+)
+
+// errNotOwnedByIngress is returned when a Certificate with the name of an Ingress host's
+// Certificate exists but wasn't created for the Ingress
+var errNotOwnedByIngress = errors.New("certificate exists and is not owned by the ingress")
+
+// IngressReconciler creates a Certificate for each TLS host of Ingresses annotated with
+// certificate.println.kr/ingress-certificates, owned by the Ingress. Only the domain and
+// the ClusterIssuer are managed, other spec fields such as the providers can be set on the
+// Certificates and are kept.
+type IngressReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// InstanceID limits the reconciler to the Ingresses whose instance label matches it and
+	// is set as the instance label of the Certificates it creates
+	InstanceID string
+
+	// Recorder emits events on Ingresses, e.g. when two hosts map to the same Certificate name. Optional.
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
+
+// Reconcile creates or updates the Certificates of an Ingress's TLS hosts and deletes the
+// ones whose host was removed, or all of them once the annotation is removed.
+// Certificates of a deleted Ingress are garbage collected through their owner reference.
+func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var ingress networkingv1.Ingress
+	if err := r.Get(ctx, req.NamespacedName, &ingress); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !ingress.DeletionTimestamp.IsZero() || !matchesInstance(&ingress, r.InstanceID) {
+		return ctrl.Result{}, nil
+	}
+
+	hosts, collisions := ingressCertificateHosts(&ingress)
+	for _, host := range collisions {
+		name := ingressCertificateName(ingress.Name, host)
+		log.Info("Skipping host, its Certificate name is taken by another host of the Ingress",
+			"host", host, "certificate", name, "otherHost", hosts[name])
+		if r.Recorder != nil {
+			r.Recorder.Eventf(&ingress, corev1.EventTypeWarning, "CertificateNameCollision",
+				"Skipping host %s, its Certificate name %s is taken by host %s", host, name, hosts[name])
+		}
+	}
+
+	var errs []error
+	for name, host := range hosts {
+		op, err := r.ensureCertificate(ctx, &ingress, name, host)
+		if errors.Is(err, errNotOwnedByIngress) {
+			// Retrying doesn't help until the conflicting Certificate is removed
+			log.Info("Skipping host, a Certificate with its name already exists", "host", host, "certificate", name)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to ensure Certificate %s for host %s: %w", name, host, err))
+			continue
+		}
+		if op != controllerutil.OperationResultNone {
+			log.Info("Ensured Certificate for Ingress host", "host", host, "certificate", name, "operation", op)
+		}
+	}
+
+	var certList certificatev1alpha1.CertificateList
+	if err := r.List(ctx, &certList, client.InNamespace(ingress.Namespace),
+		client.MatchingLabels{ingressLabel: ingress.Name}); err != nil {
+		return ctrl.Result{}, errors.Join(append(errs, err)...)
+	}
+	for i := range certList.Items {
+		cert := &certList.Items[i]
+		if _, ok := hosts[cert.Name]; ok || !metav1.IsControlledBy(cert, &ingress) {
+			continue
+		}
+		if err := r.Delete(ctx, cert); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("failed to delete Certificate %s: %w", cert.Name, err))
+			continue
+		}
+		log.Info("Deleted Certificate of a removed Ingress host", "certificate", cert.Name, "host", cert.Spec.Domain)
+	}
+
+	return ctrl.Result{}, errors.Join(errs...)
+}
+
+// ensureCertificate creates or updates the Certificate of an Ingress host
+func (r *IngressReconciler) ensureCertificate(
+	ctx context.Context,
+	ingress *networkingv1.Ingress,
+	name, host string,
+) (controllerutil.OperationResult, error) {
+	cert := &certificatev1alpha1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ingress.Namespace},
+	}
+	return controllerutil.CreateOrUpdate(ctx, r.Client, cert, func() error {
+		if cert.ResourceVersion != "" && !metav1.IsControlledBy(cert, ingress) {
+			return errNotOwnedByIngress
+		}
+
+		if cert.Labels == nil {
+			cert.Labels = make(map[string]string)
+		}
+		cert.Labels[ingressLabel] = ingress.Name
+		if r.InstanceID != "" {
+			cert.Labels[certificatev1alpha1.InstanceLabel] = r.InstanceID
+		}
+		cert.Spec.Domain = host
+		if issuer := ingress.Annotations[ingressClusterIssuerAnnotation]; issuer != "" {
+			cert.Spec.ClusterIssuerName = issuer
+		}
+		return controllerutil.SetControllerReference(ingress, cert, r.Scheme)
+	})
+}
+
+// ingressCertificateHosts returns the TLS hosts of an annotated Ingress keyed by the name of
+// their Certificate, or nothing when the Ingress isn't annotated. Hosts whose Certificate
+// name is taken by a different host listed before them, e.g. a-b.example.com after
+// a.b-example.com, are returned as collisions.
+func ingressCertificateHosts(ingress *networkingv1.Ingress) (hosts map[string]string, collisions []string) {
+	hosts = make(map[string]string)
+	if enabled, _ := strconv.ParseBool(ingress.Annotations[ingressCertificatesAnnotation]); !enabled {
+		return hosts, nil
+	}
+	for _, tls := range ingress.Spec.TLS {
+		for _, host := range tls.Hosts {
+			if host == "" {
+				continue
+			}
+			host = strings.ToLower(host)
+			name := ingressCertificateName(ingress.Name, host)
+			if other, ok := hosts[name]; ok {
+				if strings.TrimSuffix(other, ".") != strings.TrimSuffix(host, ".") {
+					collisions = append(collisions, host)
+				}
+				continue
+			}
+			hosts[name] = host
+		}
+	}
+	return hosts, collisions
+}
+
+// ingressCertificateName returns the name of the Certificate for an Ingress host, e.g.
+// web-wildcard-example-com for *.example.com on the Ingress web
+func ingressCertificateName(ingressName, host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	host = strings.ReplaceAll(host, "*", "wildcard")
+	return ingressName + "-" + strings.ReplaceAll(host, ".", "-")
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1.Ingress{}, builder.WithPredicates(instancePredicate(r.InstanceID))).
+		Owns(&certificatev1alpha1.Certificate{}).
+		Named("ingress").
+		Complete(r)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+var _ = Describe("Ingress Controller", func() {
+	var (
+		ctx        context.Context
+		reconciler *IngressReconciler
+	)
+
+	newIngress := func(annotations map[string]string, hosts ...string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "web",
+				Namespace:   "default",
+				UID:         "web-uid",
+				Annotations: annotations,
+			},
+			Spec: networkingv1.IngressSpec{
+				TLS: []networkingv1.IngressTLS{{Hosts: hosts, SecretName: "web-tls"}},
+			},
+		}
+	}
+
+	setup := func(objs ...client.Object) {
+		ctx = context.Background()
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+		reconciler = &IngressReconciler{Client: c, Scheme: c.Scheme()}
+	}
+
+	reconcileIngress := func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	getCertificate := func(name string) (*certificatev1alpha1.Certificate, error) {
+		cert := &certificatev1alpha1.Certificate{}
+		err := reconciler.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, cert)
+		return cert, err
+	}
+
+	It("should create a Certificate owned by the Ingress for each TLS host", func() {
+		ingress := newIngress(map[string]string{
+			ingressCertificatesAnnotation:  "true",
+			ingressClusterIssuerAnnotation: "letsencrypt-staging",
+		}, "example.com", "*.Example.com")
+		setup(ingress)
+
+		reconcileIngress()
+
+		cert, err := getCertificate("web-example-com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Spec.Domain).To(Equal("example.com"))
+		Expect(cert.Spec.ClusterIssuerName).To(Equal("letsencrypt-staging"))
+		Expect(cert.Labels).To(HaveKeyWithValue(ingressLabel, "web"))
+		Expect(metav1.IsControlledBy(cert, ingress)).To(BeTrue())
+
+		wildcard, err := getCertificate("web-wildcard-example-com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wildcard.Spec.Domain).To(Equal("*.example.com"))
+	})
+
+	It("should label the Certificates with the operator instance", func() {
+		ingress := newIngress(map[string]string{ingressCertificatesAnnotation: "true"}, "example.com")
+		ingress.Labels = map[string]string{certificatev1alpha1.InstanceLabel: "team-a"}
+		setup(ingress)
+		reconciler.InstanceID = "team-a"
+
+		reconcileIngress()
+
+		cert, err := getCertificate("web-example-com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Labels).To(HaveKeyWithValue(certificatev1alpha1.InstanceLabel, "team-a"))
+
+		By("ignoring Ingresses of other instances")
+		reconciler.InstanceID = "team-b"
+		Expect(reconciler.Delete(ctx, cert)).To(Succeed())
+		reconcileIngress()
+
+		_, err = getCertificate("web-example-com")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should ignore Ingresses without the annotation", func() {
+		setup(newIngress(nil, "example.com"))
+
+		reconcileIngress()
+
+		_, err := getCertificate("web-example-com")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should keep provider settings and delete the Certificates of removed hosts", func() {
+		ingress := newIngress(map[string]string{ingressCertificatesAnnotation: "true"}, "example.com", "www.example.com")
+		setup(ingress)
+		reconcileIngress()
+
+		By("configuring a provider on a created Certificate")
+		cert, err := getCertificate("web-example-com")
+		Expect(err).NotTo(HaveOccurred())
+		cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+		Expect(reconciler.Update(ctx, cert)).To(Succeed())
+
+		By("removing a host from the Ingress")
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(ingress), ingress)).To(Succeed())
+		ingress.Spec.TLS[0].Hosts = []string{"example.com"}
+		Expect(reconciler.Update(ctx, ingress)).To(Succeed())
+		reconcileIngress()
+
+		cert, err = getCertificate("web-example-com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Spec.CloudflareSecretRef).To(Equal("cloudflare-credentials"))
+		_, err = getCertificate("web-www-example-com")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		By("removing the annotation")
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(ingress), ingress)).To(Succeed())
+		ingress.Annotations = nil
+		Expect(reconciler.Update(ctx, ingress)).To(Succeed())
+		reconcileIngress()
+
+		_, err = getCertificate("web-example-com")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should not take over a Certificate it didn't create", func() {
+		existing := &certificatev1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-example-com", Namespace: "default"},
+			Spec:       certificatev1alpha1.CertificateSpec{Domain: "other.example.com"},
+		}
+		setup(newIngress(map[string]string{ingressCertificatesAnnotation: "true"}, "example.com", "www.example.com"), existing)
+
+		reconcileIngress()
+
+		cert, err := getCertificate("web-example-com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Spec.Domain).To(Equal("other.example.com"))
+		Expect(cert.OwnerReferences).To(BeEmpty())
+		_, err = getCertificate("web-www-example-com")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should skip a host whose Certificate name is taken by another host", func() {
+		setup(newIngress(map[string]string{ingressCertificatesAnnotation: "true"}, "a.b-c.example.com", "a-b.c.example.com"))
+		recorder := record.NewFakeRecorder(1)
+		reconciler.Recorder = recorder
+
+		reconcileIngress()
+
+		cert, err := getCertificate("web-a-b-c-example-com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Spec.Domain).To(Equal("a.b-c.example.com"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Skipping host a-b.c.example.com")))
+
+		By("keeping the Certificate of the first host on later reconciles")
+		reconcileIngress()
+		cert, err = getCertificate("web-a-b-c-example-com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Spec.Domain).To(Equal("a.b-c.example.com"))
+	})
+})
@@ -0,0 +1,37 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// matchesInstance reports whether obj belongs to the operator instance instanceID through
+// its instance label. Objects without the label belong to the instance without an ID.
+func matchesInstance(obj client.Object, instanceID string) bool {
+	return obj.GetLabels()[certificatev1alpha1.InstanceLabel] == instanceID
+}
+
+// instancePredicate filters events to the objects of the operator instance instanceID
+func instancePredicate(instanceID string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return matchesInstance(obj, instanceID)
+	})
+}
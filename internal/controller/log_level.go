@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// logLevelAnnotation raises the log verbosity of reconciles of a single Certificate,
+// e.g. "2" also emits V(1) and V(2) lines regardless of the global --zap-log-level
+const logLevelAnnotation = "certificate.println.kr/log-level"
+
+// withCertificateLogLevel returns ctx with its logger raised to the verbosity requested by
+// the Certificate's logLevelAnnotation. Other Certificates keep the global verbosity.
+func withCertificateLogLevel(ctx context.Context, cert *certificatev1alpha1.Certificate) context.Context {
+	value, ok := cert.Annotations[logLevelAnnotation]
+	if !ok {
+		return ctx
+	}
+
+	log := logf.FromContext(ctx)
+	level, err := strconv.Atoi(value)
+	if err != nil || level < 0 {
+		log.Info("Ignoring invalid log level annotation, expected a non-negative integer",
+			"annotation", logLevelAnnotation, "value", value)
+		return ctx
+	}
+	if level == 0 || log.GetSink() == nil {
+		return ctx
+	}
+
+	// Skip the wrapper's frame so callers are still reported correctly
+	sink := log.GetSink()
+	if callDepthSink, ok := sink.(logr.CallDepthLogSink); ok {
+		sink = callDepthSink.WithCallDepth(1)
+	}
+	return logf.IntoContext(ctx, log.WithSink(&verbositySink{sink: sink, boost: level}))
+}
+
+// verbositySink emits V(n) lines of the wrapped sink as V(n-boost), so up to boost
+// more detail is logged than the wrapped sink is configured for
+type verbositySink struct {
+	sink  logr.LogSink
+	boost int
+}
+
+var _ logr.CallDepthLogSink = &verbositySink{}
+
+// level lowers a verbosity level by the boost
+func (s *verbositySink) level(level int) int {
+	return max(level-s.boost, 0)
+}
+
+// Init implements logr.LogSink. The wrapped sink is already initialized.
+func (s *verbositySink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements logr.LogSink
+func (s *verbositySink) Enabled(level int) bool {
+	return s.sink.Enabled(s.level(level))
+}
+
+// Info implements logr.LogSink
+func (s *verbositySink) Info(level int, msg string, keysAndValues ...any) {
+	s.sink.Info(s.level(level), msg, keysAndValues...)
+}
+
+// Error implements logr.LogSink
+func (s *verbositySink) Error(err error, msg string, keysAndValues ...any) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+// WithValues implements logr.LogSink
+func (s *verbositySink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &verbositySink{sink: s.sink.WithValues(keysAndValues...), boost: s.boost}
+}
+
+// WithName implements logr.LogSink
+func (s *verbositySink) WithName(name string) logr.LogSink {
+	return &verbositySink{sink: s.sink.WithName(name), boost: s.boost}
+}
+
+// WithCallDepth implements logr.CallDepthLogSink
+func (s *verbositySink) WithCallDepth(depth int) logr.LogSink {
+	if sink, ok := s.sink.(logr.CallDepthLogSink); ok {
+		return &verbositySink{sink: sink.WithCallDepth(depth), boost: s.boost}
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// certificatePriorityQueue orders reconciles by the Certificate's spec.priority.
+//
+// Every enqueue, whichever watch it comes from, replaces the priority chosen by
+// controller-runtime with the Certificate's own priority, looked up from the cache.
+// Tradeoffs:
+//   - Resync and initial list events are no longer demoted below real changes, so
+//     an operator restart reconciles high-priority Certificates first instead.
+//   - Under a sustained backlog, low-priority Certificates can be starved until the
+//     higher-priority work drains. Rate-limited retries still back off per item.
+//   - Each enqueue costs one cache read; deleted Certificates fall back to priority 0.
+type certificatePriorityQueue struct {
+	priorityqueue.PriorityQueue[reconcile.Request]
+
+	priority func(key types.NamespacedName) int
+}
+
+// newCertificatePriorityQueue wraps queue so items are enqueued with the priority returned by priority
+func newCertificatePriorityQueue(
+	queue priorityqueue.PriorityQueue[reconcile.Request],
+	priority func(key types.NamespacedName) int,
+) *certificatePriorityQueue {
+	return &certificatePriorityQueue{PriorityQueue: queue, priority: priority}
+}
+
+// Add enqueues the items with their Certificate priority
+func (q *certificatePriorityQueue) Add(item reconcile.Request) {
+	q.AddWithOpts(priorityqueue.AddOpts{}, item)
+}
+
+// AddAfter enqueues the item after duration with its Certificate priority
+func (q *certificatePriorityQueue) AddAfter(item reconcile.Request, duration time.Duration) {
+	q.AddWithOpts(priorityqueue.AddOpts{After: duration}, item)
+}
+
+// AddRateLimited enqueues the item after its rate limit with its Certificate priority
+func (q *certificatePriorityQueue) AddRateLimited(item reconcile.Request) {
+	q.AddWithOpts(priorityqueue.AddOpts{RateLimited: true}, item)
+}
+
+// AddWithOpts enqueues each item with its Certificate priority
+func (q *certificatePriorityQueue) AddWithOpts(o priorityqueue.AddOpts, items ...reconcile.Request) {
+	for _, item := range items {
+		priority := q.priority(item.NamespacedName)
+		o.Priority = &priority
+		q.PriorityQueue.AddWithOpts(o, item)
+	}
+}
+
+// certificatePriority returns the spec.priority of the Certificate, or 0 if it can't be read
+func certificatePriority(reader client.Reader) func(key types.NamespacedName) int {
+	return func(key types.NamespacedName) int {
+		cert := &certificatev1alpha1.Certificate{}
+		if err := reader.Get(context.Background(), key, cert); err != nil {
+			return 0
+		}
+		return int(cert.Spec.Priority)
+	}
+}
+
+// newCertificateQueue builds the controller work queue as a priority queue ordered by spec.priority
+func newCertificateQueue(reader client.Reader) func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return func(name string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		queue := priorityqueue.New(name, func(o *priorityqueue.Opts[reconcile.Request]) {
+			o.RateLimiter = rateLimiter
+		})
+		return newCertificatePriorityQueue(queue, certificatePriority(reader))
+	}
+}
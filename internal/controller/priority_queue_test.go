@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+var _ = Describe("certificatePriorityQueue", func() {
+	var queue workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	request := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}
+	}
+
+	newPriorityCertificate := func(name string, priority int32) *certificatev1alpha1.Certificate {
+		return &certificatev1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       certificatev1alpha1.CertificateSpec{Domain: name + ".example.com", Priority: priority},
+		}
+	}
+
+	BeforeEach(func() {
+		reader := newFakeReconciler(&fakeProcessor{},
+			newPriorityCertificate("dev", -10),
+			newPriorityCertificate("staging", 0),
+			newPriorityCertificate("prod", 100),
+		).Client
+		queue = newCertificateQueue(reader)("certificate", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		DeferCleanup(queue.ShutDown)
+	})
+
+	It("should be a controller-runtime priority queue", func() {
+		Expect(queue).To(BeAssignableToTypeOf(&certificatePriorityQueue{}))
+		_, ok := queue.(priorityqueue.PriorityQueue[reconcile.Request])
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should process higher-priority Certificates first under backlog", func() {
+		queue.Add(request("dev"))
+		queue.Add(request("staging"))
+		queue.Add(request("missing"))
+		queue.Add(request("prod"))
+
+		var order []string
+		for range 4 {
+			item, _ := queue.Get()
+			order = append(order, item.Name)
+			queue.Done(item)
+		}
+		Expect(order[0]).To(Equal("prod"))
+		Expect(order[1:3]).To(ConsistOf("staging", "missing"))
+		Expect(order[3]).To(Equal("dev"))
+	})
+
+	It("should override the priority chosen by the event handler", func() {
+		low := handler.LowPriority
+		queue.(priorityqueue.PriorityQueue[reconcile.Request]).AddWithOpts(
+			priorityqueue.AddOpts{Priority: &low}, request("prod"), request("staging"))
+
+		item, priority, _ := queue.(priorityqueue.PriorityQueue[reconcile.Request]).GetWithPriority()
+		Expect(item.Name).To(Equal("prod"))
+		Expect(priority).To(Equal(100))
+	})
+})
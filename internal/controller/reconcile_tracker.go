@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ReconcileTracker records the reconciles in flight per Certificate so a readiness check
+// can detect a stalled reconcile loop, i.e. a reconcile that hasn't returned within the
+// threshold. Reconciles that fail return and are retried with backoff, so a failing
+// Certificate is reported on its own status and never marks the controller stalled.
+type ReconcileTracker struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	now       func() time.Time
+	inFlight  map[types.NamespacedName]time.Time
+}
+
+// NewReconcileTracker creates a tracker that reports unready once a reconcile runs
+// longer than threshold. A zero threshold disables the check.
+func NewReconcileTracker(threshold time.Duration) *ReconcileTracker {
+	return &ReconcileTracker{
+		threshold: threshold,
+		now:       time.Now,
+		inFlight:  map[types.NamespacedName]time.Time{},
+	}
+}
+
+// Started records the start of a reconcile of key
+func (t *ReconcileTracker) Started(key types.NamespacedName) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inFlight[key] = t.now()
+}
+
+// Finished records that the reconcile of key returned, successfully or not
+func (t *ReconcileTracker) Finished(key types.NamespacedName) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.inFlight, key)
+}
+
+// Check implements healthz.Checker, reporting the longest running reconcile beyond the threshold
+func (t *ReconcileTracker) Check(_ *http.Request) error {
+	if t == nil || t.threshold <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var (
+		stalled types.NamespacedName
+		longest time.Duration
+	)
+	for key, started := range t.inFlight {
+		if running := t.now().Sub(started); running > longest {
+			stalled, longest = key, running
+		}
+	}
+	if longest > t.threshold {
+		return fmt.Errorf("reconcile of %s running for %s (threshold %s), %d reconciles in flight",
+			stalled, longest.Round(time.Second), t.threshold, len(t.inFlight))
+	}
+	return nil
+}
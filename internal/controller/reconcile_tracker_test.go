@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("ReconcileTracker", func() {
+	var (
+		tracker *ReconcileTracker
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		tracker = NewReconcileTracker(10 * time.Minute)
+		tracker.now = func() time.Time { return now }
+	})
+
+	It("should stay ready while idle", func() {
+		now = now.Add(time.Hour)
+		Expect(tracker.Check(nil)).To(Succeed())
+	})
+
+	It("should report unready when a reconcile runs beyond the threshold", func() {
+		stuck := types.NamespacedName{Namespace: "default", Name: "stuck"}
+		tracker.Started(stuck)
+		now = now.Add(5 * time.Minute)
+		Expect(tracker.Check(nil)).To(Succeed())
+
+		now = now.Add(6 * time.Minute)
+		Expect(tracker.Check(nil)).To(MatchError(ContainSubstring("reconcile of default/stuck running for 11m0s")))
+
+		By("recovering once the reconcile returns")
+		tracker.Finished(stuck)
+		Expect(tracker.Check(nil)).To(Succeed())
+	})
+
+	It("should track reconciles per Certificate", func() {
+		tracker.Started(types.NamespacedName{Namespace: "default", Name: "a"})
+		now = now.Add(9 * time.Minute)
+		tracker.Started(types.NamespacedName{Namespace: "default", Name: "b"})
+		now = now.Add(2 * time.Minute)
+		tracker.Finished(types.NamespacedName{Namespace: "default", Name: "b"})
+
+		Expect(tracker.Check(nil)).To(MatchError(ContainSubstring("default/a")))
+	})
+
+	It("should not report a failing Certificate as stalled", func() {
+		cert := newDeletingCertificate("tracked")
+		cert.DeletionTimestamp = nil
+		reconciler := newFakeReconciler(&fakeProcessor{processErr: fmt.Errorf("provider unavailable")}, cert)
+		reconciler.Tracker = tracker
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cert)})
+		Expect(err).To(HaveOccurred())
+
+		now = now.Add(11 * time.Minute)
+		Expect(tracker.Check(nil)).To(Succeed())
+	})
+})
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// statusWriteTracker remembers the resourceVersion of each Certificate's latest status write.
+// A reconcile that follows right after the write, e.g. the second of the two reconciles
+// enqueued by the Secret and the cert-manager Certificate of one issuance, may read the
+// Certificate from a cache that hasn't seen the write yet. Its status then misses the upload
+// and the certificate would be uploaded again.
+type statusWriteTracker struct {
+	mu       sync.Mutex
+	versions map[types.NamespacedName]string
+}
+
+// record remembers resourceVersion as the latest status write of key
+func (t *statusWriteTracker) record(key types.NamespacedName, resourceVersion string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.versions == nil {
+		t.versions = make(map[types.NamespacedName]string)
+	}
+	t.versions[key] = resourceVersion
+}
+
+// forget drops the status write of key, e.g. once the Certificate is gone
+func (t *statusWriteTracker) forget(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.versions, key)
+}
+
+// stale reports whether cert, read from the cache, may predate its latest status write.
+// The write is forgotten once the cache has caught up with it.
+// resourceVersions can't be ordered, so a cert changed by someone else since the write is
+// reported as stale too.
+func (t *statusWriteTracker) stale(cert *certificatev1alpha1.Certificate) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := types.NamespacedName{Namespace: cert.Namespace, Name: cert.Name}
+	written, ok := t.versions[key]
+	if !ok {
+		return false
+	}
+	if written == cert.ResourceVersion {
+		delete(t.versions, key)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+const (
+	// adoptAWSARNAnnotation is the ARN of a certificate already in AWS ACM, e.g. imported
+	// manually, that the operator re-imports into instead of importing a new one
+	adoptAWSARNAnnotation = "certificate.println.kr/adopt-aws-arn"
+
+	// adoptCloudflareIDAnnotation is the ID of a certificate already uploaded to Cloudflare
+	// that the operator replaces instead of uploading a new one
+	adoptCloudflareIDAnnotation = "certificate.println.kr/adopt-cloudflare-id"
+
+	// adoptionRequeueInterval is when the upload into the adopted certificates is retried in
+	// case the status write doesn't trigger a reconcile
+	adoptionRequeueInterval = time.Second
+)
+
+// adoptProviderIdentifiers records the provider identifiers of the adoption annotations in
+// status and reports whether it did. Nothing must be uploaded until status is written, or an
+// upload creating a new certificate could lose the adopted identifier. The next reconcile
+// finds them recorded, removes the annotations, and replaces the adopted certificates.
+func (m *CertificateManager) adoptProviderIdentifiers(ctx context.Context, cert *certificatev1alpha1.Certificate) (bool, error) {
+	arn, adoptARN := cert.Annotations[adoptAWSARNAnnotation]
+	id, adoptID := cert.Annotations[adoptCloudflareIDAnnotation]
+	if awsAdoptionWithoutAWS(cert) {
+		// Nothing would import into the ARN or delete it on finalization. The annotation is
+		// kept and reported in ProvidersConfigured until spec.aws is set.
+		arn, adoptARN = "", false
+	}
+	if !adoptARN && !adoptID {
+		return false, nil
+	}
+	log := logf.FromContext(ctx)
+
+	// Empty annotations adopt nothing and are just removed
+	recorded := (arn == "" || cert.Status.AWSCertificateARN == arn) &&
+		(id == "" || cert.Status.CloudflareCertificateID == id)
+	if recorded {
+		return false, m.removeAdoptionAnnotations(ctx, cert)
+	}
+
+	if arn != "" {
+		log.Info("Adopting existing AWS ACM certificate", "arn", arn, "previous", cert.Status.AWSCertificateARN)
+		setProviderIdentifier(cert, awsProviderName, arn)
+	}
+	if id != "" {
+		log.Info("Adopting existing Cloudflare certificate", "id", id, "previous", cert.Status.CloudflareCertificateID)
+		setProviderIdentifier(cert, cloudflareProviderName, id)
+	}
+	// Replace the adopted certificates with the current one even if it was uploaded before
+	resetUploadStatus(cert)
+	return true, nil
+}
+
+// removeAdoptionAnnotations removes the adoption annotations from the Certificate without
+// touching its status, which the reconcile may still change
+func (m *CertificateManager) removeAdoptionAnnotations(ctx context.Context, cert *certificatev1alpha1.Certificate) error {
+	patched := cert.DeepCopy()
+	if !awsAdoptionWithoutAWS(cert) {
+		delete(patched.Annotations, adoptAWSARNAnnotation)
+	}
+	delete(patched.Annotations, adoptCloudflareIDAnnotation)
+	if err := m.k8sClient.Patch(ctx, patched, client.MergeFrom(cert)); err != nil {
+		return fmt.Errorf("failed to remove adoption annotations: %w", err)
+	}
+	cert.Annotations = patched.Annotations
+	cert.ResourceVersion = patched.ResourceVersion
+
+	logf.FromContext(ctx).Info("Adopted provider identifiers are recorded in status, removed adoption annotations")
+	return nil
+}
+
+// awsAdoptionWithoutAWS reports whether the Certificate asks to adopt an AWS ACM certificate
+// without spec.aws, which the ARN can't be adopted without
+func awsAdoptionWithoutAWS(cert *certificatev1alpha1.Certificate) bool {
+	return cert.Spec.AWS == nil && cert.Annotations[adoptAWSARNAnnotation] != ""
+}
@@ -0,0 +1,444 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package akamai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/tae2089/certificate-operator/internal/driver/retry"
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// Media types of the CPS API requests and responses
+const (
+	enrollmentMediaType       = "application/vnd.akamai.cps.enrollment.v11+json"
+	enrollmentStatusMediaType = "application/vnd.akamai.cps.enrollment-status.v1+json"
+	changeMediaType           = "application/vnd.akamai.cps.change.v2+json"
+	csrMediaType              = "application/vnd.akamai.cps.csr.v2+json"
+	certificateMediaType      = "application/vnd.akamai.cps.certificate-and-trust-chain.v2+json"
+	changeIDMediaType         = "application/vnd.akamai.cps.change-id.v1+json"
+)
+
+// thirdPartyCertificateInput is the input a change of a third-party enrollment waits for
+// once Akamai generated its CSRs
+const thirdPartyCertificateInput = "third-party-certificate"
+
+// requestTimeout bounds a single CPS API request
+const requestTimeout = 30 * time.Second
+
+// maxSignedBodySize is how much of a request body the EdgeGrid signature covers
+const maxSignedBodySize = 131072
+
+// Driver implements the CloudProvider interface for Akamai CPS. CPS generates and keeps
+// the private key of an enrollment, so each upload starts a renewal change of a third-party
+// enrollment, has the issuer sign the CSRs CPS generated for it, and uploads the signed
+// certificates into the change.
+type Driver struct {
+	client       client.Client
+	secretRef    string
+	namespace    string
+	enrollmentID int64
+	request      RequestConfig
+	backoff      retry.Backoff
+	httpClient   *http.Client
+
+	// scheme of the API requests, overridable for testing
+	scheme string
+}
+
+// Config holds Akamai driver configuration
+type Config struct {
+	Client       client.Client
+	SecretRef    string
+	Namespace    string
+	EnrollmentID int64
+	MaxRetries   int // Retries for rate-limited or failed (5xx) requests
+
+	// Request configures the cert-manager CertificateRequests signing the CSRs of changes
+	Request RequestConfig
+}
+
+// RequestConfig configures the cert-manager CertificateRequests signing the CSRs CPS
+// generates for a change
+type RequestConfig struct {
+	NamePrefix      string // Requests are named <prefix>-akamai-<change ID>-<key algorithm>
+	Namespace       string
+	IssuerKind      string // ClusterIssuer or Issuer
+	IssuerName      string
+	ManagedBy       string // app.kubernetes.io/managed-by label value
+	OwnerReferences []metav1.OwnerReference
+}
+
+// NewDriver creates a new Akamai CPS driver
+func NewDriver(cfg Config) *Driver {
+	return &Driver{
+		client:       cfg.Client,
+		secretRef:    cfg.SecretRef,
+		namespace:    cfg.Namespace,
+		enrollmentID: cfg.EnrollmentID,
+		request:      cfg.Request,
+		backoff:      retry.Backoff{MaxRetries: cfg.MaxRetries},
+		httpClient:   &http.Client{Timeout: requestTimeout},
+		scheme:       "https",
+	}
+}
+
+// Name returns the provider name
+func (d *Driver) Name() string {
+	return "akamai"
+}
+
+// enrollment is the part of a CPS enrollment the driver reads
+type enrollment struct {
+	ValidationType string `json:"validationType"`
+	PendingChanges []struct {
+		Location   string `json:"location"`
+		ChangeType string `json:"changeType"`
+	} `json:"pendingChanges"`
+}
+
+// readOnlyEnrollmentFields are the fields of a CPS enrollment that are returned but can't be
+// sent back when updating it
+var readOnlyEnrollmentFields = []string{
+	"id", "location", "pendingChanges", "maxAllowedSanNames", "maxAllowedWildcardSanNames",
+	"assignedSlots", "stagingSlots", "productionSlots", "autoRenewalStartTime", "orgId",
+}
+
+// change is the part of the status of a CPS change the driver reads
+type change struct {
+	StatusInfo struct {
+		State       string `json:"state"`
+		Status      string `json:"status"`
+		Description string `json:"description"`
+	} `json:"statusInfo"`
+	AllowedInput []struct {
+		Type   string `json:"type"`
+		Info   string `json:"info"`
+		Update string `json:"update"`
+	} `json:"allowedInput"`
+}
+
+// finished reports whether the change completed or was cancelled
+func (c *change) finished() bool {
+	for _, value := range []string{c.StatusInfo.State, c.StatusInfo.Status} {
+		if value == "completed" || value == "cancelled" {
+			return true
+		}
+	}
+	return false
+}
+
+// csr is a CSR Akamai generated for a change
+type csr struct {
+	CSR          string `json:"csr"`
+	KeyAlgorithm string `json:"keyAlgorithm"`
+}
+
+// certificateAndTrustChain is a certificate uploaded for a CSR of a change
+type certificateAndTrustChain struct {
+	Certificate  string `json:"certificate"`
+	KeyAlgorithm string `json:"keyAlgorithm"`
+	TrustChain   string `json:"trustChain,omitempty"`
+}
+
+// Upload continues the CPS change certData.ExistingID identifies, or starts a renewal
+// change of the enrollment when there is none or it finished. Once CPS generated the CSRs
+// of the change and the issuer signed them, the certificates are uploaded into it. The
+// certificate in certData isn't uploaded, CPS can't use its private key.
+//
+// Until then, the returned error wraps ErrUploadPending and the result identifies the
+// change to continue. Enrollments with a change the operator didn't start are refused.
+func (d *Driver) Upload(ctx context.Context, certData drivertypes.CertificateData) (drivertypes.UploadResult, error) {
+	log := logf.FromContext(ctx)
+
+	config, err := d.getConfig(ctx)
+	if err != nil {
+		return drivertypes.UploadResult{}, err
+	}
+
+	// A change of another enrollment is left alone once the spec switched enrollments
+	changePath := certData.ExistingID
+	var current *change
+	if strings.HasPrefix(changePath, d.enrollmentPath()+"/") {
+		current, err = d.getChange(ctx, config, changePath)
+		if err != nil {
+			return drivertypes.UploadResult{}, err
+		}
+	}
+	if current == nil {
+		changePath, err = d.renewEnrollment(ctx, config)
+		if err != nil {
+			return drivertypes.UploadResult{}, err
+		}
+		log.Info("Started Akamai CPS renewal change", "enrollment", d.enrollmentID, "change", changePath)
+		return drivertypes.UploadResult{Identifier: changePath},
+			fmt.Errorf("%w: started Akamai CPS change %s, waiting for its CSR", drivertypes.ErrUploadPending, changePath)
+	}
+	if current.StatusInfo.State == "error" {
+		return drivertypes.UploadResult{Identifier: changePath},
+			fmt.Errorf("CPS change %s failed: %s", changePath, current.StatusInfo.Description)
+	}
+
+	var infoPath, updatePath string
+	for _, input := range current.AllowedInput {
+		if input.Type == thirdPartyCertificateInput {
+			infoPath, updatePath = input.Info, input.Update
+		}
+	}
+	if updatePath == "" {
+		// The CSR isn't generated yet, or the change is deploying an earlier upload
+		return drivertypes.UploadResult{Identifier: changePath},
+			fmt.Errorf("%w: Akamai CPS change %s is %s", drivertypes.ErrUploadPending, changePath, current.StatusInfo.Status)
+	}
+
+	var requested struct {
+		CSRs []csr `json:"csrs"`
+	}
+	if err := d.do(ctx, config, http.MethodGet, infoPath, csrMediaType, "", nil, &requested); err != nil {
+		return drivertypes.UploadResult{}, fmt.Errorf("failed to get the CSRs of Akamai CPS change %s: %w", changePath, err)
+	}
+	if len(requested.CSRs) == 0 {
+		return drivertypes.UploadResult{Identifier: changePath},
+			fmt.Errorf("%w: Akamai CPS change %s has no CSR yet", drivertypes.ErrUploadPending, changePath)
+	}
+
+	var uploads []certificateAndTrustChain
+	for _, entry := range requested.CSRs {
+		certificate, chain, err := d.signCSR(ctx, changePath, entry)
+		if err != nil {
+			return drivertypes.UploadResult{Identifier: changePath}, err
+		}
+		uploads = append(uploads, certificateAndTrustChain{
+			Certificate:  string(certificate),
+			KeyAlgorithm: entry.KeyAlgorithm,
+			TrustChain:   string(chain),
+		})
+	}
+
+	payload, err := json.Marshal(struct {
+		CertificatesAndTrustChains []certificateAndTrustChain `json:"certificatesAndTrustChains"`
+	}{CertificatesAndTrustChains: uploads})
+	if err != nil {
+		return drivertypes.UploadResult{}, err
+	}
+	if err := d.do(ctx, config, http.MethodPost, updatePath, changeIDMediaType, certificateMediaType, payload, nil); err != nil {
+		return drivertypes.UploadResult{Identifier: changePath}, fmt.Errorf("failed to upload certificate to Akamai CPS: %w", err)
+	}
+	log.Info("Uploaded certificate to Akamai CPS change", "enrollment", d.enrollmentID, "change", changePath)
+
+	return drivertypes.UploadResult{Identifier: changePath}, nil
+}
+
+// Delete cancels the change identifier refers to unless it finished already. A deployed
+// certificate stays on the enrollment, which the operator doesn't manage.
+func (d *Driver) Delete(ctx context.Context, identifier string) error {
+	config, err := d.getConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	current, err := d.getChange(ctx, config, identifier)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+
+	err = d.do(ctx, config, http.MethodDelete, identifier, changeIDMediaType, "", nil, nil)
+	if isNotFound(err) {
+		// The change was cancelled meanwhile
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel Akamai CPS change %s: %w", identifier, err)
+	}
+	return nil
+}
+
+// getChange returns the status of the change at changePath, or nil when it is gone or
+// finished
+func (d *Driver) getChange(ctx context.Context, config edgegrid.Config, changePath string) (*change, error) {
+	current := &change{}
+	err := d.do(ctx, config, http.MethodGet, changePath, changeMediaType, "", nil, current)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Akamai CPS change %s: %w", changePath, err)
+	}
+	if current.finished() {
+		return nil, nil
+	}
+	return current, nil
+}
+
+// renewEnrollment starts a renewal change of the enrollment and returns its location
+func (d *Driver) renewEnrollment(ctx context.Context, config edgegrid.Config) (string, error) {
+	enrollmentPath := d.enrollmentPath()
+
+	var raw map[string]json.RawMessage
+	if err := d.do(ctx, config, http.MethodGet, enrollmentPath, enrollmentMediaType, "", nil, &raw); err != nil {
+		return "", fmt.Errorf("failed to get Akamai CPS enrollment %d: %w", d.enrollmentID, err)
+	}
+	var current enrollment
+	if err := remarshal(raw, &current); err != nil {
+		return "", fmt.Errorf("failed to decode Akamai CPS enrollment %d: %w", d.enrollmentID, err)
+	}
+	if current.ValidationType != "third-party" {
+		return "", fmt.Errorf("CPS enrollment %d is validated by %q, only third-party enrollments accept certificates",
+			d.enrollmentID, current.ValidationType)
+	}
+	if len(current.PendingChanges) > 0 {
+		return "", fmt.Errorf("CPS enrollment %d has a pending change the operator did not start (%s), complete or cancel it first",
+			d.enrollmentID, current.PendingChanges[0].Location)
+	}
+
+	for _, field := range readOnlyEnrollmentFields {
+		delete(raw, field)
+	}
+	payload, err := json.Marshal(raw)
+	if err != nil {
+		return "", err
+	}
+	var updated struct {
+		Changes []string `json:"changes"`
+	}
+	if err := d.do(ctx, config, http.MethodPut, enrollmentPath+"?allow-cancel-pending-changes=false&force-renewal=true",
+		enrollmentStatusMediaType, enrollmentMediaType, payload, &updated); err != nil {
+		return "", fmt.Errorf("failed to start a renewal of Akamai CPS enrollment %d: %w", d.enrollmentID, err)
+	}
+	if len(updated.Changes) == 0 {
+		return "", fmt.Errorf("CPS didn't start a change renewing enrollment %d", d.enrollmentID)
+	}
+	return updated.Changes[0], nil
+}
+
+// enrollmentPath returns the CPS API path of the enrollment
+func (d *Driver) enrollmentPath() string {
+	return fmt.Sprintf("/cps/v2/enrollments/%d", d.enrollmentID)
+}
+
+// remarshal decodes the fields of a decoded JSON object into out
+func remarshal(raw map[string]json.RawMessage, out any) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// getConfig reads the EdgeGrid credentials from the Akamai Secret
+func (d *Driver) getConfig(ctx context.Context) (edgegrid.Config, error) {
+	akamaiSecret := &corev1.Secret{}
+	if err := d.client.Get(ctx, types.NamespacedName{
+		Name:      d.secretRef,
+		Namespace: d.namespace,
+	}, akamaiSecret); err != nil {
+		return edgegrid.Config{}, fmt.Errorf("failed to get Akamai secret: %w", err)
+	}
+
+	config := edgegrid.Config{
+		Host:         strings.TrimSuffix(strings.TrimPrefix(string(akamaiSecret.Data["host"]), "https://"), "/"),
+		ClientToken:  string(akamaiSecret.Data["client-token"]),
+		ClientSecret: string(akamaiSecret.Data["client-secret"]),
+		AccessToken:  string(akamaiSecret.Data["access-token"]),
+		MaxBody:      maxSignedBodySize,
+	}
+	if config.Host == "" || config.ClientToken == "" || config.ClientSecret == "" || config.AccessToken == "" {
+		return edgegrid.Config{}, fmt.Errorf("host, client-token, client-secret, and access-token are required in the Akamai secret")
+	}
+	return config, nil
+}
+
+// apiError is a CPS API response with an error status
+type apiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// isNotFound reports whether err is a CPS API 404 response
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// do sends a CPS API request signed with the EdgeGrid credentials, retrying rate limits and
+// 5xx errors, and decodes the response into out when set
+func (d *Driver) do(
+	ctx context.Context,
+	config edgegrid.Config,
+	method, path, accept, contentType string,
+	body []byte,
+	out any,
+) error {
+	return d.backoff.Do(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, method, d.scheme+"://"+config.Host+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", accept)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		req = edgegrid.AddRequestHeader(config, req)
+
+		resp, err := d.httpClient.Do(req)
+		if err != nil {
+			return drivertypes.NewRetriableError(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return drivertypes.NewRetriableError(err)
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			apiErr := &apiError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(data))}
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+				return drivertypes.NewRetriableError(apiErr)
+			}
+			return apiErr
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode Akamai API response: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,387 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package akamai
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+const (
+	enrollmentPath = "/cps/v2/enrollments/1234"
+	changePath     = enrollmentPath + "/changes/5678"
+	infoPath       = changePath + "/input/info/third-party-csr"
+	updatePath     = changePath + "/input/update/third-party-cert-and-trust-chain"
+	requestName    = "example-akamai-5678-ecdsa"
+)
+
+// fakeCPS serves the CPS API for the third-party enrollment 1234, whose renewal starts the
+// change 5678. Responses queued in failures are returned first.
+type fakeCPS struct {
+	mu sync.Mutex
+
+	csr            string
+	validationType string
+	pendingChanges []map[string]string
+	changeStatus   string
+	allowedInput   []map[string]string
+	failures       map[string][]int
+
+	requests []*http.Request
+	renewal  map[string]any
+	uploaded map[string]any
+	canceled []string
+}
+
+func newFakeCPS(csr string) *fakeCPS {
+	return &fakeCPS{
+		csr:            csr,
+		validationType: "third-party",
+		changeStatus:   "wait-upload-third-party",
+		allowedInput: []map[string]string{
+			{"type": "third-party-certificate", "info": infoPath, "update": updatePath},
+		},
+		failures: map[string][]int{},
+	}
+}
+
+func (f *fakeCPS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r)
+
+	key := r.Method + " " + r.URL.Path
+	if codes := f.failures[key]; len(codes) > 0 {
+		f.failures[key] = codes[1:]
+		http.Error(w, `{"title":"failure"}`, codes[0])
+		return
+	}
+
+	switch key {
+	case "GET " + enrollmentPath:
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"location":        enrollmentPath,
+			"validationType":  f.validationType,
+			"certificateType": "third-party",
+			"csr":             map[string]string{"cn": "example.com"},
+			"pendingChanges":  f.pendingChanges,
+		})
+	case "PUT " + enrollmentPath:
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &f.renewal)
+		f.pendingChanges = []map[string]string{{"location": changePath, "changeType": "renewal"}}
+		_ = json.NewEncoder(w).Encode(map[string]any{"enrollment": enrollmentPath, "changes": []string{changePath}})
+	case "GET " + changePath:
+		if f.changeStatus == "" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"statusInfo":   map[string]string{"status": f.changeStatus},
+			"allowedInput": f.allowedInput,
+		})
+	case "GET " + infoPath:
+		_ = json.NewEncoder(w).Encode(map[string]any{"csrs": []map[string]string{{"csr": f.csr, "keyAlgorithm": "ECDSA"}}})
+	case "POST " + updatePath:
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &f.uploaded)
+		_ = json.NewEncoder(w).Encode(map[string]string{"change": changePath})
+	case "DELETE " + changePath:
+		f.canceled = append(f.canceled, r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]string{"change": changePath})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// generateCSR returns a PEM CSR, as CPS generates for a change
+func generateCSR() string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "example.com"},
+	}, key)
+	Expect(err).NotTo(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+const (
+	signedLeaf         = "-----BEGIN CERTIFICATE-----\nbGVhZg==\n-----END CERTIFICATE-----\n"
+	signedIntermediate = "-----BEGIN CERTIFICATE-----\naW50ZXJtZWRpYXRl\n-----END CERTIFICATE-----\n"
+)
+
+var _ = Describe("Driver", func() {
+	var (
+		ctx       = context.Background()
+		csr       string
+		cps       *fakeCPS
+		server    *httptest.Server
+		k8sClient client.Client
+	)
+
+	BeforeEach(func() {
+		csr = generateCSR()
+		cps = newFakeCPS(csr)
+		server = httptest.NewServer(cps)
+		DeferCleanup(server.Close)
+
+		serverURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		k8sClient = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "akamai-credentials", Namespace: "default"},
+			Data: map[string][]byte{
+				"host":          []byte(serverURL.Host),
+				"client-token":  []byte("client-token"),
+				"client-secret": []byte("client-secret"),
+				"access-token":  []byte("access-token"),
+			},
+		}).WithStatusSubresource(&certmanagerv1.CertificateRequest{}).Build()
+	})
+
+	newTestDriver := func(maxRetries int) *Driver {
+		d := NewDriver(Config{
+			Client:       k8sClient,
+			SecretRef:    "akamai-credentials",
+			Namespace:    "default",
+			EnrollmentID: 1234,
+			MaxRetries:   maxRetries,
+			Request: RequestConfig{
+				NamePrefix: "example",
+				Namespace:  "apps",
+				IssuerKind: "ClusterIssuer",
+				IssuerName: "letsencrypt-prod",
+				ManagedBy:  "certificate-operator",
+			},
+		})
+		d.backoff.BaseDelay = time.Millisecond
+		d.scheme = "http"
+		return d
+	}
+
+	// signRequest sets the condition of the CertificateRequest for the CSR of the change,
+	// with the signed certificate when it is ready
+	signRequest := func(condition certmanagerv1.CertificateRequestCondition) {
+		request := &certmanagerv1.CertificateRequest{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: requestName, Namespace: "apps"}, request)).To(Succeed())
+		request.Status.Conditions = []certmanagerv1.CertificateRequestCondition{condition}
+		if condition.Type == certmanagerv1.CertificateRequestConditionReady && condition.Status == cmmeta.ConditionTrue {
+			request.Status.Certificate = []byte(signedLeaf + signedIntermediate)
+		}
+		Expect(k8sClient.Status().Update(ctx, request)).To(Succeed())
+	}
+
+	Context("when uploading without a change", func() {
+		It("should start a renewal change and wait for its CSR", func() {
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{})
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeTrue())
+			Expect(result.Identifier).To(Equal(changePath))
+
+			renewal := cps.requests[len(cps.requests)-1]
+			Expect(renewal.URL.Query().Get("force-renewal")).To(Equal("true"))
+			Expect(renewal.Header.Get("Content-Type")).To(Equal(enrollmentMediaType))
+			Expect(cps.renewal).To(HaveKey("csr"))
+			Expect(cps.renewal).NotTo(HaveKey("location"))
+			Expect(cps.renewal).NotTo(HaveKey("pendingChanges"))
+			Expect(cps.uploaded).To(BeNil())
+		})
+
+		It("should start a renewal change once the recorded change finished", func() {
+			cps.changeStatus = "completed"
+
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeTrue())
+			Expect(result.Identifier).To(Equal(changePath))
+			Expect(cps.renewal).NotTo(BeNil())
+		})
+
+		It("should leave the change of another enrollment alone", func() {
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: "/cps/v2/enrollments/1/changes/2"})
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeTrue())
+			Expect(result.Identifier).To(Equal(changePath))
+			Expect(cps.renewal).NotTo(BeNil())
+		})
+
+		It("should refuse an enrollment with a change it didn't start", func() {
+			cps.pendingChanges = []map[string]string{{"location": enrollmentPath + "/changes/1", "changeType": "new-certificate"}}
+
+			_, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{})
+			Expect(err).To(MatchError(ContainSubstring("pending change the operator did not start")))
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeFalse())
+			Expect(cps.renewal).To(BeNil())
+		})
+
+		It("should refuse an enrollment that isn't third-party", func() {
+			cps.validationType = "dv"
+
+			_, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{})
+			Expect(err).To(MatchError(ContainSubstring("only third-party enrollments accept certificates")))
+			Expect(cps.renewal).To(BeNil())
+		})
+	})
+
+	Context("when continuing the change it started", func() {
+		It("should wait while CPS generates the CSR", func() {
+			cps.changeStatus = "wait-review-pre-verification-safety-checks"
+			cps.allowedInput = nil
+
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeTrue())
+			Expect(result.Identifier).To(Equal(changePath))
+			Expect(cps.renewal).To(BeNil())
+		})
+
+		It("should have the issuer sign the CSR of the change", func() {
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeTrue())
+			Expect(result.Identifier).To(Equal(changePath))
+
+			request := &certmanagerv1.CertificateRequest{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: requestName, Namespace: "apps"}, request)).To(Succeed())
+			Expect(string(request.Spec.Request)).To(Equal(csr))
+			Expect(request.Spec.IssuerRef).To(Equal(cmmeta.ObjectReference{
+				Name: "letsencrypt-prod", Kind: "ClusterIssuer", Group: "cert-manager.io",
+			}))
+			Expect(request.Labels).To(HaveKeyWithValue(managedByLabel, "certificate-operator"))
+			Expect(cps.uploaded).To(BeNil())
+		})
+
+		It("should upload the signed certificate and chain into the change", func() {
+			_, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeTrue())
+			signRequest(certmanagerv1.CertificateRequestCondition{
+				Type: certmanagerv1.CertificateRequestConditionReady, Status: cmmeta.ConditionTrue,
+			})
+
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Identifier).To(Equal(changePath))
+			Expect(cps.uploaded).To(Equal(map[string]any{
+				"certificatesAndTrustChains": []any{map[string]any{
+					"certificate":  signedLeaf,
+					"keyAlgorithm": "ECDSA",
+					"trustChain":   signedIntermediate,
+				}},
+			}))
+
+			for _, req := range cps.requests {
+				Expect(req.Header.Get("Authorization")).To(HavePrefix(
+					"EG1-HMAC-SHA256 client_token=client-token;access_token=access-token;timestamp="))
+				Expect(req.Header.Get("Authorization")).To(ContainSubstring(";signature="))
+			}
+			Expect(cps.requests[len(cps.requests)-1].Header.Get("Content-Type")).To(Equal(certificateMediaType))
+		})
+
+		It("should fail when the issuer denies the CSR", func() {
+			_, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeTrue())
+			signRequest(certmanagerv1.CertificateRequestCondition{
+				Type: certmanagerv1.CertificateRequestConditionDenied, Status: cmmeta.ConditionTrue, Message: "policy",
+			})
+
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(err).To(MatchError(ContainSubstring("is Denied: policy")))
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeFalse())
+			Expect(result.Identifier).To(Equal(changePath))
+			Expect(cps.uploaded).To(BeNil())
+		})
+
+		It("should retry rate limits and server errors", func() {
+			_, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeTrue())
+			signRequest(certmanagerv1.CertificateRequestCondition{
+				Type: certmanagerv1.CertificateRequestConditionReady, Status: cmmeta.ConditionTrue,
+			})
+			cps.failures["POST "+updatePath] = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}
+
+			_, err = newTestDriver(3).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cps.uploaded).NotTo(BeNil())
+		})
+
+		It("should not retry client errors", func() {
+			_, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(errors.Is(err, drivertypes.ErrUploadPending)).To(BeTrue())
+			signRequest(certmanagerv1.CertificateRequestCondition{
+				Type: certmanagerv1.CertificateRequestConditionReady, Status: cmmeta.ConditionTrue,
+			})
+			cps.failures["POST "+updatePath] = []int{http.StatusBadRequest}
+
+			_, err = newTestDriver(3).Upload(ctx, drivertypes.CertificateData{ExistingID: changePath})
+			Expect(err).To(MatchError(ContainSubstring("status 400")))
+			Expect(drivertypes.IsRetriable(err)).To(BeFalse())
+			Expect(cps.uploaded).To(BeNil())
+		})
+	})
+
+	It("should fail without complete credentials", func() {
+		d := newTestDriver(0)
+		d.client = fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "akamai-credentials", Namespace: "default"},
+			Data:       map[string][]byte{"host": []byte("akab.example.net")},
+		}).Build()
+
+		_, err := d.Upload(ctx, drivertypes.CertificateData{})
+		Expect(err).To(MatchError(ContainSubstring("client-token")))
+		Expect(cps.requests).To(BeEmpty())
+	})
+
+	Context("when deleting a certificate", func() {
+		It("should cancel the change it started", func() {
+			Expect(newTestDriver(0).Delete(ctx, changePath)).To(Succeed())
+			Expect(cps.canceled).To(Equal([]string{changePath}))
+		})
+
+		It("should leave a finished change alone", func() {
+			cps.changeStatus = "completed"
+
+			Expect(newTestDriver(0).Delete(ctx, changePath)).To(Succeed())
+			Expect(cps.canceled).To(BeEmpty())
+		})
+
+		It("should succeed once the change is gone", func() {
+			cps.changeStatus = ""
+
+			Expect(newTestDriver(0).Delete(ctx, changePath)).To(Succeed())
+			Expect(cps.canceled).To(BeEmpty())
+		})
+	})
+})
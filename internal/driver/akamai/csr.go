@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package akamai
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// managedByLabel marks the CertificateRequests created by the operator
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// signCSR has the issuer sign a CSR CPS generated for the change at changePath through a
+// cert-manager CertificateRequest, and returns the signed certificate and its chain. Until
+// the issuer signed it, the returned error wraps ErrUploadPending.
+func (d *Driver) signCSR(ctx context.Context, changePath string, entry csr) ([]byte, []byte, error) {
+	name := fmt.Sprintf("%s-akamai-%s-%s", d.request.NamePrefix, path.Base(changePath), strings.ToLower(entry.KeyAlgorithm))
+
+	request := &certmanagerv1.CertificateRequest{}
+	err := d.client.Get(ctx, types.NamespacedName{Name: name, Namespace: d.request.Namespace}, request)
+	if apierrors.IsNotFound(err) {
+		request = d.newCertificateRequest(name, entry)
+		if err := d.client.Create(ctx, request); err != nil {
+			return nil, nil, fmt.Errorf("failed to create CertificateRequest %s for the Akamai CPS CSR: %w", name, err)
+		}
+		return nil, nil, fmt.Errorf("%w: waiting for the issuer to sign CertificateRequest %s", drivertypes.ErrUploadPending, name)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get CertificateRequest %s: %w", name, err)
+	}
+
+	for _, condition := range request.Status.Conditions {
+		switch {
+		case condition.Type == certmanagerv1.CertificateRequestConditionDenied && condition.Status == cmmeta.ConditionTrue,
+			condition.Type == certmanagerv1.CertificateRequestConditionInvalidRequest && condition.Status == cmmeta.ConditionTrue:
+			return nil, nil, fmt.Errorf("CertificateRequest %s for the Akamai CPS CSR is %s: %s", name, condition.Type, condition.Message)
+		case condition.Type == certmanagerv1.CertificateRequestConditionReady && condition.Status == cmmeta.ConditionFalse &&
+			condition.Reason == certmanagerv1.CertificateRequestReasonFailed:
+			return nil, nil, fmt.Errorf("issuer failed to sign CertificateRequest %s for the Akamai CPS CSR: %s", name, condition.Message)
+		}
+	}
+	if len(request.Status.Certificate) == 0 {
+		return nil, nil, fmt.Errorf("%w: waiting for the issuer to sign CertificateRequest %s", drivertypes.ErrUploadPending, name)
+	}
+
+	certificate, chain := drivertypes.SplitCertificateChain(request.Status.Certificate)
+	return certificate, chain, nil
+}
+
+// newCertificateRequest returns the CertificateRequest named name for a CSR of a change
+func (d *Driver) newCertificateRequest(name string, entry csr) *certmanagerv1.CertificateRequest {
+	managedBy := d.request.ManagedBy
+	if managedBy == "" {
+		managedBy = "certificate-operator"
+	}
+	return &certmanagerv1.CertificateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       d.request.Namespace,
+			Labels:          map[string]string{managedByLabel: managedBy},
+			OwnerReferences: d.request.OwnerReferences,
+		},
+		Spec: certmanagerv1.CertificateRequestSpec{
+			Request: []byte(entry.CSR),
+			IssuerRef: cmmeta.ObjectReference{
+				Name:  d.request.IssuerName,
+				Kind:  d.request.IssuerKind,
+				Group: "cert-manager.io",
+			},
+			Usages: []certmanagerv1.KeyUsage{
+				certmanagerv1.UsageDigitalSignature,
+				certmanagerv1.UsageKeyEncipherment,
+				certmanagerv1.UsageServerAuth,
+			},
+		},
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// uploadApproved reports whether the approved annotation of cert is set to true
+func uploadApproved(cert *certificatev1alpha1.Certificate) bool {
+	approved, err := strconv.ParseBool(cert.Annotations[certificatev1alpha1.ApprovedAnnotation])
+	return err == nil && approved
+}
+
+// setPendingApprovalCondition records whether the upload is approved in the PendingApproval
+// condition and reports whether the condition changed
+func setPendingApprovalCondition(cert *certificatev1alpha1.Certificate, approved bool) bool {
+	condition := metav1.Condition{
+		Type:               certificatev1alpha1.ConditionPendingApproval,
+		Status:             metav1.ConditionFalse,
+		Reason:             certificatev1alpha1.ReasonApproved,
+		Message:            "Upload approved",
+		ObservedGeneration: cert.Generation,
+	}
+	if !approved {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = certificatev1alpha1.ReasonAwaitingApproval
+		condition.Message = fmt.Sprintf("Upload withheld until the %s annotation is set to \"true\"",
+			certificatev1alpha1.ApprovedAnnotation)
+	}
+	return meta.SetStatusCondition(&cert.Status.Conditions, condition)
+}
@@ -18,21 +18,69 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
 	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/tae2089/certificate-operator/internal/driver/retry"
 	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
 )
 
+// roleSessionName identifies the operator in CloudTrail when assuming roles
+const roleSessionName = "certificate-operator"
+
+// Accepted Secret keys for each credential, in order of precedence. The aliases match the
+// naming used by the AWS CLI credentials file, environment variables, and other operators.
+var (
+	accessKeyIDKeys     = []string{"access-key-id", "aws_access_key_id", "AWS_ACCESS_KEY_ID", "accessKeyId"}
+	secretAccessKeyKeys = []string{"secret-access-key", "aws_secret_access_key", "AWS_SECRET_ACCESS_KEY", "secretAccessKey"}
+	sessionTokenKeys    = []string{"session-token", "aws_session_token", "AWS_SESSION_TOKEN", "sessionToken"}
+	regionKeys          = []string{"region", "aws_region", "AWS_REGION", "AWS_DEFAULT_REGION"}
+)
+
+// Chain modes of an import, matching the values of spec.aws.chainMode
+const (
+	ChainModeInline   = "inline"
+	ChainModeSeparate = "separate"
+)
+
+// ErrRegionNotSet is returned when none of the region sources of a driver is set, before
+// the AWS SDK fails the request with a less helpful error
+var ErrRegionNotSet = errors.New("no AWS region configured")
+
+// ErrCertificateRejected is returned when ACM rejects the imported certificate, most often
+// because its chain doesn't build to a root ACM trusts
+var ErrCertificateRejected = errors.New("ACM rejected the certificate")
+
+// acmAPI is the subset of the ACM client used by the driver
+type acmAPI interface {
+	ImportCertificate(ctx context.Context, params *acm.ImportCertificateInput, optFns ...func(*acm.Options)) (*acm.ImportCertificateOutput, error)
+	DeleteCertificate(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error)
+	ListTagsForCertificate(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error)
+	AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error)
+	RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error)
+	GetCertificate(ctx context.Context, params *acm.GetCertificateInput, optFns ...func(*acm.Options)) (*acm.GetCertificateOutput, error)
+	DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error)
+}
+
 // Driver implements the CloudProvider interface for AWS ACM
 type Driver struct {
 	client         client.Client
@@ -40,6 +88,16 @@ type Driver struct {
 	secretRef      string
 	namespace      string
 	domain         string
+	assumeRoleARN  string
+	chainMode      string
+	privateCA      bool
+	skipUnchanged  bool
+	defaultRegion  string
+	backoff        retry.Backoff
+
+	// Client factories, overridable for testing
+	newACMClient func(cfg aws.Config) acmAPI
+	newSTSClient func(cfg aws.Config) stscreds.AssumeRoleAPIClient
 }
 
 // Config holds AWS driver configuration
@@ -49,6 +107,12 @@ type Config struct {
 	SecretRef      string // Empty string means use IRSA/Instance Profile
 	Namespace      string
 	Domain         string
+	MaxRetries     int    // Retries for throttled or failed (5xx) imports
+	AssumeRoleARN  string // Role to assume via STS for cross-account imports, empty for the base account
+	ChainMode      string // ChainModeInline or ChainModeSeparate, empty for inline
+	PrivateCA      bool   // Import certificates without intermediates when the chain is separate
+	SkipUnchanged  bool   // Skip re-imports to ExistingID when ACM already holds the same certificates
+	DefaultRegion  string // Region used when neither the Secret nor the environment sets one
 }
 
 // NewDriver creates a new AWS ACM driver
@@ -59,6 +123,18 @@ func NewDriver(cfg Config) *Driver {
 		secretRef:      cfg.SecretRef,
 		namespace:      cfg.Namespace,
 		domain:         cfg.Domain,
+		assumeRoleARN:  cfg.AssumeRoleARN,
+		chainMode:      cfg.ChainMode,
+		privateCA:      cfg.PrivateCA,
+		skipUnchanged:  cfg.SkipUnchanged,
+		defaultRegion:  cfg.DefaultRegion,
+		backoff:        retry.Backoff{MaxRetries: cfg.MaxRetries},
+		newACMClient: func(cfg aws.Config) acmAPI {
+			return acm.NewFromConfig(cfg)
+		},
+		newSTSClient: func(cfg aws.Config) stscreds.AssumeRoleAPIClient {
+			return sts.NewFromConfig(cfg)
+		},
 	}
 }
 
@@ -71,100 +147,379 @@ func (d *Driver) Name() string {
 func (d *Driver) Upload(ctx context.Context, certData drivertypes.CertificateData) (drivertypes.UploadResult, error) {
 	log := logf.FromContext(ctx)
 
-	cfg, err := d.loadAWSConfig(ctx)
+	cfg, err := d.awsConfig(ctx)
 	if err != nil {
 		return drivertypes.UploadResult{}, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	// Import certificate (re-import if ARN exists for renewal)
+	input, err := d.importInput(certData)
+	if err != nil {
+		return drivertypes.UploadResult{}, err
+	}
+
 	// Create ACM client
-	acmClient := acm.NewFromConfig(cfg)
+	acmClient := d.newACMClient(cfg)
 
-	// Import certificate (re-import if ARN exists for renewal)
-	input := &acm.ImportCertificateInput{
-		Certificate: certData.Certificate,
-		PrivateKey:  certData.PrivateKey,
-		Tags: []acmtypes.Tag{
-			{
-				Key:   aws.String("ManagedBy"),
-				Value: aws.String("certificate-operator"),
-			},
-			{
-				Key:   aws.String("Domain"),
-				Value: aws.String(certData.Domain),
-			},
-		},
+	// Re-importing the certificate ACM already holds only counts as a new import
+	if certData.ExistingID != "" && d.skipUnchanged {
+		unchanged, err := d.importedUnchanged(ctx, acmClient, certData)
+		if err != nil {
+			log.Info("Failed to read the imported certificate, re-importing", "arn", certData.ExistingID, "error", err.Error())
+		}
+		if unchanged {
+			log.Info("AWS ACM already holds the certificate, skipping re-import", "arn", certData.ExistingID)
+			if err := d.syncTags(ctx, acmClient, certData.ExistingID, certificateTags(certData)); err != nil {
+				return drivertypes.UploadResult{}, fmt.Errorf("failed to update tags in AWS ACM: %w", err)
+			}
+			return drivertypes.UploadResult{
+				Identifier: certData.ExistingID,
+				ACM:        d.describe(ctx, acmClient, certData.ExistingID),
+			}, nil
+		}
 	}
 
-	// If certificate already exists, re-import using the same ARN
+	// If certificate already exists, re-import using the same ARN. ACM rejects tags on
+	// re-import, so they are synced once the import succeeded.
 	if certData.ExistingID != "" {
 		log.Info("Re-importing certificate to existing ARN", "arn", certData.ExistingID)
 		input.CertificateArn = aws.String(certData.ExistingID)
+	} else {
+		input.Tags = certificateTags(certData)
 	}
 
-	result, err := acmClient.ImportCertificate(ctx, input)
+	// Retry throttled and server-side failures with backoff
+	var result *acm.ImportCertificateOutput
+	err = d.backoff.Do(ctx, func(ctx context.Context) error {
+		var importErr error
+		result, importErr = acmClient.ImportCertificate(ctx, input)
+		return ClassifyError(importErr)
+	})
 	if err != nil {
-		return drivertypes.UploadResult{}, fmt.Errorf("failed to import certificate to AWS ACM: %w", err)
+		return drivertypes.UploadResult{}, fmt.Errorf("failed to import certificate to AWS ACM: %w", d.importError(err))
 	}
 
+	if certData.ExistingID != "" {
+		if err := d.syncTags(ctx, acmClient, certData.ExistingID, certificateTags(certData)); err != nil {
+			return drivertypes.UploadResult{}, fmt.Errorf("failed to update tags in AWS ACM: %w", err)
+		}
+	}
+
+	certificateARN := aws.ToString(result.CertificateArn)
 	return drivertypes.UploadResult{
-		Identifier: aws.ToString(result.CertificateArn),
+		Identifier: certificateARN,
+		ACM:        d.describe(ctx, acmClient, certificateARN),
 	}, nil
 }
 
+// describe reads the state ACM reports for the certificate imported under certificateARN.
+// The import succeeded already, so a failed read is only logged and returns nil.
+func (d *Driver) describe(ctx context.Context, acmClient acmAPI, certificateARN string) *drivertypes.ACMCertificateDetails {
+	var output *acm.DescribeCertificateOutput
+	err := d.backoff.Do(ctx, func(ctx context.Context) error {
+		var describeErr error
+		output, describeErr = acmClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+			CertificateArn: aws.String(certificateARN),
+		})
+		return ClassifyError(describeErr)
+	})
+	if err != nil {
+		logf.FromContext(ctx).Info("Failed to describe the imported certificate", "arn", certificateARN, "error", err.Error())
+		return nil
+	}
+	if output.Certificate == nil {
+		return nil
+	}
+
+	detail := output.Certificate
+	return &drivertypes.ACMCertificateDetails{
+		Status:             string(detail.Status),
+		Type:               string(detail.Type),
+		RenewalEligibility: string(detail.RenewalEligibility),
+		InUseByCount:       len(detail.InUseBy),
+		ImportedAt:         aws.ToTime(detail.ImportedAt),
+		FailureReason:      string(detail.FailureReason),
+	}
+}
+
+// importInput returns the import of the certificate data, with the chain passed as
+// configured by the chain mode
+func (d *Driver) importInput(certData drivertypes.CertificateData) (*acm.ImportCertificateInput, error) {
+	input := &acm.ImportCertificateInput{
+		Certificate: certData.FullChain(),
+		PrivateKey:  certData.PrivateKey,
+	}
+	if d.chainMode != ChainModeSeparate {
+		return input, nil
+	}
+
+	if len(certData.Chain) == 0 && !d.privateCA {
+		return nil, fmt.Errorf("certificate has no intermediates to import as the chain, set aws.privateCA to import certificates from a private CA without one")
+	}
+	input.Certificate = certData.Certificate
+	if len(certData.Chain) > 0 {
+		input.CertificateChain = certData.Chain
+	}
+	return input, nil
+}
+
+// importError maps an ACM ValidationException to ErrCertificateRejected, with a hint at
+// the settings that import certificates from a private CA
+func (d *Driver) importError(err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ValidationException" {
+		return err
+	}
+	if d.chainMode == ChainModeSeparate && d.privateCA {
+		return fmt.Errorf("%w: %w", ErrCertificateRejected, err)
+	}
+	return fmt.Errorf("%w (for a certificate from a private CA, set aws.chainMode to separate and aws.privateCA to true): %w",
+		ErrCertificateRejected, err)
+}
+
+// certificateTags returns the tags of an imported certificate: the tags identifying it as
+// managed by the operator and the extra tags of the certificate data, sorted by key
+func certificateTags(certData drivertypes.CertificateData) []acmtypes.Tag {
+	values := maps.Clone(certData.Tags)
+	if values == nil {
+		values = map[string]string{}
+	}
+	values["ManagedBy"] = "certificate-operator"
+	values["Domain"] = certData.Domain
+
+	tags := make([]acmtypes.Tag, 0, len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		tags = append(tags, acmtypes.Tag{Key: aws.String(key), Value: aws.String(values[key])})
+	}
+	return tags
+}
+
+// syncTags sets tags on an existing certificate and removes the tags it no longer has
+func (d *Driver) syncTags(ctx context.Context, acmClient acmAPI, certificateARN string, tags []acmtypes.Tag) error {
+	var current *acm.ListTagsForCertificateOutput
+	err := d.backoff.Do(ctx, func(ctx context.Context) error {
+		var listErr error
+		current, listErr = acmClient.ListTagsForCertificate(ctx, &acm.ListTagsForCertificateInput{
+			CertificateArn: aws.String(certificateARN),
+		})
+		return ClassifyError(listErr)
+	})
+	if err != nil {
+		return err
+	}
+
+	var stale []acmtypes.Tag
+	for _, tag := range current.Tags {
+		if !slices.ContainsFunc(tags, func(t acmtypes.Tag) bool { return aws.ToString(t.Key) == aws.ToString(tag.Key) }) {
+			// Without a value, the tag is removed whatever its value
+			stale = append(stale, acmtypes.Tag{Key: tag.Key})
+		}
+	}
+	if len(stale) > 0 {
+		err = d.backoff.Do(ctx, func(ctx context.Context) error {
+			_, removeErr := acmClient.RemoveTagsFromCertificate(ctx, &acm.RemoveTagsFromCertificateInput{
+				CertificateArn: aws.String(certificateARN),
+				Tags:           stale,
+			})
+			return ClassifyError(removeErr)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Existing keys are overwritten with the new values
+	return d.backoff.Do(ctx, func(ctx context.Context) error {
+		_, addErr := acmClient.AddTagsToCertificate(ctx, &acm.AddTagsToCertificateInput{
+			CertificateArn: aws.String(certificateARN),
+			Tags:           tags,
+		})
+		return ClassifyError(addErr)
+	})
+}
+
 // Delete deletes a certificate from AWS ACM
 func (d *Driver) Delete(ctx context.Context, identifier string) error {
-	cfg, err := d.loadAWSConfig(ctx)
+	cfg, err := d.awsConfig(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	acmClient := acm.NewFromConfig(cfg)
+	acmClient := d.newACMClient(cfg)
 
 	// Delete the certificate
 	_, err = acmClient.DeleteCertificate(ctx, &acm.DeleteCertificateInput{
 		CertificateArn: aws.String(identifier),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete certificate from AWS ACM: %w", err)
+		return fmt.Errorf("failed to delete certificate from AWS ACM: %w", ClassifyError(err))
 	}
 
 	return nil
 }
 
-// loadAWSConfig loads AWS configuration based on credential type
-func (d *Driver) loadAWSConfig(ctx context.Context) (aws.Config, error) {
+// Verify reads the certificate and chain imported under identifier back from ACM and
+// compares their fingerprints with the uploaded bundle
+func (d *Driver) Verify(ctx context.Context, identifier string, certData drivertypes.CertificateData) error {
+	cfg, err := d.awsConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	stored, err := d.storedFingerprints(ctx, d.newACMClient(cfg), identifier)
+	if err != nil {
+		return err
+	}
+	uploaded := drivertypes.CertificateFingerprints(certData.FullChain())
+	if !slices.Equal(stored, uploaded) {
+		return fmt.Errorf("%w: AWS ACM holds %d certificates with fingerprints %v, uploaded %v",
+			drivertypes.ErrVerificationMismatch, len(stored), stored, uploaded)
+	}
+	return nil
+}
+
+// importedUnchanged reports whether the certificate imported under certData.ExistingID has
+// the fingerprints of the certificate data
+func (d *Driver) importedUnchanged(ctx context.Context, acmClient acmAPI, certData drivertypes.CertificateData) (bool, error) {
+	stored, err := d.storedFingerprints(ctx, acmClient, certData.ExistingID)
+	if err != nil {
+		return false, err
+	}
+	uploaded := drivertypes.CertificateFingerprints(certData.FullChain())
+	return len(uploaded) > 0 && slices.Equal(stored, uploaded), nil
+}
+
+// storedFingerprints reads the certificate and chain imported under identifier from ACM and
+// returns their fingerprints
+func (d *Driver) storedFingerprints(ctx context.Context, acmClient acmAPI, identifier string) ([]string, error) {
+	var output *acm.GetCertificateOutput
+	err := d.backoff.Do(ctx, func(ctx context.Context) error {
+		var getErr error
+		output, getErr = acmClient.GetCertificate(ctx, &acm.GetCertificateInput{
+			CertificateArn: aws.String(identifier),
+		})
+		return ClassifyError(getErr)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate from AWS ACM: %w", err)
+	}
+	return drivertypes.CertificateFingerprints([]byte(aws.ToString(output.Certificate) + "\n" + aws.ToString(output.CertificateChain))), nil
+}
+
+// awsConfig loads the AWS configuration and, when an assume role ARN is set,
+// switches to temporary credentials for that role
+func (d *Driver) awsConfig(ctx context.Context) (aws.Config, error) {
+	cfg, err := LoadConfig(ctx, Credentials{
+		Client:         d.client,
+		CredentialType: d.credentialType,
+		SecretRef:      d.secretRef,
+		Namespace:      d.namespace,
+		DefaultRegion:  d.defaultRegion,
+	})
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.Region == "" {
+		return cfg, RegionNotSetError(Credentials{
+			CredentialType: d.credentialType,
+			SecretRef:      d.secretRef,
+			Namespace:      d.namespace,
+		})
+	}
+	if d.assumeRoleARN == "" {
+		return cfg, nil
+	}
+
+	logf.FromContext(ctx).Info("Assuming AWS role for cross-account access", "roleARN", d.assumeRoleARN)
+	cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(
+		d.newSTSClient(cfg),
+		d.assumeRoleARN,
+		func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = roleSessionName
+		},
+	))
+	return cfg, nil
+}
+
+// AccountIDFromRoleARN returns the AWS account ID of an IAM role ARN
+func AccountIDFromRoleARN(roleARN string) (string, error) {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return "", fmt.Errorf("invalid role ARN %q: %w", roleARN, err)
+	}
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") || parsed.AccountID == "" {
+		return "", fmt.Errorf("invalid role ARN %q: not an IAM role", roleARN)
+	}
+	return parsed.AccountID, nil
+}
+
+// Credentials locates the AWS credentials of a driver
+type Credentials struct {
+	Client         client.Client
+	CredentialType string
+	SecretRef      string // Empty string means use IRSA/Instance Profile
+	Namespace      string
+	DefaultRegion  string // Region used when neither the Secret nor the environment sets one
+}
+
+// LoadConfig loads AWS configuration based on credential type. It is shared by the
+// drivers of all AWS services. The region is read from the Secret, then the operator's
+// environment and shared config, then creds.DefaultRegion, and may still be empty.
+func LoadConfig(ctx context.Context, creds Credentials) (aws.Config, error) {
+	cfg, err := loadConfig(ctx, creds)
+	if err == nil && cfg.Region == "" {
+		cfg.Region = creds.DefaultRegion
+	}
+	return cfg, err
+}
+
+// RegionNotSetError returns ErrRegionNotSet with where the region can be set, starting with
+// the given spec fields that override the region of the credentials
+func RegionNotSetError(creds Credentials, specFields ...string) error {
+	sources := slices.Clone(specFields)
+	if creds.CredentialType == "access-key" {
+		sources = append(sources, fmt.Sprintf("the region key of Secret %s/%s", creds.Namespace, creds.SecretRef))
+	}
+	sources = append(sources, "AWS_REGION in the operator's environment", "the operator's --default-aws-region flag")
+	return fmt.Errorf("%w: set %s, or %s", ErrRegionNotSet,
+		strings.Join(sources[:len(sources)-1], ", "), sources[len(sources)-1])
+}
+
+// loadConfig loads the AWS configuration of creds without the default region
+func loadConfig(ctx context.Context, creds Credentials) (aws.Config, error) {
 	log := logf.FromContext(ctx)
 
-	switch d.credentialType {
+	switch creds.CredentialType {
 	case "access-key":
 		// Use static credentials from Kubernetes Secret
-		if d.secretRef == "" {
+		if creds.SecretRef == "" {
 			return aws.Config{}, fmt.Errorf("secretRef is required when using access-key credential type")
 		}
 
 		// Get AWS credentials from Secret
 		awsSecret := &corev1.Secret{}
-		if err := d.client.Get(ctx, types.NamespacedName{
-			Name:      d.secretRef,
-			Namespace: d.namespace,
+		if err := creds.Client.Get(ctx, types.NamespacedName{
+			Name:      creds.SecretRef,
+			Namespace: creds.Namespace,
 		}, awsSecret); err != nil {
 			return aws.Config{}, fmt.Errorf("failed to get AWS secret: %w", err)
 		}
 
-		accessKeyID := string(awsSecret.Data["access-key-id"])
-		secretAccessKey := string(awsSecret.Data["secret-access-key"])
-		region := string(awsSecret.Data["region"])
+		accessKeyID := secretValue(awsSecret, accessKeyIDKeys)
+		secretAccessKey := secretValue(awsSecret, secretAccessKeyKeys)
+		sessionToken := secretValue(awsSecret, sessionTokenKeys)
+		region := secretValue(awsSecret, regionKeys)
 
 		if accessKeyID == "" || secretAccessKey == "" {
 			return aws.Config{}, fmt.Errorf("AWS credentials incomplete in secret (access-key-id and secret-access-key required)")
 		}
 
-		// Create AWS config with static credentials
+		// Create AWS config with static credentials, temporary if a session token is set
 		configOpts := []func(*config.LoadOptions) error{
 			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
 				accessKeyID,
 				secretAccessKey,
-				"",
+				sessionToken,
 			)),
 		}
 
@@ -173,15 +528,52 @@ func (d *Driver) loadAWSConfig(ctx context.Context) (aws.Config, error) {
 			configOpts = append(configOpts, config.WithRegion(region))
 		}
 
-		log.Info("Using AWS access-key credentials from secret", "secretRef", d.secretRef)
+		log.Info("Using AWS access-key credentials from secret", "secretRef", creds.SecretRef)
 		return config.LoadDefaultConfig(ctx, configOpts...)
 
 	case "assume-role", "":
 		// Use default credential chain (IRSA, Instance Profile, etc.)
-		log.Info("Using AWS default credential chain (IRSA/Instance Profile/AssumeRole)", "credentialType", d.credentialType)
+		log.Info("Using AWS default credential chain (IRSA/Instance Profile/AssumeRole)", "credentialType", creds.CredentialType)
 		return config.LoadDefaultConfig(ctx)
 
 	default:
-		return aws.Config{}, fmt.Errorf("unsupported credential type: %s (supported types: access-key, assume-role)", d.credentialType)
+		return aws.Config{}, fmt.Errorf("unsupported credential type: %s (supported types: access-key, assume-role)", creds.CredentialType)
+	}
+}
+
+// secretValue returns the value of the first of keys set in the Secret
+func secretValue(secret *corev1.Secret, keys []string) string {
+	for _, key := range keys {
+		if value := secret.Data[key]; len(value) > 0 {
+			return string(value)
+		}
+	}
+	return ""
+}
+
+// throttlingErrorCodes are the AWS API error codes returned when requests are rate limited
+var throttlingErrorCodes = map[string]bool{
+	"ThrottlingException":      true,
+	"Throttling":               true,
+	"TooManyRequestsException": true,
+	"RequestLimitExceeded":     true,
+	"SlowDown":                 true, // S3
+}
+
+// ClassifyError marks throttling and server-side AWS failures as retriable
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && throttlingErrorCodes[apiErr.ErrorCode()] {
+		return drivertypes.NewRetriableError(err)
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() >= http.StatusInternalServerError {
+		return drivertypes.NewRetriableError(err)
 	}
+	return err
 }
@@ -0,0 +1,614 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// fakeACM returns the queued errors in order before succeeding, and keeps the tags
+// of a single certificate. GetCertificate returns the last import unless stored or getErr is set.
+// DescribeCertificate returns described, or an error when describeErr is set.
+type fakeACM struct {
+	importErrs    []error
+	importCalls   int
+	lastImport    *acm.ImportCertificateInput
+	tags          map[string]string
+	stored        *acm.GetCertificateOutput
+	getErr        error
+	getCalls      int
+	described     *acmtypes.CertificateDetail
+	describeErr   error
+	describedARNs []string
+}
+
+func (f *fakeACM) ImportCertificate(_ context.Context, params *acm.ImportCertificateInput, _ ...func(*acm.Options)) (*acm.ImportCertificateOutput, error) {
+	f.importCalls++
+	f.lastImport = params
+	if len(f.importErrs) > 0 {
+		err := f.importErrs[0]
+		f.importErrs = f.importErrs[1:]
+		return nil, err
+	}
+	return &acm.ImportCertificateOutput{CertificateArn: aws.String("arn:aws:acm:us-east-1:123456789012:certificate/test")}, nil
+}
+
+func (f *fakeACM) DeleteCertificate(_ context.Context, _ *acm.DeleteCertificateInput, _ ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error) {
+	return &acm.DeleteCertificateOutput{}, nil
+}
+
+func (f *fakeACM) ListTagsForCertificate(_ context.Context, _ *acm.ListTagsForCertificateInput, _ ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error) {
+	var tags []acmtypes.Tag
+	for key, value := range f.tags {
+		tags = append(tags, acmtypes.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return &acm.ListTagsForCertificateOutput{Tags: tags}, nil
+}
+
+func (f *fakeACM) AddTagsToCertificate(_ context.Context, params *acm.AddTagsToCertificateInput, _ ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+	if f.tags == nil {
+		f.tags = map[string]string{}
+	}
+	for _, tag := range params.Tags {
+		f.tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return &acm.AddTagsToCertificateOutput{}, nil
+}
+
+func (f *fakeACM) RemoveTagsFromCertificate(_ context.Context, params *acm.RemoveTagsFromCertificateInput, _ ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+	for _, tag := range params.Tags {
+		delete(f.tags, aws.ToString(tag.Key))
+	}
+	return &acm.RemoveTagsFromCertificateOutput{}, nil
+}
+
+func (f *fakeACM) GetCertificate(_ context.Context, _ *acm.GetCertificateInput, _ ...func(*acm.Options)) (*acm.GetCertificateOutput, error) {
+	f.getCalls++
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	if f.stored != nil {
+		return f.stored, nil
+	}
+	return &acm.GetCertificateOutput{
+		Certificate:      aws.String(string(f.lastImport.Certificate)),
+		CertificateChain: aws.String(string(f.lastImport.CertificateChain)),
+	}, nil
+}
+
+func (f *fakeACM) DescribeCertificate(_ context.Context, params *acm.DescribeCertificateInput, _ ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	f.describedARNs = append(f.describedARNs, aws.ToString(params.CertificateArn))
+	if f.describeErr != nil {
+		return nil, f.describeErr
+	}
+	return &acm.DescribeCertificateOutput{Certificate: f.described}, nil
+}
+
+// fakeSTS issues fixed temporary credentials and records the assumed roles.
+type fakeSTS struct {
+	assumedRoles []string
+}
+
+func (f *fakeSTS) AssumeRole(_ context.Context, params *sts.AssumeRoleInput, _ ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	f.assumedRoles = append(f.assumedRoles, aws.ToString(params.RoleArn))
+	return &sts.AssumeRoleOutput{
+		Credentials: &ststypes.Credentials{
+			AccessKeyId:     aws.String("ASIAASSUMED"),
+			SecretAccessKey: aws.String("assumed-secret"),
+			SessionToken:    aws.String("assumed-token"),
+			Expiration:      aws.Time(time.Now().Add(time.Hour)),
+		},
+	}, nil
+}
+
+var _ = Describe("Driver", func() {
+	var (
+		ctx = context.Background()
+		api *fakeACM
+	)
+
+	newTestDriver := func(maxRetries int) *Driver {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials", Namespace: "default"},
+			Data: map[string][]byte{
+				"access-key-id":     []byte("AKIAEXAMPLE"),
+				"secret-access-key": []byte("secret"),
+				"region":            []byte("us-east-1"),
+			},
+		}
+		d := NewDriver(Config{
+			Client:         fake.NewClientBuilder().WithObjects(secret).Build(),
+			CredentialType: "access-key",
+			SecretRef:      "aws-credentials",
+			Namespace:      "default",
+			Domain:         "example.com",
+			MaxRetries:     maxRetries,
+		})
+		d.backoff.BaseDelay = time.Millisecond
+		d.newACMClient = func(aws.Config) acmAPI { return api }
+		return d
+	}
+
+	throttled := func() error {
+		return &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+	}
+
+	BeforeEach(func() {
+		api = &fakeACM{}
+	})
+
+	Context("when ImportCertificate is throttled", func() {
+		It("should retry until the import succeeds", func() {
+			api.importErrs = []error{throttled(), throttled()}
+
+			result, err := newTestDriver(3).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Identifier).To(HavePrefix("arn:aws:acm"))
+			Expect(api.importCalls).To(Equal(3))
+		})
+
+		It("should return a retriable error once retries are exhausted", func() {
+			api.importErrs = []error{throttled(), throttled(), throttled()}
+
+			_, err := newTestDriver(1).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).To(HaveOccurred())
+			Expect(drivertypes.IsRetriable(err)).To(BeTrue())
+			Expect(api.importCalls).To(Equal(2))
+		})
+	})
+
+	Context("when ImportCertificate fails with a client error", func() {
+		It("should not retry", func() {
+			api.importErrs = []error{&smithy.GenericAPIError{Code: "ValidationException", Message: "bad certificate"}}
+
+			_, err := newTestDriver(3).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).To(HaveOccurred())
+			Expect(drivertypes.IsRetriable(err)).To(BeFalse())
+			Expect(api.importCalls).To(Equal(1))
+		})
+	})
+
+	Context("when the certificate is issued by a private CA", func() {
+		var (
+			leaf         = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("leaf")})
+			intermediate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("intermediate")})
+			root         = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("root")})
+		)
+
+		newPrivateCADriver := func(privateCA bool) *Driver {
+			d := newTestDriver(0)
+			d.chainMode = ChainModeSeparate
+			d.privateCA = privateCA
+			return d
+		}
+
+		It("should import a full chain separately from the leaf", func() {
+			chain := slices.Concat(intermediate, root)
+
+			_, err := newPrivateCADriver(true).Upload(ctx, drivertypes.CertificateData{Domain: "example.com", Certificate: leaf, Chain: chain})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.lastImport.Certificate).To(Equal(leaf))
+			Expect(api.lastImport.CertificateChain).To(Equal(chain))
+		})
+
+		It("should import a leaf without a chain", func() {
+			_, err := newPrivateCADriver(true).Upload(ctx, drivertypes.CertificateData{Domain: "example.com", Certificate: leaf})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.lastImport.Certificate).To(Equal(leaf))
+			Expect(api.lastImport.CertificateChain).To(BeNil())
+		})
+
+		It("should refuse a leaf without a chain unless privateCA is set", func() {
+			_, err := newPrivateCADriver(false).Upload(ctx, drivertypes.CertificateData{Domain: "example.com", Certificate: leaf})
+			Expect(err).To(MatchError(ContainSubstring("no intermediates")))
+			Expect(api.importCalls).To(BeZero())
+		})
+
+		It("should import the bundle as the certificate with the inline chain mode", func() {
+			_, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{Domain: "example.com", Certificate: leaf, Chain: intermediate})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.lastImport.Certificate).To(Equal(slices.Concat(leaf, intermediate)))
+			Expect(api.lastImport.CertificateChain).To(BeNil())
+		})
+
+		It("should map a ValidationException to ErrCertificateRejected with a hint", func() {
+			api.importErrs = []error{&smithy.GenericAPIError{Code: "ValidationException", Message: "Could not validate the certificate with the certificate chain."}}
+
+			_, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{Domain: "example.com", Certificate: leaf})
+			Expect(err).To(MatchError(ErrCertificateRejected))
+			Expect(err).To(MatchError(ContainSubstring("aws.privateCA")))
+			Expect(err).To(MatchError(ContainSubstring("Could not validate the certificate")))
+			Expect(drivertypes.IsRetriable(err)).To(BeFalse())
+		})
+	})
+
+	Context("when verifying an import", func() {
+		var (
+			leaf         = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("leaf")})
+			intermediate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("intermediate")})
+			certData     = drivertypes.CertificateData{Domain: "example.com", Certificate: leaf, Chain: intermediate}
+			arn          = "arn:aws:acm:us-east-1:123456789012:certificate/test"
+		)
+
+		It("should accept a copy matching the uploaded bundle", func() {
+			d := newTestDriver(0)
+			d.chainMode = ChainModeSeparate
+
+			_, err := d.Upload(ctx, certData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(d.Verify(ctx, arn, certData)).To(Succeed())
+		})
+
+		It("should report a copy that is missing the chain", func() {
+			api.stored = &acm.GetCertificateOutput{Certificate: aws.String(string(leaf))}
+
+			err := newTestDriver(0).Verify(ctx, arn, certData)
+			Expect(err).To(MatchError(drivertypes.ErrVerificationMismatch))
+		})
+
+		It("should report a copy of another certificate", func() {
+			other := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("other")})
+			api.stored = &acm.GetCertificateOutput{Certificate: aws.String(string(other)), CertificateChain: aws.String(string(intermediate))}
+
+			err := newTestDriver(0).Verify(ctx, arn, certData)
+			Expect(err).To(MatchError(drivertypes.ErrVerificationMismatch))
+		})
+	})
+
+	Context("when unchanged re-imports are skipped", func() {
+		var (
+			leaf         = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("leaf")})
+			intermediate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("intermediate")})
+			arn          = "arn:aws:acm:us-east-1:111111111111:certificate/existing"
+			certData     = drivertypes.CertificateData{
+				Domain:      "example.com",
+				Certificate: leaf,
+				Chain:       intermediate,
+				ExistingID:  arn,
+				Tags:        map[string]string{"team": "platform"},
+			}
+		)
+
+		newSkippingDriver := func() *Driver {
+			d := newTestDriver(0)
+			d.skipUnchanged = true
+			return d
+		}
+
+		It("should skip the re-import when ACM holds the same certificates", func() {
+			api.stored = &acm.GetCertificateOutput{Certificate: aws.String(string(leaf)), CertificateChain: aws.String(string(intermediate))}
+
+			result, err := newSkippingDriver().Upload(ctx, certData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Identifier).To(Equal(arn))
+			Expect(api.importCalls).To(BeZero())
+			Expect(api.tags).To(HaveKeyWithValue("team", "platform"))
+		})
+
+		It("should report the ACM state of the certificate it didn't re-import", func() {
+			api.stored = &acm.GetCertificateOutput{Certificate: aws.String(string(leaf)), CertificateChain: aws.String(string(intermediate))}
+			api.described = &acmtypes.CertificateDetail{Status: acmtypes.CertificateStatusIssued, InUseBy: []string{"arn:aws:elasticloadbalancing:lb"}}
+
+			result, err := newSkippingDriver().Upload(ctx, certData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.describedARNs).To(Equal([]string{arn}))
+			Expect(result.ACM).NotTo(BeNil())
+			Expect(result.ACM.InUseByCount).To(Equal(1))
+		})
+
+		It("should re-import a renewed certificate", func() {
+			renewed := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("renewed")})
+			api.stored = &acm.GetCertificateOutput{Certificate: aws.String(string(leaf)), CertificateChain: aws.String(string(intermediate))}
+			renewal := certData
+			renewal.Certificate = renewed
+
+			_, err := newSkippingDriver().Upload(ctx, renewal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.importCalls).To(Equal(1))
+			Expect(aws.ToString(api.lastImport.CertificateArn)).To(Equal(arn))
+		})
+
+		It("should re-import when the imported certificate can't be read", func() {
+			api.getErr = &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: "Could not find certificate"}
+
+			_, err := newSkippingDriver().Upload(ctx, certData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.importCalls).To(Equal(1))
+		})
+
+		It("should not read the imported certificate unless enabled", func() {
+			api.stored = &acm.GetCertificateOutput{Certificate: aws.String(string(leaf)), CertificateChain: aws.String(string(intermediate))}
+
+			_, err := newTestDriver(0).Upload(ctx, certData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.getCalls).To(BeZero())
+			Expect(api.importCalls).To(Equal(1))
+		})
+	})
+
+	Context("when reading the ACM state of an import", func() {
+		It("should report the status, renewal eligibility, and usage of the imported certificate", func() {
+			importedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+			api.described = &acmtypes.CertificateDetail{
+				Status:             acmtypes.CertificateStatusIssued,
+				Type:               acmtypes.CertificateTypeImported,
+				RenewalEligibility: acmtypes.RenewalEligibilityIneligible,
+				InUseBy:            []string{"arn:aws:elasticloadbalancing:lb-1", "arn:aws:cloudfront::distribution/d-1"},
+				ImportedAt:         aws.Time(importedAt),
+			}
+
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.describedARNs).To(Equal([]string{result.Identifier}))
+			Expect(result.ACM).To(Equal(&drivertypes.ACMCertificateDetails{
+				Status:             "ISSUED",
+				Type:               "IMPORTED",
+				RenewalEligibility: "INELIGIBLE",
+				InUseByCount:       2,
+				ImportedAt:         importedAt,
+			}))
+		})
+
+		It("should report the reason ACM failed the certificate", func() {
+			api.described = &acmtypes.CertificateDetail{
+				Status:        acmtypes.CertificateStatusFailed,
+				FailureReason: acmtypes.FailureReasonInvalidPublicDomain,
+			}
+
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ACM.Status).To(Equal("FAILED"))
+			Expect(result.ACM.FailureReason).To(Equal("INVALID_PUBLIC_DOMAIN"))
+		})
+
+		It("should not fail the upload when the certificate can't be described", func() {
+			api.describeErr = &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized to perform acm:DescribeCertificate"}
+
+			result, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Identifier).To(Equal("arn:aws:acm:us-east-1:123456789012:certificate/test"))
+			Expect(result.ACM).To(BeNil())
+		})
+	})
+
+	Context("when provider tags are set", func() {
+		certData := drivertypes.CertificateData{
+			Domain: "example.com",
+			Tags:   map[string]string{"team": "platform", "env": "prod"},
+		}
+
+		It("should tag a new certificate on import", func() {
+			_, err := newTestDriver(0).Upload(ctx, certData)
+			Expect(err).NotTo(HaveOccurred())
+
+			tags := map[string]string{}
+			for _, tag := range api.lastImport.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			Expect(tags).To(Equal(map[string]string{
+				"ManagedBy": "certificate-operator",
+				"Domain":    "example.com",
+				"team":      "platform",
+				"env":       "prod",
+			}))
+		})
+
+		It("should sync the tags of a re-imported certificate", func() {
+			api.tags = map[string]string{
+				"ManagedBy": "certificate-operator",
+				"Domain":    "example.com",
+				"team":      "web",
+				"obsolete":  "true",
+			}
+			reimport := certData
+			reimport.ExistingID = "arn:aws:acm:us-east-1:123456789012:certificate/test"
+
+			_, err := newTestDriver(0).Upload(ctx, reimport)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(api.lastImport.Tags).To(BeEmpty())
+			Expect(api.tags).To(Equal(map[string]string{
+				"ManagedBy": "certificate-operator",
+				"Domain":    "example.com",
+				"team":      "platform",
+				"env":       "prod",
+			}))
+		})
+	})
+
+	Context("when an assume role ARN is configured", func() {
+		It("should import with credentials for the assumed role", func() {
+			const roleARN = "arn:aws:iam::111111111111:role/certificate-importer"
+			stsAPI := &fakeSTS{}
+			var acmCredentials aws.Credentials
+
+			d := newTestDriver(0)
+			d.assumeRoleARN = roleARN
+			d.newSTSClient = func(aws.Config) stscreds.AssumeRoleAPIClient { return stsAPI }
+			d.newACMClient = func(cfg aws.Config) acmAPI {
+				var err error
+				acmCredentials, err = cfg.Credentials.Retrieve(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				return api
+			}
+
+			_, err := d.Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stsAPI.assumedRoles).To(ConsistOf(roleARN))
+			Expect(acmCredentials.AccessKeyID).To(Equal("ASIAASSUMED"))
+			Expect(acmCredentials.SessionToken).To(Equal("assumed-token"))
+		})
+	})
+
+	DescribeTable("loading access-key credentials from the Secret",
+		func(data map[string]string, sessionToken string) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials", Namespace: "default"},
+				Data:       map[string][]byte{},
+			}
+			for key, value := range data {
+				secret.Data[key] = []byte(value)
+			}
+			d := NewDriver(Config{
+				Client:         fake.NewClientBuilder().WithObjects(secret).Build(),
+				CredentialType: "access-key",
+				SecretRef:      "aws-credentials",
+				Namespace:      "default",
+			})
+
+			cfg, err := d.awsConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			creds, err := cfg.Credentials.Retrieve(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.AccessKeyID).To(Equal("AKIAEXAMPLE"))
+			Expect(creds.SecretAccessKey).To(Equal("secret"))
+			Expect(creds.SessionToken).To(Equal(sessionToken))
+			Expect(cfg.Region).To(Equal("eu-west-1"))
+		},
+		Entry("default keys",
+			map[string]string{"access-key-id": "AKIAEXAMPLE", "secret-access-key": "secret", "region": "eu-west-1"}, ""),
+		Entry("AWS CLI keys",
+			map[string]string{"aws_access_key_id": "AKIAEXAMPLE", "aws_secret_access_key": "secret", "aws_region": "eu-west-1"}, ""),
+		Entry("environment variable keys",
+			map[string]string{"AWS_ACCESS_KEY_ID": "AKIAEXAMPLE", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_REGION": "eu-west-1"}, ""),
+		Entry("camel case keys",
+			map[string]string{"accessKeyId": "AKIAEXAMPLE", "secretAccessKey": "secret", "AWS_DEFAULT_REGION": "eu-west-1"}, ""),
+		Entry("session token",
+			map[string]string{"access-key-id": "AKIAEXAMPLE", "secret-access-key": "secret", "region": "eu-west-1", "session-token": "token"}, "token"),
+		Entry("session token alias",
+			map[string]string{"AWS_ACCESS_KEY_ID": "AKIAEXAMPLE", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_REGION": "eu-west-1", "aws_session_token": "token"}, "token"),
+		Entry("default keys over aliases",
+			map[string]string{"access-key-id": "AKIAEXAMPLE", "aws_access_key_id": "AKIAOTHER", "secret-access-key": "secret", "region": "eu-west-1"}, ""),
+	)
+
+	Context("when resolving the region", func() {
+		newRegionDriver := func(secretRegion, defaultRegion string) *Driver {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials", Namespace: "default"},
+				Data: map[string][]byte{
+					"access-key-id":     []byte("AKIAEXAMPLE"),
+					"secret-access-key": []byte("secret"),
+				},
+			}
+			if secretRegion != "" {
+				secret.Data["region"] = []byte(secretRegion)
+			}
+			return NewDriver(Config{
+				Client:         fake.NewClientBuilder().WithObjects(secret).Build(),
+				CredentialType: "access-key",
+				SecretRef:      "aws-credentials",
+				Namespace:      "default",
+				DefaultRegion:  defaultRegion,
+			})
+		}
+
+		BeforeEach(func() {
+			// Keep the region of the environment running the tests out of the resolution
+			GinkgoT().Setenv("AWS_REGION", "")
+			GinkgoT().Setenv("AWS_DEFAULT_REGION", "")
+			GinkgoT().Setenv("AWS_CONFIG_FILE", filepath.Join(GinkgoT().TempDir(), "config"))
+		})
+
+		It("should list where the region can be set when none is configured", func() {
+			d := newRegionDriver("", "")
+			d.newACMClient = func(aws.Config) acmAPI { return api }
+
+			_, err := d.Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).To(MatchError(ErrRegionNotSet))
+			Expect(err).To(MatchError(ContainSubstring("region key of Secret default/aws-credentials")))
+			Expect(err).To(MatchError(ContainSubstring("AWS_REGION")))
+			Expect(err).To(MatchError(ContainSubstring("--default-aws-region")))
+			Expect(api.importCalls).To(BeZero())
+		})
+
+		It("should fall back to the operator's default region", func() {
+			cfg, err := newRegionDriver("", "ap-northeast-2").awsConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Region).To(Equal("ap-northeast-2"))
+		})
+
+		It("should prefer the region of the environment over the default region", func() {
+			GinkgoT().Setenv("AWS_REGION", "eu-central-1")
+
+			cfg, err := newRegionDriver("", "ap-northeast-2").awsConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Region).To(Equal("eu-central-1"))
+		})
+
+		It("should prefer the region of the Secret over the default region", func() {
+			cfg, err := newRegionDriver("us-west-2", "ap-northeast-2").awsConfig(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Region).To(Equal("us-west-2"))
+		})
+	})
+
+	It("should reject a Secret without an access key under any known key", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials", Namespace: "default"},
+			Data:       map[string][]byte{"access_key": []byte("AKIAEXAMPLE"), "aws_secret_access_key": []byte("secret")},
+		}
+		d := NewDriver(Config{
+			Client:         fake.NewClientBuilder().WithObjects(secret).Build(),
+			CredentialType: "access-key",
+			SecretRef:      "aws-credentials",
+			Namespace:      "default",
+		})
+
+		_, err := d.awsConfig(ctx)
+		Expect(err).To(MatchError(ContainSubstring("AWS credentials incomplete")))
+	})
+
+	DescribeTable("AccountIDFromRoleARN",
+		func(roleARN, accountID string, valid bool) {
+			got, err := AccountIDFromRoleARN(roleARN)
+			if !valid {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(accountID))
+		},
+		Entry("role", "arn:aws:iam::111111111111:role/importer", "111111111111", true),
+		Entry("role with path", "arn:aws:iam::222222222222:role/team/importer", "222222222222", true),
+		Entry("user", "arn:aws:iam::111111111111:user/importer", "", false),
+		Entry("not an ARN", "importer", "", false),
+	)
+
+	It("should leave nil and unknown errors unclassified", func() {
+		Expect(ClassifyError(nil)).To(Succeed())
+		Expect(drivertypes.IsRetriable(ClassifyError(errors.New("boom")))).To(BeFalse())
+	})
+})
@@ -0,0 +1,30 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// bundleChain returns the intermediates to upload for the requested bundle type: none for
+// leaf-only, the chain as issued otherwise
+func bundleChain(chain []byte, bundle certificatev1alpha1.BundleType) []byte {
+	if bundle == certificatev1alpha1.BundleLeafOnly {
+		return nil
+	}
+	return chain
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// calculateCertHash calculates the SHA256 hash of the leaf certificate's DER encoding.
+// Hashing the parsed leaf instead of the PEM bytes keeps cosmetic differences, such as
+// line endings, trailing newlines, or the order of the chain, from looking like a renewal.
+// Data without a parsable certificate is hashed as-is.
+func calculateCertHash(certPEM []byte) string {
+	data := leafCertificateDER(certPEM)
+	if data == nil {
+		data = certPEM
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// legacyCertHash calculates the SHA256 hash of the raw PEM bytes, as stored in
+// status.lastUploadedCertHash before hashes were normalized
+func legacyCertHash(certPEM []byte) string {
+	hash := sha256.Sum256(certPEM)
+	return hex.EncodeToString(hash[:])
+}
+
+// calculateChainFingerprint calculates the SHA256 fingerprint of the DER encodings of every
+// certificate in a PEM bundle, in order. Unlike calculateCertHash it changes when an
+// intermediate is rotated while the leaf stays the same. Data without a parsable
+// certificate is fingerprinted as-is.
+func calculateChainFingerprint(certPEM []byte) string {
+	hash := sha256.New()
+	found := false
+	rest := certPEM
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			continue
+		}
+		// Each DER certificate is self-delimiting, so concatenation is unambiguous
+		hash.Write(block.Bytes)
+		found = true
+	}
+	if !found {
+		hash.Write(certPEM)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// calculateUploadSpecHash calculates the SHA256 hash of the settings that shape what is
+// uploaded to cloud providers without being part of the certificate: the provider tags,
+// bundle types, AWS chain mode, whether Cloudflare is disabled, and the Akamai CPS
+// enrollment, with defaults resolved so defaulting alone never looks like a change. tags
+// are the provider tags resolved from the spec and labels.
+func calculateUploadSpecHash(cert *certificatev1alpha1.Certificate, tags map[string]string) string {
+	spec := cert.EffectiveSpec()
+	fields := struct {
+		ProviderTags     map[string]string                `json:"providerTags,omitempty"`
+		CloudflareBundle certificatev1alpha1.BundleType   `json:"cloudflareBundle,omitempty"`
+		AWSBundle        certificatev1alpha1.BundleType   `json:"awsBundle,omitempty"`
+		AWSChainMode     certificatev1alpha1.AWSChainMode `json:"awsChainMode,omitempty"`
+		// Renewals aren't uploaded to a disabled Cloudflare, so enabling it again must upload
+		CloudflareDisabled bool `json:"cloudflareDisabled,omitempty"`
+		// Enabling Akamai or switching enrollments must upload
+		AkamaiEnrollmentID int64 `json:"akamaiEnrollmentID,omitempty"`
+	}{
+		ProviderTags:       tags,
+		CloudflareBundle:   spec.CloudflareBundle,
+		CloudflareDisabled: spec.CloudflareSecretRef != "" && !*spec.CloudflareEnabled,
+	}
+	if spec.AkamaiEnabled {
+		fields.AkamaiEnrollmentID = spec.AkamaiEnrollmentID
+	}
+	if spec.AWS != nil {
+		fields.AWSBundle = spec.AWS.Bundle
+		// Inline is how certificates were imported before chain modes existed
+		if spec.AWS.ChainMode == certificatev1alpha1.AWSChainModeSeparate {
+			fields.AWSChainMode = spec.AWS.ChainMode
+		}
+	}
+
+	// Map keys are marshaled in sorted order, so the hash is stable
+	data, _ := json.Marshal(fields)
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// leafCertificateDER returns the DER encoding of the leaf certificate in a PEM bundle:
+// the first certificate that is not a CA, or the first certificate if all are CAs.
+// It returns nil when the bundle contains no parsable certificate.
+func leafCertificateDER(certPEM []byte) []byte {
+	var first []byte
+	rest := certPEM
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return first
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if !cert.IsCA {
+			return cert.Raw
+		}
+		if first == nil {
+			first = cert.Raw
+		}
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// circuitBreaker returns the circuit breaker of provider in the status, nil when it has none
+func circuitBreaker(cert *certificatev1alpha1.Certificate, provider string) *certificatev1alpha1.ProviderCircuitBreaker {
+	for i := range cert.Status.ProviderCircuitBreakers {
+		if cert.Status.ProviderCircuitBreakers[i].Provider == provider {
+			return &cert.Status.ProviderCircuitBreakers[i]
+		}
+	}
+	return nil
+}
+
+// shouldUpload reports whether to upload to provider, given whether the certificate changed
+// since the last upload. An Open breaker skips the upload until its cooldown has passed and
+// then half-opens, uploading once to test whether the provider recovered even when the
+// certificate didn't change.
+func (m *CertificateManager) shouldUpload(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	provider string,
+	certChanged bool,
+	now time.Time,
+	statusUpdated *bool,
+) bool {
+	breaker := circuitBreaker(cert, provider)
+	if m.breakerThreshold == 0 || breaker == nil {
+		return certChanged
+	}
+
+	switch breaker.State {
+	case certificatev1alpha1.CircuitBreakerOpen:
+		retryAt := breakerRetryAt(breaker, m.breakerCooldown)
+		if now.Before(retryAt) {
+			if certChanged {
+				logf.FromContext(ctx).Info("Circuit breaker is open, skipping upload",
+					"provider", provider, "failures", breaker.ConsecutiveFailures, "retryAt", retryAt.UTC())
+			}
+			return false
+		}
+		breaker.State = certificatev1alpha1.CircuitBreakerHalfOpen
+		*statusUpdated = true
+		logf.FromContext(ctx).Info("Circuit breaker cooldown passed, testing whether the provider recovered",
+			"provider", provider)
+		return true
+	case certificatev1alpha1.CircuitBreakerHalfOpen:
+		// The test upload didn't finish, e.g. because the operator restarted
+		return true
+	default:
+		return certChanged
+	}
+}
+
+// recordUploadResult updates the circuit breaker of provider with the outcome of an upload
+// and reports whether the status changed. A success removes the breaker; a failure opens it
+// once the failures reach the threshold, or right away when it was half-open.
+func (m *CertificateManager) recordUploadResult(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	provider string,
+	uploadErr error,
+	now time.Time,
+) bool {
+	if m.breakerThreshold == 0 {
+		return false
+	}
+
+	if uploadErr == nil {
+		before := len(cert.Status.ProviderCircuitBreakers)
+		cert.Status.ProviderCircuitBreakers = slices.DeleteFunc(cert.Status.ProviderCircuitBreakers,
+			func(b certificatev1alpha1.ProviderCircuitBreaker) bool { return b.Provider == provider })
+		if len(cert.Status.ProviderCircuitBreakers) == before {
+			return false
+		}
+		logf.FromContext(ctx).Info("Upload succeeded, circuit breaker closed", "provider", provider)
+		return true
+	}
+
+	breaker := circuitBreaker(cert, provider)
+	if breaker == nil {
+		cert.Status.ProviderCircuitBreakers = append(cert.Status.ProviderCircuitBreakers,
+			certificatev1alpha1.ProviderCircuitBreaker{Provider: provider, State: certificatev1alpha1.CircuitBreakerClosed})
+		breaker = &cert.Status.ProviderCircuitBreakers[len(cert.Status.ProviderCircuitBreakers)-1]
+	}
+	breaker.ConsecutiveFailures++
+	breaker.LastError = uploadErr.Error()
+	breaker.ObservedGeneration = cert.Generation
+
+	if breaker.State == certificatev1alpha1.CircuitBreakerHalfOpen ||
+		(breaker.State == certificatev1alpha1.CircuitBreakerClosed && int(breaker.ConsecutiveFailures) >= m.breakerThreshold) {
+		breaker.State = certificatev1alpha1.CircuitBreakerOpen
+		breaker.OpenedAt = &metav1.Time{Time: now}
+		logf.FromContext(ctx).Info("Circuit breaker opened, pausing uploads to the provider",
+			"provider", provider, "failures", breaker.ConsecutiveFailures, "cooldown", m.breakerCooldown)
+	}
+	return true
+}
+
+// nextBreakerRetry returns how long until the first Open circuit breaker half-opens, zero
+// when no breaker is waiting for its cooldown
+func (m *CertificateManager) nextBreakerRetry(cert *certificatev1alpha1.Certificate, now time.Time) time.Duration {
+	if m.breakerThreshold == 0 {
+		return 0
+	}
+
+	var next time.Duration
+	for i := range cert.Status.ProviderCircuitBreakers {
+		breaker := &cert.Status.ProviderCircuitBreakers[i]
+		if breaker.State != certificatev1alpha1.CircuitBreakerOpen {
+			continue
+		}
+		if wait := breakerRetryAt(breaker, m.breakerCooldown).Sub(now); wait > 0 && (next == 0 || wait < next) {
+			next = wait
+		}
+	}
+	return next
+}
+
+// pruneCircuitBreakers removes the breakers of providers the Certificate no longer uploads
+// to, or all of them when the breaker is disabled, and reports whether any were removed
+func (m *CertificateManager) pruneCircuitBreakers(cert *certificatev1alpha1.Certificate) bool {
+	if len(cert.Status.ProviderCircuitBreakers) == 0 {
+		return false
+	}
+
+	used := usedProviders(cert)
+	before := len(cert.Status.ProviderCircuitBreakers)
+	cert.Status.ProviderCircuitBreakers = slices.DeleteFunc(cert.Status.ProviderCircuitBreakers,
+		func(b certificatev1alpha1.ProviderCircuitBreaker) bool {
+			return m.breakerThreshold == 0 || !slices.Contains(used, b.Provider)
+		})
+	return len(cert.Status.ProviderCircuitBreakers) != before
+}
+
+// resetCircuitBreakersOnSpecChange resets the breakers that counted failures at an earlier
+// generation of the Certificate and reports whether any changed. A spec change, e.g. fixed
+// credentials, may have resolved the failures: Open breakers half-open to test the provider
+// right away, and Closed ones are removed with the failures they counted.
+func resetCircuitBreakersOnSpecChange(ctx context.Context, cert *certificatev1alpha1.Certificate) bool {
+	changed := false
+	breakers := cert.Status.ProviderCircuitBreakers[:0]
+	for _, breaker := range cert.Status.ProviderCircuitBreakers {
+		switch {
+		case breaker.ObservedGeneration == cert.Generation:
+		case breaker.ObservedGeneration == 0:
+			// Recorded before generations were tracked, the failures may be for the current spec
+			breaker.ObservedGeneration = cert.Generation
+			changed = true
+		default:
+			logf.FromContext(ctx).Info("Certificate spec changed, resetting circuit breaker",
+				"provider", breaker.Provider, "state", breaker.State, "failures", breaker.ConsecutiveFailures)
+			changed = true
+			if breaker.State == certificatev1alpha1.CircuitBreakerClosed {
+				continue
+			}
+			breaker.State = certificatev1alpha1.CircuitBreakerHalfOpen
+			breaker.ObservedGeneration = cert.Generation
+		}
+		breakers = append(breakers, breaker)
+	}
+	if len(breakers) == 0 {
+		breakers = nil
+	}
+	cert.Status.ProviderCircuitBreakers = breakers
+	return changed
+}
+
+// breakerRetryAt returns when an Open breaker half-opens
+func breakerRetryAt(breaker *certificatev1alpha1.ProviderCircuitBreaker, cooldown time.Duration) time.Time {
+	if breaker.OpenedAt == nil {
+		return time.Time{}
+	}
+	return breaker.OpenedAt.Add(cooldown)
+}
@@ -18,7 +18,11 @@ package cloudflare
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/cloudflare/cloudflare-go"
 	corev1 "k8s.io/api/core/v1"
@@ -26,23 +30,36 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/tae2089/certificate-operator/internal/driver/retry"
 	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
 )
 
+// sslAPI is the subset of the Cloudflare client used by the driver
+type sslAPI interface {
+	CreateSSL(ctx context.Context, zoneID string, options cloudflare.ZoneCustomSSLOptions) (cloudflare.ZoneCustomSSL, error)
+	DeleteSSL(ctx context.Context, zoneID, certificateID string) error
+	SSLDetails(ctx context.Context, zoneID, certificateID string) (cloudflare.ZoneCustomSSL, error)
+}
+
 // Driver implements the CloudProvider interface for Cloudflare
 type Driver struct {
 	client    client.Client
 	secretRef string
 	namespace string
 	zoneID    string
+	backoff   retry.Backoff
+
+	// newAPI creates the Cloudflare client from an API token, overridable for testing
+	newAPI func(apiToken string) (sslAPI, error)
 }
 
 // Config holds Cloudflare driver configuration
 type Config struct {
-	Client    client.Client
-	SecretRef string
-	Namespace string
-	ZoneID    string
+	Client     client.Client
+	SecretRef  string
+	Namespace  string
+	ZoneID     string
+	MaxRetries int // Retries for rate-limited or failed (5xx) uploads
 }
 
 // NewDriver creates a new Cloudflare driver
@@ -52,6 +69,10 @@ func NewDriver(cfg Config) *Driver {
 		secretRef: cfg.SecretRef,
 		namespace: cfg.Namespace,
 		zoneID:    cfg.ZoneID,
+		backoff:   retry.Backoff{MaxRetries: cfg.MaxRetries},
+		newAPI: func(apiToken string) (sslAPI, error) {
+			return cloudflare.NewWithAPIToken(apiToken)
+		},
 	}
 }
 
@@ -78,10 +99,15 @@ func (d *Driver) Upload(ctx context.Context, certData drivertypes.CertificateDat
 		}
 	}
 
-	// Upload custom SSL certificate to Cloudflare using zone ID
-	sslCert, err := api.CreateSSL(ctx, d.zoneID, cloudflare.ZoneCustomSSLOptions{
-		Certificate: string(certData.Certificate),
-		PrivateKey:  string(certData.PrivateKey),
+	// Upload custom SSL certificate to Cloudflare using zone ID, retrying rate limits and 5xx errors
+	var sslCert cloudflare.ZoneCustomSSL
+	err = d.backoff.Do(ctx, func(ctx context.Context) error {
+		var createErr error
+		sslCert, createErr = api.CreateSSL(ctx, d.zoneID, cloudflare.ZoneCustomSSLOptions{
+			Certificate: string(certData.FullChain()),
+			PrivateKey:  string(certData.PrivateKey),
+		})
+		return classifyError(createErr)
 	})
 	if err != nil {
 		return drivertypes.UploadResult{}, fmt.Errorf("failed to upload certificate to Cloudflare: %w", err)
@@ -102,14 +128,66 @@ func (d *Driver) Delete(ctx context.Context, identifier string) error {
 	// Delete certificate from Cloudflare using zone ID
 	err = api.DeleteSSL(ctx, d.zoneID, identifier)
 	if err != nil {
-		return fmt.Errorf("failed to delete certificate from Cloudflare: %w", err)
+		return fmt.Errorf("failed to delete certificate from Cloudflare: %w", classifyError(err))
+	}
+
+	return nil
+}
+
+// Verify reads the custom certificate under identifier back from Cloudflare and compares
+// it with the uploaded leaf. Cloudflare doesn't return the certificate itself, so its
+// expiry and hosts are compared instead of a fingerprint.
+func (d *Driver) Verify(ctx context.Context, identifier string, certData drivertypes.CertificateData) error {
+	leaf, err := leafCertificate(certData.Certificate)
+	if err != nil {
+		return err
+	}
+
+	api, err := d.getCloudflareClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	var details cloudflare.ZoneCustomSSL
+	err = d.backoff.Do(ctx, func(ctx context.Context) error {
+		var detailsErr error
+		details, detailsErr = api.SSLDetails(ctx, d.zoneID, identifier)
+		return classifyError(detailsErr)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get certificate from Cloudflare: %w", err)
 	}
 
+	if !details.ExpiresOn.Equal(leaf.NotAfter) {
+		return fmt.Errorf("%w: Cloudflare certificate expires on %s, uploaded certificate on %s",
+			drivertypes.ErrVerificationMismatch, details.ExpiresOn.UTC(), leaf.NotAfter.UTC())
+	}
+	for _, host := range leaf.DNSNames {
+		if !slices.Contains(details.Hosts, host) {
+			return fmt.Errorf("%w: Cloudflare certificate covers %v, missing %s",
+				drivertypes.ErrVerificationMismatch, details.Hosts, host)
+		}
+	}
 	return nil
 }
 
+// leafCertificate parses the first certificate of a PEM bundle
+func leafCertificate(certPEM []byte) (*x509.Certificate, error) {
+	rest := certPEM
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no certificate found in the uploaded data")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
 // getCloudflareClient creates a Cloudflare API client
-func (d *Driver) getCloudflareClient(ctx context.Context) (*cloudflare.API, error) {
+func (d *Driver) getCloudflareClient(ctx context.Context) (sslAPI, error) {
 	// Get Cloudflare credentials
 	cfSecret := &corev1.Secret{}
 	if err := d.client.Get(ctx, types.NamespacedName{
@@ -125,10 +203,25 @@ func (d *Driver) getCloudflareClient(ctx context.Context) (*cloudflare.API, erro
 	}
 
 	// Create Cloudflare client
-	api, err := cloudflare.NewWithAPIToken(apiToken)
+	api, err := d.newAPI(apiToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Cloudflare client: %w", err)
 	}
 
 	return api, nil
 }
+
+// classifyError marks rate-limit and server-side Cloudflare failures as retriable
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// The SDK returns these errors as pointers
+	var rateLimitErr *cloudflare.RatelimitError
+	var serviceErr *cloudflare.ServiceError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &serviceErr) {
+		return drivertypes.NewRetriableError(err)
+	}
+	return err
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudflare
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// fakeSSLAPI returns the queued errors in order before succeeding, and details as the
+// custom certificate.
+type fakeSSLAPI struct {
+	createErrs  []error
+	createCalls int
+	details     cloudflare.ZoneCustomSSL
+}
+
+func (f *fakeSSLAPI) CreateSSL(_ context.Context, _ string, _ cloudflare.ZoneCustomSSLOptions) (cloudflare.ZoneCustomSSL, error) {
+	f.createCalls++
+	if len(f.createErrs) > 0 {
+		err := f.createErrs[0]
+		f.createErrs = f.createErrs[1:]
+		return cloudflare.ZoneCustomSSL{}, err
+	}
+	return cloudflare.ZoneCustomSSL{ID: "cf-cert-id"}, nil
+}
+
+func (f *fakeSSLAPI) DeleteSSL(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (f *fakeSSLAPI) SSLDetails(_ context.Context, _, _ string) (cloudflare.ZoneCustomSSL, error) {
+	return f.details, nil
+}
+
+// generateLeaf returns a self-signed PEM certificate for dnsNames expiring at notAfter
+func generateLeaf(notAfter time.Time, dnsNames ...string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+var _ = Describe("Driver", func() {
+	var (
+		ctx = context.Background()
+		api *fakeSSLAPI
+	)
+
+	newTestDriver := func(maxRetries int) *Driver {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloudflare-credentials", Namespace: "default"},
+			Data:       map[string][]byte{"api-token": []byte("token")},
+		}
+		d := NewDriver(Config{
+			Client:     fake.NewClientBuilder().WithObjects(secret).Build(),
+			SecretRef:  "cloudflare-credentials",
+			Namespace:  "default",
+			ZoneID:     "zone-id",
+			MaxRetries: maxRetries,
+		})
+		d.backoff.BaseDelay = time.Millisecond
+		d.newAPI = func(string) (sslAPI, error) { return api, nil }
+		return d
+	}
+
+	// The SDK returns pointers to its error types
+	rateLimited := func() error {
+		err := cloudflare.NewRatelimitError(&cloudflare.Error{StatusCode: http.StatusTooManyRequests})
+		return &err
+	}
+	serviceUnavailable := func() error {
+		err := cloudflare.NewServiceError(&cloudflare.Error{StatusCode: http.StatusServiceUnavailable})
+		return &err
+	}
+
+	BeforeEach(func() {
+		api = &fakeSSLAPI{}
+	})
+
+	Context("when CreateSSL is rate limited", func() {
+		It("should retry until the upload succeeds", func() {
+			api.createErrs = []error{rateLimited(), rateLimited()}
+
+			result, err := newTestDriver(3).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Identifier).To(Equal("cf-cert-id"))
+			Expect(api.createCalls).To(Equal(3))
+		})
+
+		It("should not retry when retries are disabled", func() {
+			api.createErrs = []error{rateLimited()}
+
+			_, err := newTestDriver(0).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).To(HaveOccurred())
+			Expect(drivertypes.IsRetriable(err)).To(BeTrue())
+			Expect(api.createCalls).To(Equal(1))
+		})
+	})
+
+	Context("when CreateSSL fails with a server error", func() {
+		It("should retry until the upload succeeds", func() {
+			api.createErrs = []error{serviceUnavailable()}
+
+			result, err := newTestDriver(3).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Identifier).To(Equal("cf-cert-id"))
+			Expect(api.createCalls).To(Equal(2))
+		})
+	})
+
+	Context("when CreateSSL fails with a client error", func() {
+		It("should not retry", func() {
+			api.createErrs = []error{cloudflare.NewRequestError(&cloudflare.Error{StatusCode: http.StatusBadRequest})}
+
+			_, err := newTestDriver(3).Upload(ctx, drivertypes.CertificateData{Domain: "example.com"})
+			Expect(err).To(HaveOccurred())
+			Expect(drivertypes.IsRetriable(err)).To(BeFalse())
+			Expect(api.createCalls).To(Equal(1))
+		})
+	})
+
+	Context("when verifying an upload", func() {
+		notAfter := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		It("should accept a certificate with the uploaded expiry and hosts", func() {
+			api.details = cloudflare.ZoneCustomSSL{ExpiresOn: notAfter, Hosts: []string{"www.example.com", "example.com"}}
+
+			certData := drivertypes.CertificateData{Certificate: generateLeaf(notAfter, "example.com", "www.example.com")}
+			Expect(newTestDriver(0).Verify(ctx, "cf-cert-id", certData)).To(Succeed())
+		})
+
+		It("should report a certificate with another expiry", func() {
+			api.details = cloudflare.ZoneCustomSSL{ExpiresOn: notAfter.Add(-30 * 24 * time.Hour), Hosts: []string{"example.com"}}
+
+			certData := drivertypes.CertificateData{Certificate: generateLeaf(notAfter, "example.com")}
+			Expect(newTestDriver(0).Verify(ctx, "cf-cert-id", certData)).To(MatchError(drivertypes.ErrVerificationMismatch))
+		})
+
+		It("should report a certificate missing an uploaded host", func() {
+			api.details = cloudflare.ZoneCustomSSL{ExpiresOn: notAfter, Hosts: []string{"example.com"}}
+
+			certData := drivertypes.CertificateData{Certificate: generateLeaf(notAfter, "example.com", "www.example.com")}
+			Expect(newTestDriver(0).Verify(ctx, "cf-cert-id", certData)).To(MatchError(ContainSubstring("www.example.com")))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	cloudflaredriver "github.com/tae2089/certificate-operator/internal/driver/cloudflare"
+)
+
+// disabledCleanupRetryInterval is how long to wait before retrying to delete the copy of a
+// provider the Certificate disabled
+const disabledCleanupRetryInterval = time.Minute
+
+// deleteDisabledCloudflareUpload deletes the Cloudflare certificate of a Certificate whose
+// spec.cloudflareEnabled was set to false and clears its ID from status, when the cleanup is
+// enabled. It reports whether the status changed. A provider disabled by the operator is
+// left alone, its copy is only withheld from updates.
+func (m *CertificateManager) deleteDisabledCloudflareUpload(ctx context.Context, cert *certificatev1alpha1.Certificate) (bool, error) {
+	if !m.deleteDisabledUploads || *cert.EffectiveSpec().CloudflareEnabled ||
+		cert.Status.CloudflareCertificateID == "" || m.providerDisabled(cloudflareProviderName) {
+		return false, nil
+	}
+	log := logf.FromContext(ctx)
+
+	if cert.Spec.CloudflareSecretRef == "" {
+		// The zone can't be reached without credentials
+		log.Info("No Cloudflare credentials configured, skipping cleanup of the disabled upload",
+			"id", cert.Status.CloudflareCertificateID)
+		return false, nil
+	}
+
+	driver := m.newCloudflareDriver(cloudflaredriver.Config{
+		Client:     m.k8sClient,
+		SecretRef:  cert.Spec.CloudflareSecretRef,
+		Namespace:  m.secretNamespace(cert),
+		ZoneID:     cert.Spec.CloudflareZoneID,
+		MaxRetries: m.maxRetries,
+	})
+	if err := driver.Delete(ctx, cert.Status.CloudflareCertificateID); err != nil {
+		return false, fmt.Errorf("failed to delete certificate %s from Cloudflare: %w", cert.Status.CloudflareCertificateID, err)
+	}
+
+	log.Info("Deleted certificate from Cloudflare after it was disabled", "id", cert.Status.CloudflareCertificateID)
+	forgetProvider(cert, cloudflareProviderName)
+
+	// Nothing is uploaded when Cloudflare was the only provider, so record the disabled
+	// state as uploaded for enabling Cloudflare again to upload
+	tags := m.providerTags(cert)
+	enabled := cert.DeepCopy()
+	enabled.Spec.CloudflareEnabled = ptr.To(true)
+	if cert.Status.LastUploadedSpecHash == calculateUploadSpecHash(enabled, tags) {
+		cert.Status.LastUploadedSpecHash = calculateUploadSpecHash(cert, tags)
+	}
+	return true, nil
+}
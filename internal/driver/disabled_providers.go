@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// Provider names, as returned by the drivers' Name methods
+const (
+	akamaiProviderName        = "akamai"
+	awsProviderName           = "aws"
+	cloudflareProviderName    = "cloudflare"
+	remoteClusterProviderName = "remote-cluster"
+	s3ProviderName            = "s3"
+)
+
+// providerDisabled reports whether uploads to the provider are disabled by the operator
+func (m *CertificateManager) providerDisabled(provider string) bool {
+	return slices.Contains(m.disabledProviders, provider)
+}
+
+// usedProviders returns the providers the Certificate uploads to, in a stable order
+func usedProviders(cert *certificatev1alpha1.Certificate) []string {
+	var used []string
+	if cert.Spec.CloudflareSecretRef != "" && *cert.EffectiveSpec().CloudflareEnabled {
+		used = append(used, cloudflareProviderName)
+	}
+	if cert.Spec.AWS != nil {
+		used = append(used, awsProviderName)
+	}
+	if cert.Spec.AkamaiEnabled {
+		used = append(used, akamaiProviderName)
+	}
+	if cert.Spec.S3 != nil {
+		used = append(used, s3ProviderName)
+	}
+	if len(cert.Spec.RemoteClusters) > 0 {
+		used = append(used, remoteClusterProviderName)
+	}
+	return used
+}
+
+// disabledProvidersFor returns the providers the Certificate uploads to that are disabled
+// by the operator, in a stable order
+func (m *CertificateManager) disabledProvidersFor(cert *certificatev1alpha1.Certificate) []string {
+	var disabled []string
+	for _, provider := range usedProviders(cert) {
+		if m.providerDisabled(provider) {
+			disabled = append(disabled, provider)
+		}
+	}
+	return disabled
+}
+
+// setProvidersDisabledCondition records the providers disabled by the operator in the
+// ProvidersDisabled condition, removing it when none are, and reports whether the
+// conditions changed
+func setProvidersDisabledCondition(cert *certificatev1alpha1.Certificate, disabled []string) bool {
+	if len(disabled) == 0 {
+		return meta.RemoveStatusCondition(&cert.Status.Conditions, certificatev1alpha1.ConditionProvidersDisabled)
+	}
+	return meta.SetStatusCondition(&cert.Status.Conditions, metav1.Condition{
+		Type:               certificatev1alpha1.ConditionProvidersDisabled,
+		Status:             metav1.ConditionTrue,
+		Reason:             certificatev1alpha1.ReasonAdministrativelyDisabled,
+		Message:            fmt.Sprintf("Uploads to %s are disabled by the operator", strings.Join(disabled, ", ")),
+		ObservedGeneration: cert.Generation,
+	})
+}
+
+// markReuploadPending records that the providers disabled by the operator have to be
+// uploaded to again once they are enabled, and reports whether the status changed. Remote
+// clusters are marked as not synced instead.
+func markReuploadPending(cert *certificatev1alpha1.Certificate, disabled []string) bool {
+	changed := false
+	for _, provider := range disabled {
+		if provider == remoteClusterProviderName {
+			for i := range cert.Status.RemoteClusters {
+				if cert.Status.RemoteClusters[i].Synced {
+					cert.Status.RemoteClusters[i].Synced = false
+					changed = true
+				}
+			}
+			continue
+		}
+		if status, ok := cert.Status.Providers[provider]; ok && status.ReuploadPending && !status.Uploaded {
+			continue
+		}
+		updateProviderStatus(cert, provider, func(status *certificatev1alpha1.ProviderStatus) {
+			status.Uploaded = false
+			status.ReuploadPending = true
+		})
+		changed = true
+	}
+	return changed
+}
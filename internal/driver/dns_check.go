@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+const (
+	// dnsCheckRequeueInterval is how soon a Certificate whose domain doesn't resolve as
+	// spec.dnsCheck expects is checked again
+	dnsCheckRequeueInterval = time.Minute
+
+	// dnsLookupTimeout bounds the lookups of a DNS check
+	dnsLookupTimeout = 5 * time.Second
+)
+
+// dnsResolver resolves the domains of spec.dnsCheck, implemented by *net.Resolver
+type dnsResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+}
+
+// newDNSResolver returns a resolver sending its queries to the DNS server at address, or
+// the system resolver when address is empty
+func newDNSResolver(address string) dnsResolver {
+	if address == "" {
+		return net.DefaultResolver
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// dnsCheckApplies reports whether spec.dnsCheck is set and spec.domain can be resolved,
+// wildcard domains have no record of their own to look up
+func dnsCheckApplies(cert *certificatev1alpha1.Certificate) bool {
+	return cert.Spec.DNSCheck != nil && !strings.HasPrefix(cert.Spec.Domain, "*.")
+}
+
+// checkDNS returns why spec.domain doesn't resolve as spec.dnsCheck expects, or nil when it does
+func (m *CertificateManager) checkDNS(ctx context.Context, cert *certificatev1alpha1.Certificate) error {
+	check := cert.Spec.DNSCheck
+	domain := strings.TrimSuffix(cert.Spec.Domain, ".")
+
+	ctx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+	defer cancel()
+
+	addresses, err := m.dnsResolver.LookupHost(ctx, domain)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", domain, err)
+	}
+	var missing []string
+	for _, expected := range check.ExpectedAddresses {
+		if !slices.ContainsFunc(addresses, func(address string) bool { return sameAddress(address, expected) }) {
+			missing = append(missing, expected)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s resolves to [%s], missing %s",
+			domain, strings.Join(addresses, ", "), strings.Join(missing, ", "))
+	}
+
+	if check.ExpectedCNAME != "" {
+		cname, err := m.dnsResolver.LookupCNAME(ctx, domain)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the canonical name of %s: %w", domain, err)
+		}
+		cname = strings.TrimSuffix(cname, ".")
+		if expected := strings.TrimSuffix(check.ExpectedCNAME, "."); !strings.EqualFold(cname, expected) {
+			return fmt.Errorf("%s resolves through %s, expected %s", domain, cname, expected)
+		}
+	}
+	return nil
+}
+
+// sameAddress compares IP addresses by value, so differently written IPv6 addresses match
+func sameAddress(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	return ipA.Equal(ipB)
+}
+
+// setDNSNotReadyCondition records the result of checkDNS in the DNSNotReady condition and
+// reports whether the condition changed
+func setDNSNotReadyCondition(cert *certificatev1alpha1.Certificate, dnsErr error) bool {
+	condition := metav1.Condition{
+		Type:               certificatev1alpha1.ConditionDNSNotReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             certificatev1alpha1.ReasonDNSResolved,
+		Message:            fmt.Sprintf("%s resolves as expected", cert.Spec.Domain),
+		ObservedGeneration: cert.Generation,
+	}
+	if dnsErr != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = certificatev1alpha1.ReasonDNSMismatch
+		condition.Message = fmt.Sprintf("Domain doesn't resolve as expected, upload deferred: %v", dnsErr)
+	}
+	return meta.SetStatusCondition(&cert.Status.Conditions, condition)
+}
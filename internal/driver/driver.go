@@ -29,4 +29,15 @@ type (
 	CertSpec        = types.CertSpec
 	CertResult      = types.CertResult
 	TLSSecret       = types.TLSSecret
+	RetriableError  = types.RetriableError
 )
+
+// NewRetriableError marks a provider error as retriable
+func NewRetriableError(err error) error {
+	return types.NewRetriableError(err)
+}
+
+// IsRetriable reports whether a provider error is expected to succeed on retry
+func IsRetriable(err error) bool {
+	return types.IsRetriable(err)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akamaidriver "github.com/tae2089/certificate-operator/internal/driver/akamai"
+	awsdriver "github.com/tae2089/certificate-operator/internal/driver/aws"
+	cloudflaredriver "github.com/tae2089/certificate-operator/internal/driver/cloudflare"
+	remoteclusterdriver "github.com/tae2089/certificate-operator/internal/driver/remotecluster"
+	s3driver "github.com/tae2089/certificate-operator/internal/driver/s3"
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// dryRunIdentifier is the identifier a dry-run upload reports for a certificate that has
+// no copy at the provider yet
+const dryRunIdentifier = "dry-run"
+
+// WithDryRun processes Certificates without side effects. Writes to the API server are sent
+// as dry runs, nothing is uploaded to or deleted from providers, and neither events nor
+// post-upload webhooks are sent. The status still changes as if the uploads had succeeded, with the
+// identifier "dry-run" for copies that don't exist yet.
+func WithDryRun() ManagerOption {
+	return func(m *CertificateManager) {
+		m.dryRun = true
+	}
+}
+
+// enableDryRun wraps the client and the provider constructors of m so nothing is written
+func (m *CertificateManager) enableDryRun() {
+	m.k8sClient = client.NewDryRunClient(m.k8sClient)
+	m.eventRecorder = nil
+
+	newCloudflareDriver := m.newCloudflareDriver
+	m.newCloudflareDriver = func(cfg cloudflaredriver.Config) types.CloudProvider {
+		return dryRunProvider{name: newCloudflareDriver(cfg).Name()}
+	}
+	newAkamaiDriver := m.newAkamaiDriver
+	m.newAkamaiDriver = func(cfg akamaidriver.Config) types.CloudProvider {
+		return dryRunProvider{name: newAkamaiDriver(cfg).Name()}
+	}
+	newAWSDriver := m.newAWSDriver
+	m.newAWSDriver = func(cfg awsdriver.Config) types.CloudProvider {
+		return dryRunProvider{name: newAWSDriver(cfg).Name()}
+	}
+	newRemoteClusterDriver := m.newRemoteClusterDriver
+	m.newRemoteClusterDriver = func(cfg remoteclusterdriver.Config) types.CloudProvider {
+		return dryRunProvider{name: newRemoteClusterDriver(cfg).Name()}
+	}
+	newS3Driver := m.newS3Driver
+	m.newS3Driver = func(cfg s3driver.Config) types.CloudProvider {
+		return dryRunProvider{name: newS3Driver(cfg).Name()}
+	}
+}
+
+// dryRunProvider reports uploads and deletions as successful without calling the provider
+type dryRunProvider struct {
+	name string
+}
+
+// Upload reports the existing copy, or dryRunIdentifier for a new one
+func (p dryRunProvider) Upload(_ context.Context, cert types.CertificateData) (types.UploadResult, error) {
+	if cert.ExistingID != "" {
+		return types.UploadResult{Identifier: cert.ExistingID}, nil
+	}
+	return types.UploadResult{Identifier: dryRunIdentifier}, nil
+}
+
+// Delete does nothing
+func (p dryRunProvider) Delete(context.Context, string) error {
+	return nil
+}
+
+// Name returns the name of the wrapped provider
+func (p dryRunProvider) Name() string {
+	return p.name
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// fakeProvider is an in-memory CloudProvider that records every call.
+type fakeProvider struct {
+	mu sync.Mutex
+
+	name       string
+	identifier string
+	objectKeys []string
+	acm        *types.ACMCertificateDetails
+	uploadErr  error
+	deleteErr  error
+	// deleteErrs fails the deletion of single identifiers
+	deleteErrs map[string]error
+	verifyErr  error
+
+	uploads  []types.CertificateData
+	deletes  []string
+	verifies []string
+
+	// block, when set, holds uploads until it is closed or the upload context is cancelled.
+	// Each held upload is announced on started.
+	block   chan struct{}
+	started chan struct{}
+}
+
+func newFakeProvider(name, identifier string) *fakeProvider {
+	return &fakeProvider{name: name, identifier: identifier}
+}
+
+func (p *fakeProvider) Upload(ctx context.Context, cert types.CertificateData) (types.UploadResult, error) {
+	if p.block != nil {
+		p.started <- struct{}{}
+		select {
+		case <-p.block:
+		case <-ctx.Done():
+			return types.UploadResult{}, ctx.Err()
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.uploads = append(p.uploads, cert)
+	if p.uploadErr != nil {
+		// Providers that upload in steps identify what they created so far
+		return types.UploadResult{Identifier: p.identifier}, p.uploadErr
+	}
+	return types.UploadResult{Identifier: p.identifier, ObjectKeys: p.objectKeys, ACM: p.acm}, nil
+}
+
+func (p *fakeProvider) Delete(_ context.Context, identifier string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.deletes = append(p.deletes, identifier)
+	if err, ok := p.deleteErrs[identifier]; ok {
+		return err
+	}
+	return p.deleteErr
+}
+
+func (p *fakeProvider) Verify(_ context.Context, identifier string, _ types.CertificateData) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.verifies = append(p.verifies, identifier)
+	return p.verifyErr
+}
+
+func (p *fakeProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeProvider) uploadCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.uploads)
+}
+
+func (p *fakeProvider) lastUpload() types.CertificateData {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	Expect(p.uploads).NotTo(BeEmpty())
+	return p.uploads[len(p.uploads)-1]
+}
+
+// fakeDNSResolver is a stubbed dnsResolver answering from in-memory records.
+type fakeDNSResolver struct {
+	mu sync.Mutex
+
+	addresses map[string][]string
+	cnames    map[string]string
+	lookups   int
+}
+
+func (r *fakeDNSResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	addresses, ok := r.addresses[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addresses, nil
+}
+
+func (r *fakeDNSResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.addresses[host]; !ok {
+		return "", &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	if cname, ok := r.cnames[host]; ok {
+		return cname, nil
+	}
+	return host + ".", nil
+}
+
+func (r *fakeDNSResolver) set(host string, addresses []string, cname string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.addresses == nil {
+		r.addresses = map[string][]string{}
+		r.cnames = map[string]string{}
+	}
+	r.addresses[host] = addresses
+	if cname != "" {
+		r.cnames[host] = cname
+	}
+}
+
+func (r *fakeDNSResolver) lookupCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lookups
+}
+
+// testCertificate is a generated certificate with its PEM encodings.
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// testCertOptions customizes generated certificates.
+type testCertOptions struct {
+	// dnsNames defaults to the common name for leaf certificates
+	dnsNames  []string
+	notBefore time.Time
+	notAfter  time.Time
+	isCA      bool
+	parent    *testCertificate
+}
+
+// generateTestCertificate creates a certificate signed by opts.parent, or self-signed if nil.
+func generateTestCertificate(commonName string, opts testCertOptions) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	Expect(err).NotTo(HaveOccurred())
+
+	if opts.notBefore.IsZero() {
+		opts.notBefore = time.Now().Add(-time.Hour)
+	}
+	if opts.notAfter.IsZero() {
+		opts.notAfter = time.Now().Add(90 * 24 * time.Hour)
+	}
+	if opts.dnsNames == nil && !opts.isCA {
+		// cert-manager issues leaves with the domain as a SAN
+		opts.dnsNames = []string{commonName}
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              opts.dnsNames,
+		NotBefore:             opts.notBefore,
+		NotAfter:              opts.notAfter,
+		BasicConstraintsValid: true,
+		IsCA:                  opts.isCA,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if opts.isCA {
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+
+	parentCert, parentKey := template, key
+	if opts.parent != nil {
+		parentCert, parentKey = opts.parent.cert, opts.parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// generateTestChain creates a leaf for domain signed by a fresh intermediate CA.
+func generateTestChain(domain string) (leaf, intermediate *testCertificate) {
+	intermediate = generateTestCertificate("Test Intermediate CA", testCertOptions{isCA: true})
+	leaf = generateTestCertificate(domain, testCertOptions{
+		dnsNames: []string{domain},
+		parent:   intermediate,
+	})
+	return leaf, intermediate
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"time"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// activeIssuerRef returns the kind and name of the issuer the cert-manager Certificate
+// should use: the fallback issuer once the Certificate switched to it, the primary otherwise
+func activeIssuerRef(cert *certificatev1alpha1.Certificate) (string, string) {
+	if fallbackIssuerActive(cert) {
+		return string(certificatev1alpha1.IssuerKindClusterIssuer), cert.Spec.FallbackClusterIssuerName
+	}
+	return issuerRef(cert)
+}
+
+// fallbackIssuerActive reports whether the Certificate switched to spec.fallbackClusterIssuerName
+func fallbackIssuerActive(cert *certificatev1alpha1.Certificate) bool {
+	return cert.Spec.FallbackClusterIssuerName != "" &&
+		cert.Status.ActiveIssuer == formatIssuer(string(certificatev1alpha1.IssuerKindClusterIssuer), cert.Spec.FallbackClusterIssuerName)
+}
+
+// formatIssuer formats an issuer reference as kind/name
+func formatIssuer(kind, name string) string {
+	return kind + "/" + name
+}
+
+// trackIssuanceFailure records in status.issuanceFailingSince when the latest issuance
+// attempt of the cert-manager Certificate was first seen failing, clearing it once
+// cert-manager issued the certificate, and reports whether the status changed
+func trackIssuanceFailure(cert *certificatev1alpha1.Certificate, cmCert *certmanagerv1.Certificate, now time.Time) bool {
+	failing := cmCert != nil && cmCert.Status.LastFailureTime != nil
+	switch {
+	case failing && cert.Status.IssuanceFailingSince == nil:
+		cert.Status.IssuanceFailingSince = &metav1.Time{Time: now}
+		return true
+	case !failing && cert.Status.IssuanceFailingSince != nil:
+		cert.Status.IssuanceFailingSince = nil
+		return true
+	}
+	return false
+}
+
+// fallbackIssuerDue reports whether issuance against the primary issuer has kept failing
+// for spec.fallbackAfter, so the Certificate should switch to the fallback issuer
+func fallbackIssuerDue(cert *certificatev1alpha1.Certificate, now time.Time) bool {
+	if cert.Spec.FallbackClusterIssuerName == "" || fallbackIssuerActive(cert) || cert.Status.IssuanceFailingSince == nil {
+		return false
+	}
+	return now.Sub(cert.Status.IssuanceFailingSince.Time) >= cert.EffectiveSpec().FallbackAfter.Duration
+}
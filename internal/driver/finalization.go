@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"slices"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// recordCleanup records the outcome of deleting a provider resource in the finalization
+// summary, naming the resource provider/identifier
+func recordCleanup(summary *certificatev1alpha1.FinalizationStatus, provider, identifier string, err error) {
+	resource := provider + "/" + identifier
+	if err != nil {
+		summary.Failed = append(summary.Failed, certificatev1alpha1.CleanupFailure{Resource: resource, Error: err.Error()})
+		return
+	}
+	summary.Deleted = append(summary.Deleted, resource)
+}
+
+// FinalizationChanged reports whether the finalization summary names other resources than
+// stored. Error messages are ignored, they may differ between attempts, e.g. by request ID,
+// and writing them would trigger a reconcile before the retry interval.
+func FinalizationChanged(stored, current *certificatev1alpha1.FinalizationStatus) bool {
+	if stored == nil || current == nil {
+		return stored != current
+	}
+	return !slices.Equal(stored.Deleted, current.Deleted) ||
+		!slices.EqualFunc(stored.Failed, current.Failed, func(a, b certificatev1alpha1.CleanupFailure) bool {
+			return a.Resource == b.Resource
+		})
+}
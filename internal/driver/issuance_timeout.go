@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// checkIssuanceTimeout sets the Timeout condition once issuance has been pending for
+// spec.issuanceTimeout, emitting a warning event when it is first set, and removes it
+// without a timeout. It reports whether the conditions changed and how long until the
+// timeout passes, zero once it passed or without one.
+func (m *CertificateManager) checkIssuanceTimeout(cert *certificatev1alpha1.Certificate, now time.Time) (bool, time.Duration) {
+	timeout := cert.Spec.IssuanceTimeout
+	if timeout == nil || timeout.Duration <= 0 || cert.Status.IssuanceStartedAt == nil {
+		return clearIssuanceTimeout(cert), 0
+	}
+	if remaining := cert.Status.IssuanceStartedAt.Add(timeout.Duration).Sub(now); remaining > 0 {
+		return clearIssuanceTimeout(cert), remaining
+	}
+
+	message := fmt.Sprintf("certificate was not issued within %s", timeout.Duration)
+	if cert.Status.IssuanceDetail != "" {
+		message += ": " + cert.Status.IssuanceDetail
+	}
+	timedOut := meta.IsStatusConditionTrue(cert.Status.Conditions, certificatev1alpha1.ConditionTimeout)
+	changed := meta.SetStatusCondition(&cert.Status.Conditions, metav1.Condition{
+		Type:               certificatev1alpha1.ConditionTimeout,
+		Status:             metav1.ConditionTrue,
+		Reason:             certificatev1alpha1.ReasonIssuanceTimedOut,
+		Message:            message,
+		ObservedGeneration: cert.Generation,
+	})
+	if !timedOut && m.eventRecorder != nil {
+		m.eventRecorder.Event(cert, corev1.EventTypeWarning, certificatev1alpha1.ReasonIssuanceTimedOut, message)
+	}
+	return changed, 0
+}
+
+// clearIssuanceTimeout removes the Timeout condition and reports whether the conditions changed
+func clearIssuanceTimeout(cert *certificatev1alpha1.Certificate) bool {
+	return meta.RemoveStatusCondition(&cert.Status.Conditions, certificatev1alpha1.ConditionTimeout)
+}
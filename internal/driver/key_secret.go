@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// getTLSMaterial reads the certificate and private key of cert. Without spec.keySecretRef
+// both come from the TLS Secret, otherwise the private key comes from the key Secret.
+// Like CertManager.GetTLSSecret, it returns a NotFound error when the TLS Secret doesn't
+// exist and nil when the material is incomplete.
+func (m *CertificateManager) getTLSMaterial(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	secretName string,
+	keys types.TLSSecretKeys,
+) (*types.TLSSecret, error) {
+	if cert.Spec.KeySecretRef == "" {
+		return m.certManager.GetTLSSecret(ctx, secretName, cert.Namespace, keys)
+	}
+	log := logf.FromContext(ctx)
+
+	certSecret := &corev1.Secret{}
+	if err := m.k8sClient.Get(ctx, client.ObjectKey{Namespace: cert.Namespace, Name: secretName}, certSecret); err != nil {
+		return nil, err
+	}
+	certPEM := certSecret.Data[keys.Certificate]
+	if len(certPEM) == 0 {
+		log.Info("TLS secret has no certificate yet", "secret", secretName, "key", keys.Certificate)
+		return nil, nil
+	}
+
+	keySecret := &corev1.Secret{}
+	err := m.k8sClient.Get(ctx, client.ObjectKey{Namespace: cert.Namespace, Name: cert.Spec.KeySecretRef}, keySecret)
+	if apierrors.IsNotFound(err) {
+		log.Info("Key secret doesn't exist yet", "secret", cert.Spec.KeySecretRef)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key secret %s: %w", cert.Spec.KeySecretRef, err)
+	}
+	keyPEM := keySecret.Data[keys.PrivateKey]
+	if len(keyPEM) == 0 {
+		log.Info("Key secret has no private key yet", "secret", cert.Spec.KeySecretRef, "key", keys.PrivateKey)
+		return nil, nil
+	}
+
+	// The Secrets are updated one after the other, so a renewed certificate may briefly be
+	// paired with the previous key
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		log.Info("Private key doesn't match the certificate yet", "secret", cert.Spec.KeySecretRef, "reason", err.Error())
+		return nil, nil
+	}
+
+	leaf, chain := types.SplitCertificateChain(certPEM)
+	return &types.TLSSecret{
+		Secret:      certSecret,
+		Certificate: leaf,
+		Chain:       chain,
+		PrivateKey:  keyPEM,
+	}, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+
+	acmev1 "github.com/cert-manager/cert-manager/pkg/apis/acme/v1"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// ErrCertManagerAPIUnavailable is returned by CheckCertManagerAPI when the cluster doesn't
+// serve a cert-manager API version or resource the operator uses
+var ErrCertManagerAPIUnavailable = errors.New("cert-manager API is unavailable")
+
+// requiredAPIResources are the cert-manager resources the operator reads or writes, by the
+// group version it was built against
+var requiredAPIResources = []struct {
+	groupVersion string
+	resources    []string
+}{
+	{certmanagerv1.SchemeGroupVersion.String(), []string{"certificates", "certificaterequests", "issuers"}},
+	{acmev1.SchemeGroupVersion.String(), []string{"orders", "challenges"}},
+}
+
+// CheckCertManagerAPI uses discovery to check that the cluster serves the cert-manager API
+// versions and resources the operator uses. Clusters with an older cert-manager, or none at
+// all, fail with an error wrapping ErrCertManagerAPIUnavailable that names the versions they
+// serve instead, rather than every reconcile failing on a missing kind.
+func CheckCertManagerAPI(dc discovery.DiscoveryInterface) error {
+	for _, required := range requiredAPIResources {
+		list, err := dc.ServerResourcesForGroupVersion(required.groupVersion)
+		if apierrors.IsNotFound(err) {
+			return versionUnavailableError(dc, required.groupVersion)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to discover %s: %w", required.groupVersion, err)
+		}
+
+		served := make([]string, 0, len(list.APIResources))
+		for _, resource := range list.APIResources {
+			served = append(served, resource.Name)
+		}
+		for _, resource := range required.resources {
+			if !slices.Contains(served, resource) {
+				return fmt.Errorf("%w: %s doesn't serve %s, check that the cert-manager CRDs are installed",
+					ErrCertManagerAPIUnavailable, required.groupVersion, resource)
+			}
+		}
+	}
+	return nil
+}
+
+// versionUnavailableError returns the error for a group version that isn't served, listing
+// the versions of its group that are
+func versionUnavailableError(dc discovery.DiscoveryInterface, groupVersion string) error {
+	group, _, _ := strings.Cut(groupVersion, "/")
+	groups, err := dc.ServerGroups()
+	if err != nil {
+		return fmt.Errorf("%w: %s is not served by the cluster", ErrCertManagerAPIUnavailable, groupVersion)
+	}
+
+	var served []string
+	for _, g := range groups.Groups {
+		if g.Name != group {
+			continue
+		}
+		for _, v := range g.Versions {
+			served = append(served, v.GroupVersion)
+		}
+	}
+	if len(served) == 0 {
+		return fmt.Errorf("%w: %s is not served by the cluster, install cert-manager v1.0 or later",
+			ErrCertManagerAPIUnavailable, groupVersion)
+	}
+	return fmt.Errorf("%w: %s is not served by the cluster (found %s), upgrade cert-manager to v1.0 or later",
+		ErrCertManagerAPIUnavailable, groupVersion, strings.Join(served, ", "))
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// newFakeDiscovery returns a discovery client serving the given resource lists
+func newFakeDiscovery(resources ...*metav1.APIResourceList) *discoveryfake.FakeDiscovery {
+	return &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: resources}}
+}
+
+// resourceList returns the resource list of a group version serving the named resources
+func resourceList(groupVersion string, names ...string) *metav1.APIResourceList {
+	list := &metav1.APIResourceList{GroupVersion: groupVersion}
+	for _, name := range names {
+		list.APIResources = append(list.APIResources, metav1.APIResource{Name: name})
+	}
+	return list
+}
+
+var _ = Describe("CheckCertManagerAPI", func() {
+	acmeResources := resourceList("acme.cert-manager.io/v1", "orders", "challenges")
+
+	It("should pass when cert-manager v1 is installed", func() {
+		dc := newFakeDiscovery(
+			resourceList("cert-manager.io/v1", "certificates", "certificaterequests", "issuers", "clusterissuers"),
+			acmeResources,
+		)
+		Expect(CheckCertManagerAPI(dc)).To(Succeed())
+	})
+
+	It("should name the served versions when only an older cert-manager is installed", func() {
+		dc := newFakeDiscovery(
+			resourceList("cert-manager.io/v1alpha2", "certificates", "certificaterequests", "issuers"),
+			resourceList("acme.cert-manager.io/v1alpha2", "orders", "challenges"),
+		)
+		err := CheckCertManagerAPI(dc)
+		Expect(err).To(MatchError(ErrCertManagerAPIUnavailable))
+		Expect(err.Error()).To(ContainSubstring("cert-manager.io/v1 is not served by the cluster (found cert-manager.io/v1alpha2)"))
+		Expect(err.Error()).To(ContainSubstring("upgrade cert-manager"))
+	})
+
+	It("should ask to install cert-manager when it is missing", func() {
+		err := CheckCertManagerAPI(newFakeDiscovery(resourceList("v1", "secrets")))
+		Expect(err).To(MatchError(ErrCertManagerAPIUnavailable))
+		Expect(err.Error()).To(ContainSubstring("install cert-manager"))
+	})
+
+	It("should fail when a required resource isn't served", func() {
+		dc := newFakeDiscovery(resourceList("cert-manager.io/v1", "certificates", "issuers"), acmeResources)
+		err := CheckCertManagerAPI(dc)
+		Expect(err).To(MatchError(ErrCertManagerAPIUnavailable))
+		Expect(err.Error()).To(ContainSubstring("doesn't serve certificaterequests"))
+	})
+
+	It("should wrap discovery errors", func() {
+		dc := newFakeDiscovery()
+		discoveryErr := errors.New("connection refused")
+		dc.PrependReactor("*", "*", func(clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, discoveryErr
+		})
+		err := CheckCertManagerAPI(dc)
+		Expect(err).To(MatchError(discoveryErr))
+		Expect(err).NotTo(MatchError(ErrCertManagerAPIUnavailable))
+	})
+})
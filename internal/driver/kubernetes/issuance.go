@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	acmev1 "github.com/cert-manager/cert-manager/pkg/apis/acme/v1"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+)
+
+// issuanceDetail describes the progress of a pending issuance by following the
+// Certificate -> CertificateRequest -> Order -> Challenge ownership chain.
+func (d *Driver) issuanceDetail(ctx context.Context, cert *certmanagerv1.Certificate) (string, error) {
+	certReqs := &certmanagerv1.CertificateRequestList{}
+	if err := d.client.List(ctx, certReqs, client.InNamespace(cert.Namespace)); err != nil {
+		return "", err
+	}
+
+	certReq := latestCertificateRequest(cert, certReqs.Items)
+	if certReq == nil {
+		if cond := findCertificateCondition(cert.Status.Conditions, certmanagerv1.CertificateConditionIssuing); cond != nil && cond.Message != "" {
+			return cond.Message, nil
+		}
+		return "waiting for cert-manager to create a CertificateRequest", nil
+	}
+
+	for _, cond := range certReq.Status.Conditions {
+		if cond.Type == certmanagerv1.CertificateRequestConditionReady && cond.Status == cmmeta.ConditionFalse &&
+			(cond.Reason == certmanagerv1.CertificateRequestReasonFailed || cond.Reason == certmanagerv1.CertificateRequestReasonDenied) {
+			return fmt.Sprintf("certificate request %s %s: %s", certReq.Name, strings.ToLower(cond.Reason), cond.Message), nil
+		}
+	}
+
+	orders := &acmev1.OrderList{}
+	if err := d.client.List(ctx, orders, client.InNamespace(cert.Namespace)); err != nil {
+		return "", err
+	}
+
+	var order *acmev1.Order
+	for i := range orders.Items {
+		if metav1.IsControlledBy(&orders.Items[i], certReq) {
+			order = &orders.Items[i]
+			break
+		}
+	}
+	if order == nil {
+		// Non-ACME issuers sign the request directly
+		return fmt.Sprintf("waiting for certificate request %s to be signed", certReq.Name), nil
+	}
+
+	challenges := &acmev1.ChallengeList{}
+	if err := d.client.List(ctx, challenges, client.InNamespace(cert.Namespace)); err != nil {
+		return "", err
+	}
+
+	for i := range challenges.Items {
+		challenge := &challenges.Items[i]
+		if !metav1.IsControlledBy(challenge, order) || challenge.Status.State == acmev1.Valid {
+			continue
+		}
+
+		state := challenge.Status.State
+		if state == acmev1.Unknown {
+			state = acmev1.Pending
+		}
+		detail := fmt.Sprintf("%s %s challenge for %s", state, challengeTypeName(challenge.Spec.Type), challenge.Spec.DNSName)
+		if challenge.Status.Reason != "" {
+			detail += ": " + challenge.Status.Reason
+		}
+		return detail, nil
+	}
+
+	if order.Status.Reason != "" {
+		return fmt.Sprintf("order %s is %s: %s", order.Name, orderState(order), order.Status.Reason), nil
+	}
+	return fmt.Sprintf("order %s is %s", order.Name, orderState(order)), nil
+}
+
+// latestCertificateRequest returns the CertificateRequest for the newest revision owned by cert
+func latestCertificateRequest(cert *certmanagerv1.Certificate, certReqs []certmanagerv1.CertificateRequest) *certmanagerv1.CertificateRequest {
+	var latest *certmanagerv1.CertificateRequest
+	latestRevision := -1
+	for i := range certReqs {
+		certReq := &certReqs[i]
+		if !metav1.IsControlledBy(certReq, cert) {
+			continue
+		}
+
+		revision, err := strconv.Atoi(certReq.Annotations[certmanagerv1.CertificateRequestRevisionAnnotationKey])
+		if err != nil {
+			revision = 0
+		}
+		if revision > latestRevision {
+			latest = certReq
+			latestRevision = revision
+		}
+	}
+	return latest
+}
+
+// findCertificateCondition returns the condition of the given type, or nil
+func findCertificateCondition(conditions []certmanagerv1.CertificateCondition, condType certmanagerv1.CertificateConditionType) *certmanagerv1.CertificateCondition {
+	for i := range conditions {
+		if conditions[i].Type == condType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// challengeTypeName converts an ACME challenge type like "HTTP-01" to "http01"
+func challengeTypeName(challengeType acmev1.ACMEChallengeType) string {
+	return strings.ToLower(strings.ReplaceAll(string(challengeType), "-", ""))
+}
+
+// orderState returns the order state, treating an unset state as pending
+func orderState(order *acmev1.Order) acmev1.State {
+	if order.Status.State == acmev1.Unknown {
+		return acmev1.Pending
+	}
+	return order.Status.State
+}
@@ -18,12 +18,18 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -33,22 +39,63 @@ import (
 	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
 )
 
+// pkcs12SecretKeys are the Secret keys checked for a PKCS#12 keystore, in order
+var pkcs12SecretKeys = []string{"keystore.p12", "tls.p12"}
+
+const (
+	// managedByLabel is set on the Certificates to the operator, overriding CertSpec.Labels
+	managedByLabel = "app.kubernetes.io/managed-by"
+
+	// propagatedLabelsAnnotation lists the label keys set from CertSpec.Labels, so labels
+	// removed from the spec are removed from the Certificate
+	propagatedLabelsAnnotation = "certificate.println.kr/propagated-labels"
+
+	// propagatedAnnotationsAnnotation lists the annotation keys set from CertSpec.Annotations
+	propagatedAnnotationsAnnotation = "certificate.println.kr/propagated-annotations"
+)
+
 // Driver implements the CertManager interface for Kubernetes cert-manager
 type Driver struct {
 	client client.Client
 	scheme *runtime.Scheme
+	clock  clock.PassiveClock
+}
+
+// Option configures a Driver
+type Option func(*Driver)
+
+// WithClock replaces the time source of the condition timestamps the driver sets
+func WithClock(c clock.PassiveClock) Option {
+	return func(d *Driver) {
+		d.clock = c
+	}
 }
 
 // NewDriver creates a new Kubernetes cert-manager driver
-func NewDriver(k8sClient client.Client, scheme *runtime.Scheme) *Driver {
-	return &Driver{
+func NewDriver(k8sClient client.Client, scheme *runtime.Scheme, opts ...Option) *Driver {
+	d := &Driver{
 		client: k8sClient,
 		scheme: scheme,
+		clock:  clock.RealClock{},
+	}
+	for _, opt := range opts {
+		opt(d)
 	}
+	return d
 }
 
-// EnsureCertificate creates or updates a cert-manager Certificate
+// EnsureCertificate creates or updates a cert-manager Certificate. It refuses with
+// ErrSecretConflict when another cert-manager Certificate issues into spec.SecretName, since
+// cert-manager would keep overwriting the Secret with both certificates, and with
+// ErrNameConflict when the Certificate or Secret it would take over wasn't created for it.
 func (d *Driver) EnsureCertificate(ctx context.Context, spec drivertypes.CertSpec) (*drivertypes.CertResult, error) {
+	if err := d.checkSecretConflict(ctx, spec); err != nil {
+		return nil, err
+	}
+	if err := d.checkNameConflict(ctx, spec); err != nil {
+		return nil, err
+	}
+
 	certReq := &certmanagerv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      spec.Name,
@@ -56,31 +103,49 @@ func (d *Driver) EnsureCertificate(ctx context.Context, spec drivertypes.CertSpe
 		},
 	}
 
+	// Set default ClusterIssuer if not specified
+	issuerRef := cmmeta.ObjectReference{
+		Name:  spec.IssuerName,
+		Kind:  spec.IssuerKind,
+		Group: "cert-manager.io",
+	}
+	if issuerRef.Kind == "" {
+		issuerRef.Kind = "ClusterIssuer"
+	}
+	if issuerRef.Name == "" && issuerRef.Kind == "ClusterIssuer" {
+		issuerRef.Name = "letsencrypt-prod"
+	}
+
+	issuerChanged, subjectChanged := false, false
 	_, err := ctrl.CreateOrUpdate(ctx, d.client, certReq, func() error {
-		if certReq.Labels == nil {
-			certReq.Labels = make(map[string]string)
+		// An existing Certificate keeps its issued Secret when the issuer or subject changes
+		issuerChanged = certReq.ResourceVersion != "" && certReq.Spec.IssuerRef.Name != "" &&
+			(certReq.Spec.IssuerRef.Kind != issuerRef.Kind || certReq.Spec.IssuerRef.Name != issuerRef.Name)
+		subjectChanged = certReq.ResourceVersion != "" && !equality.Semantic.DeepEqual(certReq.Spec.Subject, spec.Subject)
+
+		propagateMetadata(certReq, spec)
+		certReq.Labels[managedByLabel] = "certificate-operator"
+		if spec.ManagedBy != "" {
+			certReq.Labels[managedByLabel] = spec.ManagedBy
 		}
-		certReq.Labels["app.kubernetes.io/managed-by"] = "certificate-operator"
 
 		// Set owner references
 		if len(spec.OwnerReferences) > 0 {
 			certReq.OwnerReferences = spec.OwnerReferences
 		}
 
-		// Set default ClusterIssuer if not specified
-		clusterIssuerName := spec.ClusterIssuerName
-		if clusterIssuerName == "" {
-			clusterIssuerName = "letsencrypt-prod"
-		}
-
 		certReq.Spec = certmanagerv1.CertificateSpec{
-			DNSNames:   []string{spec.Domain},
-			SecretName: spec.SecretName,
-			IssuerRef: cmmeta.ObjectReference{
-				Name:  clusterIssuerName,
-				Kind:  "ClusterIssuer",
-				Group: "cert-manager.io",
-			},
+			DNSNames:                []string{spec.Domain},
+			SecretName:              spec.SecretName,
+			IssuerRef:               issuerRef,
+			Subject:                 spec.Subject,
+			AdditionalOutputFormats: spec.AdditionalOutputFormats,
+			RevisionHistoryLimit:    spec.RevisionHistoryLimit,
+		}
+		if spec.PrivateKeyRotationPolicy != "" {
+			certReq.Spec.PrivateKey = &certmanagerv1.CertificatePrivateKey{
+				RotationPolicy: certmanagerv1.PrivateKeyRotationPolicy(spec.PrivateKeyRotationPolicy),
+			}
 		}
 		return nil
 	})
@@ -89,14 +154,156 @@ func (d *Driver) EnsureCertificate(ctx context.Context, spec drivertypes.CertSpe
 		return nil, err
 	}
 
+	switch {
+	case issuerChanged:
+		message := fmt.Sprintf("Reissuing with %s %s", certReq.Spec.IssuerRef.Kind, certReq.Spec.IssuerRef.Name)
+		if err := d.triggerReissue(ctx, certReq, "IssuerChanged", message); err != nil {
+			return nil, err
+		}
+	case subjectChanged:
+		if err := d.triggerReissue(ctx, certReq, "SubjectChanged", "Reissuing with the updated subject"); err != nil {
+			return nil, err
+		}
+	}
+
 	return &drivertypes.CertResult{
-		Certificate: certReq,
-		Name:        certReq.Name,
+		Certificate:    certReq,
+		Name:           certReq.Name,
+		IssuerChanged:  issuerChanged,
+		SubjectChanged: subjectChanged,
 	}, nil
 }
 
-// GetTLSSecret retrieves and validates a TLS Secret
-func (d *Driver) GetTLSSecret(ctx context.Context, name, namespace string) (*drivertypes.TLSSecret, error) {
+// propagateMetadata sets the labels and annotations of spec on certReq and removes the ones
+// set by a previous call that spec no longer lists. Labels and annotations set by others are kept.
+func propagateMetadata(certReq *certmanagerv1.Certificate, spec drivertypes.CertSpec) {
+	if certReq.Annotations == nil {
+		certReq.Annotations = make(map[string]string)
+	}
+	certReq.Labels = syncPropagated(certReq.Labels, spec.Labels, certReq.Annotations, propagatedLabelsAnnotation)
+	certReq.Annotations = syncPropagated(certReq.Annotations, spec.Annotations, certReq.Annotations, propagatedAnnotationsAnnotation)
+	if len(certReq.Annotations) == 0 {
+		certReq.Annotations = nil
+	}
+}
+
+// syncPropagated sets desired on current, deletes the keys listed under trackingKey in
+// annotations that desired no longer has, and records the keys of desired under trackingKey
+func syncPropagated(current, desired, annotations map[string]string, trackingKey string) map[string]string {
+	if current == nil {
+		current = make(map[string]string)
+	}
+	for key := range strings.SplitSeq(annotations[trackingKey], ",") {
+		if _, ok := desired[key]; key != "" && !ok {
+			delete(current, key)
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		current[key] = value
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		delete(annotations, trackingKey)
+		return current
+	}
+	slices.Sort(keys)
+	annotations[trackingKey] = strings.Join(keys, ",")
+	return current
+}
+
+// checkSecretConflict returns ErrSecretConflict when a cert-manager Certificate other than
+// spec.Name in the namespace has spec.SecretName as its secretName
+func (d *Driver) checkSecretConflict(ctx context.Context, spec drivertypes.CertSpec) error {
+	var certList certmanagerv1.CertificateList
+	if err := d.client.List(ctx, &certList, client.InNamespace(spec.Namespace)); err != nil {
+		return fmt.Errorf("failed to list cert-manager Certificates: %w", err)
+	}
+	for _, other := range certList.Items {
+		if other.Name != spec.Name && other.Spec.SecretName == spec.SecretName {
+			return fmt.Errorf("%w: Secret %s is issued by cert-manager Certificate %s",
+				drivertypes.ErrSecretConflict, spec.SecretName, other.Name)
+		}
+	}
+	return nil
+}
+
+// checkNameConflict returns ErrNameConflict when the cert-manager Certificate spec.Name
+// exists without the controller of spec.OwnerReferences as its controller, or when it
+// doesn't exist yet and the Secret spec.SecretName exists without having been issued for it.
+// Without a controller in spec.OwnerReferences, any existing Certificate is taken over.
+func (d *Driver) checkNameConflict(ctx context.Context, spec drivertypes.CertSpec) error {
+	var owner *metav1.OwnerReference
+	for i := range spec.OwnerReferences {
+		if ref := spec.OwnerReferences[i]; ref.Controller != nil && *ref.Controller {
+			owner = &spec.OwnerReferences[i]
+		}
+	}
+	if owner == nil {
+		return nil
+	}
+
+	existing := &certmanagerv1.Certificate{}
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: spec.Namespace, Name: spec.Name}, existing)
+	if err == nil {
+		if controller := metav1.GetControllerOf(existing); controller == nil || controller.UID != owner.UID {
+			return fmt.Errorf("%w: cert-manager Certificate %s exists and is not controlled by %s %s",
+				drivertypes.ErrNameConflict, spec.Name, owner.Kind, owner.Name)
+		}
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get cert-manager Certificate %s: %w", spec.Name, err)
+	}
+
+	// cert-manager would overwrite a Secret it didn't issue for this Certificate
+	secret := &corev1.Secret{}
+	err = d.client.Get(ctx, types.NamespacedName{Namespace: spec.Namespace, Name: spec.SecretName}, secret)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get Secret %s: %w", spec.SecretName, err)
+	}
+	if secret.Annotations[certmanagerv1.CertificateNameKey] != spec.Name {
+		return fmt.Errorf("%w: Secret %s exists and was not issued for cert-manager Certificate %s",
+			drivertypes.ErrNameConflict, spec.SecretName, spec.Name)
+	}
+	return nil
+}
+
+// triggerReissue asks cert-manager to reissue a Certificate by setting its Issuing
+// condition, the same way "cmctl renew" does
+func (d *Driver) triggerReissue(ctx context.Context, certReq *certmanagerv1.Certificate, reason, message string) error {
+	now := metav1.NewTime(d.clock.Now())
+	condition := certmanagerv1.CertificateCondition{
+		Type:               certmanagerv1.CertificateConditionIssuing,
+		Status:             cmmeta.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: &now,
+		ObservedGeneration: certReq.Generation,
+	}
+
+	conditions := certReq.Status.Conditions[:0]
+	for _, cond := range certReq.Status.Conditions {
+		if cond.Type != certmanagerv1.CertificateConditionIssuing {
+			conditions = append(conditions, cond)
+		}
+	}
+	certReq.Status.Conditions = append(conditions, condition)
+
+	if err := d.client.Status().Update(ctx, certReq); err != nil {
+		return fmt.Errorf("failed to trigger reissuance of Certificate %s: %w", certReq.Name, err)
+	}
+	logf.FromContext(ctx).Info("Triggered reissuance", "certificate", certReq.Name, "reason", reason)
+	return nil
+}
+
+// GetTLSSecret retrieves and validates a TLS Secret, reading the certificate and key from
+// the given data keys
+func (d *Driver) GetTLSSecret(ctx context.Context, name, namespace string, keys drivertypes.TLSSecretKeys) (*drivertypes.TLSSecret, error) {
 	secret := &corev1.Secret{}
 	err := d.client.Get(ctx, types.NamespacedName{
 		Name:      name,
@@ -107,22 +314,43 @@ func (d *Driver) GetTLSSecret(ctx context.Context, name, namespace string) (*dri
 		return nil, err
 	}
 
-	tlsCert := secret.Data["tls.crt"]
-	tlsKey := secret.Data["tls.key"]
+	certKey, keyKey := keys.Certificate, keys.PrivateKey
+	if certKey == "" {
+		certKey = corev1.TLSCertKey
+	}
+	if keyKey == "" {
+		keyKey = corev1.TLSPrivateKeyKey
+	}
+	tlsCert := secret.Data[certKey]
+	tlsKey := secret.Data[keyKey]
 
 	if len(tlsCert) == 0 || len(tlsKey) == 0 {
+		// Fall back to a PKCS#12 keystore, the caller decodes it with its password
+		for _, key := range pkcs12SecretKeys {
+			if keystore := secret.Data[key]; len(keystore) > 0 {
+				return &drivertypes.TLSSecret{
+					Secret: secret,
+					PKCS12: keystore,
+				}, nil
+			}
+		}
 		return nil, nil // Empty secret, not ready yet
 	}
 
+	// cert-manager writes the leaf followed by the intermediates to tls.crt
+	leaf, chain := drivertypes.SplitCertificateChain(tlsCert)
 	return &drivertypes.TLSSecret{
 		Secret:      secret,
-		Certificate: tlsCert,
+		Certificate: leaf,
+		Chain:       chain,
 		PrivateKey:  tlsKey,
 	}, nil
 }
 
-// WaitForReadiness checks if Certificate is ready
-func (d *Driver) WaitForReadiness(ctx context.Context, certName, namespace string) (ctrl.Result, error) {
+// WaitForReadiness checks if Certificate is ready.
+// While issuance is pending it also returns a short description of the current
+// CertificateRequest/Order/Challenge progress, empty once the Certificate is ready.
+func (d *Driver) WaitForReadiness(ctx context.Context, certName, namespace string) (ctrl.Result, string, error) {
 	log := logf.FromContext(ctx)
 
 	// Get Certificate
@@ -131,7 +359,7 @@ func (d *Driver) WaitForReadiness(ctx context.Context, certName, namespace strin
 		Name:      certName,
 		Namespace: namespace,
 	}, cert); err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, "", err
 	}
 
 	// Check if Certificate is Ready
@@ -144,11 +372,16 @@ func (d *Driver) WaitForReadiness(ctx context.Context, certName, namespace strin
 	}
 
 	if !certReady {
-		log.Info("Waiting for Certificate to be ready", "certificate", certName)
-		return ctrl.Result{RequeueAfter: time.Minute}, nil
+		detail, err := d.issuanceDetail(ctx, cert)
+		if err != nil {
+			// Progress details are informational only, keep waiting without them
+			log.Error(err, "Failed to read issuance progress", "certificate", certName)
+		}
+		log.Info("Waiting for Certificate to be ready", "certificate", certName, "detail", detail)
+		return ctrl.Result{RequeueAfter: time.Minute}, detail, nil
 	}
 
 	// Certificate is ready
 	log.Info("Certificate is ready, waiting for TLS secret to be created", "certificate", certName)
-	return ctrl.Result{RequeueAfter: time.Minute}, nil
+	return ctrl.Result{RequeueAfter: time.Minute}, "", nil
 }
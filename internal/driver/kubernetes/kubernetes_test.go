@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/pem"
+	"slices"
+	"time"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+var _ = Describe("GetTLSSecret", func() {
+	var (
+		ctx          = context.Background()
+		leaf         = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("leaf")})
+		intermediate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("intermediate")})
+		root         = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("root")})
+	)
+
+	newDriver := func(data map[string][]byte) *Driver {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-tls", Namespace: "default"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}
+		return NewDriver(fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(), scheme)
+	}
+
+	It("should split a bundled tls.crt into the leaf and the chain", func() {
+		d := newDriver(map[string][]byte{
+			corev1.TLSCertKey:       slices.Concat(leaf, intermediate, root),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		})
+
+		tlsSecret, err := d.GetTLSSecret(ctx, "example-tls", "default", drivertypes.TLSSecretKeys{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tlsSecret.Certificate).To(Equal(leaf))
+		Expect(tlsSecret.Chain).To(Equal(slices.Concat(intermediate, root)))
+		Expect(tlsSecret.FullChain()).To(Equal(slices.Concat(leaf, intermediate, root)))
+		Expect(tlsSecret.PrivateKey).To(Equal([]byte("key")))
+	})
+
+	It("should return no chain for a tls.crt holding only the leaf", func() {
+		d := newDriver(map[string][]byte{
+			corev1.TLSCertKey:       leaf,
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		})
+
+		tlsSecret, err := d.GetTLSSecret(ctx, "example-tls", "default", drivertypes.TLSSecretKeys{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tlsSecret.Certificate).To(Equal(leaf))
+		Expect(tlsSecret.Chain).To(BeNil())
+	})
+
+	It("should skip blocks that aren't certificates", func() {
+		params := pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte("params")})
+		d := newDriver(map[string][]byte{
+			"cert.pem": slices.Concat(params, leaf, intermediate),
+			"key.pem":  []byte("key"),
+		})
+
+		tlsSecret, err := d.GetTLSSecret(ctx, "example-tls", "default",
+			drivertypes.TLSSecretKeys{Certificate: "cert.pem", PrivateKey: "key.pem"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tlsSecret.Certificate).To(Equal(leaf))
+		Expect(tlsSecret.Chain).To(Equal(intermediate))
+	})
+})
+
+var _ = Describe("triggerReissue", func() {
+	It("should stamp the Issuing condition with the driver's clock", func() {
+		scheme := runtime.NewScheme()
+		Expect(certmanagerv1.AddToScheme(scheme)).To(Succeed())
+		certReq := &certmanagerv1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-cert", Namespace: "default", Generation: 2},
+			Status: certmanagerv1.CertificateStatus{Conditions: []certmanagerv1.CertificateCondition{{
+				Type:   certmanagerv1.CertificateConditionIssuing,
+				Status: cmmeta.ConditionFalse,
+			}}},
+		}
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(certReq).
+			WithStatusSubresource(&certmanagerv1.Certificate{}).
+			Build()
+		now := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+		d := NewDriver(k8sClient, scheme, WithClock(clocktesting.NewFakePassiveClock(now)))
+
+		Expect(d.triggerReissue(context.Background(), certReq, "IssuerChanged", "issuer changed")).To(Succeed())
+
+		updated := &certmanagerv1.Certificate{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(certReq), updated)).To(Succeed())
+		Expect(updated.Status.Conditions).To(HaveLen(1))
+		condition := updated.Status.Conditions[0]
+		Expect(condition.Type).To(Equal(certmanagerv1.CertificateConditionIssuing))
+		Expect(condition.Status).To(Equal(cmmeta.ConditionTrue))
+		Expect(condition.Reason).To(Equal("IssuerChanged"))
+		Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+		Expect(condition.LastTransitionTime.Time).To(BeTemporally("==", now))
+	})
+})
@@ -18,113 +18,877 @@ package driver
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
+	"errors"
+	"slices"
+	"sync"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/blackout"
+	akamaidriver "github.com/tae2089/certificate-operator/internal/driver/akamai"
 	awsdriver "github.com/tae2089/certificate-operator/internal/driver/aws"
 	cloudflaredriver "github.com/tae2089/certificate-operator/internal/driver/cloudflare"
 	kubernetesdriver "github.com/tae2089/certificate-operator/internal/driver/kubernetes"
+	remoteclusterdriver "github.com/tae2089/certificate-operator/internal/driver/remotecluster"
+	s3driver "github.com/tae2089/certificate-operator/internal/driver/s3"
 	"github.com/tae2089/certificate-operator/internal/driver/types"
 )
 
+const (
+	// defaultMaxRetries is the default number of in-driver retries for provider uploads
+	defaultMaxRetries = 3
+
+	// emptySecretRequeueInterval is how soon an existing but empty TLS secret is checked again,
+	// in case the update that populates it is missed by the watch
+	emptySecretRequeueInterval = 10 * time.Second
+
+	// secretConflictRequeueInterval is how soon a Certificate whose TLS secret is the target of
+	// another cert-manager Certificate, or whose names are taken by unmanaged resources, is
+	// checked again, the other resources aren't watched
+	secretConflictRequeueInterval = time.Minute
+
+	// awsAccountRetryInterval is how soon the import into an AWS account of
+	// spec.awsAssumeRoleARNs is retried after it failed
+	awsAccountRetryInterval = 5 * time.Minute
+
+	// akamaiPendingRequeueInterval is how soon an Akamai CPS change the operator started is
+	// checked again while CPS generates its CSRs and the issuer signs them
+	akamaiPendingRequeueInterval = time.Minute
+
+	// defaultShutdownGracePeriod is how long in-flight uploads may run after shutdown starts
+	defaultShutdownGracePeriod = 25 * time.Second
+
+	// defaultManagedBy is the managed-by label value of the cert-manager Certificates created
+	// by an operator without an instance ID, unless WithManagedBy sets another one
+	defaultManagedBy = "certificate-operator"
+)
+
 // CertificateManager orchestrates certificate operations across multiple drivers
 type CertificateManager struct {
 	certManager types.CertManager
 	k8sClient   client.Client
 	scheme      *runtime.Scheme
+
+	// credentialsNamespace is where provider credential Secrets are read from when set
+	credentialsNamespace string
+
+	// maxRetries is the number of in-driver retries for transient provider upload failures
+	maxRetries int
+
+	// shutdownGracePeriod is how long in-flight uploads may run after shutdown starts
+	shutdownGracePeriod time.Duration
+
+	// postUploadWebhookTimeout bounds a call to a Certificate's post-upload webhook
+	postUploadWebhookTimeout time.Duration
+
+	// uploadSlots caps the uploads in flight per provider name, providers without an
+	// entry are not limited
+	uploadSlots map[string]chan struct{}
+
+	// disabledProviders are the provider names nothing is uploaded to
+	disabledProviders []string
+
+	// verifyUploads reads uploaded certificates back from providers that support it
+	verifyUploads bool
+
+	// deleteDisabledUploads deletes the copy of a provider once a Certificate disables it
+	deleteDisabledUploads bool
+
+	// renewalUploadWindow spreads the re-uploads of renewed certificates over this window,
+	// zero uploads them as soon as they are issued
+	renewalUploadWindow time.Duration
+
+	// defaultAWSRegion is the region of AWS ACM and S3 requests whose credentials and
+	// environment set none
+	defaultAWSRegion string
+
+	// breakerThreshold is the number of consecutive upload failures that open a provider's
+	// circuit breaker, zero disables the breaker
+	breakerThreshold int
+
+	// breakerCooldown is how long an open circuit breaker skips uploads before half-opening
+	breakerCooldown time.Duration
+
+	// uploadBlackout holds uploads back during its windows, nil for none
+	uploadBlackout *blackout.Schedule
+
+	// dnsResolver resolves the domains of Certificates with spec.dnsCheck
+	dnsResolver dnsResolver
+
+	// tagLabelPrefix selects the Certificate labels set as provider tags, empty for none
+	tagLabelPrefix string
+
+	// instanceID identifies this operator instance in the managed-by label of the
+	// cert-manager Certificates it creates, empty for none
+	instanceID string
+
+	// managedByValue is the managed-by label value the instance ID is appended to
+	managedByValue string
+
+	// certificateNameSuffix and secretNameSuffix are appended to the name of a Certificate to
+	// name its cert-manager Certificate and TLS Secret
+	certificateNameSuffix string
+	secretNameSuffix      string
+
+	// eventRecorder emits events on Certificates, nil for none
+	eventRecorder record.EventRecorder
+
+	// dryRun processes Certificates without writing to the API server or providers
+	dryRun bool
+
+	// clock is the time source of status timestamps, cooldowns, and windows, shared with
+	// the kubernetes driver
+	clock clock.PassiveClock
+
+	// Uploads in flight, tracked so shutdown can wait for them. uploadCtx is cancelled
+	// once the shutdown grace period expires.
+	uploadsMu     sync.Mutex
+	uploads       sync.WaitGroup
+	shuttingDown  bool
+	uploadCtx     context.Context
+	cancelUploads context.CancelFunc
+
+	// Provider constructors, overridable for testing
+	newCloudflareDriver    func(cfg cloudflaredriver.Config) types.CloudProvider
+	newAkamaiDriver        func(cfg akamaidriver.Config) types.CloudProvider
+	newAWSDriver           func(cfg awsdriver.Config) types.CloudProvider
+	newRemoteClusterDriver func(cfg remoteclusterdriver.Config) types.CloudProvider
+	newS3Driver            func(cfg s3driver.Config) types.CloudProvider
+}
+
+// ManagerOption configures a CertificateManager
+type ManagerOption func(*CertificateManager)
+
+// WithCredentialsNamespace reads provider credential Secrets from a shared namespace
+// instead of the Certificate's namespace. A Certificate's spec.credentialsNamespace may
+// still select its own namespace.
+func WithCredentialsNamespace(namespace string) ManagerOption {
+	return func(m *CertificateManager) {
+		m.credentialsNamespace = namespace
+	}
+}
+
+// WithMaxRetries sets how many times a provider upload is retried on throttling or
+// server-side errors before the failure is surfaced to the reconciler
+func WithMaxRetries(n int) ManagerOption {
+	return func(m *CertificateManager) {
+		m.maxRetries = n
+	}
+}
+
+// WithShutdownGracePeriod sets how long in-flight provider uploads may run after the
+// operator starts shutting down before they are cancelled
+func WithShutdownGracePeriod(d time.Duration) ManagerOption {
+	return func(m *CertificateManager) {
+		m.shutdownGracePeriod = d
+	}
+}
+
+// WithMaxConcurrentUploads caps the uploads in flight to each provider, keyed by provider
+// name, so one provider's rate limits don't hold up uploads to the others
+func WithMaxConcurrentUploads(limits map[string]int) ManagerOption {
+	return func(m *CertificateManager) {
+		m.uploadSlots = make(map[string]chan struct{}, len(limits))
+		for provider, limit := range limits {
+			m.uploadSlots[provider] = make(chan struct{}, limit)
+		}
+	}
+}
+
+// WithDisabledProviders stops uploads to the named providers for every Certificate, e.g.
+// during a provider incident. Finalize still cleans up what was uploaded to them.
+func WithDisabledProviders(providers []string) ManagerOption {
+	return func(m *CertificateManager) {
+		m.disabledProviders = providers
+	}
+}
+
+// WithRenewalUploadWindow spreads the re-uploads of renewed certificates over window, so
+// certificates renewed together aren't re-uploaded all at once
+func WithRenewalUploadWindow(window time.Duration) ManagerOption {
+	return func(m *CertificateManager) {
+		m.renewalUploadWindow = window
+	}
+}
+
+// WithUploadVerification reads each uploaded certificate back from providers that support it
+// and reports copies that differ from the upload in the VerificationFailed condition
+func WithUploadVerification(enabled bool) ManagerOption {
+	return func(m *CertificateManager) {
+		m.verifyUploads = enabled
+	}
+}
+
+// WithDisabledUploadCleanup deletes the Cloudflare certificate of a Certificate once its
+// spec.cloudflareEnabled is set to false, instead of leaving the last upload in place
+func WithDisabledUploadCleanup(enabled bool) ManagerOption {
+	return func(m *CertificateManager) {
+		m.deleteDisabledUploads = enabled
+	}
+}
+
+// WithDefaultAWSRegion sets the region of AWS ACM and S3 requests when neither the
+// credentials Secret nor the operator's environment sets one
+func WithDefaultAWSRegion(region string) ManagerOption {
+	return func(m *CertificateManager) {
+		m.defaultAWSRegion = region
+	}
+}
+
+// WithCircuitBreaker stops uploads to a provider for cooldown once threshold uploads to it
+// failed in a row for a Certificate, then tests the provider with a single upload.
+// A threshold of zero disables the breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ManagerOption {
+	return func(m *CertificateManager) {
+		m.breakerThreshold = threshold
+		m.breakerCooldown = cooldown
+	}
+}
+
+// WithTagLabelPrefix sets the labels of a Certificate starting with prefix as provider tags
+// keyed by the rest of the label key
+func WithTagLabelPrefix(prefix string) ManagerOption {
+	return func(m *CertificateManager) {
+		m.tagLabelPrefix = prefix
+	}
+}
+
+// WithUploadBlackout holds uploads to providers back while schedule is in a blackout
+// window and requeues them for when the window closes. Issuance isn't affected.
+func WithUploadBlackout(schedule *blackout.Schedule) ManagerOption {
+	return func(m *CertificateManager) {
+		m.uploadBlackout = schedule
+	}
+}
+
+// WithDNSResolver sends the spec.dnsCheck lookups of Certificates to the DNS server at
+// address (host:port) instead of the system resolver
+func WithDNSResolver(address string) ManagerOption {
+	return func(m *CertificateManager) {
+		m.dnsResolver = newDNSResolver(address)
+	}
+}
+
+// WithInstanceID includes the operator instance ID in the managed-by label of the
+// cert-manager Certificates the manager creates
+func WithInstanceID(instanceID string) ManagerOption {
+	return func(m *CertificateManager) {
+		m.instanceID = instanceID
+	}
+}
+
+// WithManagedBy sets the managed-by label value of the cert-manager Certificates the manager
+// creates, defaults to certificate-operator. The instance ID is appended to it.
+func WithManagedBy(value string) ManagerOption {
+	return func(m *CertificateManager) {
+		if value != "" {
+			m.managedByValue = value
+		}
+	}
+}
+
+// WithNameSuffixes sets the suffixes appended to the name of a Certificate to name its
+// cert-manager Certificate and TLS Secret, default to -cert and -tls. An empty suffix keeps
+// the default.
+func WithNameSuffixes(certificateSuffix, secretSuffix string) ManagerOption {
+	return func(m *CertificateManager) {
+		if certificateSuffix != "" {
+			m.certificateNameSuffix = certificateSuffix
+		}
+		if secretSuffix != "" {
+			m.secretNameSuffix = secretSuffix
+		}
+	}
+}
+
+// WithEventRecorder emits events on Certificates, e.g. when issuance takes longer than
+// spec.issuanceTimeout
+func WithEventRecorder(recorder record.EventRecorder) ManagerOption {
+	return func(m *CertificateManager) {
+		m.eventRecorder = recorder
+	}
+}
+
+// WithClock replaces the time source of the manager and its kubernetes driver, tests use
+// a fake clock to step through cooldowns and windows
+func WithClock(c clock.PassiveClock) ManagerOption {
+	return func(m *CertificateManager) {
+		m.clock = c
+	}
 }
 
 // NewCertificateManager creates a new certificate manager
-func NewCertificateManager(k8sClient client.Client, scheme *runtime.Scheme) *CertificateManager {
-	return &CertificateManager{
-		certManager: kubernetesdriver.NewDriver(k8sClient, scheme),
-		k8sClient:   k8sClient,
-		scheme:      scheme,
+func NewCertificateManager(k8sClient client.Client, scheme *runtime.Scheme, opts ...ManagerOption) *CertificateManager {
+	m := &CertificateManager{
+		k8sClient:                k8sClient,
+		scheme:                   scheme,
+		maxRetries:               defaultMaxRetries,
+		shutdownGracePeriod:      defaultShutdownGracePeriod,
+		postUploadWebhookTimeout: defaultPostUploadWebhookTimeout,
+		dnsResolver:              newDNSResolver(""),
+		managedByValue:           defaultManagedBy,
+		certificateNameSuffix:    certificatev1alpha1.DefaultCertificateNameSuffix,
+		secretNameSuffix:         certificatev1alpha1.DefaultSecretNameSuffix,
+		clock:                    clock.RealClock{},
+		newCloudflareDriver: func(cfg cloudflaredriver.Config) types.CloudProvider {
+			return cloudflaredriver.NewDriver(cfg)
+		},
+		newAkamaiDriver: func(cfg akamaidriver.Config) types.CloudProvider {
+			return akamaidriver.NewDriver(cfg)
+		},
+		newAWSDriver: func(cfg awsdriver.Config) types.CloudProvider {
+			return awsdriver.NewDriver(cfg)
+		},
+		newRemoteClusterDriver: func(cfg remoteclusterdriver.Config) types.CloudProvider {
+			return remoteclusterdriver.NewDriver(cfg)
+		},
+		newS3Driver: func(cfg s3driver.Config) types.CloudProvider {
+			return s3driver.NewDriver(cfg)
+		},
+	}
+	m.uploadCtx, m.cancelUploads = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(m)
 	}
+	if m.dryRun {
+		m.enableDryRun()
+	}
+	m.certManager = kubernetesdriver.NewDriver(m.k8sClient, scheme, kubernetesdriver.WithClock(m.clock))
+	return m
 }
 
 // ProcessCertificate processes a certificate CR
 func (m *CertificateManager) ProcessCertificate(ctx context.Context, cert *certificatev1alpha1.Certificate) (ctrl.Result, bool, error) {
 	log := logf.FromContext(ctx)
 
-	// Set default ClusterIssuer name if not specified
-	clusterIssuerName := cert.Spec.ClusterIssuerName
-	if clusterIssuerName == "" {
-		clusterIssuerName = "letsencrypt-prod"
+	// Ensure cert-manager Certificate with the ClusterIssuer or Issuer reference
+	certSpec := m.certManagerSpec(cert)
+	secretName, issuerKind, issuerName := certSpec.SecretName, certSpec.IssuerKind, certSpec.IssuerName
+	certResult, err := m.certManager.EnsureCertificate(ctx, certSpec)
+	if errors.Is(err, types.ErrSecretConflict) {
+		log.Info("TLS secret is the target of another cert-manager Certificate, not issuing", "reason", err.Error())
+		return ctrl.Result{RequeueAfter: secretConflictRequeueInterval}, setSecretConflictCondition(cert, err), nil
+	}
+	if errors.Is(err, types.ErrNameConflict) {
+		log.Info("Name is taken by a resource the operator doesn't manage, not issuing", "reason", err.Error())
+		return ctrl.Result{RequeueAfter: secretConflictRequeueInterval}, setNameConflictCondition(cert, err), nil
 	}
-
-	// Ensure cert-manager Certificate with ClusterIssuer reference
-	certResult, err := m.certManager.EnsureCertificate(ctx, types.CertSpec{
-		Name:              cert.Name + "-cert",
-		Namespace:         cert.Namespace,
-		Domain:            cert.Spec.Domain,
-		ClusterIssuerName: clusterIssuerName,
-		SecretName:        cert.Name + "-tls",
-		OwnerReferences: []metav1.OwnerReference{
-			*metav1.NewControllerRef(cert, certificatev1alpha1.GroupVersion.WithKind("Certificate")),
-		},
-	})
 	if err != nil {
 		return ctrl.Result{}, false, err
 	}
 
 	// Update status if needed
-	statusUpdated := false
+	statusUpdated := setSecretConflictCondition(cert, nil)
+	if setNameConflictCondition(cert, nil) {
+		statusUpdated = true
+	}
+
+	// Fill status.providers for Certificates uploaded before it existed
+	if migrateProviderStatus(cert) {
+		statusUpdated = true
+	}
+
+	// Re-import into certificates uploaded before the operator managed them
+	adopted, err := m.adoptProviderIdentifiers(ctx, cert)
+	if err != nil {
+		return ctrl.Result{}, statusUpdated, err
+	}
+	if adopted {
+		return ctrl.Result{RequeueAfter: adoptionRequeueInterval}, true, nil
+	}
+
+	// Switch to the fallback issuer once issuance against the primary keeps failing
+	now := m.clock.Now()
+	if trackIssuanceFailure(cert, certResult.Certificate, now) {
+		statusUpdated = true
+	}
+	if fallbackIssuerDue(cert, now) {
+		log.Info("Issuance keeps failing, switching to the fallback issuer",
+			"issuer", cert.Spec.FallbackClusterIssuerName, "failingSince", cert.Status.IssuanceFailingSince.Time)
+		issuerKind, issuerName = string(certificatev1alpha1.IssuerKindClusterIssuer), cert.Spec.FallbackClusterIssuerName
+		certSpec.IssuerKind, certSpec.IssuerName = issuerKind, issuerName
+		certResult, err = m.certManager.EnsureCertificate(ctx, certSpec)
+		if err != nil {
+			return ctrl.Result{}, statusUpdated, err
+		}
+	}
+	if activeIssuer := formatIssuer(issuerKind, issuerName); cert.Status.ActiveIssuer != activeIssuer {
+		cert.Status.ActiveIssuer = activeIssuer
+		statusUpdated = true
+	}
+
 	if cert.Status.CertificateRef != certResult.Name {
 		cert.Status.CertificateRef = certResult.Name
 		statusUpdated = true
 	}
+	if cert.Status.SecretName != secretName {
+		cert.Status.SecretName = secretName
+		statusUpdated = true
+	}
+
+	// Surface providers that are enabled but silently skipped, the others still upload
+	problems := providerMisconfigurations(cert)
+	if err := ValidateCredentialsNamespace(cert, m.credentialsNamespace); err != nil {
+		problems = append(problems, err.Error()+", it is ignored")
+	}
+	if len(problems) > 0 {
+		log.Info("Certificate has misconfigured providers", "problems", problems)
+	}
+	if setProvidersConfiguredCondition(cert, problems) {
+		statusUpdated = true
+	}
+
+	// Providers disabled by the operator are skipped, re-upload to each once it is enabled
+	// again since it may have missed renewals in between
+	disabled := m.disabledProvidersFor(cert)
+	if len(disabled) > 0 {
+		log.Info("Uploads to providers are disabled by the operator", "providers", disabled)
+	}
+	if setProvidersDisabledCondition(cert, disabled) {
+		statusUpdated = true
+	}
+	if markReuploadPending(cert, disabled) {
+		statusUpdated = true
+	}
+
+	// The certificate is reissued by the new issuer, upload it once it replaces the current one
+	if certResult.IssuerChanged {
+		log.Info("Issuer changed, forcing reissuance and re-upload", "kind", issuerKind, "issuer", issuerName)
+		if resetUploadStatus(cert) {
+			statusUpdated = true
+		}
+	}
+
+	// The current certificate stays in the TLS secret until the reissued one replaces it,
+	// its hash is unchanged so it isn't uploaded again
+	if certResult.SubjectChanged && !certResult.IssuerChanged {
+		log.Info("Subject changed, forcing reissuance")
+	}
+
+	// Issue against the shadow issuer too, its secret is never uploaded
+	if err := m.reconcileShadowCertificate(ctx, cert, &statusUpdated); err != nil {
+		return ctrl.Result{}, statusUpdated, err
+	}
 
 	// Get TLS Secret
-	tlsSecret, err := m.certManager.GetTLSSecret(ctx, cert.Name+"-tls", cert.Namespace)
+	secretKeys, err := tlsSecretKeys(cert)
+	if err != nil {
+		return ctrl.Result{}, statusUpdated, err
+	}
+	tlsSecret, err := m.getTLSMaterial(ctx, cert, secretName, secretKeys)
 	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, statusUpdated, err
+		}
+
+		// Secret was deleted after a previous upload; forget the uploaded hash so the
+		// certificate re-issued by cert-manager into the recreated secret is uploaded again.
+		if resetUploadStatus(cert) {
+			log.Info("TLS secret was deleted, cleared upload status to force re-upload", "secret", secretName)
+			statusUpdated = true
+		}
+
 		// Secret doesn't exist, wait for readiness
-		result, waitErr := m.certManager.WaitForReadiness(ctx, certResult.Name, cert.Namespace)
+		result, waitErr := m.waitForIssuance(ctx, cert, certResult.Name, &statusUpdated)
 		return result, statusUpdated, waitErr
 	}
 
 	if tlsSecret == nil {
-		// Secret exists but is empty
-		log.Info("TLS secret is empty, waiting...")
-		return ctrl.Result{}, statusUpdated, nil
+		// Secret exists but is empty, or the key Secret isn't ready yet
+		log.Info("TLS secret is incomplete, waiting...", "requeueAfter", emptySecretRequeueInterval)
+		return ctrl.Result{RequeueAfter: emptySecretRequeueInterval}, statusUpdated, nil
+	}
+
+	// Don't upload a certificate from the previous issuer while reissuance is pending
+	if !issuedBy(tlsSecret.Secret, issuerKind, issuerName) {
+		log.Info("TLS secret was issued by a different issuer, waiting for reissuance",
+			"kind", issuerKind, "issuer", issuerName)
+		result, waitErr := m.waitForIssuance(ctx, cert, certResult.Name, &statusUpdated)
+		return result, statusUpdated, waitErr
+	}
+
+	// A pre-created Opaque Secret keeps its type when cert-manager issues into it
+	secretType, mismatch := tlsSecretTypeMismatch(cert, tlsSecret, secretKeys)
+	if setTLSSecretTypeCondition(cert, secretName, secretType, mismatch) {
+		if mismatch {
+			log.Info("TLS secret is not of type kubernetes.io/tls, ingress controllers may reject it",
+				"secret", secretName, "type", secretType)
+		}
+		statusUpdated = true
+	}
+
+	if len(tlsSecret.PKCS12) > 0 {
+		password, err := m.pkcs12Password(ctx, cert)
+		if err != nil {
+			return ctrl.Result{}, statusUpdated, err
+		}
+		tlsSecret.Certificate, tlsSecret.Chain, tlsSecret.PrivateKey, err = decodePKCS12(tlsSecret.PKCS12, password)
+		if err != nil {
+			log.Error(err, "Failed to decode PKCS#12 keystore", "secret", tlsSecret.Secret.Name)
+			return ctrl.Result{}, statusUpdated, err
+		}
 	}
 
 	log.V(1).Info("TLS Secret found, proceeding with certificate upload")
 
+	// Issuance finished, clear any pending progress. Only the reconcile that first finds the
+	// certificate still has the start of issuance.
+	recordIssuanceDuration(cert, m.clock.Now())
+	if cert.Status.IssuanceDetail != "" || cert.Status.IssuanceStartedAt != nil {
+		cert.Status.IssuanceDetail = ""
+		cert.Status.IssuanceStartedAt = nil
+		statusUpdated = true
+	}
+	resetPendingIssuance(cert)
+	if clearIssuanceTimeout(cert) {
+		statusUpdated = true
+	}
+
+	// Record the expiry for the inventory, renewals update it
+	if notAfter, ok := leafNotAfter(tlsSecret.Certificate); ok {
+		if cert.Status.NotAfter == nil || !cert.Status.NotAfter.Time.Equal(notAfter) {
+			cert.Status.NotAfter = &metav1.Time{Time: notAfter}
+			statusUpdated = true
+		}
+	}
+
+	// Breakers of providers removed from the spec would never close
+	if m.pruneCircuitBreakers(cert) {
+		statusUpdated = true
+	}
+	if resetCircuitBreakersOnSpecChange(ctx, cert) {
+		statusUpdated = true
+	}
+
+	// A provider the Certificate disabled would otherwise keep serving its last upload
+	cleanupUpdated, cleanupErr := m.deleteDisabledCloudflareUpload(ctx, cert)
+	if cleanupUpdated {
+		statusUpdated = true
+	}
+	if cleanupErr != nil {
+		log.Error(cleanupErr, "Failed to clean up the disabled Cloudflare upload", "retryAfter", disabledCleanupRetryInterval)
+	}
+
 	// Upload certificates to cloud providers if changed
-	certChanged := m.uploadToCloudProviders(ctx, cert, tlsSecret.Certificate, tlsSecret.PrivateKey, &statusUpdated)
+	certChanged, requeueAfter := m.uploadToCloudProviders(ctx, cert, tlsSecret, &statusUpdated)
+	if requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, statusUpdated, nil
+	}
 
 	// Update hash and timestamp if certificate was uploaded
-	if certChanged && (cert.Status.CloudflareUploaded || cert.Status.AWSUploaded) {
-		now := metav1.Now()
+	if certChanged && (cert.Status.CloudflareUploaded || cert.Status.AWSUploaded || cert.Status.S3Uploaded ||
+		cert.Status.Providers[akamaiProviderName].Uploaded || anyRemoteClusterSynced(cert)) {
+		now := metav1.NewTime(m.clock.Now())
 		cert.Status.LastUploadedCertHash = calculateCertHash(tlsSecret.Certificate)
+		cert.Status.LastUploadedChainFingerprint = calculateChainFingerprint(tlsSecret.FullChain())
+		cert.Status.LastUploadedSpecHash = calculateUploadSpecHash(cert, m.providerTags(cert))
 		cert.Status.LastUploadedTime = &now
 		statusUpdated = true
+
+		if !m.dryRun {
+			m.notifyPostUploadWebhook(ctx, cert)
+		}
+	}
+
+	// Test the providers whose breaker opened once their cooldown has passed
+	if retryAfter := m.nextBreakerRetry(cert, m.clock.Now()); retryAfter > 0 {
+		if cleanupErr != nil {
+			retryAfter = min(retryAfter, disabledCleanupRetryInterval)
+		}
+		if akamaiUploadPending(cert) {
+			retryAfter = min(retryAfter, akamaiPendingRequeueInterval)
+		}
+		if m.awsAccountRetryPending(cert) {
+			retryAfter = min(retryAfter, awsAccountRetryInterval)
+		}
+		return ctrl.Result{RequeueAfter: retryAfter}, statusUpdated, nil
+	}
+	if cleanupErr != nil {
+		return ctrl.Result{RequeueAfter: disabledCleanupRetryInterval}, statusUpdated, nil
+	}
+	// Check on the CPS change until the certificate is uploaded into it
+	if akamaiUploadPending(cert) {
+		return ctrl.Result{RequeueAfter: akamaiPendingRequeueInterval}, statusUpdated, nil
+	}
+	// Retry the AWS accounts whose import failed, the hash no longer marks them for upload
+	if m.awsAccountRetryPending(cert) {
+		return ctrl.Result{RequeueAfter: awsAccountRetryInterval}, statusUpdated, nil
 	}
 
 	return ctrl.Result{}, statusUpdated, nil
 }
 
-// uploadToCloudProviders uploads certificates to configured cloud providers
+// waitForIssuance waits for cert-manager to issue the certificate, reporting the progress,
+// how long issuance has been pending, and whether it exceeded spec.issuanceTimeout
+func (m *CertificateManager) waitForIssuance(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	certificateName string,
+	statusUpdated *bool,
+) (ctrl.Result, error) {
+	now := metav1.NewTime(m.clock.Now())
+	if cert.Status.IssuanceStartedAt == nil {
+		cert.Status.IssuanceStartedAt = &now
+		*statusUpdated = true
+	}
+	recordPendingIssuance(cert, now.Time)
+
+	result, detail, err := m.certManager.WaitForReadiness(ctx, certificateName, cert.Namespace)
+	if m.dryRun && apierrors.IsNotFound(err) {
+		// A dry run doesn't create the cert-manager Certificate, issuance would start with it
+		result, detail, err = ctrl.Result{RequeueAfter: time.Minute}, "", nil
+	}
+	if err != nil {
+		return result, err
+	}
+	if cert.Status.IssuanceDetail != detail {
+		cert.Status.IssuanceDetail = detail
+		*statusUpdated = true
+	}
+
+	// Check again as soon as the timeout passes rather than at the next poll
+	timeoutChanged, untilTimeout := m.checkIssuanceTimeout(cert, now.Time)
+	if timeoutChanged {
+		*statusUpdated = true
+	}
+	if untilTimeout > 0 && result.RequeueAfter > untilTimeout {
+		result.RequeueAfter = untilTimeout
+	}
+	return result, nil
+}
+
+// reconcileShadowCertificate creates the cert-manager Certificate for spec.shadowClusterIssuerName
+// and reports its readiness, or removes it once the shadow issuer is unset
+func (m *CertificateManager) reconcileShadowCertificate(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	statusUpdated *bool,
+) error {
+	log := logf.FromContext(ctx)
+
+	if cert.Spec.ShadowClusterIssuerName == "" {
+		if cert.Status.ShadowCertificateRef == "" {
+			return nil
+		}
+
+		shadowCert := &certmanagerv1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cert.Status.ShadowCertificateRef,
+				Namespace: cert.Namespace,
+			},
+		}
+		if err := m.k8sClient.Delete(ctx, shadowCert); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Shadow issuer removed, deleted shadow Certificate", "certificate", shadowCert.Name)
+
+		cert.Status.ShadowCertificateRef = ""
+		cert.Status.ShadowReady = false
+		*statusUpdated = true
+		return nil
+	}
+
+	shadowResult, err := m.certManager.EnsureCertificate(ctx, types.CertSpec{
+		Name:       cert.Name + "-shadow" + m.certificateNameSuffix,
+		Namespace:  cert.Namespace,
+		Domain:     cert.Spec.Domain,
+		IssuerKind: string(certificatev1alpha1.IssuerKindClusterIssuer),
+		IssuerName: cert.Spec.ShadowClusterIssuerName,
+		SecretName: cert.Name + "-shadow" + m.secretNameSuffix,
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(cert, certificatev1alpha1.GroupVersion.WithKind("Certificate")),
+		},
+		Subject:     certManagerSubject(cert.Spec.Subject),
+		ManagedBy:   m.managedBy(),
+		Labels:      cert.Spec.CertificateLabels,
+		Annotations: cert.Spec.CertificateAnnotations,
+	})
+	if err != nil {
+		return err
+	}
+
+	shadowReady := isCertificateReady(shadowResult.Certificate)
+	if cert.Status.ShadowCertificateRef != shadowResult.Name || cert.Status.ShadowReady != shadowReady {
+		if shadowReady && !cert.Status.ShadowReady {
+			log.Info("Shadow Certificate issued", "certificate", shadowResult.Name, "issuer", cert.Spec.ShadowClusterIssuerName)
+		}
+		cert.Status.ShadowCertificateRef = shadowResult.Name
+		cert.Status.ShadowReady = shadowReady
+		*statusUpdated = true
+	}
+	return nil
+}
+
+// certManagerSpec returns the spec of the cert-manager Certificate issuing cert
+func (m *CertificateManager) certManagerSpec(cert *certificatev1alpha1.Certificate) types.CertSpec {
+	issuerKind, issuerName := activeIssuerRef(cert)
+	return types.CertSpec{
+		Name:       cert.Name + m.certificateNameSuffix,
+		Namespace:  cert.Namespace,
+		Domain:     cert.Spec.Domain,
+		IssuerKind: issuerKind,
+		IssuerName: issuerName,
+		SecretName: cert.Name + m.secretNameSuffix,
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(cert, certificatev1alpha1.GroupVersion.WithKind("Certificate")),
+		},
+		PrivateKeyRotationPolicy: string(cert.Spec.PrivateKeyRotationPolicy),
+		RevisionHistoryLimit:     cert.Spec.RevisionHistoryLimit,
+		Subject:                  certManagerSubject(cert.Spec.Subject),
+		AdditionalOutputFormats:  certManagerOutputFormats(cert.Spec.AdditionalOutputFormats),
+		ManagedBy:                m.managedBy(),
+		Labels:                   cert.Spec.CertificateLabels,
+		Annotations:              cert.Spec.CertificateAnnotations,
+	}
+}
+
+// issuerRef returns the kind and name of the issuer of the cert-manager Certificate
+func issuerRef(cert *certificatev1alpha1.Certificate) (string, string) {
+	spec := cert.EffectiveSpec()
+	if spec.IssuerKind == certificatev1alpha1.IssuerKindIssuer {
+		return string(certificatev1alpha1.IssuerKindIssuer), spec.IssuerName
+	}
+	return string(certificatev1alpha1.IssuerKindClusterIssuer), spec.ClusterIssuerName
+}
+
+// certManagerSubject maps the Certificate subject to the cert-manager subject
+func certManagerSubject(subject *certificatev1alpha1.X509Subject) *certmanagerv1.X509Subject {
+	if subject == nil {
+		return nil
+	}
+	return &certmanagerv1.X509Subject{
+		Organizations:       subject.Organizations,
+		Countries:           subject.Countries,
+		OrganizationalUnits: subject.OrganizationalUnits,
+		Localities:          subject.Localities,
+		Provinces:           subject.Provinces,
+		StreetAddresses:     subject.StreetAddresses,
+		PostalCodes:         subject.PostalCodes,
+		SerialNumber:        subject.SerialNumber,
+	}
+}
+
+// certManagerOutputFormats converts the additional output formats to cert-manager's, in
+// order and without duplicates
+func certManagerOutputFormats(formats []certificatev1alpha1.AdditionalOutputFormat) []certmanagerv1.CertificateAdditionalOutputFormat {
+	var result []certmanagerv1.CertificateAdditionalOutputFormat
+	for _, format := range formats {
+		outputFormat := certmanagerv1.CertificateAdditionalOutputFormat{Type: certmanagerv1.CertificateOutputFormatType(format)}
+		if !slices.Contains(result, outputFormat) {
+			result = append(result, outputFormat)
+		}
+	}
+	return result
+}
+
+// issuedBy reports whether cert-manager issued the secret with the given issuer.
+// Secrets without cert-manager's issuer annotations are assumed to match.
+func issuedBy(secret *corev1.Secret, kind, name string) bool {
+	if secret == nil {
+		return true
+	}
+	secretKind, hasKind := secret.Annotations[certmanagerv1.IssuerKindAnnotationKey]
+	secretName, hasName := secret.Annotations[certmanagerv1.IssuerNameAnnotationKey]
+	if !hasKind || !hasName {
+		return true
+	}
+	return secretKind == kind && secretName == name
+}
+
+// isCertificateReady reports whether a cert-manager Certificate has the Ready condition
+func isCertificateReady(cmCert *certmanagerv1.Certificate) bool {
+	if cmCert == nil {
+		return false
+	}
+	for _, cond := range cmCert.Status.Conditions {
+		if cond.Type == certmanagerv1.CertificateConditionReady && cond.Status == cmmeta.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadToCloudProviders uploads certificates to configured cloud providers.
+// It returns whether the certificate changed since the last upload, and a non-zero
+// requeue delay when the upload was deferred because the certificate is not valid yet, its
+// domain doesn't resolve as spec.dnsCheck expects, an upload blackout window is active, or
+// its renewal re-upload is spread out.
+// Nothing is uploaded when the leaf SANs don't include spec.domain, or while an upload that
+// requires approval isn't approved.
 func (m *CertificateManager) uploadToCloudProviders(
 	ctx context.Context,
 	cert *certificatev1alpha1.Certificate,
-	tlsCert, tlsKey []byte,
+	tlsSecret *types.TLSSecret,
 	statusUpdated *bool,
-) bool {
+) (bool, time.Duration) {
 	log := logf.FromContext(ctx)
+	tlsCert, fullChain := tlsSecret.Certificate, tlsSecret.FullChain()
 
 	// Calculate certificate hash to detect renewals
 	currentCertHash := calculateCertHash(tlsCert)
 	certChanged := currentCertHash != cert.Status.LastUploadedCertHash
 
+	// Hashes stored before normalization cover the raw PEM bytes; migrate them instead of re-uploading
+	if certChanged && cert.Status.LastUploadedCertHash == legacyCertHash(fullChain) {
+		log.Info("Migrating last uploaded certificate hash to normalized form", "hash", currentCertHash)
+		cert.Status.LastUploadedCertHash = currentCertHash
+		*statusUpdated = true
+		certChanged = false
+	}
+
+	// A rotated intermediate changes the uploaded bundle even though the leaf is unchanged
+	currentChainFingerprint := calculateChainFingerprint(fullChain)
+	if !certChanged && cert.Status.LastUploadedChainFingerprint != currentChainFingerprint {
+		if cert.Status.LastUploadedChainFingerprint == "" {
+			// Recorded before chain fingerprints were tracked, adopt it without re-uploading
+			cert.Status.LastUploadedChainFingerprint = currentChainFingerprint
+			*statusUpdated = true
+		} else {
+			log.Info("Certificate chain changed, re-uploading to cloud providers",
+				"oldFingerprint", cert.Status.LastUploadedChainFingerprint,
+				"newFingerprint", currentChainFingerprint)
+			certChanged = true
+		}
+	}
+
+	// Tags and bundle types change what providers hold without a renewal
+	providerTags := m.providerTags(cert)
+	currentSpecHash := calculateUploadSpecHash(cert, providerTags)
+	if !certChanged && cert.Status.LastUploadedSpecHash != currentSpecHash {
+		if cert.Status.LastUploadedSpecHash == "" {
+			// Uploaded before spec hashes were tracked, adopt it without re-uploading
+			cert.Status.LastUploadedSpecHash = currentSpecHash
+			*statusUpdated = true
+		} else {
+			log.Info("Upload settings changed, updating cloud providers",
+				"oldSpecHash", cert.Status.LastUploadedSpecHash,
+				"newSpecHash", currentSpecHash)
+			certChanged = true
+		}
+	}
+
+	// The upload hashes stay at the last uploaded certificate, so unpausing uploads the latest one
+	if *cert.EffectiveSpec().UploadsPaused {
+		if certChanged {
+			log.Info("Uploads are paused, skipping upload to cloud providers", "hash", currentCertHash)
+		}
+		return false, 0
+	}
+
 	if certChanged {
 		if cert.Status.LastUploadedCertHash != "" {
 			log.Info("Certificate hash changed, re-uploading to cloud providers",
@@ -133,108 +897,777 @@ func (m *CertificateManager) uploadToCloudProviders(
 		} else {
 			log.Info("Certificate ready for initial upload", "hash", currentCertHash)
 		}
+
+		// Providers reject certificates whose NotBefore is in the future (clock skew or
+		// pre-issued certificates), so wait until the certificate becomes valid
+		now := m.clock.Now()
+		if skew := timeUntilValid(tlsCert, now); skew > 0 {
+			log.Info("Certificate is not valid yet, deferring upload to cloud providers",
+				"notBefore", now.Add(skew).UTC(), "skew", skew.Round(time.Second))
+			return false, min(skew, maxNotYetValidRequeue)
+		}
+
+		// A misconfigured issuer can issue a certificate for other domains, never push it
+		if sans, ok := leafSANs(tlsCert); ok {
+			matched := sansInclude(sans, cert.Spec.Domain)
+			if setSANMismatchCondition(cert, sans, matched) {
+				*statusUpdated = true
+			}
+			if !matched {
+				log.Info("Certificate SANs don't include the domain, skipping upload to cloud providers",
+					"domain", cert.Spec.Domain, "sans", sans)
+				return false, 0
+			}
+		}
+
+		// A misconfigured issuer can also sign with an unexpected CA
+		if cert.Spec.TrustedCASecretRef != "" {
+			verifyErr := m.verifyTrustedChain(ctx, cert, fullChain)
+			if setChainVerificationCondition(cert, verifyErr) {
+				*statusUpdated = true
+			}
+			if verifyErr != nil {
+				log.Info("Certificate doesn't chain to the trusted CA, skipping upload to cloud providers",
+					"trustedCASecretRef", cert.Spec.TrustedCASecretRef, "reason", verifyErr.Error())
+				return false, 0
+			}
+		} else if meta.RemoveStatusCondition(&cert.Status.Conditions, certificatev1alpha1.ConditionChainVerificationFailed) {
+			*statusUpdated = true
+		}
+
+		// Records may only be pointed at the endpoint serving the certificate after issuance
+		if dnsCheckApplies(cert) {
+			dnsErr := m.checkDNS(ctx, cert)
+			if setDNSNotReadyCondition(cert, dnsErr) {
+				*statusUpdated = true
+			}
+			if dnsErr != nil {
+				log.Info("Domain doesn't resolve as expected, deferring upload to cloud providers",
+					"domain", cert.Spec.Domain, "reason", dnsErr.Error(), "requeueAfter", dnsCheckRequeueInterval)
+				return false, dnsCheckRequeueInterval
+			}
+		} else if meta.RemoveStatusCondition(&cert.Status.Conditions, certificatev1alpha1.ConditionDNSNotReady) {
+			*statusUpdated = true
+		}
+
+		// Regulated environments require a human to sign off before certificates go out
+		if cert.Spec.RequireApproval {
+			approved := uploadApproved(cert)
+			if setPendingApprovalCondition(cert, approved) {
+				*statusUpdated = true
+			}
+			if !approved {
+				log.Info("Upload is not approved yet, withholding upload to cloud providers",
+					"annotation", certificatev1alpha1.ApprovedAnnotation)
+				return false, 0
+			}
+		} else if meta.RemoveStatusCondition(&cert.Status.Conditions, certificatev1alpha1.ConditionPendingApproval) {
+			*statusUpdated = true
+		}
+
+		// Uploads are production changes, some organizations forbid them at certain times
+		if end, ok := m.uploadBlackout.End(now); ok {
+			log.Info("Upload blackout window is active, deferring upload to cloud providers",
+				"until", end.UTC(), "delay", end.Sub(now).Round(time.Second))
+			return false, end.Sub(now)
+		}
+
+		// Renewals issued in a batch would otherwise all re-upload at once and hit provider
+		// rate limits, so hold each back by an offset keyed by its expiry
+		if cert.Status.LastUploadedCertHash != "" && currentCertHash != cert.Status.LastUploadedCertHash {
+			if delay := renewalUploadDelay(cert, tlsCert, m.renewalUploadWindow, now); delay > 0 {
+				log.Info("Spreading renewal re-uploads, deferring upload to cloud providers",
+					"uploadAt", now.Add(delay).UTC(), "delay", delay.Round(time.Second))
+				return false, delay
+			}
+		}
 	}
 
 	certData := types.CertificateData{
 		Domain:      cert.Spec.Domain,
 		Certificate: tlsCert,
-		PrivateKey:  tlsKey,
+		Chain:       tlsSecret.Chain,
+		PrivateKey:  tlsSecret.PrivateKey,
+		Tags:        providerTags,
 	}
 
+	verification := &uploadVerification{}
+
 	// Upload to Cloudflare if configured
-	cloudflareEnabled := cert.Spec.CloudflareEnabled == nil || *cert.Spec.CloudflareEnabled
-	if cert.Spec.CloudflareSecretRef != "" && cloudflareEnabled && certChanged {
+	cloudflareEnabled := *cert.EffectiveSpec().CloudflareEnabled
+	if cert.Spec.CloudflareSecretRef != "" && cloudflareEnabled && !m.providerDisabled(cloudflareProviderName) &&
+		m.shouldUpload(ctx, cert, cloudflareProviderName,
+			certChanged || reuploadPending(cert, cloudflareProviderName), m.clock.Now(), statusUpdated) {
 		certData.ExistingID = cert.Status.CloudflareCertificateID
-		driver := cloudflaredriver.NewDriver(cloudflaredriver.Config{
-			Client:    m.k8sClient,
-			SecretRef: cert.Spec.CloudflareSecretRef,
-			Namespace: cert.Namespace,
-			ZoneID:    cert.Spec.CloudflareZoneID,
+		certData.Chain = bundleChain(tlsSecret.Chain, cert.Spec.CloudflareBundle)
+		driver := m.newCloudflareDriver(cloudflaredriver.Config{
+			Client:     m.k8sClient,
+			SecretRef:  cert.Spec.CloudflareSecretRef,
+			Namespace:  m.secretNamespace(cert),
+			ZoneID:     cert.Spec.CloudflareZoneID,
+			MaxRetries: m.maxRetries,
 		})
 
-		result, err := driver.Upload(ctx, certData)
+		result, err := m.upload(ctx, driver, certData)
+		if m.recordUploadResult(ctx, cert, cloudflareProviderName, err, m.clock.Now()) {
+			*statusUpdated = true
+		}
 		if err != nil {
 			log.Error(err, "Failed to upload to Cloudflare")
+			recordProviderError(cert, cloudflareProviderName, err)
+			*statusUpdated = true
 		} else {
-			cert.Status.CloudflareUploaded = true
-			cert.Status.CloudflareCertificateID = result.Identifier
+			recordProviderUpload(cert, cloudflareProviderName, result.Identifier, m.clock.Now())
 			*statusUpdated = true
 			log.Info("Successfully uploaded certificate to Cloudflare", "id", result.Identifier)
+			if !m.verifyUpload(ctx, driver, result.Identifier, certData, verification) {
+				// The copy is replaced on the next upload
+				markProviderNotUploaded(cert, cloudflareProviderName)
+			}
 		}
 	}
 
 	// Upload to AWS ACM if configured
-	if cert.Spec.AWS != nil && certChanged {
+	if cert.Spec.AWS != nil && !m.providerDisabled(awsProviderName) &&
+		m.shouldUpload(ctx, cert, awsProviderName,
+			certChanged || reuploadPending(cert, awsProviderName), m.clock.Now(), statusUpdated) {
 		certData.ExistingID = cert.Status.AWSCertificateARN
-		driver := awsdriver.NewDriver(awsdriver.Config{
+		certData.Chain = bundleChain(tlsSecret.Chain, cert.Spec.AWS.Bundle)
+		driver := m.newAWSDriver(awsdriver.Config{
 			Client:         m.k8sClient,
 			CredentialType: cert.Spec.AWS.CredentialType,
 			SecretRef:      cert.Spec.AWS.SecretRef,
-			Namespace:      cert.Namespace,
+			Namespace:      m.secretNamespace(cert),
 			Domain:         cert.Spec.Domain,
+			DefaultRegion:  m.defaultAWSRegion,
+			MaxRetries:     m.maxRetries,
+			ChainMode:      string(cert.Spec.AWS.ChainMode),
+			PrivateCA:      cert.Spec.AWS.PrivateCA,
+			SkipUnchanged:  cert.Spec.AWS.SkipUnchangedReimport,
 		})
 
-		result, err := driver.Upload(ctx, certData)
+		result, err := m.upload(ctx, driver, certData)
+		if m.recordUploadResult(ctx, cert, awsProviderName, err, m.clock.Now()) {
+			*statusUpdated = true
+		}
 		if err != nil {
 			log.Error(err, "Failed to upload to AWS")
+			recordProviderError(cert, awsProviderName, err)
+			*statusUpdated = true
 		} else {
-			cert.Status.AWSUploaded = true
-			cert.Status.AWSCertificateARN = result.Identifier
+			recordProviderUpload(cert, awsProviderName, result.Identifier, m.clock.Now())
+			setACMStatus(cert, result.ACM)
 			*statusUpdated = true
 			log.Info("Successfully uploaded certificate to AWS ACM", "arn", result.Identifier)
+			if !m.verifyUpload(ctx, driver, result.Identifier, certData, verification) {
+				// The copy is replaced on the next upload
+				markProviderNotUploaded(cert, awsProviderName)
+			}
+		}
+
+		m.uploadToAWSAccounts(ctx, cert, certData, false, statusUpdated)
+	} else if !certChanged && m.awsAccountRetryPending(cert) {
+		// The accounts whose import of the current certificate failed are retried on their own
+		certData.Chain = bundleChain(tlsSecret.Chain, cert.Spec.AWS.Bundle)
+		m.uploadToAWSAccounts(ctx, cert, certData, true, statusUpdated)
+	}
+
+	// Upload to the Akamai CPS enrollment if configured, continuing a pending change
+	if cert.Spec.AkamaiEnabled && !m.providerDisabled(akamaiProviderName) &&
+		m.shouldUpload(ctx, cert, akamaiProviderName,
+			certChanged || akamaiUploadPending(cert) || reuploadPending(cert, akamaiProviderName), m.clock.Now(), statusUpdated) {
+		certData.ExistingID = cert.Status.Providers[akamaiProviderName].Identifier
+		certData.Chain = tlsSecret.Chain
+		m.uploadToAkamai(ctx, cert, certData, statusUpdated)
+	}
+
+	// Certificates are written to S3 and replicated to remote clusters as issued
+	certData.Chain = tlsSecret.Chain
+	certData.ExistingID = ""
+
+	// Write the PEM files to S3 if configured
+	if cert.Spec.S3 != nil && !m.providerDisabled(s3ProviderName) &&
+		m.shouldUpload(ctx, cert, s3ProviderName,
+			certChanged || reuploadPending(cert, s3ProviderName), m.clock.Now(), statusUpdated) {
+		driver := m.newS3Driver(m.s3DriverConfig(cert))
+
+		result, err := m.upload(ctx, driver, certData)
+		if m.recordUploadResult(ctx, cert, s3ProviderName, err, m.clock.Now()) {
+			*statusUpdated = true
+		}
+		if err != nil {
+			log.Error(err, "Failed to upload to S3")
+			recordProviderError(cert, s3ProviderName, err)
+			*statusUpdated = true
+		} else {
+			m.deleteStaleS3Objects(ctx, driver, cert.Status.S3ObjectKeys, result.ObjectKeys)
+			recordProviderUpload(cert, s3ProviderName, result.Identifier, m.clock.Now())
+			cert.Status.S3ObjectKeys = result.ObjectKeys
+			*statusUpdated = true
+			log.Info("Successfully uploaded certificate to S3", "location", result.Identifier)
+		}
+	}
+
+	// Replicate the TLS secret to remote clusters
+	if !m.providerDisabled(remoteClusterProviderName) {
+		m.replicateToRemoteClusters(ctx, cert, certData, certChanged, statusUpdated)
+	}
+
+	if verification.ran && setVerificationFailedCondition(cert, verification.mismatches) {
+		*statusUpdated = true
+	}
+
+	return certChanged, 0
+}
+
+// uploadToAkamai uploads to the Akamai CPS enrollment of the Certificate. CPS first has to
+// generate the CSRs of the change the driver starts and the issuer has to sign them, so
+// the change is recorded while that is pending and the upload continues it once requeued.
+func (m *CertificateManager) uploadToAkamai(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	certData types.CertificateData,
+	statusUpdated *bool,
+) {
+	log := logf.FromContext(ctx)
+	driver := m.newAkamaiDriver(m.akamaiDriverConfig(cert))
+
+	result, err := m.upload(ctx, driver, certData)
+	if errors.Is(err, types.ErrUploadPending) {
+		// Waiting isn't a failure, so the circuit breaker isn't involved
+		log.Info("Akamai CPS upload is pending", "change", result.Identifier, "reason", err.Error())
+		setProviderIdentifier(cert, akamaiProviderName, result.Identifier)
+		markProviderNotUploaded(cert, akamaiProviderName)
+		recordProviderError(cert, akamaiProviderName, err)
+		*statusUpdated = true
+		return
+	}
+
+	if m.recordUploadResult(ctx, cert, akamaiProviderName, err, m.clock.Now()) {
+		*statusUpdated = true
+	}
+	if err != nil {
+		log.Error(err, "Failed to upload to Akamai CPS")
+		if result.Identifier != "" {
+			setProviderIdentifier(cert, akamaiProviderName, result.Identifier)
+		}
+		recordProviderError(cert, akamaiProviderName, err)
+		*statusUpdated = true
+		return
+	}
+	// The change deploys the certificate on its own, it is neither continued nor cancelled
+	// anymore, so its identifier is forgotten
+	recordProviderUpload(cert, akamaiProviderName, "", m.clock.Now())
+	*statusUpdated = true
+	log.Info("Successfully uploaded certificate to Akamai CPS", "change", result.Identifier)
+}
+
+// akamaiUploadPending reports whether the Akamai CPS change the operator started still
+// waits for the certificate
+func akamaiUploadPending(cert *certificatev1alpha1.Certificate) bool {
+	status := cert.Status.Providers[akamaiProviderName]
+	return cert.Spec.AkamaiEnabled && status.Identifier != "" && !status.Uploaded
+}
+
+// akamaiDriverConfig returns the Akamai CPS driver configuration of the Certificate. The
+// CSRs of CPS changes are signed by the issuer of the cert-manager Certificate.
+func (m *CertificateManager) akamaiDriverConfig(cert *certificatev1alpha1.Certificate) akamaidriver.Config {
+	issuerKind, issuerName := activeIssuerRef(cert)
+	return akamaidriver.Config{
+		Client:       m.k8sClient,
+		SecretRef:    cert.Spec.AkamaiSecretRef,
+		Namespace:    m.secretNamespace(cert),
+		EnrollmentID: cert.Spec.AkamaiEnrollmentID,
+		MaxRetries:   m.maxRetries,
+		Request: akamaidriver.RequestConfig{
+			NamePrefix: cert.Name,
+			Namespace:  cert.Namespace,
+			IssuerKind: issuerKind,
+			IssuerName: issuerName,
+			ManagedBy:  m.managedBy(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cert, certificatev1alpha1.GroupVersion.WithKind("Certificate")),
+			},
+		},
+	}
+}
+
+// s3DriverConfig returns the S3 driver configuration for spec.s3 with its defaults resolved
+func (m *CertificateManager) s3DriverConfig(cert *certificatev1alpha1.Certificate) s3driver.Config {
+	s3 := cert.EffectiveSpec().S3
+	return s3driver.Config{
+		Client:               m.k8sClient,
+		CredentialType:       s3.CredentialType,
+		SecretRef:            s3.SecretRef,
+		Namespace:            m.secretNamespace(cert),
+		Bucket:               s3.Bucket,
+		Prefix:               s3.Prefix,
+		Region:               s3.Region,
+		ServerSideEncryption: string(s3.ServerSideEncryption),
+		KMSKeyID:             s3.KMSKeyID,
+		MaxRetries:           m.maxRetries,
+		DefaultRegion:        m.defaultAWSRegion,
+	}
+}
+
+// deleteStaleS3Objects deletes the previously written objects that the latest upload no
+// longer wrote, e.g. after spec.s3.prefix changed
+func (m *CertificateManager) deleteStaleS3Objects(ctx context.Context, driver types.CloudProvider, previous, current []string) {
+	log := logf.FromContext(ctx)
+
+	for _, key := range previous {
+		if slices.Contains(current, key) {
+			continue
+		}
+		if err := driver.Delete(ctx, key); err != nil {
+			log.Error(err, "Failed to delete stale S3 object", "key", key)
+		} else {
+			log.Info("Deleted stale S3 object", "key", key)
+		}
+	}
+}
+
+// deleteFromS3 deletes the objects written to spec.s3
+func (m *CertificateManager) deleteFromS3(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	summary *certificatev1alpha1.FinalizationStatus,
+) []error {
+	log := logf.FromContext(ctx)
+
+	if len(cert.Status.S3ObjectKeys) == 0 {
+		return nil
+	}
+	if cert.Spec.S3 == nil {
+		// S3 was removed from the spec, the bucket can't be reached anymore
+		log.Info("No S3 bucket configured, skipping object cleanup", "keys", cert.Status.S3ObjectKeys)
+		return nil
+	}
+
+	driver := m.newS3Driver(m.s3DriverConfig(cert))
+
+	var errs []error
+	var remaining []string
+	for _, key := range cert.Status.S3ObjectKeys {
+		err := driver.Delete(ctx, key)
+		recordCleanup(summary, s3ProviderName, key, err)
+		if err != nil {
+			log.Error(err, "Failed to delete object from S3", "bucket", cert.Spec.S3.Bucket, "key", key)
+			errs = append(errs, err)
+			remaining = append(remaining, key)
+		} else {
+			log.Info("Successfully deleted object from S3", "bucket", cert.Spec.S3.Bucket, "key", key)
 		}
 	}
 
-	return certChanged
+	// Only the objects that failed are deleted again on retry
+	cert.Status.S3ObjectKeys = remaining
+	if len(remaining) == 0 {
+		forgetProvider(cert, s3ProviderName)
+	}
+	return errs
 }
 
-// Finalize performs cleanup when Certificate is being deleted
+// uploadToAWSAccounts imports the certificate into every account listed in
+// spec.awsAssumeRoleARNs, tracking the ARN and the outcome per account in status. With
+// pendingOnly, only the accounts whose last import failed are imported into.
+func (m *CertificateManager) uploadToAWSAccounts(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	certData types.CertificateData,
+	pendingOnly bool,
+	statusUpdated *bool,
+) {
+	log := logf.FromContext(ctx)
+	if cert.Spec.AWS == nil {
+		return
+	}
+
+	for _, roleARN := range cert.Spec.AWSAssumeRoleARNs {
+		accountID, err := awsdriver.AccountIDFromRoleARN(roleARN)
+		if err != nil {
+			log.Error(err, "Skipping AWS account")
+			continue
+		}
+		if pendingOnly && !awsAccountPending(cert, accountID) {
+			continue
+		}
+
+		certData.ExistingID = cert.Status.AWSAccountCertificateARNs[accountID]
+		driver := m.newAWSDriver(awsdriver.Config{
+			Client:         m.k8sClient,
+			CredentialType: cert.Spec.AWS.CredentialType,
+			SecretRef:      cert.Spec.AWS.SecretRef,
+			Namespace:      m.secretNamespace(cert),
+			Domain:         cert.Spec.Domain,
+			DefaultRegion:  m.defaultAWSRegion,
+			MaxRetries:     m.maxRetries,
+			AssumeRoleARN:  roleARN,
+			ChainMode:      string(cert.Spec.AWS.ChainMode),
+			PrivateCA:      cert.Spec.AWS.PrivateCA,
+			SkipUnchanged:  cert.Spec.AWS.SkipUnchangedReimport,
+		})
+
+		provider := awsAccountProviderName(accountID)
+		result, err := m.upload(ctx, driver, certData)
+		*statusUpdated = true
+		if err != nil {
+			log.Error(err, "Failed to upload to AWS account, retrying", "account", accountID, "roleARN", roleARN,
+				"retryAfter", awsAccountRetryInterval)
+			// The account still holds the previous certificate, if any, until the retry succeeds
+			updateProviderStatus(cert, provider, func(status *certificatev1alpha1.ProviderStatus) {
+				status.Uploaded = false
+				status.LastError = err.Error()
+			})
+			continue
+		}
+
+		if cert.Status.AWSAccountCertificateARNs == nil {
+			cert.Status.AWSAccountCertificateARNs = make(map[string]string)
+		}
+		cert.Status.AWSAccountCertificateARNs[accountID] = result.Identifier
+		recordProviderUpload(cert, provider, result.Identifier, m.clock.Now())
+		log.Info("Successfully uploaded certificate to AWS ACM account", "account", accountID, "arn", result.Identifier)
+	}
+}
+
+// awsAccountProviderName is the status.providers key of the imports into accountID
+func awsAccountProviderName(accountID string) string {
+	return awsProviderName + ":" + accountID
+}
+
+// awsAccountPending reports whether the current certificate still has to be imported into
+// accountID: its last import failed, or it was added to spec.awsAssumeRoleARNs after the upload.
+// Accounts imported into before their status was tracked count as imported.
+func awsAccountPending(cert *certificatev1alpha1.Certificate, accountID string) bool {
+	if status, ok := cert.Status.Providers[awsAccountProviderName(accountID)]; ok {
+		return !status.Uploaded
+	}
+	return cert.Status.AWSAccountCertificateARNs[accountID] == ""
+}
+
+// awsAccountRetryPending reports whether the import into one of the AWS accounts of cert has
+// to be retried
+func (m *CertificateManager) awsAccountRetryPending(cert *certificatev1alpha1.Certificate) bool {
+	if cert.Spec.AWS == nil || m.providerDisabled(awsProviderName) || *cert.EffectiveSpec().UploadsPaused ||
+		cert.Status.LastUploadedCertHash == "" {
+		return false
+	}
+	for _, roleARN := range cert.Spec.AWSAssumeRoleARNs {
+		accountID, err := awsdriver.AccountIDFromRoleARN(roleARN)
+		if err == nil && awsAccountPending(cert, accountID) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteFromAWSAccounts deletes the certificates imported through spec.awsAssumeRoleARNs
+func (m *CertificateManager) deleteFromAWSAccounts(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	summary *certificatev1alpha1.FinalizationStatus,
+) []error {
+	log := logf.FromContext(ctx)
+
+	roleARNs := make(map[string]string, len(cert.Spec.AWSAssumeRoleARNs))
+	for _, roleARN := range cert.Spec.AWSAssumeRoleARNs {
+		if accountID, err := awsdriver.AccountIDFromRoleARN(roleARN); err == nil {
+			roleARNs[accountID] = roleARN
+		}
+	}
+
+	var errs []error
+	for accountID, certARN := range cert.Status.AWSAccountCertificateARNs {
+		roleARN, ok := roleARNs[accountID]
+		if !ok || cert.Spec.AWS == nil {
+			// The role was removed from the spec, the certificate can't be reached anymore
+			log.Info("No role configured for AWS account, skipping certificate cleanup", "account", accountID, "arn", certARN)
+			continue
+		}
+
+		driver := m.newAWSDriver(awsdriver.Config{
+			Client:         m.k8sClient,
+			CredentialType: cert.Spec.AWS.CredentialType,
+			SecretRef:      cert.Spec.AWS.SecretRef,
+			Namespace:      m.secretNamespace(cert),
+			Domain:         cert.Spec.Domain,
+			DefaultRegion:  m.defaultAWSRegion,
+			AssumeRoleARN:  roleARN,
+		})
+
+		err := driver.Delete(ctx, certARN)
+		recordCleanup(summary, awsProviderName, certARN, err)
+		if err != nil {
+			log.Error(err, "Failed to delete certificate from AWS ACM account", "account", accountID, "arn", certARN)
+			errs = append(errs, err)
+		} else {
+			log.Info("Successfully deleted certificate from AWS ACM account", "account", accountID, "arn", certARN)
+			delete(cert.Status.AWSAccountCertificateARNs, accountID)
+			forgetProvider(cert, awsAccountProviderName(accountID))
+		}
+	}
+	return errs
+}
+
+// replicateToRemoteClusters writes the TLS secret into every cluster in spec.remoteClusters that
+// doesn't hold the current certificate yet, tracking the sync state per cluster in status
+func (m *CertificateManager) replicateToRemoteClusters(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	certData types.CertificateData,
+	certChanged bool,
+	statusUpdated *bool,
+) {
+	log := logf.FromContext(ctx)
+
+	previous := make(map[string]certificatev1alpha1.RemoteClusterStatus, len(cert.Status.RemoteClusters))
+	for _, status := range cert.Status.RemoteClusters {
+		previous[status.Name] = status
+	}
+
+	statuses := make([]certificatev1alpha1.RemoteClusterStatus, 0, len(cert.Spec.RemoteClusters))
+	for _, cluster := range cert.EffectiveSpecWithSecretNameSuffix(m.secretNameSuffix).RemoteClusters {
+		status, found := previous[cluster.Name]
+		delete(previous, cluster.Name)
+		if found && status.Synced && !certChanged {
+			statuses = append(statuses, status)
+			continue
+		}
+		status.Name = cluster.Name
+
+		driver := m.newRemoteClusterDriver(remoteclusterdriver.Config{
+			Client:              m.k8sClient,
+			KubeconfigSecretRef: cluster.KubeconfigSecretRef,
+			Namespace:           m.secretNamespace(cert),
+			TargetNamespace:     cluster.Namespace,
+			SecretName:          cluster.SecretName,
+			MaxRetries:          m.maxRetries,
+		})
+
+		result, err := m.upload(ctx, driver, certData)
+		if err != nil {
+			log.Error(err, "Failed to replicate TLS secret to remote cluster", "cluster", cluster.Name)
+			status.Synced = false
+			status.Error = err.Error()
+		} else {
+			now := metav1.NewTime(m.clock.Now())
+			status.SecretRef = result.Identifier
+			status.Synced = true
+			status.LastSyncedTime = &now
+			status.Error = ""
+			log.Info("Successfully replicated TLS secret to remote cluster", "cluster", cluster.Name, "secret", result.Identifier)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for name, status := range previous {
+		// The cluster was removed from the spec, its kubeconfig is no longer known
+		log.Info("Remote cluster is no longer configured, leaving its secret in place", "cluster", name, "secret", status.SecretRef)
+	}
+
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+	if !equality.Semantic.DeepEqual(cert.Status.RemoteClusters, statuses) {
+		cert.Status.RemoteClusters = statuses
+		*statusUpdated = true
+	}
+}
+
+// anyRemoteClusterSynced reports whether the certificate was replicated to at least one remote cluster
+func anyRemoteClusterSynced(cert *certificatev1alpha1.Certificate) bool {
+	for _, status := range cert.Status.RemoteClusters {
+		if status.Synced {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteFromRemoteClusters deletes the secrets replicated to spec.remoteClusters
+func (m *CertificateManager) deleteFromRemoteClusters(
+	ctx context.Context,
+	cert *certificatev1alpha1.Certificate,
+	summary *certificatev1alpha1.FinalizationStatus,
+) []error {
+	log := logf.FromContext(ctx)
+
+	clusters := make(map[string]certificatev1alpha1.RemoteCluster, len(cert.Spec.RemoteClusters))
+	for _, cluster := range cert.Spec.RemoteClusters {
+		clusters[cluster.Name] = cluster
+	}
+
+	var errs []error
+	for i := range cert.Status.RemoteClusters {
+		status := &cert.Status.RemoteClusters[i]
+		if status.SecretRef == "" {
+			continue
+		}
+		cluster, ok := clusters[status.Name]
+		if !ok {
+			// The cluster was removed from the spec, the secret can't be reached anymore
+			log.Info("No kubeconfig configured for remote cluster, skipping secret cleanup", "cluster", status.Name, "secret", status.SecretRef)
+			continue
+		}
+
+		driver := m.newRemoteClusterDriver(remoteclusterdriver.Config{
+			Client:              m.k8sClient,
+			KubeconfigSecretRef: cluster.KubeconfigSecretRef,
+			Namespace:           m.secretNamespace(cert),
+		})
+
+		err := driver.Delete(ctx, status.SecretRef)
+		recordCleanup(summary, remoteClusterProviderName, status.Name+"/"+status.SecretRef, err)
+		if err != nil {
+			log.Error(err, "Failed to delete TLS secret from remote cluster", "cluster", status.Name, "secret", status.SecretRef)
+			errs = append(errs, err)
+		} else {
+			log.Info("Successfully deleted TLS secret from remote cluster", "cluster", status.Name, "secret", status.SecretRef)
+			status.SecretRef = ""
+			status.Synced = false
+		}
+	}
+	return errs
+}
+
+// Finalize performs cleanup when Certificate is being deleted.
+// Every provider is attempted; failed deletions are returned as a joined error so the
+// caller can retry. Use IsRetriable to check whether the failure is transient.
+// The outcome per provider resource is recorded in status.finalization for the caller to write.
+// Deleted resources are removed from status, so a retry only deletes the ones that failed.
 func (m *CertificateManager) Finalize(ctx context.Context, cert *certificatev1alpha1.Certificate) error {
 	log := logf.FromContext(ctx)
 	log.Info("Finalizing Certificate", "name", cert.Name)
+	deletePendingIssuance(cert)
+
+	// Resources deleted by earlier attempts are no longer in status, keep reporting them
+	var errs []error
+	summary := &certificatev1alpha1.FinalizationStatus{}
+	if cert.Status.Finalization != nil {
+		summary.Deleted = slices.Clone(cert.Status.Finalization.Deleted)
+	}
 
 	// Cleanup AWS ACM certificate if it was uploaded
-	if cert.Status.AWSCertificateARN != "" {
-		driver := awsdriver.NewDriver(awsdriver.Config{
+	if cert.Status.AWSCertificateARN != "" && cert.Spec.AWS == nil {
+		// Without credentials the certificate can't be reached anymore
+		log.Info("AWS is not configured, skipping certificate cleanup", "arn", cert.Status.AWSCertificateARN)
+	} else if cert.Status.AWSCertificateARN != "" {
+		driver := m.newAWSDriver(awsdriver.Config{
 			Client:         m.k8sClient,
 			CredentialType: cert.Spec.AWS.CredentialType,
 			SecretRef:      cert.Spec.AWS.SecretRef,
-			Namespace:      cert.Namespace,
+			Namespace:      m.secretNamespace(cert),
 			Domain:         cert.Spec.Domain,
+			DefaultRegion:  m.defaultAWSRegion,
 		})
 
-		if err := driver.Delete(ctx, cert.Status.AWSCertificateARN); err != nil {
+		err := driver.Delete(ctx, cert.Status.AWSCertificateARN)
+		recordCleanup(summary, awsProviderName, cert.Status.AWSCertificateARN, err)
+		if err != nil {
 			log.Error(err, "Failed to delete certificate from AWS ACM", "arn", cert.Status.AWSCertificateARN)
 			// Continue with other cleanup even if AWS deletion fails
+			errs = append(errs, err)
 		} else {
 			log.Info("Successfully deleted certificate from AWS ACM", "arn", cert.Status.AWSCertificateARN)
+			forgetProvider(cert, awsProviderName)
+		}
+	}
+
+	// Cleanup certificates imported into other AWS accounts
+	errs = append(errs, m.deleteFromAWSAccounts(ctx, cert, summary)...)
+
+	// Cleanup secrets replicated to remote clusters
+	errs = append(errs, m.deleteFromRemoteClusters(ctx, cert, summary)...)
+
+	// Cleanup objects written to S3
+	errs = append(errs, m.deleteFromS3(ctx, cert, summary)...)
+
+	// Cancel the Akamai CPS change if it still waits for the certificate. Once the certificate
+	// is uploaded the change deploys it on its own and isn't tracked anymore.
+	change := cert.Status.Providers[akamaiProviderName].Identifier
+	if cert.Status.Providers[akamaiProviderName].Uploaded {
+		change = ""
+	}
+	if change != "" && (!cert.Spec.AkamaiEnabled || cert.Spec.AkamaiSecretRef == "") {
+		// Without credentials the change can't be reached anymore
+		log.Info("Akamai CPS is not configured, skipping change cleanup", "change", change)
+	} else if change != "" {
+		driver := m.newAkamaiDriver(m.akamaiDriverConfig(cert))
+
+		err := driver.Delete(ctx, change)
+		recordCleanup(summary, akamaiProviderName, change, err)
+		if err != nil {
+			log.Error(err, "Failed to cancel the Akamai CPS change", "change", change)
+			errs = append(errs, err)
+		} else {
+			log.Info("Successfully cleaned up the Akamai CPS change", "change", change)
+			forgetProvider(cert, akamaiProviderName)
 		}
 	}
 
 	// Cleanup Cloudflare certificate if it was uploaded
 	if cert.Status.CloudflareCertificateID != "" {
-		driver := cloudflaredriver.NewDriver(cloudflaredriver.Config{
+		driver := m.newCloudflareDriver(cloudflaredriver.Config{
 			Client:    m.k8sClient,
 			SecretRef: cert.Spec.CloudflareSecretRef,
-			Namespace: cert.Namespace,
+			Namespace: m.secretNamespace(cert),
 			ZoneID:    cert.Spec.CloudflareZoneID,
 		})
 
-		if err := driver.Delete(ctx, cert.Status.CloudflareCertificateID); err != nil {
+		err := driver.Delete(ctx, cert.Status.CloudflareCertificateID)
+		recordCleanup(summary, cloudflareProviderName, cert.Status.CloudflareCertificateID, err)
+		if err != nil {
 			log.Error(err, "Failed to delete certificate from Cloudflare", "id", cert.Status.CloudflareCertificateID)
 			// Continue even if Cloudflare deletion fails
+			errs = append(errs, err)
 		} else {
 			log.Info("Successfully deleted certificate from Cloudflare", "id", cert.Status.CloudflareCertificateID)
+			forgetProvider(cert, cloudflareProviderName)
 		}
 	}
 
+	cert.Status.Finalization = summary
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
 	// Note: Issuer and cert-manager Certificate will be automatically deleted via owner references
 	log.Info("Certificate finalization complete")
 	return nil
 }
 
-// calculateCertHash calculates SHA256 hash of the certificate
-func calculateCertHash(cert []byte) string {
-	hash := sha256.Sum256(cert)
-	return hex.EncodeToString(hash[:])
+// managedBy returns the managed-by label value of the cert-manager Certificates, which
+// includes the instance ID when set
+func (m *CertificateManager) managedBy() string {
+	if m.instanceID == "" {
+		return m.managedByValue
+	}
+	return m.managedByValue + "-" + m.instanceID
+}
+
+// secretNamespace resolves the namespace of the provider credential Secrets
+func (m *CertificateManager) secretNamespace(cert *certificatev1alpha1.Certificate) string {
+	return CredentialsNamespace(cert, m.credentialsNamespace)
+}
+
+// resetUploadStatus clears the upload tracking fields so the next available certificate
+// is treated as a fresh upload. Provider identifiers are kept so the upload re-imports
+// into the existing cloud resources instead of creating duplicates.
+func resetUploadStatus(cert *certificatev1alpha1.Certificate) bool {
+	if cert.Status.LastUploadedCertHash == "" && !cert.Status.CloudflareUploaded && !cert.Status.AWSUploaded && !cert.Status.S3Uploaded &&
+		!cert.Status.Providers[akamaiProviderName].Uploaded {
+		return false
+	}
+
+	cert.Status.LastUploadedCertHash = ""
+	cert.Status.LastUploadedChainFingerprint = ""
+	cert.Status.LastUploadedSpecHash = ""
+	migrateProviderStatus(cert)
+	for provider, status := range cert.Status.Providers {
+		status.Uploaded = false
+		cert.Status.Providers[provider] = status
+	}
+	deriveLegacyProviderStatus(cert)
+	return true
 }
@@ -0,0 +1,4186 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"software.sslmate.com/src/go-pkcs12"
+
+	acmev1 "github.com/cert-manager/cert-manager/pkg/apis/acme/v1"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/blackout"
+	akamaidriver "github.com/tae2089/certificate-operator/internal/driver/akamai"
+	awsdriver "github.com/tae2089/certificate-operator/internal/driver/aws"
+	cloudflaredriver "github.com/tae2089/certificate-operator/internal/driver/cloudflare"
+	remoteclusterdriver "github.com/tae2089/certificate-operator/internal/driver/remotecluster"
+	s3driver "github.com/tae2089/certificate-operator/internal/driver/s3"
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+var _ = Describe("CertificateManager", func() {
+	ctx := context.Background()
+
+	newCertificate := func() *certificatev1alpha1.Certificate {
+		return &certificatev1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "default",
+				UID:       "example-uid",
+			},
+			Spec: certificatev1alpha1.CertificateSpec{
+				Domain: "example.com",
+			},
+		}
+	}
+
+	// ownedByExample returns the owner references of the cert-manager Certificates created for
+	// the Certificate of newCertificate
+	ownedByExample := func() []metav1.OwnerReference {
+		return []metav1.OwnerReference{
+			*metav1.NewControllerRef(newCertificate(), certificatev1alpha1.GroupVersion.WithKind("Certificate")),
+		}
+	}
+
+	newTLSSecret := func(certPEM, keyPEM []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "example-tls",
+				Namespace:   "default",
+				Annotations: map[string]string{certmanagerv1.CertificateNameKey: "example-cert"},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.crt": certPEM,
+				"tls.key": keyPEM,
+			},
+		}
+	}
+
+	Context("When the TLS secret is deleted and recreated", func() {
+		It("should clear the upload status so the recreated certificate is uploaded again", func() {
+			oldLeaf := generateTestCertificate("example.com", testCertOptions{})
+			newLeaf := generateTestCertificate("example.com", testCertOptions{})
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Status.LastUploadedCertHash = calculateCertHash(oldLeaf.certPEM)
+			cert.Status.CloudflareUploaded = true
+			cert.Status.CloudflareCertificateID = "cf-id"
+
+			secret := newTLSSecret(oldLeaf.certPEM, oldLeaf.keyPEM)
+			k8sClient := newFakeClient(cert, secret)
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(k8sClient, testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			By("processing the certificate while the secret exists")
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(oldLeaf.certPEM)))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+
+			By("deleting the TLS secret")
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+			Expect(cert.Status.CloudflareUploaded).To(BeFalse())
+			Expect(cert.Status.CloudflareCertificateID).To(Equal("cf-id"))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+
+			By("recreating the TLS secret with a renewed certificate")
+			Expect(k8sClient.Create(ctx, newTLSSecret(newLeaf.certPEM, newLeaf.keyPEM))).To(Succeed())
+
+			result, statusUpdated, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cfProvider.lastUpload().Certificate).To(Equal(newLeaf.certPEM))
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(newLeaf.certPEM)))
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+		})
+	})
+
+	Context("When the TLS secret exists but is empty", func() {
+		It("should requeue shortly to pick up the populated secret", func() {
+			cert := newCertificate()
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(nil, nil)), testScheme)
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(emptySecretRequeueInterval))
+		})
+	})
+
+	Context("When providers are configured with different bundle shapes", func() {
+		var (
+			cfProvider   *fakeProvider
+			awsProvider  *fakeProvider
+			manager      *CertificateManager
+			fullChain    []byte
+			leaf         *testCertificate
+			intermediate *testCertificate
+		)
+
+		BeforeEach(func() {
+			leaf, intermediate = generateTestChain("example.com")
+			fullChain = append(append([]byte{}, leaf.certPEM...), intermediate.certPEM...)
+
+			cfProvider = newFakeProvider("cloudflare", "cf-id")
+			awsProvider = newFakeProvider("aws", "arn:aws:acm:us-east-1:123456789012:certificate/example")
+		})
+
+		process := func(cert *certificatev1alpha1.Certificate) {
+			manager = NewCertificateManager(newFakeClient(cert, newTLSSecret(fullChain, leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should pass the full chain to every provider by default", func() {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			process(cert)
+
+			for _, provider := range []*fakeProvider{cfProvider, awsProvider} {
+				Expect(provider.lastUpload().Certificate).To(Equal(leaf.certPEM))
+				Expect(provider.lastUpload().Chain).To(Equal(intermediate.certPEM))
+				Expect(provider.lastUpload().FullChain()).To(Equal(fullChain))
+			}
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(leaf.certPEM)))
+		})
+
+		It("should pass only the leaf to providers configured as leaf-only", func() {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.CloudflareBundle = certificatev1alpha1.BundleLeafOnly
+			cert.Spec.AWS = &certificatev1alpha1.AWS{Bundle: certificatev1alpha1.BundleFullChain}
+			process(cert)
+
+			Expect(cfProvider.lastUpload().Certificate).To(Equal(leaf.certPEM))
+			Expect(cfProvider.lastUpload().Chain).To(BeNil())
+			Expect(awsProvider.lastUpload().FullChain()).To(Equal(fullChain))
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(fullChain)))
+		})
+	})
+
+	Context("When checking the TLS secret type", func() {
+		It("should not report a kubernetes.io/tls secret", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+			cert := newCertificate()
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionTLSSecretTypeMismatch)).To(BeNil())
+		})
+
+		It("should warn about a mistyped secret without holding back uploads", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+			secret := newTLSSecret(leaf.certPEM, leaf.keyPEM)
+			secret.Type = corev1.SecretTypeOpaque
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			k8sClient := newFakeClient(cert, secret)
+			manager := NewCertificateManager(k8sClient, testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionTLSSecretTypeMismatch)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonNotKubernetesTLS))
+			Expect(condition.Message).To(ContainSubstring("example-tls is of type Opaque"))
+
+			By("removing the condition once the secret is recreated as kubernetes.io/tls")
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			Expect(k8sClient.Create(ctx, newTLSSecret(leaf.certPEM, leaf.keyPEM))).To(Succeed())
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionTLSSecretTypeMismatch)).To(BeNil())
+		})
+
+		It("should not check secrets read with custom data keys", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "example-tls",
+					Namespace:   "default",
+					Annotations: map[string]string{certmanagerv1.CertificateNameKey: "example-cert"},
+				},
+				Type: corev1.SecretTypeOpaque,
+				Data: map[string][]byte{"cert.pem": leaf.certPEM, "key.pem": leaf.keyPEM},
+			}
+			cert := newCertificate()
+			cert.Spec.CertDataKey = "cert.pem"
+			cert.Spec.KeyDataKey = "key.pem"
+			manager := NewCertificateManager(newFakeClient(cert, secret), testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.NotAfter).NotTo(BeNil())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionTLSSecretTypeMismatch)).To(BeNil())
+		})
+	})
+
+	Context("When the TLS secret uses custom data keys", func() {
+		var (
+			cfProvider *fakeProvider
+			leaf       *testCertificate
+		)
+
+		BeforeEach(func() {
+			leaf = generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			cfProvider = newFakeProvider("cloudflare", "cf-id")
+		})
+
+		newOpaqueSecret := func(data map[string][]byte) *corev1.Secret {
+			secret := newTLSSecret(nil, nil)
+			secret.Type = corev1.SecretTypeOpaque
+			secret.Data = data
+			return secret
+		}
+
+		process := func(cert *certificatev1alpha1.Certificate, secret *corev1.Secret) (time.Duration, error) {
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			manager := NewCertificateManager(newFakeClient(cert, secret), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			return result.RequeueAfter, err
+		}
+
+		It("should read the certificate and key from the configured keys", func() {
+			cert := newCertificate()
+			cert.Spec.CertDataKey = "cert.pem"
+			cert.Spec.KeyDataKey = "key.pem"
+
+			_, err := process(cert, newOpaqueSecret(map[string][]byte{"cert.pem": leaf.certPEM, "key.pem": leaf.keyPEM}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.lastUpload().Certificate).To(Equal(leaf.certPEM))
+			Expect(cfProvider.lastUpload().PrivateKey).To(Equal(leaf.keyPEM))
+		})
+
+		It("should wait for a secret without the configured keys", func() {
+			cert := newCertificate()
+			cert.Spec.CertDataKey = "cert.pem"
+			cert.Spec.KeyDataKey = "key.pem"
+
+			requeueAfter, err := process(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requeueAfter).To(Equal(emptySecretRequeueInterval))
+			Expect(cfProvider.uploads).To(BeEmpty())
+		})
+
+		DescribeTable("should reject invalid data keys",
+			func(certKey, keyKey, message string) {
+				cert := newCertificate()
+				cert.Spec.CertDataKey = certKey
+				cert.Spec.KeyDataKey = keyKey
+
+				_, err := process(cert, newOpaqueSecret(map[string][]byte{"cert.pem": leaf.certPEM, "key.pem": leaf.keyPEM}))
+				Expect(err).To(MatchError(ContainSubstring(message)))
+				Expect(cfProvider.uploads).To(BeEmpty())
+			},
+			Entry("same key", "cert.pem", "cert.pem", "must differ"),
+			Entry("key matching the default of the other", "tls.key", "", "must differ"),
+			Entry("invalid certificate key", "cert/pem", "key.pem", "invalid certDataKey"),
+			Entry("invalid private key key", "cert.pem", "key pem", "invalid keyDataKey"),
+		)
+	})
+
+	Context("When resolving the credentials namespace", func() {
+		var (
+			cfConfig  cloudflaredriver.Config
+			awsConfig awsdriver.Config
+		)
+
+		process := func(cert *certificatev1alpha1.Certificate, opts ...ManagerOption) {
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme, opts...)
+			manager.newCloudflareDriver = func(cfg cloudflaredriver.Config) types.CloudProvider {
+				cfConfig = cfg
+				return newFakeProvider("cloudflare", "cf-id")
+			}
+			manager.newAWSDriver = func(cfg awsdriver.Config) types.CloudProvider {
+				awsConfig = cfg
+				return newFakeProvider("aws", "arn")
+			}
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		newProviderCertificate := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{CredentialType: "access-key", SecretRef: "aws-credentials"}
+			return cert
+		}
+
+		It("should use the Certificate namespace by default", func() {
+			process(newProviderCertificate())
+
+			Expect(cfConfig.Namespace).To(Equal("default"))
+			Expect(awsConfig.Namespace).To(Equal("default"))
+		})
+
+		It("should use the operator credentials namespace when configured", func() {
+			process(newProviderCertificate(), WithCredentialsNamespace("credentials"))
+
+			Expect(cfConfig.Namespace).To(Equal("credentials"))
+			Expect(awsConfig.Namespace).To(Equal("credentials"))
+		})
+
+		It("should prefer the Certificate namespace when set in the spec", func() {
+			cert := newProviderCertificate()
+			cert.Spec.CredentialsNamespace = "default"
+			process(cert, WithCredentialsNamespace("credentials"))
+
+			Expect(cfConfig.Namespace).To(Equal("default"))
+			Expect(awsConfig.Namespace).To(Equal("default"))
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionProvidersConfigured).Message).
+				NotTo(ContainSubstring("credentialsNamespace"))
+		})
+
+		It("should ignore a spec namespace of another tenant", func() {
+			cert := newProviderCertificate()
+			cert.Spec.CredentialsNamespace = "other-team"
+			process(cert, WithCredentialsNamespace("credentials"))
+
+			Expect(cfConfig.Namespace).To(Equal("credentials"))
+			Expect(awsConfig.Namespace).To(Equal("credentials"))
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionProvidersConfigured)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring(`credentialsNamespace "other-team"`))
+			Expect(ReferencedSecrets(cert, "credentials")).To(ContainElement(
+				k8stypes.NamespacedName{Namespace: "credentials", Name: "cloudflare-credentials"}))
+		})
+
+		It("should ignore a spec namespace of another tenant without an operator namespace", func() {
+			cert := newProviderCertificate()
+			cert.Spec.CredentialsNamespace = "other-team"
+			process(cert)
+
+			Expect(cfConfig.Namespace).To(Equal("default"))
+			Expect(ValidateCredentialsNamespace(cert, "")).To(MatchError(ContainSubstring(`must be the Certificate's namespace "default"`)))
+		})
+
+		It("should list the referenced Secrets in the resolved namespaces", func() {
+			cert := newProviderCertificate()
+			cert.Spec.S3 = &certificatev1alpha1.S3{Bucket: "certs", SecretRef: "aws-credentials"}
+			cert.Spec.RemoteClusters = []certificatev1alpha1.RemoteCluster{{Name: "edge", KubeconfigSecretRef: "edge-kubeconfig"}}
+			cert.Spec.PKCS12PasswordSecretRef = "keystore-password"
+			cert.Spec.TrustedCASecretRef = "trusted-ca"
+
+			Expect(ReferencedSecrets(cert, "credentials")).To(ConsistOf(
+				k8stypes.NamespacedName{Namespace: "credentials", Name: "cloudflare-credentials"},
+				k8stypes.NamespacedName{Namespace: "credentials", Name: "aws-credentials"},
+				k8stypes.NamespacedName{Namespace: "credentials", Name: "edge-kubeconfig"},
+				k8stypes.NamespacedName{Namespace: "default", Name: "keystore-password"},
+				k8stypes.NamespacedName{Namespace: "default", Name: "trusted-ca"},
+			))
+		})
+	})
+
+	Context("When issuance is waiting on an ACME challenge", func() {
+		var (
+			cmCert    *certmanagerv1.Certificate
+			certReq   *certmanagerv1.CertificateRequest
+			order     *acmev1.Order
+			challenge *acmev1.Challenge
+		)
+
+		BeforeEach(func() {
+			cmCert = &certmanagerv1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "example-cert",
+					Namespace:       "default",
+					UID:             "cm-cert-uid",
+					OwnerReferences: ownedByExample(),
+				},
+			}
+			certReq = &certmanagerv1.CertificateRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "example-cert-1",
+					Namespace:       "default",
+					UID:             "cr-uid",
+					Annotations:     map[string]string{certmanagerv1.CertificateRequestRevisionAnnotationKey: "1"},
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cmCert, certmanagerv1.SchemeGroupVersion.WithKind("Certificate"))},
+				},
+			}
+			order = &acmev1.Order{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "example-cert-1-123",
+					Namespace:       "default",
+					UID:             "order-uid",
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(certReq, certmanagerv1.SchemeGroupVersion.WithKind("CertificateRequest"))},
+				},
+				Status: acmev1.OrderStatus{State: acmev1.Pending},
+			}
+			challenge = &acmev1.Challenge{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "example-cert-1-123-456",
+					Namespace:       "default",
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(order, acmev1.SchemeGroupVersion.WithKind("Order"))},
+				},
+				Spec:   acmev1.ChallengeSpec{Type: acmev1.ACMEChallengeTypeHTTP01, DNSName: "example.com"},
+				Status: acmev1.ChallengeStatus{State: acmev1.Pending},
+			}
+		})
+
+		It("should surface the pending challenge in the status", func() {
+			cert := newCertificate()
+			manager := NewCertificateManager(newFakeClient(cert, cmCert, certReq, order, challenge), testScheme)
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+			Expect(cert.Status.IssuanceDetail).To(Equal("pending http01 challenge for example.com"))
+		})
+
+		It("should include the challenge reason when the challenge is failing", func() {
+			challenge.Spec.Type = acmev1.ACMEChallengeTypeDNS01
+			challenge.Status.Reason = "Waiting for DNS-01 challenge propagation"
+			cert := newCertificate()
+			manager := NewCertificateManager(newFakeClient(cert, cmCert, certReq, order, challenge), testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.IssuanceDetail).To(Equal(
+				"pending dns01 challenge for example.com: Waiting for DNS-01 challenge propagation"))
+		})
+
+		It("should report the order state once all challenges are valid", func() {
+			challenge.Status.State = acmev1.Valid
+			order.Status.State = acmev1.Processing
+			cert := newCertificate()
+			manager := NewCertificateManager(newFakeClient(cert, cmCert, certReq, order, challenge), testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.IssuanceDetail).To(Equal("order example-cert-1-123 is processing"))
+		})
+
+		It("should clear the detail once the TLS secret is issued", func() {
+			cert := newCertificate()
+			cert.Status.IssuanceDetail = "pending http01 challenge for example.com"
+			manager := NewCertificateManager(newFakeClient(cert, cmCert, newTLSSecret([]byte("cert"), []byte("key"))), testScheme)
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.IssuanceDetail).To(BeEmpty())
+		})
+	})
+	Context("When importing into multiple AWS accounts", func() {
+		const (
+			roleA = "arn:aws:iam::111111111111:role/certificate-importer"
+			roleB = "arn:aws:iam::222222222222:role/certificate-importer"
+		)
+
+		var (
+			providers map[string]*fakeProvider
+			manager   *CertificateManager
+		)
+
+		newMultiAccountCertificate := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.AWS = &certificatev1alpha1.AWS{CredentialType: "assume-role"}
+			cert.Spec.AWSAssumeRoleARNs = []string{roleA, roleB}
+			return cert
+		}
+
+		BeforeEach(func() {
+			providers = map[string]*fakeProvider{
+				"":    newFakeProvider("aws", "arn:aws:acm:us-east-1:000000000000:certificate/base"),
+				roleA: newFakeProvider("aws", "arn:aws:acm:us-east-1:111111111111:certificate/a"),
+				roleB: newFakeProvider("aws", "arn:aws:acm:us-east-1:222222222222:certificate/b"),
+			}
+		})
+
+		newManager := func(objs ...client.Object) *CertificateManager {
+			m := NewCertificateManager(newFakeClient(objs...), testScheme)
+			m.newAWSDriver = func(cfg awsdriver.Config) types.CloudProvider {
+				return providers[cfg.AssumeRoleARN]
+			}
+			return m
+		}
+
+		It("should import into every account and track the ARN per account", func() {
+			cert := newMultiAccountCertificate()
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			manager = newManager(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(providers[""].uploadCount()).To(Equal(1))
+			Expect(providers[roleA].uploadCount()).To(Equal(1))
+			Expect(providers[roleB].uploadCount()).To(Equal(1))
+			Expect(cert.Status.AWSCertificateARN).To(Equal("arn:aws:acm:us-east-1:000000000000:certificate/base"))
+			Expect(cert.Status.AWSAccountCertificateARNs).To(Equal(map[string]string{
+				"111111111111": "arn:aws:acm:us-east-1:111111111111:certificate/a",
+				"222222222222": "arn:aws:acm:us-east-1:222222222222:certificate/b",
+			}))
+		})
+
+		It("should re-import into the existing ARN of each account", func() {
+			cert := newMultiAccountCertificate()
+			cert.Status.AWSAccountCertificateARNs = map[string]string{"111111111111": "existing-a"}
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			manager = newManager(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(providers[roleA].lastUpload().ExistingID).To(Equal("existing-a"))
+			Expect(providers[roleB].lastUpload().ExistingID).To(BeEmpty())
+		})
+
+		It("should keep importing into other accounts when one account fails", func() {
+			providers[roleA].uploadErr = errors.New("access denied")
+			cert := newMultiAccountCertificate()
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			manager = newManager(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cert.Status.AWSAccountCertificateARNs).NotTo(HaveKey("111111111111"))
+			Expect(cert.Status.AWSAccountCertificateARNs).To(HaveKey("222222222222"))
+		})
+
+		It("should record a failed account and retry only it until the import succeeds", func() {
+			providers[roleA].uploadErr = errors.New("AccessDenied: not authorized to perform sts:AssumeRole")
+			cert := newMultiAccountCertificate()
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			manager = newManager(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(awsAccountRetryInterval))
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(leaf.certPEM)))
+			failed := cert.Status.Providers["aws:111111111111"]
+			Expect(failed.Uploaded).To(BeFalse())
+			Expect(failed.LastError).To(ContainSubstring("sts:AssumeRole"))
+			Expect(cert.Status.Providers["aws:222222222222"].Uploaded).To(BeTrue())
+			Expect(cert.Status.Providers["aws:222222222222"].Identifier).To(Equal("arn:aws:acm:us-east-1:222222222222:certificate/b"))
+
+			By("retrying the failed account while it keeps failing")
+			result, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(awsAccountRetryInterval))
+			Expect(providers[roleA].uploadCount()).To(Equal(2))
+
+			By("recording the account once it recovers")
+			providers[roleA].uploadErr = nil
+			result, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(providers[roleA].uploadCount()).To(Equal(3))
+			Expect(providers[roleA].lastUpload().Certificate).To(ContainSubstring(string(leaf.certPEM)))
+			recovered := cert.Status.Providers["aws:111111111111"]
+			Expect(recovered.Uploaded).To(BeTrue())
+			Expect(recovered.LastError).To(BeEmpty())
+			Expect(cert.Status.AWSAccountCertificateARNs).To(HaveKeyWithValue("111111111111", "arn:aws:acm:us-east-1:111111111111:certificate/a"))
+
+			By("importing into neither the base account nor the other account again")
+			Expect(providers[""].uploadCount()).To(Equal(1))
+			Expect(providers[roleB].uploadCount()).To(Equal(1))
+
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers[roleA].uploadCount()).To(Equal(3))
+		})
+
+		It("should delete the certificate from every account on finalize", func() {
+			cert := newMultiAccountCertificate()
+			cert.Status.AWSAccountCertificateARNs = map[string]string{
+				"111111111111": "arn-a",
+				"222222222222": "arn-b",
+			}
+			cert.Status.Providers = map[string]certificatev1alpha1.ProviderStatus{
+				"aws:111111111111": {Uploaded: true, Identifier: "arn-a"},
+			}
+			manager = newManager(cert)
+
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(providers[roleA].deletes).To(ConsistOf("arn-a"))
+			Expect(providers[roleB].deletes).To(ConsistOf("arn-b"))
+			Expect(cert.Status.Providers).NotTo(HaveKey("aws:111111111111"))
+		})
+
+		It("should return account deletion failures so finalization is retried", func() {
+			providers[roleB].deleteErr = errors.New("throttled")
+			cert := newMultiAccountCertificate()
+			cert.Status.AWSAccountCertificateARNs = map[string]string{"222222222222": "arn-b"}
+			manager = newManager(cert)
+
+			Expect(manager.Finalize(ctx, cert)).To(MatchError(ContainSubstring("throttled")))
+		})
+
+		It("should only retry the accounts whose deletion failed", func() {
+			providers[roleB].deleteErr = errors.New("throttled")
+			cert := newMultiAccountCertificate()
+			cert.Status.AWSAccountCertificateARNs = map[string]string{
+				"111111111111": "arn-a",
+				"222222222222": "arn-b",
+			}
+			manager = newManager(cert)
+
+			Expect(manager.Finalize(ctx, cert)).To(MatchError(ContainSubstring("throttled")))
+			Expect(cert.Status.AWSAccountCertificateARNs).To(Equal(map[string]string{"222222222222": "arn-b"}))
+
+			providers[roleB].deleteErr = nil
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(providers[roleA].deletes).To(ConsistOf("arn-a"))
+			Expect(providers[roleB].deletes).To(ConsistOf("arn-b", "arn-b"))
+			Expect(cert.Status.AWSAccountCertificateARNs).To(BeEmpty())
+		})
+	})
+	Context("When the certificate is not valid yet", func() {
+		It("should defer the upload and requeue until NotBefore", func() {
+			notBefore := time.Now().Add(10 * time.Minute)
+			leaf := generateTestCertificate("example.com", testCertOptions{
+				dnsNames:  []string{"example.com"},
+				notBefore: notBefore,
+				notAfter:  notBefore.Add(90 * 24 * time.Hour),
+			})
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 10*time.Minute, time.Minute))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+		})
+
+		It("should upload as soon as the clock reaches NotBefore", func() {
+			notBefore := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+			leaf := generateTestCertificate("example.com", testCertOptions{
+				dnsNames:  []string{"example.com"},
+				notBefore: notBefore,
+				notAfter:  notBefore.Add(90 * 24 * time.Hour),
+			})
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			clock := clocktesting.NewFakePassiveClock(notBefore.Add(-10 * time.Minute))
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme,
+				WithClock(clock))
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+
+			clock.SetTime(notBefore)
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.LastUploadedTime.Time).To(BeTemporally("==", notBefore))
+		})
+
+		It("should cap the requeue for certificates valid far in the future", func() {
+			notBefore := time.Now().Add(48 * time.Hour)
+			leaf := generateTestCertificate("example.com", testCertOptions{
+				notBefore: notBefore,
+				notAfter:  notBefore.Add(90 * 24 * time.Hour),
+			})
+
+			cert := newCertificate()
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(maxNotYetValidRequeue))
+		})
+
+		It("should not block certificates that are already valid or unparseable", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+			Expect(timeUntilValid(leaf.certPEM, time.Now())).To(BeZero())
+			Expect(timeUntilValid([]byte("not a certificate"), time.Now())).To(BeZero())
+		})
+	})
+	Context("When recording the certificate expiry", func() {
+		It("should store the leaf NotAfter in status and update it on renewal", func() {
+			notAfter := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+			leaf := generateTestCertificate("example.com", testCertOptions{notAfter: notAfter})
+
+			cert := newCertificate()
+			k8sClient := newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.NotAfter).NotTo(BeNil())
+			Expect(cert.Status.NotAfter.Time).To(BeTemporally("==", notAfter))
+
+			renewedNotAfter := notAfter.Add(60 * 24 * time.Hour)
+			renewed := generateTestCertificate("example.com", testCertOptions{notAfter: renewedNotAfter})
+			Expect(k8sClient.Update(ctx, newTLSSecret(renewed.certPEM, renewed.keyPEM))).To(Succeed())
+
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.NotAfter.Time).To(BeTemporally("==", renewedNotAfter))
+		})
+	})
+	Context("When many certificates are renewed at once", func() {
+		const window = time.Hour
+
+		// renew processes a Certificate whose previous certificate was uploaded and whose
+		// renewal, issued a minute ago, is in the TLS secret
+		renew := func(name string, renewed *testCertificate) (time.Duration, int) {
+			cert := newCertificate()
+			cert.Name = name
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Status.LastUploadedCertHash = calculateCertHash([]byte("previous-cert"))
+			cert.Status.CloudflareUploaded = true
+			cert.Status.CloudflareCertificateID = "cf-id"
+
+			secret := newTLSSecret(renewed.certPEM, renewed.keyPEM)
+			secret.Name = name + "-tls"
+			secret.Annotations[certmanagerv1.CertificateNameKey] = name + "-cert"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, secret), testScheme, WithRenewalUploadWindow(window))
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			return result.RequeueAfter, cfProvider.uploadCount()
+		}
+
+		It("should stagger the re-uploads over the window", func() {
+			notBefore := time.Now().Add(-time.Minute)
+			notAfter := notBefore.Add(90 * 24 * time.Hour)
+
+			delays := map[time.Duration]bool{}
+			immediate := 0
+			for i := range 30 {
+				renewed := generateTestCertificate("example.com", testCertOptions{notBefore: notBefore, notAfter: notAfter})
+				delay, uploads := renew(fmt.Sprintf("burst-%d", i), renewed)
+				if delay == 0 {
+					Expect(uploads).To(Equal(1))
+					immediate++
+					continue
+				}
+				Expect(uploads).To(BeZero())
+				Expect(delay).To(BeNumerically("<", window))
+				delays[delay] = true
+			}
+
+			Expect(immediate).To(BeNumerically("<", 5))
+			Expect(delays).To(HaveLen(30 - immediate))
+			sorted := slices.Sorted(maps.Keys(delays))
+			Expect(sorted[len(sorted)-1] - sorted[0]).To(BeNumerically(">", window/2))
+		})
+
+		It("should keep the upload time of a certificate stable across reconciles", func() {
+			renewed := generateTestCertificate("example.com", testCertOptions{notBefore: time.Now().Add(-time.Minute)})
+			cert := newCertificate()
+			now := time.Now()
+
+			first := renewalUploadDelay(cert, renewed.certPEM, window, now)
+			Expect(renewalUploadDelay(cert, renewed.certPEM, window, now.Add(10*time.Minute))).
+				To(Equal(max(first-10*time.Minute, 0)))
+			Expect(renewalUploadDelay(cert, renewed.certPEM, 0, now)).To(BeZero())
+		})
+
+		It("should upload a renewal once its offset has passed", func() {
+			renewed := generateTestCertificate("example.com", testCertOptions{notBefore: time.Now().Add(-2 * window)})
+			delay, uploads := renew("late", renewed)
+			Expect(delay).To(BeZero())
+			Expect(uploads).To(Equal(1))
+		})
+
+		It("should not delay initial uploads", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{notBefore: time.Now().Add(-time.Minute)})
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme,
+				WithRenewalUploadWindow(window))
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+		})
+	})
+	Context("When upload verification is enabled", func() {
+		var (
+			cfProvider  *fakeProvider
+			awsProvider *fakeProvider
+			cert        *certificatev1alpha1.Certificate
+		)
+
+		BeforeEach(func() {
+			cert = newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			cfProvider = newFakeProvider("cloudflare", "cf-id")
+			awsProvider = newFakeProvider("aws", "arn:aws:acm:us-east-1:123456789012:certificate/example")
+		})
+
+		process := func(opts ...ManagerOption) {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme, opts...)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should verify every upload and report the match", func() {
+			process(WithUploadVerification(true))
+
+			Expect(cfProvider.verifies).To(ConsistOf("cf-id"))
+			Expect(awsProvider.verifies).To(ConsistOf(awsProvider.identifier))
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+			Expect(cert.Status.AWSUploaded).To(BeTrue())
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionVerificationFailed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonVerified))
+		})
+
+		It("should report a provider copy that differs from the upload", func() {
+			awsProvider.verifyErr = fmt.Errorf("%w: chain missing", types.ErrVerificationMismatch)
+			process(WithUploadVerification(true))
+
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+			Expect(cert.Status.AWSUploaded).To(BeFalse())
+			Expect(cert.Status.AWSCertificateARN).To(Equal(awsProvider.identifier))
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionVerificationFailed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonMismatch))
+			Expect(condition.Message).To(ContainSubstring("aws: "))
+			Expect(condition.Message).To(ContainSubstring("chain missing"))
+		})
+
+		It("should treat a failed verification as unverified, not as a mismatch", func() {
+			cfProvider.verifyErr = errors.New("throttled")
+			awsProvider.verifyErr = errors.New("throttled")
+			process(WithUploadVerification(true))
+
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+			Expect(cert.Status.AWSUploaded).To(BeTrue())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionVerificationFailed)).To(BeNil())
+		})
+
+		It("should not verify uploads by default", func() {
+			process()
+
+			Expect(cfProvider.verifies).To(BeEmpty())
+			Expect(awsProvider.verifies).To(BeEmpty())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionVerificationFailed)).To(BeNil())
+		})
+	})
+	Context("When verifying the certificate SANs", func() {
+		It("should upload when the SANs include the domain", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{
+				dnsNames: []string{"www.example.com", "EXAMPLE.com"},
+			})
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.LastUploadedCertHash).NotTo(BeEmpty())
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionSANMismatch)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonSANsMatch))
+		})
+
+		It("should skip the upload and report a mismatch when the SANs don't include the domain", func() {
+			leaf := generateTestCertificate("other.example.org", testCertOptions{
+				dnsNames: []string{"other.example.org", "*.example.com"},
+			})
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			k8sClient := newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+			manager := NewCertificateManager(k8sClient, testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.CloudflareUploaded).To(BeFalse())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionSANMismatch)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonDomainNotInSANs))
+			Expect(condition.Message).To(ContainSubstring("other.example.org"))
+
+			By("uploading once a matching certificate is issued")
+			fixed := generateTestCertificate("example.com", testCertOptions{})
+			Expect(k8sClient.Update(ctx, newTLSSecret(fixed.certPEM, fixed.keyPEM))).To(Succeed())
+
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(meta.IsStatusConditionFalse(cert.Status.Conditions, certificatev1alpha1.ConditionSANMismatch)).To(BeTrue())
+		})
+
+		It("should compare DNS names literally and IP addresses by value", func() {
+			Expect(sansInclude([]string{"Example.COM."}, "example.com")).To(BeTrue())
+			Expect(sansInclude([]string{"*.example.com"}, "*.example.com")).To(BeTrue())
+			Expect(sansInclude([]string{"*.example.com"}, "www.example.com")).To(BeFalse())
+			Expect(sansInclude([]string{"10.0.0.1"}, "10.0.0.1")).To(BeTrue())
+			Expect(sansInclude([]string{"10.0.0.2"}, "10.0.0.1")).To(BeFalse())
+			Expect(sansInclude(nil, "example.com")).To(BeFalse())
+		})
+	})
+
+	Context("When a trusted CA is configured", func() {
+		newTrustedCASecret := func(caPEM []byte) *corev1.Secret {
+			return &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "trusted-ca", Namespace: "default"},
+				Data:       map[string][]byte{"ca.crt": caPEM},
+			}
+		}
+
+		// newTrustedChain returns a root CA and a leaf for example.com issued through an intermediate
+		newTrustedChain := func() (root *testCertificate, chainPEM, keyPEM []byte) {
+			root = generateTestCertificate("Test Root CA", testCertOptions{isCA: true})
+			intermediate := generateTestCertificate("Test Intermediate CA", testCertOptions{isCA: true, parent: root})
+			leaf := generateTestCertificate("example.com", testCertOptions{parent: intermediate})
+			return root, append(append([]byte{}, leaf.certPEM...), intermediate.certPEM...), leaf.keyPEM
+		}
+
+		It("should upload when the chain verifies against the trusted CA", func() {
+			root, chainPEM, keyPEM := newTrustedChain()
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.TrustedCASecretRef = "trusted-ca"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(
+				newFakeClient(cert, newTLSSecret(chainPEM, keyPEM), newTrustedCASecret(root.certPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionChainVerificationFailed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonChainTrusted))
+		})
+
+		It("should skip the upload when the chain doesn't verify against the trusted CA", func() {
+			_, chainPEM, keyPEM := newTrustedChain()
+			otherRoot := generateTestCertificate("Other Root CA", testCertOptions{isCA: true})
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.TrustedCASecretRef = "trusted-ca"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(
+				newFakeClient(cert, newTLSSecret(chainPEM, keyPEM), newTrustedCASecret(otherRoot.certPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionChainVerificationFailed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonUntrustedChain))
+			Expect(condition.Message).To(ContainSubstring("trusted-ca"))
+
+			By("uploading and removing the condition once the trusted CA is unset")
+			cert.Spec.TrustedCASecretRef = ""
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionChainVerificationFailed)).To(BeNil())
+		})
+
+		It("should skip the upload when the trusted CA secret is missing", func() {
+			_, chainPEM, keyPEM := newTrustedChain()
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.TrustedCASecretRef = "trusted-ca"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(chainPEM, keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(meta.IsStatusConditionTrue(cert.Status.Conditions, certificatev1alpha1.ConditionChainVerificationFailed)).To(BeTrue())
+		})
+	})
+	Context("When uploads require approval", func() {
+		newApprovalManager := func(cert *certificatev1alpha1.Certificate) (*CertificateManager, *fakeProvider) {
+			leaf := generateTestCertificate(cert.Spec.Domain, testCertOptions{})
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			return manager, cfProvider
+		}
+
+		newGatedCertificate := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.RequireApproval = true
+			return cert
+		}
+
+		It("should withhold the upload until the approved annotation is set", func() {
+			cert := newGatedCertificate()
+			manager, cfProvider := newApprovalManager(cert)
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.CertificateRef).NotTo(BeEmpty())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionPendingApproval)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonAwaitingApproval))
+			Expect(condition.Message).To(ContainSubstring(certificatev1alpha1.ApprovedAnnotation))
+
+			By("ignoring an annotation that isn't true")
+			cert.Annotations = map[string]string{certificatev1alpha1.ApprovedAnnotation: "yes"}
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+
+			By("uploading once approved")
+			cert.Annotations[certificatev1alpha1.ApprovedAnnotation] = "true"
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.LastUploadedCertHash).NotTo(BeEmpty())
+			condition = meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionPendingApproval)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonApproved))
+		})
+
+		It("should upload an approved Certificate right away", func() {
+			cert := newGatedCertificate()
+			cert.Annotations = map[string]string{certificatev1alpha1.ApprovedAnnotation: "true"}
+			manager, cfProvider := newApprovalManager(cert)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(meta.IsStatusConditionFalse(cert.Status.Conditions, certificatev1alpha1.ConditionPendingApproval)).To(BeTrue())
+		})
+
+		It("should remove the condition once approval is no longer required", func() {
+			cert := newGatedCertificate()
+			manager, cfProvider := newApprovalManager(cert)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(cert.Status.Conditions, certificatev1alpha1.ConditionPendingApproval)).To(BeTrue())
+
+			cert.Spec.RequireApproval = false
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionPendingApproval)).To(BeNil())
+		})
+	})
+
+	Context("When a Certificate disables Cloudflare", func() {
+		var (
+			cert       *certificatev1alpha1.Certificate
+			cfProvider *fakeProvider
+		)
+
+		BeforeEach(func() {
+			cert = newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.CloudflareEnabled = ptr.To(false)
+			cert.Status.CloudflareCertificateID = "cf-old"
+			cert.Status.CloudflareUploaded = true
+			cfProvider = newFakeProvider("cloudflare", "cf-id")
+		})
+
+		newManager := func(opts ...ManagerOption) *CertificateManager {
+			leaf := generateTestCertificate(cert.Spec.Domain, testCertOptions{})
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme, opts...)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			return manager
+		}
+
+		It("should delete the Cloudflare certificate and clear its ID", func() {
+			result, statusUpdated, err := newManager(WithDisabledUploadCleanup(true)).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.deletes).To(Equal([]string{"cf-old"}))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.CloudflareCertificateID).To(BeEmpty())
+			Expect(cert.Status.CloudflareUploaded).To(BeFalse())
+		})
+
+		It("should upload again once Cloudflare is enabled again", func() {
+			manager := newManager(WithDisabledUploadCleanup(true))
+
+			By("starting from an upload made while Cloudflare was enabled")
+			cert.Spec.CloudflareEnabled = ptr.To(true)
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			cfProvider.uploads = nil
+
+			By("disabling Cloudflare")
+			cert.Spec.CloudflareEnabled = ptr.To(false)
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.deletes).To(Equal([]string{"cf-id"}))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+
+			By("enabling Cloudflare again")
+			cert.Spec.CloudflareEnabled = ptr.To(true)
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cfProvider.lastUpload().ExistingID).To(BeEmpty())
+			Expect(cert.Status.CloudflareCertificateID).To(Equal("cf-id"))
+		})
+
+		It("should keep the Cloudflare certificate unless the cleanup is enabled", func() {
+			_, _, err := newManager().ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.deletes).To(BeEmpty())
+			Expect(cert.Status.CloudflareCertificateID).To(Equal("cf-old"))
+		})
+
+		It("should keep the Cloudflare certificate while the operator disables Cloudflare", func() {
+			_, _, err := newManager(WithDisabledUploadCleanup(true), WithDisabledProviders([]string{"cloudflare"})).
+				ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.deletes).To(BeEmpty())
+			Expect(cert.Status.CloudflareCertificateID).To(Equal("cf-old"))
+		})
+
+		It("should keep the ID and retry when the deletion fails", func() {
+			cfProvider.deleteErr = errors.New("zone not found")
+
+			result, _, err := newManager(WithDisabledUploadCleanup(true)).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(disabledCleanupRetryInterval))
+			Expect(cert.Status.CloudflareCertificateID).To(Equal("cf-old"))
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+		})
+	})
+
+	Context("When a DNS check is configured", func() {
+		newDNSCheckedManager := func(cert *certificatev1alpha1.Certificate, resolver *fakeDNSResolver) (*CertificateManager, *fakeProvider) {
+			leaf := generateTestCertificate(cert.Spec.Domain, testCertOptions{})
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			manager.dnsResolver = resolver
+			return manager, cfProvider
+		}
+
+		It("should defer the upload until the domain resolves to the expected addresses", func() {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.DNSCheck = &certificatev1alpha1.DNSCheck{ExpectedAddresses: []string{"203.0.113.10", "2001:db8::1"}}
+			resolver := &fakeDNSResolver{}
+			manager, cfProvider := newDNSCheckedManager(cert, resolver)
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(dnsCheckRequeueInterval))
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionDNSNotReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonDNSMismatch))
+			Expect(condition.Message).To(ContainSubstring("failed to resolve example.com"))
+
+			By("waiting while the domain resolves to other addresses")
+			resolver.set("example.com", []string{"198.51.100.7", "2001:db8:0:0::1"}, "")
+			result, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(dnsCheckRequeueInterval))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			condition = meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionDNSNotReady)
+			Expect(condition.Message).To(ContainSubstring("missing 203.0.113.10"))
+			Expect(condition.Message).NotTo(ContainSubstring("missing 203.0.113.10, 2001:db8::1"))
+
+			By("uploading once the domain resolves to every expected address")
+			resolver.set("example.com", []string{"203.0.113.10", "198.51.100.7", "2001:db8::1"}, "")
+			result, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			condition = meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionDNSNotReady)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonDNSResolved))
+		})
+
+		It("should compare the canonical name case-insensitively", func() {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.DNSCheck = &certificatev1alpha1.DNSCheck{ExpectedCNAME: "LB.example.net"}
+			resolver := &fakeDNSResolver{}
+			resolver.set("example.com", []string{"203.0.113.10"}, "old-lb.example.net.")
+			manager, cfProvider := newDNSCheckedManager(cert, resolver)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionDNSNotReady)
+			Expect(condition.Message).To(ContainSubstring("resolves through old-lb.example.net, expected LB.example.net"))
+
+			resolver.set("example.com", []string{"203.0.113.10"}, "lb.example.net.")
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(meta.IsStatusConditionFalse(cert.Status.Conditions, certificatev1alpha1.ConditionDNSNotReady)).To(BeTrue())
+		})
+
+		It("should only require the domain to resolve without expectations", func() {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.DNSCheck = &certificatev1alpha1.DNSCheck{}
+			resolver := &fakeDNSResolver{}
+			resolver.set("example.com", []string{"198.51.100.7"}, "")
+			manager, cfProvider := newDNSCheckedManager(cert, resolver)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+		})
+
+		It("should not resolve anything without a DNS check or for wildcard domains", func() {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			resolver := &fakeDNSResolver{}
+			manager, cfProvider := newDNSCheckedManager(cert, resolver)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionDNSNotReady)).To(BeNil())
+
+			wildcard := newCertificate()
+			wildcard.Spec.Domain = "*.example.com"
+			wildcard.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			wildcard.Spec.DNSCheck = &certificatev1alpha1.DNSCheck{}
+			manager, cfProvider = newDNSCheckedManager(wildcard, resolver)
+
+			_, _, err = manager.ProcessCertificate(ctx, wildcard)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(resolver.lookupCount()).To(BeZero())
+		})
+	})
+
+	Context("When an upload blackout window is configured", func() {
+		newBlackoutManager := func(cert *certificatev1alpha1.Certificate, windows ...string) (*CertificateManager, *fakeProvider) {
+			schedule, err := blackout.NewSchedule(windows, "")
+			Expect(err).NotTo(HaveOccurred())
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme,
+				WithUploadBlackout(schedule))
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			return manager, cfProvider
+		}
+
+		It("should defer uploads until the window closes while issuance proceeds", func() {
+			// Covers the rest of today in UTC, even when the day changes during the test
+			now := time.Now().UTC()
+			days := now.AddDate(0, 0, -1).Weekday().String()[:3] + "-" + now.Weekday().String()[:3]
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			manager, cfProvider := newBlackoutManager(cert, days+" 00:00-24:00")
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 24*time.Hour))
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.CloudflareUploaded).To(BeFalse())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+
+			By("tracking the issued certificate in the meantime")
+			Expect(cert.Status.CertificateRef).To(Equal("example-cert"))
+			Expect(cert.Status.NotAfter).NotTo(BeNil())
+		})
+
+		It("should upload outside the windows", func() {
+			// A window on neither today nor yesterday can't be active
+			inThreeDays := time.Now().UTC().AddDate(0, 0, 3).Weekday().String()[:3]
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			manager, cfProvider := newBlackoutManager(cert, inThreeDays+" 00:00-01:00")
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.LastUploadedCertHash).NotTo(BeEmpty())
+		})
+	})
+
+	Context("When uploads are paused", func() {
+		It("should keep issuance and expiry tracking going without uploading", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.UploadsPaused = ptr.To(true)
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			k8sClient := newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+			manager := NewCertificateManager(k8sClient, testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, &certmanagerv1.Certificate{})).To(Succeed())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.NotAfter.Time).To(BeTemporally("==", leaf.cert.NotAfter))
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+
+			By("tracking a renewal issued while paused")
+			renewed := generateTestCertificate("example.com", testCertOptions{notAfter: time.Now().Add(120 * 24 * time.Hour)})
+			Expect(k8sClient.Update(ctx, newTLSSecret(renewed.certPEM, renewed.keyPEM))).To(Succeed())
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.NotAfter.Time).To(BeTemporally("==", renewed.cert.NotAfter))
+
+			By("uploading the latest certificate once unpaused")
+			cert.Spec.UploadsPaused = ptr.To(false)
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cfProvider.lastUpload().Certificate).To(ContainSubstring(string(renewed.certPEM)))
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(renewed.certPEM)))
+		})
+	})
+	Context("When processing as a dry run", func() {
+		It("should report the uploads without writing to the API server or providers", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.CloudflareZoneID = "zone"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{CredentialType: "access-key", SecretRef: "aws-credentials"}
+			cert.Status.AWSCertificateARN = "arn:aws:acm:us-east-1:123456789012:certificate/existing"
+			cert.Annotations = map[string]string{postUploadWebhookAnnotation: "http://127.0.0.1:1/unreachable"}
+			k8sClient := newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+			// No credential Secrets exist, the real drivers would fail to upload
+			manager := NewCertificateManager(k8sClient, testScheme, WithDryRun())
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+			Expect(cert.Status.CloudflareCertificateID).To(Equal(dryRunIdentifier))
+			Expect(cert.Status.AWSUploaded).To(BeTrue())
+			Expect(cert.Status.AWSCertificateARN).To(Equal("arn:aws:acm:us-east-1:123456789012:certificate/existing"))
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(leaf.certPEM)))
+
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, &certmanagerv1.Certificate{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should wait for issuance of a cert-manager Certificate it didn't create", func() {
+			cert := newCertificate()
+			manager := NewCertificateManager(newFakeClient(cert), testScheme, WithDryRun())
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(cert.Status.IssuanceStartedAt).NotTo(BeNil())
+		})
+	})
+	Context("When finalizing a deleted Certificate", func() {
+		It("should record which provider certificates were deleted and which failed", func() {
+			const arn = "arn:aws:acm:us-east-1:123456789012:certificate/example"
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{CredentialType: "access-key", SecretRef: "aws-credentials"}
+			cert.Status.CloudflareCertificateID = "cf-id"
+			cert.Status.AWSCertificateARN = arn
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			cfProvider.deleteErr = errors.New("zone not found")
+			awsProvider := newFakeProvider("aws", arn)
+			manager := NewCertificateManager(newFakeClient(cert), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+
+			Expect(manager.Finalize(ctx, cert)).To(MatchError(ContainSubstring("zone not found")))
+			Expect(awsProvider.deletes).To(ConsistOf(arn))
+
+			Expect(cert.Status.Finalization).NotTo(BeNil())
+			Expect(cert.Status.Finalization.Deleted).To(ConsistOf("aws/" + arn))
+			Expect(cert.Status.Finalization.Failed).To(ConsistOf(certificatev1alpha1.CleanupFailure{
+				Resource: "cloudflare/cf-id",
+				Error:    "zone not found",
+			}))
+
+			By("clearing the deleted certificate's identifier")
+			Expect(cert.Status.AWSCertificateARN).To(BeEmpty())
+			Expect(cert.Status.Providers).NotTo(HaveKey("aws"))
+			Expect(cert.Status.CloudflareCertificateID).To(Equal("cf-id"))
+
+			By("only retrying the failed deletion")
+			Expect(manager.Finalize(ctx, cert)).To(MatchError(ContainSubstring("zone not found")))
+			Expect(awsProvider.deletes).To(ConsistOf(arn))
+			Expect(cfProvider.deletes).To(HaveLen(2))
+			Expect(cert.Status.Finalization.Deleted).To(ConsistOf("aws/" + arn))
+
+			By("clearing the failure once the retry succeeds")
+			cfProvider.deleteErr = nil
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(awsProvider.deletes).To(ConsistOf(arn))
+			Expect(cert.Status.CloudflareCertificateID).To(BeEmpty())
+			Expect(cert.Status.Finalization.Deleted).To(ConsistOf("aws/"+arn, "cloudflare/cf-id"))
+			Expect(cert.Status.Finalization.Failed).To(BeEmpty())
+		})
+
+		It("should only report a changed summary when other resources are named", func() {
+			failed := &certificatev1alpha1.FinalizationStatus{
+				Failed: []certificatev1alpha1.CleanupFailure{{Resource: "cloudflare/cf-id", Error: "request 1 failed"}},
+			}
+			retried := failed.DeepCopy()
+			retried.Failed[0].Error = "request 2 failed"
+			Expect(FinalizationChanged(failed, retried)).To(BeFalse())
+
+			deleted := &certificatev1alpha1.FinalizationStatus{Deleted: []string{"cloudflare/cf-id"}}
+			Expect(FinalizationChanged(failed, deleted)).To(BeTrue())
+			Expect(FinalizationChanged(nil, deleted)).To(BeTrue())
+			Expect(FinalizationChanged(nil, nil)).To(BeFalse())
+		})
+	})
+	Context("When uploading to Akamai CPS", func() {
+		const change = "/cps/v2/enrollments/1234/changes/5678"
+
+		var (
+			cert           *certificatev1alpha1.Certificate
+			akamaiProvider *fakeProvider
+			akamaiConfigs  []akamaidriver.Config
+			manager        *CertificateManager
+		)
+
+		BeforeEach(func() {
+			cert = newCertificate()
+			cert.Spec.AkamaiEnabled = true
+			cert.Spec.AkamaiEnrollmentID = 1234
+			cert.Spec.AkamaiSecretRef = "akamai-credentials"
+			akamaiProvider = newFakeProvider("akamai", change)
+			akamaiConfigs = nil
+
+			leaf := generateTestCertificate(cert.Spec.Domain, testCertOptions{})
+			manager = NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme,
+				WithCircuitBreaker(2, time.Hour))
+			manager.newAkamaiDriver = func(cfg akamaidriver.Config) types.CloudProvider {
+				akamaiConfigs = append(akamaiConfigs, cfg)
+				return akamaiProvider
+			}
+		})
+
+		It("should record the pending change and continue it until the certificate is uploaded", func() {
+			akamaiProvider.uploadErr = fmt.Errorf("%w: waiting for the CSR", types.ErrUploadPending)
+
+			By("starting the change")
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(akamaiPendingRequeueInterval))
+			Expect(akamaiProvider.lastUpload().ExistingID).To(BeEmpty())
+			Expect(cert.Status.Providers["akamai"].Identifier).To(Equal(change))
+			Expect(cert.Status.Providers["akamai"].Uploaded).To(BeFalse())
+			Expect(cert.Status.Providers["akamai"].LastError).To(ContainSubstring("waiting for the CSR"))
+			Expect(cert.Status.ProviderCircuitBreakers).To(BeEmpty())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+
+			By("signing the CSRs with the issuer of the cert-manager Certificate")
+			Expect(akamaiConfigs[0].EnrollmentID).To(BeEquivalentTo(1234))
+			Expect(akamaiConfigs[0].Request.Namespace).To(Equal("default"))
+			Expect(akamaiConfigs[0].Request.IssuerKind).To(Equal("ClusterIssuer"))
+			Expect(akamaiConfigs[0].Request.IssuerName).To(Equal("letsencrypt-prod"))
+			Expect(akamaiConfigs[0].Request.OwnerReferences).To(Equal(ownedByExample()))
+
+			By("continuing the recorded change while it is pending")
+			for range 3 {
+				_, _, err = manager.ProcessCertificate(ctx, cert)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(akamaiProvider.lastUpload().ExistingID).To(Equal(change))
+			}
+			Expect(cert.Status.ProviderCircuitBreakers).To(BeEmpty())
+
+			By("uploading once the change accepts the certificate")
+			akamaiProvider.uploadErr = nil
+			result, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(cert.Status.Providers["akamai"].Uploaded).To(BeTrue())
+			Expect(cert.Status.Providers["akamai"].LastError).To(BeEmpty())
+			Expect(cert.Status.Providers["akamai"].Identifier).To(BeEmpty())
+			Expect(cert.Status.LastUploadedCertHash).NotTo(BeEmpty())
+
+			By("not uploading again until the certificate changes")
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(akamaiProvider.uploadCount()).To(Equal(5))
+		})
+
+		It("should count failed uploads towards the circuit breaker", func() {
+			akamaiProvider.uploadErr = errors.New("CertificateRequest is Denied")
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.Providers["akamai"].LastError).To(Equal("CertificateRequest is Denied"))
+			Expect(cert.Status.ProviderCircuitBreakers).To(HaveLen(1))
+			Expect(result.RequeueAfter).To(Equal(akamaiPendingRequeueInterval))
+		})
+
+		It("should only cancel the change it recorded on finalize", func() {
+			By("leaving the enrollment alone without a recorded change")
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(akamaiProvider.deletes).To(BeEmpty())
+
+			By("cancelling the recorded change")
+			setProviderIdentifier(cert, akamaiProviderName, change)
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(akamaiProvider.deletes).To(Equal([]string{change}))
+			Expect(cert.Status.Finalization.Deleted).To(ConsistOf("akamai/" + change))
+			Expect(cert.Status.Providers).NotTo(HaveKey("akamai"))
+		})
+
+		It("should not cancel a change whose certificate was uploaded", func() {
+			updateProviderStatus(cert, akamaiProviderName, func(status *certificatev1alpha1.ProviderStatus) {
+				status.Identifier = change
+				status.Uploaded = true
+			})
+
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(akamaiProvider.deletes).To(BeEmpty())
+			Expect(akamaiConfigs).To(BeEmpty())
+		})
+
+		DescribeTable("should skip cancelling the change when Akamai is no longer configured",
+			func(update func(*certificatev1alpha1.CertificateSpec)) {
+				setProviderIdentifier(cert, akamaiProviderName, change)
+				update(&cert.Spec)
+
+				Expect(manager.Finalize(ctx, cert)).To(Succeed())
+				Expect(akamaiConfigs).To(BeEmpty())
+				Expect(cert.Status.Finalization.Failed).To(BeEmpty())
+			},
+			Entry("disabled", func(spec *certificatev1alpha1.CertificateSpec) { spec.AkamaiEnabled = false }),
+			Entry("without credentials", func(spec *certificatev1alpha1.CertificateSpec) { spec.AkamaiSecretRef = "" }),
+		)
+
+		It("should keep the change and retry when cancelling fails", func() {
+			setProviderIdentifier(cert, akamaiProviderName, change)
+			akamaiProvider.deleteErr = errors.New("request failed with status 500")
+
+			Expect(manager.Finalize(ctx, cert)).To(MatchError(ContainSubstring("status 500")))
+			Expect(cert.Status.Providers["akamai"].Identifier).To(Equal(change))
+			Expect(cert.Status.Finalization.Failed).To(ConsistOf(certificatev1alpha1.CleanupFailure{
+				Resource: "akamai/" + change,
+				Error:    "request failed with status 500",
+			}))
+		})
+	})
+
+	Context("When a shadow issuer is configured", func() {
+		var (
+			cfProvider *fakeProvider
+			k8sClient  client.Client
+			manager    *CertificateManager
+		)
+
+		newShadowCertificate := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.ClusterIssuerName = "letsencrypt-prod"
+			cert.Spec.ShadowClusterIssuerName = "letsencrypt-staging"
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			return cert
+		}
+
+		setup := func(objs ...client.Object) {
+			cfProvider = newFakeProvider("cloudflare", "cf-id")
+			k8sClient = newFakeClient(objs...)
+			manager = NewCertificateManager(k8sClient, testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+		}
+
+		It("should create a shadow Certificate against the shadow issuer", func() {
+			cert := newShadowCertificate()
+			setup(cert)
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.ShadowCertificateRef).To(Equal("example-shadow-cert"))
+			Expect(cert.Status.ShadowReady).To(BeFalse())
+
+			shadowCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-shadow-cert"}, shadowCert)).To(Succeed())
+			Expect(shadowCert.Spec.IssuerRef.Name).To(Equal("letsencrypt-staging"))
+			Expect(shadowCert.Spec.SecretName).To(Equal("example-shadow-tls"))
+			Expect(metav1.IsControlledBy(shadowCert, cert)).To(BeTrue())
+
+			mainCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, mainCert)).To(Succeed())
+			Expect(mainCert.Spec.IssuerRef.Name).To(Equal("letsencrypt-prod"))
+		})
+
+		It("should report shadow readiness without uploading the shadow secret", func() {
+			shadow := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			shadowSecret := newTLSSecret(shadow.certPEM, shadow.keyPEM)
+			shadowSecret.Name = "example-shadow-tls"
+			shadowSecret.Annotations[certmanagerv1.CertificateNameKey] = "example-shadow-cert"
+			shadowCert := &certmanagerv1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "example-shadow-cert",
+					Namespace:       "default",
+					OwnerReferences: ownedByExample(),
+				},
+				Status: certmanagerv1.CertificateStatus{
+					Conditions: []certmanagerv1.CertificateCondition{
+						{Type: certmanagerv1.CertificateConditionReady, Status: cmmeta.ConditionTrue},
+					},
+				},
+			}
+			cert := newShadowCertificate()
+			setup(cert, shadowCert, shadowSecret)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.ShadowReady).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+
+			By("uploading only the real certificate once it is issued")
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			Expect(k8sClient.Create(ctx, newTLSSecret(leaf.certPEM, leaf.keyPEM))).To(Succeed())
+
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cfProvider.lastUpload().Certificate).To(Equal(leaf.certPEM))
+		})
+
+		It("should delete the shadow Certificate when the shadow issuer is removed", func() {
+			cert := newShadowCertificate()
+			setup(cert)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			cert.Spec.ShadowClusterIssuerName = ""
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.ShadowCertificateRef).To(BeEmpty())
+
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-shadow-cert"}, &certmanagerv1.Certificate{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+	Context("When adopting certificates uploaded before", func() {
+		const (
+			adoptedARN = "arn:aws:acm:us-east-1:123456789012:certificate/manual"
+			adoptedID  = "manual-cf-id"
+		)
+
+		var (
+			cfProvider  *fakeProvider
+			awsProvider *fakeProvider
+			k8sClient   client.Client
+			manager     *CertificateManager
+		)
+
+		newAdoptingCertificate := func(annotations map[string]string) *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Annotations = annotations
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			return cert
+		}
+
+		setup := func(cert *certificatev1alpha1.Certificate) {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+			cfProvider = newFakeProvider("cloudflare", adoptedID)
+			awsProvider = newFakeProvider("aws", adoptedARN)
+			k8sClient = newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+			manager = NewCertificateManager(k8sClient, testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+		}
+
+		storedAnnotations := func() map[string]string {
+			stored := &certificatev1alpha1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example"}, stored)).To(Succeed())
+			return stored.Annotations
+		}
+
+		It("should record the adopted identifiers before uploading into them", func() {
+			cert := newAdoptingCertificate(map[string]string{
+				adoptAWSARNAnnotation:       adoptedARN,
+				adoptCloudflareIDAnnotation: adoptedID,
+			})
+			setup(cert)
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(cert.Status.AWSCertificateARN).To(Equal(adoptedARN))
+			Expect(cert.Status.CloudflareCertificateID).To(Equal(adoptedID))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(awsProvider.uploadCount()).To(BeZero())
+			Expect(storedAnnotations()).To(HaveKey(adoptAWSARNAnnotation))
+
+			By("removing the annotations and re-importing once status is written")
+			Expect(k8sClient.Status().Update(ctx, cert)).To(Succeed())
+
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedAnnotations()).NotTo(HaveKey(adoptAWSARNAnnotation))
+			Expect(storedAnnotations()).NotTo(HaveKey(adoptCloudflareIDAnnotation))
+			Expect(cert.Annotations).To(BeEmpty())
+			Expect(cfProvider.lastUpload().ExistingID).To(Equal(adoptedID))
+			Expect(awsProvider.lastUpload().ExistingID).To(Equal(adoptedARN))
+			Expect(cert.Status.AWSUploaded).To(BeTrue())
+			Expect(cert.Status.LastUploadedCertHash).NotTo(BeEmpty())
+		})
+
+		It("should replace identifiers from a previous upload", func() {
+			cert := newAdoptingCertificate(map[string]string{adoptAWSARNAnnotation: adoptedARN})
+			cert.Status.AWSCertificateARN = "arn:aws:acm:us-east-1:123456789012:certificate/duplicate"
+			cert.Status.AWSUploaded = true
+			cert.Status.LastUploadedCertHash = "previous"
+			setup(cert)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.AWSCertificateARN).To(Equal(adoptedARN))
+			Expect(cert.Status.AWSUploaded).To(BeFalse())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+		})
+
+		It("should just remove empty adoption annotations", func() {
+			cert := newAdoptingCertificate(map[string]string{adoptCloudflareIDAnnotation: ""})
+			setup(cert)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedAnnotations()).NotTo(HaveKey(adoptCloudflareIDAnnotation))
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cfProvider.lastUpload().ExistingID).To(BeEmpty())
+		})
+
+		It("should not adopt an ARN without spec.aws", func() {
+			cert := newAdoptingCertificate(map[string]string{adoptAWSARNAnnotation: adoptedARN})
+			cert.Spec.AWS = nil
+			setup(cert)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.AWSCertificateARN).To(BeEmpty())
+			Expect(awsProvider.uploadCount()).To(BeZero())
+			Expect(storedAnnotations()).To(HaveKey(adoptAWSARNAnnotation))
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionProvidersConfigured)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring(adoptAWSARNAnnotation))
+
+			By("finalizing without panicking on an ARN recorded before spec.aws was removed")
+			cert.Status.AWSCertificateARN = adoptedARN
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(awsProvider.deletes).To(BeEmpty())
+		})
+	})
+	Context("When a fallback issuer is configured", func() {
+		var (
+			k8sClient client.Client
+			manager   *CertificateManager
+		)
+
+		newFallbackCertificate := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.ClusterIssuerName = "letsencrypt-prod"
+			cert.Spec.FallbackClusterIssuerName = "zerossl"
+			cert.Spec.FallbackAfter = &metav1.Duration{Duration: 30 * time.Minute}
+			return cert
+		}
+
+		// failingCertificate is the cert-manager Certificate whose latest issuance failed
+		failingCertificate := func() *certmanagerv1.Certificate {
+			return &certmanagerv1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "example-cert", Namespace: "default", OwnerReferences: ownedByExample()},
+				Spec: certmanagerv1.CertificateSpec{
+					IssuerRef: cmmeta.ObjectReference{Name: "letsencrypt-prod", Kind: "ClusterIssuer"},
+				},
+				Status: certmanagerv1.CertificateStatus{
+					LastFailureTime: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+				},
+			}
+		}
+
+		issuerOf := func() string {
+			mainCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, mainCert)).To(Succeed())
+			return mainCert.Spec.IssuerRef.Name
+		}
+
+		setup := func(objs ...client.Object) {
+			k8sClient = newFakeClient(objs...)
+			manager = NewCertificateManager(k8sClient, testScheme)
+		}
+
+		It("should issue against the primary issuer while it succeeds", func() {
+			cert := newFallbackCertificate()
+			setup(cert)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issuerOf()).To(Equal("letsencrypt-prod"))
+			Expect(cert.Status.ActiveIssuer).To(Equal("ClusterIssuer/letsencrypt-prod"))
+			Expect(cert.Status.IssuanceFailingSince).To(BeNil())
+		})
+
+		It("should keep the primary issuer until failures outlast the window", func() {
+			cert := newFallbackCertificate()
+			setup(cert, failingCertificate())
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.IssuanceFailingSince).NotTo(BeNil())
+			Expect(cert.Status.ActiveIssuer).To(Equal("ClusterIssuer/letsencrypt-prod"))
+			Expect(issuerOf()).To(Equal("letsencrypt-prod"))
+		})
+
+		It("should switch to the fallback issuer once the primary keeps failing", func() {
+			cert := newFallbackCertificate()
+			cert.Status.IssuanceFailingSince = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			setup(cert, failingCertificate())
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.ActiveIssuer).To(Equal("ClusterIssuer/zerossl"))
+			Expect(issuerOf()).To(Equal("zerossl"))
+
+			By("staying on the fallback issuer once it issued the certificate")
+			mainCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, mainCert)).To(Succeed())
+			mainCert.Status.LastFailureTime = nil
+			Expect(k8sClient.Status().Update(ctx, mainCert)).To(Succeed())
+
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.IssuanceFailingSince).To(BeNil())
+			Expect(cert.Status.ActiveIssuer).To(Equal("ClusterIssuer/zerossl"))
+			Expect(issuerOf()).To(Equal("zerossl"))
+
+			By("returning to the primary issuer once the fallback is removed")
+			cert.Spec.FallbackClusterIssuerName = ""
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.ActiveIssuer).To(Equal("ClusterIssuer/letsencrypt-prod"))
+			Expect(issuerOf()).To(Equal("letsencrypt-prod"))
+		})
+
+		It("should not fall back without a fallback issuer", func() {
+			cert := newFallbackCertificate()
+			cert.Spec.FallbackClusterIssuerName = ""
+			cert.Status.IssuanceFailingSince = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			setup(cert, failingCertificate())
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.ActiveIssuer).To(Equal("ClusterIssuer/letsencrypt-prod"))
+			Expect(issuerOf()).To(Equal("letsencrypt-prod"))
+		})
+	})
+	Context("When the TLS secret holds a PKCS#12 keystore", func() {
+		var (
+			leaf, intermediate *testCertificate
+			cfProvider         *fakeProvider
+		)
+
+		newKeystoreSecret := func(key, password string) *corev1.Secret {
+			keystore, err := pkcs12.Modern.Encode(leaf.key, leaf.cert, []*x509.Certificate{intermediate.cert}, password)
+			Expect(err).NotTo(HaveOccurred())
+			return &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "example-tls",
+					Namespace:   "default",
+					Annotations: map[string]string{certmanagerv1.CertificateNameKey: "example-cert"},
+				},
+				Data: map[string][]byte{key: keystore},
+			}
+		}
+
+		newPasswordSecret := func(password string) *corev1.Secret {
+			return &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "keystore-password", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte(password)},
+			}
+		}
+
+		process := func(cert *certificatev1alpha1.Certificate, objs ...client.Object) error {
+			manager := NewCertificateManager(newFakeClient(append(objs, cert)...), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			return err
+		}
+
+		newKeystoreCertificate := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.PKCS12PasswordSecretRef = "keystore-password"
+			return cert
+		}
+
+		BeforeEach(func() {
+			leaf, intermediate = generateTestChain("example.com")
+			cfProvider = newFakeProvider("cloudflare", "cf-id")
+		})
+
+		It("should decode the keystore into the certificate chain and key", func() {
+			cert := newKeystoreCertificate()
+			Expect(process(cert, newKeystoreSecret("keystore.p12", "s3cret"), newPasswordSecret("s3cret"))).To(Succeed())
+
+			upload := cfProvider.lastUpload()
+			Expect(upload.Certificate).To(Equal(leaf.certPEM))
+			Expect(upload.Chain).To(Equal(intermediate.certPEM))
+
+			block, _ := pem.Decode(upload.PrivateKey)
+			Expect(block).NotTo(BeNil())
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(leaf.key.Equal(key)).To(BeTrue())
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+		})
+
+		It("should accept a tls.p12 entry", func() {
+			cert := newKeystoreCertificate()
+			Expect(process(cert, newKeystoreSecret("tls.p12", "s3cret"), newPasswordSecret("s3cret"))).To(Succeed())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+		})
+
+		It("should fail without uploading when the password is wrong", func() {
+			cert := newKeystoreCertificate()
+			err := process(cert, newKeystoreSecret("keystore.p12", "s3cret"), newPasswordSecret("wrong"))
+			Expect(err).To(MatchError(ContainSubstring("incorrect PKCS#12 password")))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+		})
+
+		It("should fail when the password secret is missing", func() {
+			cert := newKeystoreCertificate()
+			err := process(cert, newKeystoreSecret("keystore.p12", "s3cret"))
+			Expect(err).To(MatchError(ContainSubstring("PKCS#12 password secret")))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+		})
+
+		It("should fail on a corrupt keystore", func() {
+			cert := newKeystoreCertificate()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "example-tls",
+					Namespace:   "default",
+					Annotations: map[string]string{certmanagerv1.CertificateNameKey: "example-cert"},
+				},
+				Data: map[string][]byte{"keystore.p12": []byte("not a keystore")},
+			}
+			err := process(cert, secret, newPasswordSecret("s3cret"))
+			Expect(err).To(MatchError(ContainSubstring("failed to decode PKCS#12 keystore")))
+		})
+	})
+
+	Context("When replicating to remote clusters", func() {
+		var (
+			providers map[string]*fakeProvider
+			configs   map[string]remoteclusterdriver.Config
+		)
+
+		newRemoteCertificate := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.RemoteClusters = []certificatev1alpha1.RemoteCluster{
+				{Name: "edge-a", KubeconfigSecretRef: "edge-a-kubeconfig"},
+				{Name: "edge-b", KubeconfigSecretRef: "edge-b-kubeconfig", Namespace: "ingress", SecretName: "wildcard-tls"},
+			}
+			return cert
+		}
+
+		BeforeEach(func() {
+			providers = map[string]*fakeProvider{
+				"edge-a-kubeconfig": newFakeProvider("remote-cluster", "default/example-tls"),
+				"edge-b-kubeconfig": newFakeProvider("remote-cluster", "ingress/wildcard-tls"),
+			}
+			configs = map[string]remoteclusterdriver.Config{}
+		})
+
+		newManager := func(objs ...client.Object) *CertificateManager {
+			m := NewCertificateManager(newFakeClient(objs...), testScheme)
+			m.newRemoteClusterDriver = func(cfg remoteclusterdriver.Config) types.CloudProvider {
+				configs[cfg.KubeconfigSecretRef] = cfg
+				return providers[cfg.KubeconfigSecretRef]
+			}
+			return m
+		}
+
+		It("should replicate the secret to every cluster and track the sync status", func() {
+			cert := newRemoteCertificate()
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			manager := newManager(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+
+			Expect(configs["edge-a-kubeconfig"].TargetNamespace).To(Equal("default"))
+			Expect(configs["edge-a-kubeconfig"].SecretName).To(Equal("example-tls"))
+			Expect(configs["edge-b-kubeconfig"].TargetNamespace).To(Equal("ingress"))
+			Expect(configs["edge-b-kubeconfig"].SecretName).To(Equal("wildcard-tls"))
+			Expect(providers["edge-a-kubeconfig"].lastUpload().Certificate).To(Equal(leaf.certPEM))
+
+			Expect(cert.Status.RemoteClusters).To(HaveLen(2))
+			for _, status := range cert.Status.RemoteClusters {
+				Expect(status.Synced).To(BeTrue())
+				Expect(status.LastSyncedTime).NotTo(BeNil())
+				Expect(status.Error).To(BeEmpty())
+			}
+			Expect(cert.Status.RemoteClusters[1].SecretRef).To(Equal("ingress/wildcard-tls"))
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(leaf.certPEM)))
+		})
+
+		It("should retry only the clusters that failed to sync", func() {
+			providers["edge-b-kubeconfig"].uploadErr = errors.New("connection refused")
+			cert := newRemoteCertificate()
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			manager := newManager(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.RemoteClusters[0].Synced).To(BeTrue())
+			Expect(cert.Status.RemoteClusters[1].Synced).To(BeFalse())
+			Expect(cert.Status.RemoteClusters[1].Error).To(ContainSubstring("connection refused"))
+
+			By("processing again once the cluster is reachable")
+			providers["edge-b-kubeconfig"].uploadErr = nil
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providers["edge-a-kubeconfig"].uploadCount()).To(Equal(1))
+			Expect(providers["edge-b-kubeconfig"].uploadCount()).To(Equal(2))
+			Expect(cert.Status.RemoteClusters[1].Synced).To(BeTrue())
+			Expect(cert.Status.RemoteClusters[1].Error).To(BeEmpty())
+		})
+
+		It("should delete the replicated secrets on finalize", func() {
+			cert := newRemoteCertificate()
+			cert.Status.RemoteClusters = []certificatev1alpha1.RemoteClusterStatus{
+				{Name: "edge-a", SecretRef: "default/example-tls", Synced: true},
+				{Name: "edge-b", SecretRef: "ingress/wildcard-tls", Synced: true},
+				{Name: "removed", SecretRef: "default/example-tls", Synced: true},
+			}
+			manager := newManager(cert)
+
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(providers["edge-a-kubeconfig"].deletes).To(ConsistOf("default/example-tls"))
+			Expect(providers["edge-b-kubeconfig"].deletes).To(ConsistOf("ingress/wildcard-tls"))
+
+			By("forgetting the deleted secrets so a retry doesn't delete them again")
+			Expect(cert.Status.RemoteClusters[0].SecretRef).To(BeEmpty())
+			Expect(cert.Status.RemoteClusters[1].SecretRef).To(BeEmpty())
+			Expect(cert.Status.RemoteClusters[2].SecretRef).To(Equal("default/example-tls"))
+		})
+	})
+
+	Context("When hashing certificates", func() {
+		var (
+			leaf         *testCertificate
+			intermediate *testCertificate
+			fullChain    []byte
+		)
+
+		BeforeEach(func() {
+			leaf, intermediate = generateTestChain("example.com")
+			fullChain = append(append([]byte{}, leaf.certPEM...), intermediate.certPEM...)
+		})
+
+		It("should hash cosmetically different encodings of the same certificate equally", func() {
+			hash := calculateCertHash(fullChain)
+
+			Expect(calculateCertHash(leaf.certPEM)).To(Equal(hash))
+			Expect(calculateCertHash(append(append([]byte{}, fullChain...), "\n\n"...))).To(Equal(hash))
+			Expect(calculateCertHash(bytes.ReplaceAll(fullChain, []byte("\n"), []byte("\r\n")))).To(Equal(hash))
+			Expect(calculateCertHash(append(append([]byte{}, intermediate.certPEM...), leaf.certPEM...))).To(Equal(hash))
+			Expect(calculateCertHash(append([]byte("subject=CN = example.com\n"), fullChain...))).To(Equal(hash))
+		})
+
+		It("should hash different certificates differently", func() {
+			renewed := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			Expect(calculateCertHash(renewed.certPEM)).NotTo(Equal(calculateCertHash(leaf.certPEM)))
+		})
+
+		It("should migrate a hash of the raw PEM bytes without re-uploading", func() {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Status.CloudflareUploaded = true
+			cert.Status.LastUploadedCertHash = legacyCertHash(fullChain)
+
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(fullChain, leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(fullChain)))
+		})
+	})
+
+	Context("When the issuer kind changes", func() {
+		It("should point the cert-manager Certificate at the new issuer and re-upload the reissued certificate", func() {
+			issued := func(kind, name string) *corev1.Secret {
+				tlsCert := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+				secret := newTLSSecret(tlsCert.certPEM, tlsCert.keyPEM)
+				secret.Annotations[certmanagerv1.IssuerKindAnnotationKey] = kind
+				secret.Annotations[certmanagerv1.IssuerNameAnnotationKey] = name
+				return secret
+			}
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			k8sClient := newFakeClient(cert, issued("ClusterIssuer", "letsencrypt-prod"))
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(k8sClient, testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			By("uploading the certificate issued by the ClusterIssuer")
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.LastUploadedCertHash).NotTo(BeEmpty())
+
+			By("switching to a namespaced Issuer")
+			cert.Spec.IssuerKind = certificatev1alpha1.IssuerKindIssuer
+			cert.Spec.IssuerName = "team-issuer"
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.LastUploadedCertHash).To(BeEmpty())
+			Expect(cert.Status.CloudflareUploaded).To(BeFalse())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+
+			cmCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			Expect(cmCert.Spec.IssuerRef.Kind).To(Equal("Issuer"))
+			Expect(cmCert.Spec.IssuerRef.Name).To(Equal("team-issuer"))
+			Expect(cmCert.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", certmanagerv1.CertificateConditionIssuing),
+				HaveField("Status", cmmeta.ConditionTrue),
+			)))
+
+			By("uploading the certificate reissued by the Issuer")
+			Expect(k8sClient.Update(ctx, issued("Issuer", "team-issuer"))).To(Succeed())
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(2))
+			Expect(cfProvider.lastUpload().ExistingID).To(Equal("cf-id"))
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+		})
+	})
+
+	Context("When the subject is configured", func() {
+		getCMCert := func(k8sClient client.Client) *certmanagerv1.Certificate {
+			cmCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			return cmCert
+		}
+
+		It("should set the subject on the cert-manager Certificate", func() {
+			cert := newCertificate()
+			cert.Spec.Subject = &certificatev1alpha1.X509Subject{
+				Organizations:       []string{"Example Corp"},
+				OrganizationalUnits: []string{"Platform"},
+				Countries:           []string{"DE"},
+				Localities:          []string{"Berlin"},
+				SerialNumber:        "1234",
+			}
+			k8sClient := newFakeClient(cert)
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			subject := getCMCert(k8sClient).Spec.Subject
+			Expect(subject).NotTo(BeNil())
+			Expect(subject.Organizations).To(Equal([]string{"Example Corp"}))
+			Expect(subject.OrganizationalUnits).To(Equal([]string{"Platform"}))
+			Expect(subject.Countries).To(Equal([]string{"DE"}))
+			Expect(subject.Localities).To(Equal([]string{"Berlin"}))
+			Expect(subject.SerialNumber).To(Equal("1234"))
+		})
+
+		It("should trigger reissuance when the subject changes", func() {
+			cert := newCertificate()
+			k8sClient := newFakeClient(cert)
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			By("creating the cert-manager Certificate without a subject")
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getCMCert(k8sClient).Spec.Subject).To(BeNil())
+
+			By("processing again without changes")
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getCMCert(k8sClient).Status.Conditions).To(BeEmpty())
+
+			By("adding an organization")
+			cert.Spec.Subject = &certificatev1alpha1.X509Subject{Organizations: []string{"Example Corp"}}
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			cmCert := getCMCert(k8sClient)
+			Expect(cmCert.Spec.Subject.Organizations).To(Equal([]string{"Example Corp"}))
+			Expect(cmCert.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", certmanagerv1.CertificateConditionIssuing),
+				HaveField("Status", cmmeta.ConditionTrue),
+				HaveField("Reason", "SubjectChanged"),
+			)))
+		})
+	})
+
+	Context("When the private key is delivered in a separate Secret", func() {
+		It("should wait for both Secrets and a matching key before uploading", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+			other := generateTestCertificate("example.com", testCertOptions{})
+
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.KeySecretRef = "example-key"
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			k8sClient := newFakeClient(cert, newTLSSecret(leaf.certPEM, nil))
+			manager := NewCertificateManager(k8sClient, testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			By("waiting while the key Secret is missing")
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(emptySecretRequeueInterval))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+
+			By("waiting while the key doesn't match the certificate")
+			keySecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "example-key", Namespace: "default"},
+				Data:       map[string][]byte{"tls.key": other.keyPEM},
+			}
+			Expect(k8sClient.Create(ctx, keySecret)).To(Succeed())
+			result, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(emptySecretRequeueInterval))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+
+			By("uploading the certificate with the key from the key Secret")
+			keySecret.Data["tls.key"] = leaf.keyPEM
+			Expect(k8sClient.Update(ctx, keySecret)).To(Succeed())
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cfProvider.lastUpload().Certificate).To(ContainSubstring(string(leaf.certPEM)))
+			Expect(cfProvider.lastUpload().PrivateKey).To(Equal(leaf.keyPEM))
+		})
+
+		It("should wait while the TLS Secret has no certificate", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+
+			cert := newCertificate()
+			cert.Spec.KeySecretRef = "example-key"
+			k8sClient := newFakeClient(cert, newTLSSecret(nil, nil), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "example-key", Namespace: "default"},
+				Data:       map[string][]byte{"tls.key": leaf.keyPEM},
+			})
+
+			result, _, err := NewCertificateManager(k8sClient, testScheme).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(emptySecretRequeueInterval))
+			Expect(cert.Status.NotAfter).To(BeNil())
+		})
+	})
+
+	Context("When another cert-manager Certificate targets the TLS secret", func() {
+		It("should refuse to issue into the secret until the other Certificate is removed", func() {
+			cert := newCertificate()
+			other := &certmanagerv1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "default"},
+				Spec:       certmanagerv1.CertificateSpec{SecretName: "example-tls", DNSNames: []string{"example.com"}},
+			}
+			k8sClient := newFakeClient(cert, other)
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(secretConflictRequeueInterval))
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionSecretConflict)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonSecretInUse))
+			Expect(condition.Message).To(ContainSubstring("legacy"))
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, &certmanagerv1.Certificate{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			By("removing the other Certificate")
+			Expect(k8sClient.Delete(ctx, other)).To(Succeed())
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionSecretConflict)).To(BeNil())
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, &certmanagerv1.Certificate{})).To(Succeed())
+		})
+	})
+
+	Context("When the names of the cert-manager Certificate or TLS secret are taken", func() {
+		expectNameConflict := func(cert *certificatev1alpha1.Certificate, message string) {
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionNameConflict)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonUnmanagedResource))
+			Expect(condition.Message).To(ContainSubstring(message))
+		}
+
+		It("should not take over a cert-manager Certificate it didn't create", func() {
+			cert := newCertificate()
+			unmanaged := &certmanagerv1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "example-cert", Namespace: "default"},
+				Spec: certmanagerv1.CertificateSpec{
+					SecretName: "user-tls",
+					DNSNames:   []string{"user.example.com"},
+					IssuerRef:  cmmeta.ObjectReference{Name: "user-issuer", Kind: "Issuer"},
+				},
+			}
+			k8sClient := newFakeClient(cert, unmanaged)
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(secretConflictRequeueInterval))
+			expectNameConflict(cert, "cert-manager Certificate example-cert")
+			Expect(cert.Status.CertificateRef).To(BeEmpty())
+
+			current := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, current)).To(Succeed())
+			Expect(current.Spec).To(Equal(unmanaged.Spec))
+			Expect(current.OwnerReferences).To(BeEmpty())
+
+			By("issuing once the other Certificate is removed")
+			Expect(k8sClient.Delete(ctx, unmanaged)).To(Succeed())
+			_, statusUpdated, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionNameConflict)).To(BeNil())
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, current)).To(Succeed())
+			Expect(metav1.IsControlledBy(current, cert)).To(BeTrue())
+		})
+
+		It("should not issue into a secret cert-manager didn't issue for it", func() {
+			cert := newCertificate()
+			unmanaged := newTLSSecret([]byte("user-cert"), []byte("user-key"))
+			unmanaged.Annotations = nil
+			k8sClient := newFakeClient(cert, unmanaged)
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(secretConflictRequeueInterval))
+			expectNameConflict(cert, "Secret example-tls")
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, &certmanagerv1.Certificate{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should keep managing the cert-manager Certificate it created", func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			cert := newCertificate()
+			owned := &certmanagerv1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "example-cert", Namespace: "default", OwnerReferences: ownedByExample()},
+				Spec:       certmanagerv1.CertificateSpec{SecretName: "example-tls", DNSNames: []string{"example.com"}},
+			}
+			manager := NewCertificateManager(newFakeClient(cert, owned, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionNameConflict)).To(BeNil())
+			Expect(cert.Status.CertificateRef).To(Equal("example-cert"))
+		})
+
+		It("should name the cert-manager Certificate and secret with the configured suffixes", func() {
+			cert := newCertificate()
+			unmanaged := &certmanagerv1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "example-cert", Namespace: "default"},
+				Spec:       certmanagerv1.CertificateSpec{SecretName: "user-tls", DNSNames: []string{"user.example.com"}},
+			}
+			k8sClient := newFakeClient(cert, unmanaged)
+			manager := NewCertificateManager(k8sClient, testScheme, WithNameSuffixes("-issuance", "-issued-tls"))
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionNameConflict)).To(BeNil())
+			Expect(cert.Status.CertificateRef).To(Equal("example-issuance"))
+
+			cmCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-issuance"}, cmCert)).To(Succeed())
+			Expect(cmCert.Spec.SecretName).To(Equal("example-issued-tls"))
+		})
+	})
+
+	Context("When labels and annotations are set for the cert-manager Certificate", func() {
+		getCMCert := func(k8sClient client.Client) *certmanagerv1.Certificate {
+			cmCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			return cmCert
+		}
+
+		It("should propagate them and keep them in sync with the spec", func() {
+			cert := newCertificate()
+			cert.Spec.CertificateLabels = map[string]string{"team": "payments", "cost-center": "42"}
+			cert.Spec.CertificateAnnotations = map[string]string{"example.com/owner": "payments"}
+			k8sClient := newFakeClient(cert)
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			cmCert := getCMCert(k8sClient)
+			Expect(cmCert.Labels).To(HaveKeyWithValue("team", "payments"))
+			Expect(cmCert.Labels).To(HaveKeyWithValue("cost-center", "42"))
+			Expect(cmCert.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "certificate-operator"))
+			Expect(cmCert.Annotations).To(HaveKeyWithValue("example.com/owner", "payments"))
+
+			By("keeping labels set by others across updates")
+			cmCert.Labels["backup"] = "true"
+			Expect(k8sClient.Update(ctx, cmCert)).To(Succeed())
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			cmCert = getCMCert(k8sClient)
+			Expect(cmCert.Labels).To(HaveKeyWithValue("team", "payments"))
+			Expect(cmCert.Labels).To(HaveKeyWithValue("backup", "true"))
+
+			By("updating and removing labels and annotations from the spec")
+			cert.Spec.CertificateLabels = map[string]string{"team": "checkout"}
+			cert.Spec.CertificateAnnotations = nil
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			cmCert = getCMCert(k8sClient)
+			Expect(cmCert.Labels).To(HaveKeyWithValue("team", "checkout"))
+			Expect(cmCert.Labels).NotTo(HaveKey("cost-center"))
+			Expect(cmCert.Labels).To(HaveKeyWithValue("backup", "true"))
+			Expect(cmCert.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "certificate-operator"))
+			Expect(cmCert.Annotations).NotTo(HaveKey("example.com/owner"))
+		})
+
+		It("should not let the labels override the managed-by label", func() {
+			cert := newCertificate()
+			cert.Spec.CertificateLabels = map[string]string{"app.kubernetes.io/managed-by": "someone-else"}
+			k8sClient := newFakeClient(cert)
+
+			_, _, err := NewCertificateManager(k8sClient, testScheme).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getCMCert(k8sClient).Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "certificate-operator"))
+		})
+	})
+
+	Context("When running as a named operator instance", func() {
+		It("should include the instance ID in the managed-by label", func() {
+			cert := newCertificate()
+			k8sClient := newFakeClient(cert)
+
+			_, _, err := NewCertificateManager(k8sClient, testScheme).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			cmCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			Expect(cmCert.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "certificate-operator"))
+
+			cert = newCertificate()
+			k8sClient = newFakeClient(cert)
+			_, _, err = NewCertificateManager(k8sClient, testScheme, WithInstanceID("team-a")).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			Expect(cmCert.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "certificate-operator-team-a"))
+		})
+
+		It("should use the configured managed-by value", func() {
+			cert := newCertificate()
+			k8sClient := newFakeClient(cert)
+
+			_, _, err := NewCertificateManager(k8sClient, testScheme, WithManagedBy("platform-certs")).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			cmCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			Expect(cmCert.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "platform-certs"))
+
+			cert = newCertificate()
+			k8sClient = newFakeClient(cert)
+			_, _, err = NewCertificateManager(k8sClient, testScheme,
+				WithManagedBy("platform-certs"), WithInstanceID("team-a")).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			Expect(cmCert.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "platform-certs-team-a"))
+		})
+	})
+
+	Context("When upload settings change without a renewal", func() {
+		var (
+			leaf        *testCertificate
+			awsProvider *fakeProvider
+		)
+
+		BeforeEach(func() {
+			leaf = generateTestCertificate("example.com", testCertOptions{})
+			awsProvider = newFakeProvider("aws", "arn:aws:acm:us-east-1:123456789012:certificate/example")
+		})
+
+		// uploaded returns a Certificate whose current certificate was uploaded with the given tags
+		uploaded := func(tags map[string]string) *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			cert.Spec.ProviderTags = tags
+			cert.Status.AWSUploaded = true
+			cert.Status.AWSCertificateARN = awsProvider.identifier
+			cert.Status.LastUploadedCertHash = calculateCertHash(leaf.certPEM)
+			cert.Status.LastUploadedChainFingerprint = calculateChainFingerprint(leaf.certPEM)
+			cert.Status.LastUploadedSpecHash = calculateUploadSpecHash(cert, cert.Spec.ProviderTags)
+			return cert
+		}
+
+		process := func(cert *certificatev1alpha1.Certificate) {
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should not upload when nothing changed", func() {
+			process(uploaded(map[string]string{"team": "platform"}))
+			Expect(awsProvider.uploadCount()).To(BeZero())
+		})
+
+		It("should update the provider when only the tags change", func() {
+			cert := uploaded(map[string]string{"team": "platform"})
+			cert.Spec.ProviderTags = map[string]string{"team": "web"}
+
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			Expect(awsProvider.uploads[0].ExistingID).To(Equal(awsProvider.identifier))
+			Expect(awsProvider.uploads[0].Tags).To(Equal(map[string]string{"team": "web"}))
+			Expect(cert.Status.LastUploadedSpecHash).To(Equal(calculateUploadSpecHash(cert, cert.Spec.ProviderTags)))
+
+			By("not updating it again once the new tags are uploaded")
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+		})
+
+		It("should update the provider when the bundle type changes", func() {
+			cert := uploaded(nil)
+			cert.Spec.AWS.Bundle = certificatev1alpha1.BundleLeafOnly
+
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+		})
+
+		It("should not treat defaulted settings as a change", func() {
+			cert := uploaded(nil)
+			cert.Spec.AWS.Bundle = certificatev1alpha1.BundleFullChain
+
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(BeZero())
+		})
+
+		It("should adopt the settings of a certificate uploaded before they were tracked", func() {
+			cert := uploaded(map[string]string{"team": "platform"})
+			cert.Status.LastUploadedSpecHash = ""
+
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.LastUploadedSpecHash).To(Equal(calculateUploadSpecHash(cert, cert.Spec.ProviderTags)))
+		})
+	})
+
+	Context("When recording the status of each provider", func() {
+		var (
+			leaf        *testCertificate
+			cfProvider  *fakeProvider
+			awsProvider *fakeProvider
+			s3Provider  *fakeProvider
+		)
+
+		BeforeEach(func() {
+			leaf = generateTestCertificate("example.com", testCertOptions{})
+			cfProvider = newFakeProvider("cloudflare", "cf-id")
+			awsProvider = newFakeProvider("aws", "arn:aws:acm:us-east-1:123456789012:certificate/example")
+			s3Provider = newFakeProvider("s3", "s3://certificates/example.com/")
+			s3Provider.objectKeys = []string{"example.com/tls.crt"}
+		})
+
+		newProvidersCertificate := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			cert.Spec.S3 = &certificatev1alpha1.S3{Bucket: "certificates"}
+			return cert
+		}
+
+		process := func(cert *certificatev1alpha1.Certificate) {
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+			manager.newS3Driver = func(s3driver.Config) types.CloudProvider { return s3Provider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should record every upload in status.providers and derive the legacy fields", func() {
+			cert := newProvidersCertificate()
+			process(cert)
+
+			Expect(cert.Status.Providers).To(HaveLen(3))
+			for provider, identifier := range map[string]string{
+				"cloudflare": cfProvider.identifier,
+				"aws":        awsProvider.identifier,
+				"s3":         s3Provider.identifier,
+			} {
+				status := cert.Status.Providers[provider]
+				Expect(status.Uploaded).To(BeTrue(), provider)
+				Expect(status.Identifier).To(Equal(identifier), provider)
+				Expect(status.LastUploadedTime).NotTo(BeNil(), provider)
+				Expect(status.LastError).To(BeEmpty(), provider)
+			}
+
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+			Expect(cert.Status.CloudflareCertificateID).To(Equal(cfProvider.identifier))
+			Expect(cert.Status.AWSUploaded).To(BeTrue())
+			Expect(cert.Status.AWSCertificateARN).To(Equal(awsProvider.identifier))
+			Expect(cert.Status.S3Uploaded).To(BeTrue())
+			Expect(cert.Status.S3ObjectKeys).To(Equal(s3Provider.objectKeys))
+		})
+
+		It("should record the state AWS ACM reports for the imported certificate", func() {
+			importedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+			awsProvider.acm = &types.ACMCertificateDetails{
+				Status:             "ISSUED",
+				Type:               "IMPORTED",
+				RenewalEligibility: "INELIGIBLE",
+				InUseByCount:       2,
+				ImportedAt:         importedAt,
+			}
+			cert := newProvidersCertificate()
+			process(cert)
+
+			Expect(cert.Status.Providers["aws"].ACM).To(Equal(&certificatev1alpha1.ACMCertificateStatus{
+				Status:             "ISSUED",
+				Type:               "IMPORTED",
+				RenewalEligibility: "INELIGIBLE",
+				InUseByCount:       2,
+				ImportedAt:         &metav1.Time{Time: importedAt},
+			}))
+			Expect(cert.Status.Providers["cloudflare"].ACM).To(BeNil())
+
+			By("clearing the state once it can't be read after an import")
+			awsProvider.acm = nil
+			cert.Status.LastUploadedCertHash = ""
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(Equal(2))
+			Expect(cert.Status.Providers["aws"].Uploaded).To(BeTrue())
+			Expect(cert.Status.Providers["aws"].ACM).To(BeNil())
+		})
+
+		It("should record the error of a failed upload until an upload succeeds", func() {
+			cert := newProvidersCertificate()
+			awsProvider.uploadErr = errors.New("access denied")
+			process(cert)
+
+			Expect(cert.Status.Providers["aws"].Uploaded).To(BeFalse())
+			Expect(cert.Status.Providers["aws"].LastError).To(ContainSubstring("access denied"))
+			Expect(cert.Status.AWSUploaded).To(BeFalse())
+			Expect(cert.Status.Providers["cloudflare"].Uploaded).To(BeTrue())
+
+			By("uploading again once the provider recovered")
+			awsProvider.uploadErr = nil
+			cert.Status.LastUploadedCertHash = ""
+			process(cert)
+
+			Expect(cert.Status.Providers["aws"].Uploaded).To(BeTrue())
+			Expect(cert.Status.Providers["aws"].Identifier).To(Equal(awsProvider.identifier))
+			Expect(cert.Status.Providers["aws"].LastError).To(BeEmpty())
+			Expect(cert.Status.AWSCertificateARN).To(Equal(awsProvider.identifier))
+		})
+
+		It("should fill status.providers from the legacy fields", func() {
+			uploadedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+			cert := newProvidersCertificate()
+			cert.Status.CloudflareUploaded = true
+			cert.Status.CloudflareCertificateID = "cf-legacy"
+			cert.Status.AWSCertificateARN = "arn:aws:acm:us-east-1:123456789012:certificate/legacy"
+			cert.Status.LastUploadedTime = &uploadedAt
+
+			Expect(migrateProviderStatus(cert)).To(BeTrue())
+			Expect(cert.Status.Providers).To(Equal(map[string]certificatev1alpha1.ProviderStatus{
+				"cloudflare": {Uploaded: true, Identifier: "cf-legacy", LastUploadedTime: &uploadedAt},
+				"aws":        {Identifier: "arn:aws:acm:us-east-1:123456789012:certificate/legacy"},
+			}))
+
+			By("not migrating again")
+			Expect(migrateProviderStatus(cert)).To(BeFalse())
+		})
+
+		It("should keep the identifiers when the upload status is reset", func() {
+			cert := newProvidersCertificate()
+			process(cert)
+
+			Expect(resetUploadStatus(cert)).To(BeTrue())
+			for provider, status := range cert.Status.Providers {
+				Expect(status.Uploaded).To(BeFalse(), provider)
+				Expect(status.Identifier).NotTo(BeEmpty(), provider)
+			}
+			Expect(cert.Status.AWSUploaded).To(BeFalse())
+			Expect(cert.Status.AWSCertificateARN).To(Equal(awsProvider.identifier))
+		})
+	})
+
+	Context("When planning a spec change", func() {
+		// uploaded returns a Certificate whose certificate was uploaded to AWS and S3
+		uploaded := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.ClusterIssuerName = "letsencrypt"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			cert.Spec.S3 = &certificatev1alpha1.S3{Bucket: "certificates"}
+			cert.Status.CertificateRef = "example-cert"
+			cert.Status.LastUploadedCertHash = "uploaded"
+			cert.Status.LastUploadedSpecHash = calculateUploadSpecHash(cert, nil)
+			return cert
+		}
+
+		It("should plan nothing when the spec is unchanged", func() {
+			cert := uploaded()
+			plan := NewCertificateManager(newFakeClient(), testScheme).PlanSpecChange(cert, cert.Spec)
+			Expect(plan).To(Equal(SpecChangePlan{}))
+		})
+
+		It("should plan a reissue and re-upload when the issuer changes", func() {
+			cert := uploaded()
+			candidate := *cert.Spec.DeepCopy()
+			candidate.ClusterIssuerName = "zerossl"
+
+			plan := NewCertificateManager(newFakeClient(), testScheme, WithDisabledProviders([]string{"s3"})).
+				PlanSpecChange(cert, candidate)
+			Expect(plan.CertificateChanges).To(Equal([]FieldChange{{
+				Field:     "spec.issuerRef",
+				Current:   "ClusterIssuer/letsencrypt",
+				Candidate: "ClusterIssuer/zerossl",
+			}}))
+			Expect(plan.Reissue).To(BeTrue())
+			Expect(plan.ReuploadProviders).To(Equal([]string{"aws"}))
+			Expect(cert.Spec.ClusterIssuerName).To(Equal("letsencrypt"))
+		})
+
+		It("should plan a re-upload without a reissue when only upload settings change", func() {
+			cert := uploaded()
+			candidate := *cert.Spec.DeepCopy()
+			candidate.AWS.Bundle = certificatev1alpha1.BundleLeafOnly
+			candidate.UploadsPaused = ptr.To(true)
+
+			plan := NewCertificateManager(newFakeClient(), testScheme).PlanSpecChange(cert, candidate)
+			Expect(plan.CertificateChanges).To(BeEmpty())
+			Expect(plan.Reissue).To(BeFalse())
+			Expect(plan.ReuploadProviders).To(Equal([]string{"aws", "s3"}))
+			Expect(plan.UploadsPaused).To(BeTrue())
+		})
+
+		It("should not plan re-uploads before the first upload", func() {
+			cert := uploaded()
+			cert.Status.LastUploadedCertHash = ""
+			candidate := *cert.Spec.DeepCopy()
+			candidate.Domain = "www.example.com"
+
+			plan := NewCertificateManager(newFakeClient(), testScheme).PlanSpecChange(cert, candidate)
+			Expect(plan.CertificateChanges).To(Equal([]FieldChange{{
+				Field:     "spec.dnsNames",
+				Current:   []string{"example.com"},
+				Candidate: []string{"www.example.com"},
+			}}))
+			Expect(plan.Reissue).To(BeTrue())
+			Expect(plan.ReuploadProviders).To(BeEmpty())
+		})
+	})
+
+	Context("When uploads to a provider keep failing", func() {
+		var (
+			leaf        *testCertificate
+			awsProvider *fakeProvider
+			cert        *certificatev1alpha1.Certificate
+		)
+
+		BeforeEach(func() {
+			leaf = generateTestCertificate("example.com", testCertOptions{})
+			awsProvider = newFakeProvider("aws", "arn:aws:acm:us-east-1:123456789012:certificate/example")
+			awsProvider.uploadErr = errors.New("access denied")
+			cert = newCertificate()
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+		})
+
+		process := func() ctrl.Result {
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme,
+				WithCircuitBreaker(3, time.Hour))
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			return result
+		}
+
+		It("should open the breaker after the threshold and skip uploads during the cooldown", func() {
+			for range 2 {
+				Expect(process().RequeueAfter).To(BeZero())
+			}
+			Expect(cert.Status.ProviderCircuitBreakers).To(HaveLen(1))
+			Expect(cert.Status.ProviderCircuitBreakers[0].State).To(Equal(certificatev1alpha1.CircuitBreakerClosed))
+			Expect(cert.Status.ProviderCircuitBreakers[0].ConsecutiveFailures).To(Equal(int32(2)))
+
+			By("opening on the third failure")
+			result := process()
+			breaker := cert.Status.ProviderCircuitBreakers[0]
+			Expect(breaker.Provider).To(Equal("aws"))
+			Expect(breaker.State).To(Equal(certificatev1alpha1.CircuitBreakerOpen))
+			Expect(breaker.ConsecutiveFailures).To(Equal(int32(3)))
+			Expect(breaker.LastError).To(Equal("access denied"))
+			Expect(breaker.OpenedAt).NotTo(BeNil())
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+			By("skipping uploads during the cooldown")
+			process()
+			Expect(awsProvider.uploadCount()).To(Equal(3))
+			Expect(cert.Status.ProviderCircuitBreakers[0].State).To(Equal(certificatev1alpha1.CircuitBreakerOpen))
+		})
+
+		It("should skip uploads until exactly the end of the cooldown", func() {
+			// After the NotBefore of the test certificate
+			openedAt := time.Now().Add(time.Hour).Truncate(time.Second)
+			clock := clocktesting.NewFakePassiveClock(openedAt)
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme,
+				WithCircuitBreaker(1, time.Hour), WithClock(clock))
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Hour))
+			Expect(cert.Status.ProviderCircuitBreakers[0].OpenedAt.Time).To(BeTemporally("==", openedAt))
+
+			By("skipping the upload just before the cooldown ends")
+			clock.SetTime(openedAt.Add(59 * time.Minute))
+			result, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+
+			By("testing the provider once the cooldown ends")
+			clock.SetTime(openedAt.Add(time.Hour))
+			awsProvider.uploadErr = nil
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(awsProvider.uploadCount()).To(Equal(2))
+			Expect(cert.Status.ProviderCircuitBreakers).To(BeEmpty())
+			Expect(cert.Status.Providers["aws"].LastUploadedTime.Time).To(BeTemporally("==", openedAt.Add(time.Hour)))
+		})
+
+		It("should close the breaker when the test upload after the cooldown succeeds", func() {
+			cert.Status.ProviderCircuitBreakers = []certificatev1alpha1.ProviderCircuitBreaker{{
+				Provider:            "aws",
+				State:               certificatev1alpha1.CircuitBreakerOpen,
+				ConsecutiveFailures: 3,
+				OpenedAt:            &metav1.Time{Time: time.Now().Add(-2 * time.Hour)},
+			}}
+			awsProvider.uploadErr = nil
+
+			Expect(process().RequeueAfter).To(BeZero())
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.AWSUploaded).To(BeTrue())
+			Expect(cert.Status.ProviderCircuitBreakers).To(BeEmpty())
+		})
+
+		It("should open the breaker again when the test upload fails", func() {
+			cert.Status.ProviderCircuitBreakers = []certificatev1alpha1.ProviderCircuitBreaker{{
+				Provider:            "aws",
+				State:               certificatev1alpha1.CircuitBreakerOpen,
+				ConsecutiveFailures: 3,
+				OpenedAt:            &metav1.Time{Time: time.Now().Add(-2 * time.Hour)},
+			}}
+
+			result := process()
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			breaker := cert.Status.ProviderCircuitBreakers[0]
+			Expect(breaker.State).To(Equal(certificatev1alpha1.CircuitBreakerOpen))
+			Expect(breaker.ConsecutiveFailures).To(Equal(int32(4)))
+			Expect(breaker.OpenedAt.Time).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+		})
+
+		It("should test a recovered provider even when the certificate didn't change", func() {
+			cert.Status.LastUploadedCertHash = calculateCertHash(leaf.certPEM)
+			cert.Status.LastUploadedChainFingerprint = calculateChainFingerprint(leaf.certPEM)
+			cert.Status.LastUploadedSpecHash = calculateUploadSpecHash(cert, cert.Spec.ProviderTags)
+			cert.Status.ProviderCircuitBreakers = []certificatev1alpha1.ProviderCircuitBreaker{{
+				Provider:            "aws",
+				State:               certificatev1alpha1.CircuitBreakerOpen,
+				ConsecutiveFailures: 5,
+				OpenedAt:            &metav1.Time{Time: time.Now().Add(-2 * time.Hour)},
+			}}
+			awsProvider.uploadErr = nil
+
+			process()
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.AWSUploaded).To(BeTrue())
+			Expect(cert.Status.ProviderCircuitBreakers).To(BeEmpty())
+		})
+
+		It("should reset the breaker when the spec changes", func() {
+			cert.Generation = 2
+			cert.Status.ProviderCircuitBreakers = []certificatev1alpha1.ProviderCircuitBreaker{{
+				Provider:            "aws",
+				State:               certificatev1alpha1.CircuitBreakerOpen,
+				ConsecutiveFailures: 3,
+				OpenedAt:            &metav1.Time{Time: time.Now()},
+				ObservedGeneration:  1,
+			}}
+
+			By("testing the provider right away instead of after the cooldown")
+			result := process()
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			breaker := cert.Status.ProviderCircuitBreakers[0]
+			Expect(breaker.State).To(Equal(certificatev1alpha1.CircuitBreakerOpen))
+			Expect(breaker.ConsecutiveFailures).To(Equal(int32(4)))
+			Expect(breaker.ObservedGeneration).To(Equal(int64(2)))
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+			By("backing off again while the spec stays the same")
+			process()
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+
+			By("closing the breaker once the fixed spec uploads")
+			cert.Generation = 3
+			awsProvider.uploadErr = nil
+			Expect(process().RequeueAfter).To(BeZero())
+			Expect(awsProvider.uploadCount()).To(Equal(2))
+			Expect(cert.Status.ProviderCircuitBreakers).To(BeEmpty())
+		})
+
+		It("should clear the failures counted before a spec change", func() {
+			cert.Generation = 2
+			cert.Status.ProviderCircuitBreakers = []certificatev1alpha1.ProviderCircuitBreaker{{
+				Provider:            "aws",
+				State:               certificatev1alpha1.CircuitBreakerClosed,
+				ConsecutiveFailures: 2,
+				ObservedGeneration:  1,
+			}}
+
+			process()
+			Expect(cert.Status.ProviderCircuitBreakers).To(HaveLen(1))
+			Expect(cert.Status.ProviderCircuitBreakers[0].State).To(Equal(certificatev1alpha1.CircuitBreakerClosed))
+			Expect(cert.Status.ProviderCircuitBreakers[0].ConsecutiveFailures).To(Equal(int32(1)))
+		})
+
+		It("should adopt the generation of breakers recorded before it was tracked", func() {
+			cert.Generation = 2
+			cert.Status.ProviderCircuitBreakers = []certificatev1alpha1.ProviderCircuitBreaker{{
+				Provider:            "aws",
+				State:               certificatev1alpha1.CircuitBreakerOpen,
+				ConsecutiveFailures: 3,
+				OpenedAt:            &metav1.Time{Time: time.Now()},
+			}}
+
+			process()
+			Expect(awsProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.ProviderCircuitBreakers[0].State).To(Equal(certificatev1alpha1.CircuitBreakerOpen))
+			Expect(cert.Status.ProviderCircuitBreakers[0].ObservedGeneration).To(Equal(int64(2)))
+		})
+
+		It("should drop the breakers of providers removed from the spec", func() {
+			cert.Spec.AWS = nil
+			cert.Status.ProviderCircuitBreakers = []certificatev1alpha1.ProviderCircuitBreaker{{
+				Provider:            "aws",
+				State:               certificatev1alpha1.CircuitBreakerOpen,
+				ConsecutiveFailures: 3,
+				OpenedAt:            &metav1.Time{Time: time.Now()},
+			}}
+
+			Expect(process().RequeueAfter).To(BeZero())
+			Expect(cert.Status.ProviderCircuitBreakers).To(BeEmpty())
+		})
+	})
+
+	Context("When provider tags are derived from labels", func() {
+		var (
+			leaf        *testCertificate
+			awsProvider *fakeProvider
+		)
+
+		BeforeEach(func() {
+			leaf = generateTestCertificate("example.com", testCertOptions{})
+			awsProvider = newFakeProvider("aws", "arn:aws:acm:us-east-1:123456789012:certificate/example")
+		})
+
+		process := func(cert *certificatev1alpha1.Certificate) {
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme,
+				WithTagLabelPrefix("tags.example.com/"))
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should upload the prefixed labels as tags, overridden by providerTags", func() {
+			cert := newCertificate()
+			cert.Labels = map[string]string{
+				"tags.example.com/team":        "platform",
+				"tags.example.com/cost-center": "1234",
+				"tags.example.com/aws:owner":   "ignored",
+				"app":                          "web",
+			}
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			cert.Spec.ProviderTags = map[string]string{"team": "edge"}
+
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			Expect(awsProvider.uploads[0].Tags).To(Equal(map[string]string{"team": "edge", "cost-center": "1234"}))
+			Expect(cert.Spec.ProviderTags).To(Equal(map[string]string{"team": "edge"}))
+		})
+
+		It("should update the provider when a tag label changes", func() {
+			cert := newCertificate()
+			cert.Labels = map[string]string{"tags.example.com/team": "platform"}
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+
+			By("not updating it again while the labels are unchanged")
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+
+			cert.Labels["tags.example.com/team"] = "web"
+			process(cert)
+			Expect(awsProvider.uploadCount()).To(Equal(2))
+			Expect(awsProvider.uploads[1].Tags).To(Equal(map[string]string{"team": "web"}))
+		})
+
+		It("should keep the hash of certificates without tag labels", func() {
+			cert := newCertificate()
+			cert.Spec.ProviderTags = map[string]string{"team": "platform"}
+			manager := NewCertificateManager(newFakeClient(), testScheme, WithTagLabelPrefix("tags.example.com/"))
+			Expect(calculateUploadSpecHash(cert, manager.providerTags(cert))).
+				To(Equal(calculateUploadSpecHash(cert, cert.Spec.ProviderTags)))
+		})
+
+		It("should cap the tags at the provider limit", func() {
+			cert := newCertificate()
+			cert.Labels = map[string]string{}
+			for i := range maxProviderTags + 5 {
+				cert.Labels[fmt.Sprintf("tags.example.com/key-%02d", i)] = "value"
+			}
+			cert.Spec.ProviderTags = map[string]string{"team": "platform"}
+			manager := NewCertificateManager(newFakeClient(), testScheme, WithTagLabelPrefix("tags.example.com/"))
+
+			tags := manager.providerTags(cert)
+			Expect(tags).To(HaveLen(maxProviderTags))
+			Expect(tags).To(HaveKeyWithValue("team", "platform"))
+			Expect(tags).To(HaveKey("key-00"))
+			Expect(tags).NotTo(HaveKey(fmt.Sprintf("key-%02d", maxProviderTags+4)))
+		})
+
+		DescribeTable("sanitizing tags",
+			func(key, value, wantKey, wantValue string, wantOK bool) {
+				gotKey, gotValue, ok := sanitizeTag(key, value)
+				Expect(ok).To(Equal(wantOK))
+				if wantOK {
+					Expect(gotKey).To(Equal(wantKey))
+					Expect(gotValue).To(Equal(wantValue))
+				}
+			},
+			Entry("valid tag", "team", "platform", "team", "platform", true),
+			Entry("rejected characters", "team#1", "a,b", "team_1", "a_b", true),
+			Entry("overlong key", strings.Repeat("k", 130), "v", strings.Repeat("k", 128), "v", true),
+			Entry("overlong value", "k", strings.Repeat("v", 300), "k", strings.Repeat("v", 256), true),
+			Entry("aws prefix", "AWS:owner", "v", "", "", false),
+			Entry("operator tag", "ManagedBy", "v", "", "", false),
+			Entry("empty key", "", "v", "", "", false),
+		)
+	})
+
+	Context("When a post-upload webhook is configured", func() {
+		var (
+			leaf   *testCertificate
+			events chan uploadEvent
+			status int
+			server *httptest.Server
+		)
+
+		BeforeEach(func() {
+			leaf = generateTestCertificate("example.com", testCertOptions{})
+			events = make(chan uploadEvent, 1)
+			status = http.StatusNoContent
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.Method).To(Equal(http.MethodPost))
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				var event uploadEvent
+				Expect(json.NewDecoder(r.Body).Decode(&event)).To(Succeed())
+				events <- event
+				w.WriteHeader(status)
+			}))
+			DeferCleanup(server.Close)
+		})
+
+		newHookedCertificate := func(hookURL string) *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Annotations = map[string]string{postUploadWebhookAnnotation: hookURL}
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			return cert
+		}
+
+		newHookedManager := func(cert *certificatev1alpha1.Certificate) *CertificateManager {
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider {
+				return newFakeProvider("cloudflare", "cf-id")
+			}
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider {
+				return newFakeProvider("aws", "arn:aws:acm:us-east-1:123456789012:certificate/example")
+			}
+			return manager
+		}
+
+		It("should POST the provider identifiers after the upload", func() {
+			cert := newHookedCertificate(server.URL + "/purge")
+
+			_, _, err := newHookedManager(cert).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			var event uploadEvent
+			Expect(events).To(Receive(&event))
+			Expect(event.Namespace).To(Equal("default"))
+			Expect(event.Name).To(Equal("example"))
+			Expect(event.Domain).To(Equal("example.com"))
+			Expect(event.CertHash).To(Equal(calculateCertHash(leaf.certPEM)))
+			Expect(event.CloudflareCertificateID).To(Equal("cf-id"))
+			Expect(event.AWSCertificateARN).To(Equal("arn:aws:acm:us-east-1:123456789012:certificate/example"))
+			Expect(event.UploadedAt).NotTo(BeZero())
+		})
+
+		It("should not call the webhook when nothing was uploaded", func() {
+			cert := newHookedCertificate(server.URL)
+			manager := newHookedManager(cert)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Receive())
+
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).NotTo(Receive())
+		})
+
+		It("should not fail the reconcile when the webhook fails", func() {
+			status = http.StatusInternalServerError
+			cert := newHookedCertificate(server.URL)
+
+			_, _, err := newHookedManager(cert).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Receive())
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(leaf.certPEM)))
+		})
+
+		It("should give up on a webhook that does not answer in time", func() {
+			release := make(chan struct{})
+			slow := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				<-release
+			}))
+			DeferCleanup(slow.Close)
+			DeferCleanup(func() { close(release) })
+			cert := newHookedCertificate(slow.URL)
+			manager := newHookedManager(cert)
+			manager.postUploadWebhookTimeout = 50 * time.Millisecond
+
+			err := manager.postUploadEvent(ctx, slow.URL, newUploadEvent(cert))
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+
+		It("should reject URLs that are not absolute http or https URLs", func() {
+			cert := newHookedCertificate("file:///etc/passwd")
+			manager := newHookedManager(cert)
+
+			for _, hookURL := range []string{"file:///etc/passwd", "/relative", "not a url"} {
+				Expect(manager.postUploadEvent(ctx, hookURL, newUploadEvent(cert))).
+					To(MatchError(ContainSubstring("invalid webhook URL")))
+			}
+		})
+	})
+
+	Context("When storing chain metadata in status", func() {
+		var (
+			leaf         *testCertificate
+			intermediate *testCertificate
+		)
+
+		BeforeEach(func() {
+			leaf, intermediate = generateTestChain("example.com")
+		})
+
+		chainOf := func(intermediates int) []byte {
+			chain := append([]byte{}, leaf.certPEM...)
+			for range intermediates {
+				chain = append(chain, intermediate.certPEM...)
+			}
+			return chain
+		}
+
+		statusSize := func(intermediates int) int {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(chainOf(intermediates), leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider {
+				return newFakeProvider("cloudflare", "cf-id")
+			}
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.LastUploadedChainFingerprint).To(HaveLen(64))
+			Expect(BoundStatus(&cert.Status)).To(Succeed())
+
+			data, err := json.Marshal(cert.Status)
+			Expect(err).NotTo(HaveOccurred())
+			return len(data)
+		}
+
+		It("should keep the status size independent of the chain length", func() {
+			short := statusSize(1)
+			long := statusSize(200)
+
+			Expect(len(chainOf(200))).To(BeNumerically(">", MaxStatusSize))
+			Expect(long).To(Equal(short))
+			Expect(long).To(BeNumerically("<", 1024))
+		})
+
+		It("should fingerprint every certificate in the chain", func() {
+			_, otherIntermediate := generateTestChain("example.com")
+			rotated := append(append([]byte{}, leaf.certPEM...), otherIntermediate.certPEM...)
+
+			Expect(calculateChainFingerprint(chainOf(1))).NotTo(Equal(calculateChainFingerprint(rotated)))
+			Expect(calculateChainFingerprint(chainOf(1))).NotTo(Equal(calculateChainFingerprint(leaf.certPEM)))
+			Expect(calculateChainFingerprint(bytes.ReplaceAll(chainOf(1), []byte("\n"), []byte("\r\n")))).
+				To(Equal(calculateChainFingerprint(chainOf(1))))
+		})
+
+		It("should re-upload when an intermediate is rotated", func() {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Status.CloudflareUploaded = true
+			cert.Status.LastUploadedCertHash = calculateCertHash(leaf.certPEM)
+			cert.Status.LastUploadedChainFingerprint = calculateChainFingerprint(chainOf(1))
+
+			_, otherIntermediate := generateTestChain("example.com")
+			rotated := append(append([]byte{}, leaf.certPEM...), otherIntermediate.certPEM...)
+
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(rotated, leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.LastUploadedChainFingerprint).To(Equal(calculateChainFingerprint(rotated)))
+		})
+
+		It("should adopt the fingerprint of a chain uploaded before fingerprints were tracked", func() {
+			cert := newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Status.CloudflareUploaded = true
+			cert.Status.LastUploadedCertHash = calculateCertHash(leaf.certPEM)
+
+			cfProvider := newFakeProvider("cloudflare", "cf-id")
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(chainOf(1), leaf.keyPEM)), testScheme)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.LastUploadedChainFingerprint).To(Equal(calculateChainFingerprint(chainOf(1))))
+		})
+
+		It("should truncate oversized status messages", func() {
+			status := certificatev1alpha1.CertificateStatus{
+				IssuanceDetail: strings.Repeat("x", 10*maxStatusMessageLength),
+				RemoteClusters: []certificatev1alpha1.RemoteClusterStatus{
+					{Name: "edge", Error: strings.Repeat("é", maxStatusMessageLength)},
+				},
+				Providers: map[string]certificatev1alpha1.ProviderStatus{
+					"aws": {LastError: strings.Repeat("x", 2*maxStatusMessageLength)},
+				},
+			}
+
+			Expect(BoundStatus(&status)).To(Succeed())
+			Expect(len(status.IssuanceDetail)).To(BeNumerically("<=", maxStatusMessageLength))
+			Expect(status.IssuanceDetail).To(HaveSuffix(truncationSuffix))
+			Expect(len(status.RemoteClusters[0].Error)).To(BeNumerically("<=", maxStatusMessageLength))
+			Expect(utf8.ValidString(status.RemoteClusters[0].Error)).To(BeTrue())
+			Expect(status.Providers["aws"].LastError).To(HaveSuffix(truncationSuffix))
+		})
+
+		It("should reject a status that is still too large", func() {
+			status := certificatev1alpha1.CertificateStatus{AWSAccountCertificateARNs: map[string]string{}}
+			for i := range 2000 {
+				status.AWSAccountCertificateARNs[fmt.Sprintf("%012d", i)] = "arn:aws:acm:us-east-1:123456789012:certificate/example"
+			}
+
+			Expect(BoundStatus(&status)).To(MatchError(ContainSubstring("exceeds the limit")))
+		})
+	})
+
+	Context("When issuance is pending", func() {
+		It("should report a growing pending issuance gauge that resets once issued", func() {
+			cert := newCertificate()
+			cert.Name = "pending"
+			k8sClient := newFakeClient(cert)
+			manager := NewCertificateManager(k8sClient, testScheme)
+			gauge := pendingIssuanceSeconds.WithLabelValues("default", "pending")
+
+			By("waiting for issuance")
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.IssuanceStartedAt).NotTo(BeNil())
+
+			By("waiting for issuance for longer")
+			startedAt := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+			cert.Status.IssuanceStartedAt = &startedAt
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			pending := testutil.ToFloat64(gauge)
+			Expect(pending).To(BeNumerically(">=", 300))
+
+			time.Sleep(10 * time.Millisecond)
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.IssuanceStartedAt.Time).To(BeTemporally("==", startedAt.Time))
+			Expect(testutil.ToFloat64(gauge)).To(BeNumerically(">", pending))
+
+			By("issuing the certificate")
+			tlsCert := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			secret := newTLSSecret(tlsCert.certPEM, tlsCert.keyPEM)
+			secret.Name = "pending-tls"
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			_, statusUpdated, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cert.Status.IssuanceStartedAt).To(BeNil())
+			Expect(testutil.ToFloat64(gauge)).To(BeZero())
+
+			By("deleting the certificate")
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(pendingIssuanceSeconds.DeleteLabelValues("default", "pending")).To(BeFalse())
+		})
+
+		It("should observe the issuance duration once the certificate is issued", func() {
+			startedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+			clock := clocktesting.NewFakePassiveClock(startedAt)
+			cert := newCertificate()
+			cert.Spec.ClusterIssuerName = "duration-issuer"
+			k8sClient := newFakeClient(cert)
+			manager := NewCertificateManager(k8sClient, testScheme, WithClock(clock))
+			histogram := issuanceDurationSeconds.WithLabelValues("ClusterIssuer/duration-issuer").(prometheus.Histogram)
+			observed := func() *dto.Histogram {
+				metric := &dto.Metric{}
+				Expect(histogram.Write(metric)).To(Succeed())
+				return metric.GetHistogram()
+			}
+			before := observed()
+
+			By("waiting for issuance")
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			clock.SetTime(startedAt.Add(90 * time.Second))
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(observed().GetSampleCount()).To(Equal(before.GetSampleCount()))
+
+			By("finding the issued certificate")
+			tlsCert := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			Expect(k8sClient.Create(ctx, newTLSSecret(tlsCert.certPEM, tlsCert.keyPEM))).To(Succeed())
+			clock.SetTime(startedAt.Add(2 * time.Minute))
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(observed().GetSampleCount()).To(Equal(before.GetSampleCount() + 1))
+			Expect(observed().GetSampleSum()).To(BeNumerically("~", before.GetSampleSum()+120, 0.001))
+
+			By("observing it only once")
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(observed().GetSampleCount()).To(Equal(before.GetSampleCount() + 1))
+		})
+
+		It("should time out exactly at spec.issuanceTimeout", func() {
+			startedAt := time.Date(2025, 10, 3, 12, 0, 0, 0, time.UTC)
+			clock := clocktesting.NewFakePassiveClock(startedAt)
+			cert := newCertificate()
+			cert.Spec.IssuanceTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+			manager := NewCertificateManager(newFakeClient(cert), testScheme, WithClock(clock))
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.IssuanceStartedAt.Time).To(BeTemporally("==", startedAt))
+
+			clock.SetTime(startedAt.Add(9*time.Minute + 30*time.Second))
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionTimeout)).To(BeNil())
+
+			clock.SetTime(startedAt.Add(10 * time.Minute))
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(cert.Status.Conditions, certificatev1alpha1.ConditionTimeout)).To(BeTrue())
+		})
+
+		It("should report a certificate that isn't issued within spec.issuanceTimeout", func() {
+			cert := newCertificate()
+			cert.Spec.IssuanceTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+			k8sClient := newFakeClient(cert)
+			recorder := record.NewFakeRecorder(10)
+			manager := NewCertificateManager(k8sClient, testScheme, WithEventRecorder(recorder))
+
+			By("waiting within the timeout")
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionTimeout)).To(BeNil())
+
+			By("checking again when the timeout passes")
+			startedAt := metav1.NewTime(time.Now().Add(-9*time.Minute - 30*time.Second))
+			cert.Status.IssuanceStartedAt = &startedAt
+			result, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 30*time.Second))
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("exceeding the timeout")
+			startedAt = metav1.NewTime(time.Now().Add(-15 * time.Minute))
+			cert.Status.IssuanceStartedAt = &startedAt
+			result, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionTimeout)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonIssuanceTimedOut))
+			Expect(condition.Message).To(ContainSubstring("not issued within 10m0s"))
+			Expect(recorder.Events).To(Receive(Equal("Warning IssuanceTimedOut " + condition.Message)))
+
+			By("emitting the event only once")
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(cert.Status.Conditions, certificatev1alpha1.ConditionTimeout)).To(BeTrue())
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("issuing the certificate")
+			tlsCert := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			Expect(k8sClient.Create(ctx, newTLSSecret(tlsCert.certPEM, tlsCert.keyPEM))).To(Succeed())
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionTimeout)).To(BeNil())
+		})
+
+		It("should not time out without spec.issuanceTimeout", func() {
+			cert := newCertificate()
+			startedAt := metav1.NewTime(time.Now().Add(-24 * time.Hour))
+			cert.Status.IssuanceStartedAt = &startedAt
+			recorder := record.NewFakeRecorder(10)
+			manager := NewCertificateManager(newFakeClient(cert), testScheme, WithEventRecorder(recorder))
+
+			result, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionTimeout)).To(BeNil())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+	})
+
+	Context("When a private key rotation policy is set", func() {
+		DescribeTable("should set the rotation policy on the cert-manager Certificate",
+			func(policy certificatev1alpha1.PrivateKeyRotationPolicy, expected *certmanagerv1.CertificatePrivateKey) {
+				cert := newCertificate()
+				cert.Spec.PrivateKeyRotationPolicy = policy
+				k8sClient := newFakeClient(cert)
+
+				_, _, err := NewCertificateManager(k8sClient, testScheme).ProcessCertificate(ctx, cert)
+				Expect(err).NotTo(HaveOccurred())
+
+				cmCert := &certmanagerv1.Certificate{}
+				Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+				Expect(cmCert.Spec.PrivateKey).To(Equal(expected))
+			},
+			Entry("Always", certificatev1alpha1.PrivateKeyRotationPolicyAlways,
+				&certmanagerv1.CertificatePrivateKey{RotationPolicy: certmanagerv1.RotationPolicyAlways}),
+			Entry("Never", certificatev1alpha1.PrivateKeyRotationPolicyNever,
+				&certmanagerv1.CertificatePrivateKey{RotationPolicy: certmanagerv1.RotationPolicyNever}),
+			Entry("unset", certificatev1alpha1.PrivateKeyRotationPolicy(""), nil),
+		)
+
+		It("should drop the policy once it is unset", func() {
+			cert := newCertificate()
+			cert.Spec.PrivateKeyRotationPolicy = certificatev1alpha1.PrivateKeyRotationPolicyNever
+			k8sClient := newFakeClient(cert)
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			cert.Spec.PrivateKeyRotationPolicy = ""
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			cmCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			Expect(cmCert.Spec.PrivateKey).To(BeNil())
+		})
+	})
+
+	Context("When a revision history limit is set", func() {
+		It("should set the limit on the cert-manager Certificate and drop it once unset", func() {
+			cert := newCertificate()
+			cert.Spec.RevisionHistoryLimit = ptr.To(int32(3))
+			k8sClient := newFakeClient(cert)
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			cmCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			Expect(cmCert.Spec.RevisionHistoryLimit).To(Equal(ptr.To(int32(3))))
+
+			cert.Spec.RevisionHistoryLimit = nil
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			Expect(cmCert.Spec.RevisionHistoryLimit).To(BeNil())
+		})
+
+		It("should plan the limit change without a reissue", func() {
+			cert := newCertificate()
+			cert.Status.CertificateRef = "example-cert"
+			candidate := *cert.Spec.DeepCopy()
+			candidate.RevisionHistoryLimit = ptr.To(int32(2))
+
+			plan := NewCertificateManager(newFakeClient(cert), testScheme).PlanSpecChange(cert, candidate)
+			Expect(plan.Reissue).To(BeFalse())
+			Expect(plan.CertificateChanges).To(ConsistOf(FieldChange{
+				Field:     "spec.revisionHistoryLimit",
+				Current:   (*int32)(nil),
+				Candidate: ptr.To(int32(2)),
+			}))
+		})
+	})
+
+	Context("When additional output formats are set", func() {
+		getOutputFormats := func(k8sClient client.Client) []certmanagerv1.CertificateAdditionalOutputFormat {
+			cmCert := &certmanagerv1.Certificate{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "example-cert", Namespace: "default"}, cmCert)).To(Succeed())
+			return cmCert.Spec.AdditionalOutputFormats
+		}
+
+		It("should set the formats on the cert-manager Certificate", func() {
+			cert := newCertificate()
+			cert.Spec.AdditionalOutputFormats = []certificatev1alpha1.AdditionalOutputFormat{
+				certificatev1alpha1.AdditionalOutputFormatCombinedPEM,
+				certificatev1alpha1.AdditionalOutputFormatDER,
+			}
+			k8sClient := newFakeClient(cert)
+
+			_, _, err := NewCertificateManager(k8sClient, testScheme).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getOutputFormats(k8sClient)).To(Equal([]certmanagerv1.CertificateAdditionalOutputFormat{
+				{Type: certmanagerv1.CertificateOutputFormatCombinedPEM},
+				{Type: certmanagerv1.CertificateOutputFormatDER},
+			}))
+		})
+
+		It("should drop the formats once they are unset", func() {
+			cert := newCertificate()
+			cert.Spec.AdditionalOutputFormats = []certificatev1alpha1.AdditionalOutputFormat{
+				certificatev1alpha1.AdditionalOutputFormatDER,
+			}
+			k8sClient := newFakeClient(cert)
+			manager := NewCertificateManager(k8sClient, testScheme)
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getOutputFormats(k8sClient)).To(HaveLen(1))
+
+			cert.Spec.AdditionalOutputFormats = nil
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getOutputFormats(k8sClient)).To(BeEmpty())
+		})
+
+		It("should drop duplicate formats", func() {
+			Expect(certManagerOutputFormats([]certificatev1alpha1.AdditionalOutputFormat{
+				certificatev1alpha1.AdditionalOutputFormatDER,
+				certificatev1alpha1.AdditionalOutputFormatCombinedPEM,
+				certificatev1alpha1.AdditionalOutputFormatDER,
+			})).To(Equal([]certmanagerv1.CertificateAdditionalOutputFormat{
+				{Type: certmanagerv1.CertificateOutputFormatDER},
+				{Type: certmanagerv1.CertificateOutputFormatCombinedPEM},
+			}))
+			Expect(certManagerOutputFormats(nil)).To(BeNil())
+		})
+	})
+
+	Context("When the operator shuts down during an upload", func() {
+		var (
+			cert       *certificatev1alpha1.Certificate
+			cfProvider *fakeProvider
+		)
+
+		BeforeEach(func() {
+			cert = newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cfProvider = newFakeProvider("cloudflare", "cf-id")
+			cfProvider.block = make(chan struct{})
+			cfProvider.started = make(chan struct{}, 1)
+		})
+
+		// shutDuringUpload starts processing the certificate, cancels the reconcile and the
+		// manager once the upload is in flight, and returns when each of them finishes
+		shutDuringUpload := func(opts ...ManagerOption) (<-chan error, <-chan struct{}) {
+			tlsCert := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			manager := NewCertificateManager(newFakeClient(cert, newTLSSecret(tlsCert.certPEM, tlsCert.keyPEM)), testScheme, opts...)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+
+			runCtx, shutdown := context.WithCancel(ctx)
+			processed := make(chan error, 1)
+			go func() {
+				_, _, err := manager.ProcessCertificate(runCtx, cert)
+				processed <- err
+			}()
+			Eventually(cfProvider.started).Should(Receive())
+
+			stopped := make(chan struct{})
+			go func() {
+				defer close(stopped)
+				Expect(manager.Start(runCtx)).To(Succeed())
+			}()
+			shutdown()
+			return processed, stopped
+		}
+
+		It("should let the in-flight upload finish before stopping", func() {
+			processed, stopped := shutDuringUpload()
+			Consistently(stopped, 100*time.Millisecond).ShouldNot(BeClosed())
+
+			close(cfProvider.block)
+			Eventually(processed).Should(Receive(BeNil()))
+			Eventually(stopped).Should(BeClosed())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+		})
+
+		It("should cancel the upload once the grace period expires", func() {
+			processed, stopped := shutDuringUpload(WithShutdownGracePeriod(50 * time.Millisecond))
+
+			Eventually(stopped).Should(BeClosed())
+			Eventually(processed).Should(Receive(BeNil()))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.CloudflareUploaded).To(BeFalse())
+		})
+	})
+
+	Context("When concurrent uploads are capped per provider", func() {
+		var (
+			manager     *CertificateManager
+			awsProvider *fakeProvider
+			cfProvider  *fakeProvider
+		)
+
+		newBlockedProvider := func(name string) *fakeProvider {
+			provider := newFakeProvider(name, name+"-id")
+			provider.block = make(chan struct{})
+			provider.started = make(chan struct{}, 3)
+			return provider
+		}
+
+		BeforeEach(func() {
+			manager = NewCertificateManager(newFakeClient(), testScheme,
+				WithMaxConcurrentUploads(map[string]int{"aws": 1, "cloudflare": 2}))
+			awsProvider = newBlockedProvider("aws")
+			cfProvider = newBlockedProvider("cloudflare")
+		})
+
+		// startUploads starts n uploads to the provider and returns a channel receiving their errors
+		startUploads := func(uploadCtx context.Context, provider *fakeProvider, n int) <-chan error {
+			done := make(chan error, n)
+			for range n {
+				go func() {
+					_, err := manager.upload(uploadCtx, provider, types.CertificateData{Domain: "example.com"})
+					done <- err
+				}()
+			}
+			return done
+		}
+
+		It("should hold uploads beyond each provider's cap without blocking the other provider", func() {
+			awsDone := startUploads(ctx, awsProvider, 3)
+			cfDone := startUploads(ctx, cfProvider, 3)
+
+			Eventually(awsProvider.started).Should(HaveLen(1))
+			Eventually(cfProvider.started).Should(HaveLen(2))
+			Consistently(awsProvider.started, 100*time.Millisecond).Should(HaveLen(1))
+			Consistently(cfProvider.started, 100*time.Millisecond).Should(HaveLen(2))
+
+			close(awsProvider.block)
+			close(cfProvider.block)
+			for range 3 {
+				Eventually(awsDone).Should(Receive(BeNil()))
+				Eventually(cfDone).Should(Receive(BeNil()))
+			}
+			Expect(awsProvider.uploadCount()).To(Equal(3))
+			Expect(cfProvider.uploadCount()).To(Equal(3))
+		})
+
+		It("should not limit providers without a cap", func() {
+			s3Provider := newBlockedProvider("s3")
+			s3Done := startUploads(ctx, s3Provider, 3)
+
+			Eventually(s3Provider.started).Should(HaveLen(3))
+			close(s3Provider.block)
+			for range 3 {
+				Eventually(s3Done).Should(Receive(BeNil()))
+			}
+		})
+
+		It("should stop waiting for a slot when the reconcile is cancelled", func() {
+			awsDone := startUploads(ctx, awsProvider, 1)
+			Eventually(awsProvider.started).Should(HaveLen(1))
+
+			waitCtx, cancel := context.WithCancel(ctx)
+			waiting := startUploads(waitCtx, awsProvider, 1)
+			cancel()
+			Eventually(waiting).Should(Receive(MatchError(context.Canceled)))
+
+			close(awsProvider.block)
+			Eventually(awsDone).Should(Receive(BeNil()))
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+		})
+	})
+
+	Context("When writing to S3", func() {
+		var (
+			provider *fakeProvider
+			config   s3driver.Config
+		)
+
+		newS3Certificate := func() *certificatev1alpha1.Certificate {
+			cert := newCertificate()
+			cert.Spec.S3 = &certificatev1alpha1.S3{
+				Bucket:               "certificates",
+				SecretRef:            "aws-credentials",
+				CredentialType:       "access-key",
+				ServerSideEncryption: certificatev1alpha1.S3ServerSideEncryptionKMS,
+				KMSKeyID:             "alias/certificates",
+			}
+			return cert
+		}
+
+		BeforeEach(func() {
+			provider = newFakeProvider("s3", "s3://certificates/default/example")
+			provider.objectKeys = []string{"default/example/cert.pem", "default/example/key.pem", "default/example/chain.pem"}
+			config = s3driver.Config{}
+		})
+
+		newManager := func(objs ...client.Object) *CertificateManager {
+			m := NewCertificateManager(newFakeClient(objs...), testScheme)
+			m.newS3Driver = func(cfg s3driver.Config) types.CloudProvider {
+				config = cfg
+				return provider
+			}
+			return m
+		}
+
+		It("should write the certificate as issued and track the object keys", func() {
+			cert := newS3Certificate()
+			leaf, intermediate := generateTestChain("example.com")
+			fullChain := append(append([]byte{}, leaf.certPEM...), intermediate.certPEM...)
+			manager := newManager(cert, newTLSSecret(fullChain, leaf.keyPEM))
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+
+			Expect(config.Bucket).To(Equal("certificates"))
+			Expect(config.Prefix).To(Equal("default/example"))
+			Expect(config.CredentialType).To(Equal("access-key"))
+			Expect(config.ServerSideEncryption).To(Equal("aws:kms"))
+			Expect(config.KMSKeyID).To(Equal("alias/certificates"))
+			Expect(provider.lastUpload().FullChain()).To(Equal(fullChain))
+			Expect(provider.lastUpload().PrivateKey).To(Equal(leaf.keyPEM))
+
+			Expect(cert.Status.S3Uploaded).To(BeTrue())
+			Expect(cert.Status.S3ObjectKeys).To(Equal(provider.objectKeys))
+			Expect(cert.Status.LastUploadedCertHash).To(Equal(calculateCertHash(fullChain)))
+		})
+
+		It("should delete the objects the latest upload no longer wrote", func() {
+			cert := newS3Certificate()
+			cert.Spec.S3.Prefix = "tls/example"
+			cert.Status.S3ObjectKeys = []string{"default/example/cert.pem", "default/example/key.pem"}
+			provider.objectKeys = []string{"tls/example/cert.pem", "tls/example/key.pem"}
+			leaf := generateTestCertificate("example.com", testCertOptions{dnsNames: []string{"example.com"}})
+			manager := newManager(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Prefix).To(Equal("tls/example"))
+			Expect(provider.deletes).To(ConsistOf("default/example/cert.pem", "default/example/key.pem"))
+			Expect(cert.Status.S3ObjectKeys).To(Equal(provider.objectKeys))
+		})
+
+		It("should delete every tracked object on finalize", func() {
+			cert := newS3Certificate()
+			cert.Status.S3ObjectKeys = provider.objectKeys
+			manager := newManager(cert)
+
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(provider.deletes).To(Equal(provider.objectKeys))
+		})
+
+		It("should return failed object deletions from finalize", func() {
+			cert := newS3Certificate()
+			cert.Status.S3ObjectKeys = provider.objectKeys
+			provider.deleteErr = errors.New("access denied")
+			manager := newManager(cert)
+
+			Expect(manager.Finalize(ctx, cert)).To(MatchError(ContainSubstring("access denied")))
+			Expect(provider.deletes).To(HaveLen(3))
+		})
+
+		It("should only retry the objects whose deletion failed", func() {
+			cert := newS3Certificate()
+			cert.Status.S3ObjectKeys = provider.objectKeys
+			cert.Status.S3Uploaded = true
+			provider.deleteErrs = map[string]error{"default/example/key.pem": errors.New("access denied")}
+			manager := newManager(cert)
+
+			Expect(manager.Finalize(ctx, cert)).To(MatchError(ContainSubstring("access denied")))
+			Expect(cert.Status.S3ObjectKeys).To(Equal([]string{"default/example/key.pem"}))
+			Expect(cert.Status.S3Uploaded).To(BeTrue())
+
+			provider.deleteErrs = nil
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(provider.deletes).To(Equal(append(slices.Clone(provider.objectKeys), "default/example/key.pem")))
+			Expect(cert.Status.S3ObjectKeys).To(BeEmpty())
+			Expect(cert.Status.S3Uploaded).To(BeFalse())
+		})
+	})
+
+	Context("When a provider is disabled by the operator", func() {
+		var (
+			cfProvider  *fakeProvider
+			awsProvider *fakeProvider
+			k8sClient   client.Client
+			cert        *certificatev1alpha1.Certificate
+		)
+
+		BeforeEach(func() {
+			leaf := generateTestCertificate("example.com", testCertOptions{})
+			cert = newCertificate()
+			cert.Spec.CloudflareSecretRef = "cloudflare-credentials"
+			cert.Spec.AWS = &certificatev1alpha1.AWS{}
+			k8sClient = newFakeClient(cert, newTLSSecret(leaf.certPEM, leaf.keyPEM))
+
+			cfProvider = newFakeProvider("cloudflare", "cf-id")
+			awsProvider = newFakeProvider("aws", "arn:aws:acm:us-east-1:123456789012:certificate/example")
+		})
+
+		newManager := func(opts ...ManagerOption) *CertificateManager {
+			manager := NewCertificateManager(k8sClient, testScheme, opts...)
+			manager.newCloudflareDriver = func(cloudflaredriver.Config) types.CloudProvider { return cfProvider }
+			manager.newAWSDriver = func(awsdriver.Config) types.CloudProvider { return awsProvider }
+			return manager
+		}
+
+		It("should skip the disabled provider and report it in the ProvidersDisabled condition", func() {
+			manager := newManager(WithDisabledProviders([]string{"aws"}))
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(awsProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.AWSUploaded).To(BeFalse())
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionProvidersDisabled)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonAdministrativelyDisabled))
+			Expect(condition.Message).To(ContainSubstring("aws"))
+			Expect(condition.Message).NotTo(ContainSubstring("cloudflare"))
+		})
+
+		It("should re-upload once the provider is enabled again", func() {
+			_, _, err := newManager(WithDisabledProviders([]string{"aws"})).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert.Status.LastUploadedCertHash).NotTo(BeEmpty())
+
+			Expect(cert.Status.Providers["aws"].ReuploadPending).To(BeTrue())
+
+			_, statusUpdated, err := newManager().ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.AWSUploaded).To(BeTrue())
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+			Expect(cert.Status.Providers["aws"].ReuploadPending).To(BeFalse())
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionProvidersDisabled)).To(BeNil())
+
+			By("not uploading again on the next reconcile")
+			_, _, err = newManager().ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+		})
+
+		It("should only re-upload to the provider that is enabled again", func() {
+			_, _, err := newManager(WithDisabledProviders([]string{"aws", "cloudflare"})).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(awsProvider.uploadCount()).To(BeZero())
+
+			_, _, err = newManager(WithDisabledProviders([]string{"cloudflare"})).ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			Expect(cfProvider.uploadCount()).To(BeZero())
+			Expect(cert.Status.Providers["cloudflare"].ReuploadPending).To(BeTrue())
+
+			_, _, err = newManager().ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfProvider.uploadCount()).To(Equal(1))
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			Expect(cert.Status.CloudflareUploaded).To(BeTrue())
+		})
+
+		It("should not report providers the Certificate doesn't use", func() {
+			manager := newManager(WithDisabledProviders([]string{"s3", "remote-cluster"}))
+
+			_, _, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(awsProvider.uploadCount()).To(Equal(1))
+			Expect(meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionProvidersDisabled)).To(BeNil())
+		})
+
+		It("should still clean up the disabled provider on deletion", func() {
+			const arn = "arn:aws:acm:us-east-1:123456789012:certificate/example"
+			cert.Status.AWSCertificateARN = arn
+			cert.Status.CloudflareCertificateID = "cf-id"
+			manager := newManager(WithDisabledProviders([]string{"aws", "cloudflare"}))
+
+			Expect(manager.Finalize(ctx, cert)).To(Succeed())
+			Expect(awsProvider.deletes).To(ConsistOf(arn))
+			Expect(cfProvider.deletes).To(ConsistOf("cf-id"))
+		})
+	})
+	Context("When providers are misconfigured", func() {
+		DescribeTable("detecting enabled providers that are skipped",
+			func(mutate func(spec *certificatev1alpha1.CertificateSpec), problem string) {
+				cert := newCertificate()
+				mutate(&cert.Spec)
+
+				problems := providerMisconfigurations(cert)
+				if problem == "" {
+					Expect(problems).To(BeEmpty())
+					return
+				}
+				Expect(problems).To(ConsistOf(ContainSubstring(problem)))
+			},
+			Entry("no providers", func(*certificatev1alpha1.CertificateSpec) {}, ""),
+			Entry("cloudflare configured", func(spec *certificatev1alpha1.CertificateSpec) {
+				spec.CloudflareSecretRef = "cloudflare-credentials"
+				spec.CloudflareZoneID = "zone"
+			}, ""),
+			Entry("cloudflare disabled without a secret", func(spec *certificatev1alpha1.CertificateSpec) {
+				spec.CloudflareEnabled = ptr.To(false)
+			}, ""),
+			Entry("cloudflare enabled without a secret", func(spec *certificatev1alpha1.CertificateSpec) {
+				spec.CloudflareEnabled = ptr.To(true)
+			}, "cloudflareSecretRef is not set"),
+			Entry("cloudflare without a zone", func(spec *certificatev1alpha1.CertificateSpec) {
+				spec.CloudflareSecretRef = "cloudflare-credentials"
+			}, "cloudflareZoneID is not"),
+			Entry("aws with the default credential chain", func(spec *certificatev1alpha1.CertificateSpec) {
+				spec.AWS = &certificatev1alpha1.AWS{}
+			}, ""),
+			Entry("aws access key without a secret", func(spec *certificatev1alpha1.CertificateSpec) {
+				spec.AWS = &certificatev1alpha1.AWS{CredentialType: "access-key"}
+			}, "aws.secretRef is not set"),
+			Entry("aws accounts without aws", func(spec *certificatev1alpha1.CertificateSpec) {
+				spec.AWSAssumeRoleARNs = []string{"arn:aws:iam::111111111111:role/importer"}
+			}, "awsAssumeRoleARNs is set but aws is not"),
+			Entry("s3 access key without a secret", func(spec *certificatev1alpha1.CertificateSpec) {
+				spec.S3 = &certificatev1alpha1.S3{Bucket: "certificates", CredentialType: "access-key"}
+			}, "s3.secretRef is not set"),
+		)
+
+		It("should report misconfigured providers in the ProvidersConfigured condition", func() {
+			cert := newCertificate()
+			cert.Generation = 3
+			cert.Spec.CloudflareEnabled = ptr.To(true)
+			cert.Spec.AWS = &certificatev1alpha1.AWS{CredentialType: "access-key"}
+			manager := NewCertificateManager(newFakeClient(cert), testScheme)
+
+			_, statusUpdated, err := manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdated).To(BeTrue())
+
+			condition := meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionProvidersConfigured)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonMisconfigured))
+			Expect(condition.Message).To(ContainSubstring("cloudflareSecretRef is not set"))
+			Expect(condition.Message).To(ContainSubstring("aws.secretRef is not set"))
+			Expect(condition.ObservedGeneration).To(Equal(int64(3)))
+
+			By("fixing the spec")
+			cert.Spec.CloudflareEnabled = nil
+			cert.Spec.AWS.SecretRef = "aws-credentials"
+			_, _, err = manager.ProcessCertificate(ctx, cert)
+			Expect(err).NotTo(HaveOccurred())
+
+			condition = meta.FindStatusCondition(cert.Status.Conditions, certificatev1alpha1.ConditionProvidersConfigured)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(certificatev1alpha1.ReasonConfigured))
+		})
+	})
+})
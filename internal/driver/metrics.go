@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// pendingIssuanceSeconds reports how long each certificate has been waiting for cert-manager
+// to issue it, so stuck issuance can be alerted on. It is zero once the certificate is issued.
+var pendingIssuanceSeconds = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "certificate_pending_issuance_seconds",
+		Help: "Seconds the certificate has been waiting for issuance, zero when issued.",
+	},
+	[]string{"namespace", "name"},
+)
+
+// issuanceDurationSeconds observes how long cert-manager took to issue certificates, from
+// status.issuanceStartedAt until the operator found the issued certificate, by issuer
+var issuanceDurationSeconds = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "certificate_issuance_duration_seconds",
+		Help:    "Seconds from the start of issuance until the certificate was issued.",
+		Buckets: prometheus.ExponentialBuckets(5, 2, 11),
+	},
+	[]string{"issuer"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(pendingIssuanceSeconds, issuanceDurationSeconds)
+}
+
+// recordPendingIssuance sets the pending issuance gauge from status.issuanceStartedAt
+func recordPendingIssuance(cert *certificatev1alpha1.Certificate, now time.Time) {
+	if cert.Status.IssuanceStartedAt == nil {
+		return
+	}
+	pendingIssuanceSeconds.WithLabelValues(cert.Namespace, cert.Name).
+		Set(now.Sub(cert.Status.IssuanceStartedAt.Time).Seconds())
+}
+
+// recordIssuanceDuration observes the issuance that started at status.issuanceStartedAt,
+// labeled with status.activeIssuer
+func recordIssuanceDuration(cert *certificatev1alpha1.Certificate, now time.Time) {
+	if cert.Status.IssuanceStartedAt == nil {
+		return
+	}
+	issuanceDurationSeconds.WithLabelValues(cert.Status.ActiveIssuer).
+		Observe(now.Sub(cert.Status.IssuanceStartedAt.Time).Seconds())
+}
+
+// resetPendingIssuance reports the certificate as issued
+func resetPendingIssuance(cert *certificatev1alpha1.Certificate) {
+	pendingIssuanceSeconds.WithLabelValues(cert.Namespace, cert.Name).Set(0)
+}
+
+// deletePendingIssuance removes the gauge of a deleted certificate
+func deletePendingIssuance(cert *certificatev1alpha1.Certificate) {
+	pendingIssuanceSeconds.DeleteLabelValues(cert.Namespace, cert.Name)
+}
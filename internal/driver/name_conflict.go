@@ -0,0 +1,39 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// setNameConflictCondition records a name taken by an unmanaged resource in the NameConflict
+// condition, removing it when conflict is nil, and reports whether the conditions changed
+func setNameConflictCondition(cert *certificatev1alpha1.Certificate, conflict error) bool {
+	if conflict == nil {
+		return meta.RemoveStatusCondition(&cert.Status.Conditions, certificatev1alpha1.ConditionNameConflict)
+	}
+	return meta.SetStatusCondition(&cert.Status.Conditions, metav1.Condition{
+		Type:               certificatev1alpha1.ConditionNameConflict,
+		Status:             metav1.ConditionTrue,
+		Reason:             certificatev1alpha1.ReasonUnmanagedResource,
+		Message:            conflict.Error(),
+		ObservedGeneration: cert.Generation,
+	})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"software.sslmate.com/src/go-pkcs12"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// pkcs12PasswordKey is the key holding the keystore password in spec.pkcs12PasswordSecretRef
+const pkcs12PasswordKey = "password"
+
+// pkcs12Password reads the keystore password from spec.pkcs12PasswordSecretRef.
+// Keystores without a referenced password secret are decoded with an empty password.
+func (m *CertificateManager) pkcs12Password(ctx context.Context, cert *certificatev1alpha1.Certificate) (string, error) {
+	if cert.Spec.PKCS12PasswordSecretRef == "" {
+		return "", nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := m.k8sClient.Get(ctx, client.ObjectKey{
+		Name:      cert.Spec.PKCS12PasswordSecretRef,
+		Namespace: cert.Namespace,
+	}, secret); err != nil {
+		return "", fmt.Errorf("failed to get PKCS#12 password secret: %w", err)
+	}
+
+	password, ok := secret.Data[pkcs12PasswordKey]
+	if !ok {
+		return "", fmt.Errorf("%s not found in PKCS#12 password secret %s", pkcs12PasswordKey, cert.Spec.PKCS12PasswordSecretRef)
+	}
+	return string(password), nil
+}
+
+// decodePKCS12 decodes a PKCS#12 keystore into a PEM leaf certificate, the PEM
+// intermediates of the keystore, and a PKCS#8 PEM private key
+func decodePKCS12(data []byte, password string) (certPEM, chainPEM, keyPEM []byte, err error) {
+	privateKey, leaf, caCerts, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+			return nil, nil, nil, fmt.Errorf("incorrect PKCS#12 password")
+		}
+		return nil, nil, nil, fmt.Errorf("failed to decode PKCS#12 keystore: %w", err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to encode PKCS#12 private key: %w", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})
+	for _, caCert := range caCerts {
+		chainPEM = append(chainPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})...)
+	}
+	return certPEM, chainPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
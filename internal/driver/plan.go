@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// FieldChange is a field of the cert-manager Certificate that a spec change would change
+type FieldChange struct {
+	// Field is the path of the field in the cert-manager Certificate, e.g. spec.dnsNames
+	Field string
+	// Current and Candidate are the values before and after the change
+	Current   any
+	Candidate any
+}
+
+// SpecChangePlan describes what replacing the spec of a Certificate would change
+type SpecChangePlan struct {
+	// CertificateChanges are the changes to the cert-manager Certificate, in a stable order
+	CertificateChanges []FieldChange
+	// Reissue is true when cert-manager would issue a new certificate
+	Reissue bool
+	// ReuploadProviders are the providers the certificate would be uploaded to again, in a
+	// stable order
+	ReuploadProviders []string
+	// UploadsPaused is true when the re-uploads wait until spec.uploadsPaused is unset
+	UploadsPaused bool
+}
+
+// PlanSpecChange reports what replacing the spec of cert with candidate would change,
+// following the decisions ProcessCertificate makes without changing anything.
+// Providers are only re-uploaded once the Certificate was uploaded, the first upload of a
+// new Certificate isn't part of the plan.
+func (m *CertificateManager) PlanSpecChange(
+	cert *certificatev1alpha1.Certificate,
+	candidate certificatev1alpha1.CertificateSpec,
+) SpecChangePlan {
+	candidateCert := cert.DeepCopy()
+	candidateCert.Spec = candidate
+
+	current, next := m.certManagerSpec(cert), m.certManagerSpec(candidateCert)
+	plan := SpecChangePlan{CertificateChanges: certManagerSpecChanges(current, next)}
+
+	// cert-manager reissues when the requested names change, the operator forces it when
+	// the issuer or subject of an existing Certificate changes
+	if cert.Status.CertificateRef != "" {
+		plan.Reissue = current.Domain != next.Domain ||
+			formatIssuer(current.IssuerKind, current.IssuerName) != formatIssuer(next.IssuerKind, next.IssuerName) ||
+			!equality.Semantic.DeepEqual(current.Subject, next.Subject)
+	}
+
+	// A reissued certificate is uploaded everywhere, as are changed upload settings
+	uploaded := cert.Status.LastUploadedCertHash != ""
+	specChanged := cert.Status.LastUploadedSpecHash != "" &&
+		cert.Status.LastUploadedSpecHash != calculateUploadSpecHash(candidateCert, m.providerTags(candidateCert))
+	if uploaded && (plan.Reissue || specChanged) {
+		for _, provider := range usedProviders(candidateCert) {
+			if !m.providerDisabled(provider) {
+				plan.ReuploadProviders = append(plan.ReuploadProviders, provider)
+			}
+		}
+		plan.UploadsPaused = len(plan.ReuploadProviders) > 0 && *candidateCert.EffectiveSpec().UploadsPaused
+	}
+	return plan
+}
+
+// certManagerSpecChanges returns the fields of the cert-manager Certificate that differ
+// between current and next, treating empty and unset values as equal
+func certManagerSpecChanges(current, next types.CertSpec) []FieldChange {
+	var changes []FieldChange
+	add := func(field string, currentValue, nextValue any) {
+		if !equality.Semantic.DeepEqual(currentValue, nextValue) {
+			changes = append(changes, FieldChange{Field: field, Current: currentValue, Candidate: nextValue})
+		}
+	}
+	add("metadata.labels", current.Labels, next.Labels)
+	add("metadata.annotations", current.Annotations, next.Annotations)
+	add("spec.dnsNames", []string{current.Domain}, []string{next.Domain})
+	add("spec.issuerRef", formatIssuer(current.IssuerKind, current.IssuerName), formatIssuer(next.IssuerKind, next.IssuerName))
+	add("spec.subject", current.Subject, next.Subject)
+	add("spec.privateKey.rotationPolicy", current.PrivateKeyRotationPolicy, next.PrivateKeyRotationPolicy)
+	add("spec.revisionHistoryLimit", current.RevisionHistoryLimit, next.RevisionHistoryLimit)
+	add("spec.additionalOutputFormats", current.AdditionalOutputFormats, next.AdditionalOutputFormats)
+	return changes
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// providerMisconfigurations returns why providers enabled in the spec are skipped.
+// The CRD rejects most of these on admission, this catches Certificates created before
+// the rules existed.
+func providerMisconfigurations(cert *certificatev1alpha1.Certificate) []string {
+	spec := cert.EffectiveSpec()
+
+	var problems []string
+	if *spec.CloudflareEnabled {
+		if spec.CloudflareSecretRef == "" {
+			problems = append(problems, "cloudflareEnabled is true but cloudflareSecretRef is not set, nothing is uploaded to Cloudflare")
+		} else if spec.CloudflareZoneID == "" {
+			problems = append(problems, "cloudflareSecretRef is set but cloudflareZoneID is not, Cloudflare uploads fail")
+		}
+	}
+	if spec.AkamaiEnabled && (spec.AkamaiSecretRef == "" || spec.AkamaiEnrollmentID == 0) {
+		problems = append(problems, "akamaiEnabled is true but akamaiSecretRef or akamaiEnrollmentID is not set, Akamai CPS uploads fail")
+	}
+	if spec.AWS != nil && spec.AWS.CredentialType == "access-key" && spec.AWS.SecretRef == "" {
+		problems = append(problems, "aws.credentialType is access-key but aws.secretRef is not set, AWS ACM imports fail")
+	}
+	if len(spec.AWSAssumeRoleARNs) > 0 && spec.AWS == nil {
+		problems = append(problems, "awsAssumeRoleARNs is set but aws is not, nothing is imported into the other AWS accounts")
+	}
+	if awsAdoptionWithoutAWS(cert) {
+		problems = append(problems, adoptAWSARNAnnotation+" is set but aws is not, the AWS ACM certificate is not adopted")
+	}
+	if spec.S3 != nil && spec.S3.CredentialType == "access-key" && spec.S3.SecretRef == "" {
+		problems = append(problems, "s3.credentialType is access-key but s3.secretRef is not set, S3 uploads fail")
+	}
+	return problems
+}
+
+// setProvidersConfiguredCondition records the provider misconfigurations in the
+// ProvidersConfigured condition and reports whether the condition changed
+func setProvidersConfiguredCondition(cert *certificatev1alpha1.Certificate, problems []string) bool {
+	condition := metav1.Condition{
+		Type:               certificatev1alpha1.ConditionProvidersConfigured,
+		Status:             metav1.ConditionTrue,
+		Reason:             certificatev1alpha1.ReasonConfigured,
+		Message:            "All enabled providers are configured",
+		ObservedGeneration: cert.Generation,
+	}
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = certificatev1alpha1.ReasonMisconfigured
+		condition.Message = strings.Join(problems, "; ")
+	}
+	return meta.SetStatusCondition(&cert.Status.Conditions, condition)
+}
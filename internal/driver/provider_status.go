@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// updateProviderStatus applies update to the entry of provider in status.providers and
+// derives the legacy per-provider fields from the entries
+func updateProviderStatus(cert *certificatev1alpha1.Certificate, provider string, update func(*certificatev1alpha1.ProviderStatus)) {
+	migrateProviderStatus(cert)
+	if cert.Status.Providers == nil {
+		cert.Status.Providers = make(map[string]certificatev1alpha1.ProviderStatus)
+	}
+	status := cert.Status.Providers[provider]
+	update(&status)
+	cert.Status.Providers[provider] = status
+	deriveLegacyProviderStatus(cert)
+}
+
+// recordProviderUpload records a successful upload of the certificate to provider
+func recordProviderUpload(cert *certificatev1alpha1.Certificate, provider, identifier string, now time.Time) {
+	updateProviderStatus(cert, provider, func(status *certificatev1alpha1.ProviderStatus) {
+		status.Uploaded = true
+		status.Identifier = identifier
+		status.LastUploadedTime = &metav1.Time{Time: now}
+		status.LastError = ""
+		status.ReuploadPending = false
+	})
+}
+
+// recordProviderError records a failed upload of the certificate to provider. The
+// certificate uploaded before, if any, is still there, so the rest is kept.
+func recordProviderError(cert *certificatev1alpha1.Certificate, provider string, err error) {
+	updateProviderStatus(cert, provider, func(status *certificatev1alpha1.ProviderStatus) {
+		status.LastError = err.Error()
+	})
+}
+
+// markProviderNotUploaded records that the copy at provider has to be uploaded again,
+// keeping its identifier so the upload replaces it
+func markProviderNotUploaded(cert *certificatev1alpha1.Certificate, provider string) {
+	updateProviderStatus(cert, provider, func(status *certificatev1alpha1.ProviderStatus) {
+		status.Uploaded = false
+	})
+}
+
+// reuploadPending reports whether provider has to be uploaded to again because it was
+// disabled by the operator
+func reuploadPending(cert *certificatev1alpha1.Certificate, provider string) bool {
+	return cert.Status.Providers[provider].ReuploadPending
+}
+
+// setProviderIdentifier records the identifier of the certificate at provider
+func setProviderIdentifier(cert *certificatev1alpha1.Certificate, provider, identifier string) {
+	updateProviderStatus(cert, provider, func(status *certificatev1alpha1.ProviderStatus) {
+		status.Identifier = identifier
+	})
+}
+
+// setACMStatus records the state AWS ACM reported for the imported certificate, clearing
+// it when it couldn't be read
+func setACMStatus(cert *certificatev1alpha1.Certificate, details *types.ACMCertificateDetails) {
+	updateProviderStatus(cert, awsProviderName, func(status *certificatev1alpha1.ProviderStatus) {
+		if details == nil {
+			status.ACM = nil
+			return
+		}
+		status.ACM = &certificatev1alpha1.ACMCertificateStatus{
+			Status:             details.Status,
+			Type:               details.Type,
+			RenewalEligibility: details.RenewalEligibility,
+			InUseByCount:       int32(details.InUseByCount),
+			FailureReason:      details.FailureReason,
+		}
+		if !details.ImportedAt.IsZero() {
+			status.ACM.ImportedAt = &metav1.Time{Time: details.ImportedAt}
+		}
+	})
+}
+
+// forgetProvider removes provider from status.providers once its copy was deleted
+func forgetProvider(cert *certificatev1alpha1.Certificate, provider string) {
+	migrateProviderStatus(cert)
+	delete(cert.Status.Providers, provider)
+	deriveLegacyProviderStatus(cert)
+}
+
+// deriveLegacyProviderStatus sets the flat per-provider status fields from status.providers
+func deriveLegacyProviderStatus(cert *certificatev1alpha1.Certificate) {
+	cloudflare := cert.Status.Providers[cloudflareProviderName]
+	cert.Status.CloudflareUploaded = cloudflare.Uploaded
+	cert.Status.CloudflareCertificateID = cloudflare.Identifier
+
+	aws := cert.Status.Providers[awsProviderName]
+	cert.Status.AWSUploaded = aws.Uploaded
+	cert.Status.AWSCertificateARN = aws.Identifier
+
+	cert.Status.S3Uploaded = cert.Status.Providers[s3ProviderName].Uploaded
+}
+
+// migrateProviderStatus fills status.providers from the flat per-provider fields of
+// Certificates uploaded before it existed, and reports whether the status changed
+func migrateProviderStatus(cert *certificatev1alpha1.Certificate) bool {
+	if cert.Status.Providers != nil {
+		return false
+	}
+
+	legacy := map[string]certificatev1alpha1.ProviderStatus{
+		cloudflareProviderName: {Uploaded: cert.Status.CloudflareUploaded, Identifier: cert.Status.CloudflareCertificateID},
+		awsProviderName:        {Uploaded: cert.Status.AWSUploaded, Identifier: cert.Status.AWSCertificateARN},
+		s3ProviderName:         {Uploaded: cert.Status.S3Uploaded},
+	}
+	for provider, status := range legacy {
+		if !status.Uploaded && status.Identifier == "" {
+			continue
+		}
+		if status.Uploaded && cert.Status.LastUploadedTime != nil {
+			status.LastUploadedTime = cert.Status.LastUploadedTime.DeepCopy()
+		}
+		if cert.Status.Providers == nil {
+			cert.Status.Providers = make(map[string]certificatev1alpha1.ProviderStatus)
+		}
+		cert.Status.Providers[provider] = status
+	}
+	return cert.Status.Providers != nil
+}
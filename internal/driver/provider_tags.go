@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+const (
+	// maxProviderTags is the number of tags providers accept next to the ManagedBy and Domain
+	// tags the operator sets, matching AWS ACM's limit of 50 tags per certificate
+	maxProviderTags = 48
+
+	// maxTagKeyLength and maxTagValueLength are AWS ACM's limits on tag keys and values
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// providerTags returns the tags set on the certificate at providers: the labels of cert
+// starting with the tag label prefix, keyed by the rest of the label key, overridden by
+// spec.providerTags. Label tags are sanitized for provider constraints; those that would
+// exceed the tag limit are dropped in key order.
+func (m *CertificateManager) providerTags(cert *certificatev1alpha1.Certificate) map[string]string {
+	if m.tagLabelPrefix == "" {
+		return cert.Spec.ProviderTags
+	}
+
+	tags := maps.Clone(cert.Spec.ProviderTags)
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	for _, label := range slices.Sorted(maps.Keys(cert.Labels)) {
+		name, ok := strings.CutPrefix(label, m.tagLabelPrefix)
+		if !ok {
+			continue
+		}
+		key, value, ok := sanitizeTag(name, cert.Labels[label])
+		if !ok {
+			continue
+		}
+		if _, exists := tags[key]; exists {
+			continue
+		}
+		if len(tags) >= maxProviderTags {
+			break
+		}
+		tags[key] = value
+	}
+	return tags
+}
+
+// sanitizeTag fits a tag derived from a label to provider constraints: characters providers
+// reject are replaced with underscores and overlong keys and values are truncated. Keys that
+// are empty or reserved by AWS or the operator are reported as not ok.
+func sanitizeTag(key, value string) (string, string, bool) {
+	key = truncateRunes(sanitizeTagChars(key), maxTagKeyLength)
+	value = truncateRunes(sanitizeTagChars(value), maxTagValueLength)
+	if key == "" || strings.HasPrefix(strings.ToLower(key), "aws:") || key == "ManagedBy" || key == "Domain" {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+// sanitizeTagChars replaces the characters AWS ACM rejects in tags with underscores. Tags
+// may hold letters, numbers, spaces, and _ . : / = + - @
+func sanitizeTagChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Zs, r) || strings.ContainsRune("_.:/=+-@", r) {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"slices"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// CredentialsNamespace resolves the namespace of the provider credential Secrets of cert.
+// Precedence: spec.credentialsNamespace, the operator credentials namespace, the Certificate namespace.
+// A spec.credentialsNamespace rejected by ValidateCredentialsNamespace is ignored, so a
+// Certificate can't read the credentials of another tenant's namespace.
+func CredentialsNamespace(cert *certificatev1alpha1.Certificate, operatorNamespace string) string {
+	if cert.Spec.CredentialsNamespace != "" && ValidateCredentialsNamespace(cert, operatorNamespace) == nil {
+		return cert.Spec.CredentialsNamespace
+	}
+	if operatorNamespace != "" {
+		return operatorNamespace
+	}
+	return cert.Namespace
+}
+
+// ValidateCredentialsNamespace checks that spec.credentialsNamespace of cert is either the
+// Certificate's own namespace or the operator credentials namespace
+func ValidateCredentialsNamespace(cert *certificatev1alpha1.Certificate, operatorNamespace string) error {
+	namespace := cert.Spec.CredentialsNamespace
+	if namespace == "" || namespace == cert.Namespace || namespace == operatorNamespace {
+		return nil
+	}
+	if operatorNamespace == "" {
+		return fmt.Errorf("credentialsNamespace %q must be the Certificate's namespace %q", namespace, cert.Namespace)
+	}
+	return fmt.Errorf("credentialsNamespace %q must be the Certificate's namespace %q or the operator credentials namespace %q",
+		namespace, cert.Namespace, operatorNamespace)
+}
+
+// ReferencedSecrets returns the Secrets cert reads besides its TLS Secret: provider
+// credentials, remote cluster kubeconfigs, the PKCS#12 password, the trusted CA, and the key Secret.
+// operatorNamespace is the operator credentials namespace, empty for none.
+func ReferencedSecrets(cert *certificatev1alpha1.Certificate, operatorNamespace string) []k8stypes.NamespacedName {
+	credentialsNamespace := CredentialsNamespace(cert, operatorNamespace)
+
+	var secrets []k8stypes.NamespacedName
+	add := func(namespace, name string) {
+		secret := k8stypes.NamespacedName{Namespace: namespace, Name: name}
+		if name != "" && !slices.Contains(secrets, secret) {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	add(credentialsNamespace, cert.Spec.CloudflareSecretRef)
+	add(credentialsNamespace, cert.Spec.AkamaiSecretRef)
+	if cert.Spec.AWS != nil {
+		add(credentialsNamespace, cert.Spec.AWS.SecretRef)
+	}
+	if cert.Spec.S3 != nil {
+		add(credentialsNamespace, cert.Spec.S3.SecretRef)
+	}
+	for _, cluster := range cert.Spec.RemoteClusters {
+		add(credentialsNamespace, cluster.KubeconfigSecretRef)
+	}
+	// The keystore password, trusted CA, and key Secret are read from the Certificate's
+	// namespace, like the TLS Secret
+	add(cert.Namespace, cert.Spec.PKCS12PasswordSecretRef)
+	add(cert.Namespace, cert.Spec.TrustedCASecretRef)
+	add(cert.Namespace, cert.Spec.KeySecretRef)
+	return secrets
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotecluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/tae2089/certificate-operator/internal/driver/retry"
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+const (
+	// kubeconfigKey is the key of the kubeconfig in the kubeconfig Secret
+	kubeconfigKey = "kubeconfig"
+
+	// managedByLabel marks remote Secrets written by the operator
+	managedByLabel = "app.kubernetes.io/managed-by"
+
+	// managedByValue is the managedByLabel value of remote Secrets written by the operator
+	managedByValue = "certificate-operator"
+)
+
+// Driver implements the CloudProvider interface by replicating the TLS Secret into a remote cluster
+type Driver struct {
+	client              client.Client
+	kubeconfigSecretRef string
+	namespace           string
+	targetNamespace     string
+	secretName          string
+	backoff             retry.Backoff
+
+	// newRemoteClient creates the remote cluster client from a kubeconfig, overridable for testing
+	newRemoteClient func(kubeconfig []byte) (client.Client, error)
+}
+
+// Config holds remote cluster driver configuration
+type Config struct {
+	Client              client.Client // Client of the local cluster, used to read the kubeconfig Secret
+	KubeconfigSecretRef string
+	Namespace           string // Namespace of the kubeconfig Secret
+	TargetNamespace     string // Namespace of the replicated Secret in the remote cluster
+	SecretName          string // Name of the replicated Secret in the remote cluster
+	MaxRetries          int    // Retries for throttled or failed (5xx) writes
+}
+
+// NewDriver creates a new remote cluster driver
+func NewDriver(cfg Config) *Driver {
+	return &Driver{
+		client:              cfg.Client,
+		kubeconfigSecretRef: cfg.KubeconfigSecretRef,
+		namespace:           cfg.Namespace,
+		targetNamespace:     cfg.TargetNamespace,
+		secretName:          cfg.SecretName,
+		backoff:             retry.Backoff{MaxRetries: cfg.MaxRetries},
+		newRemoteClient:     newClientFromKubeconfig,
+	}
+}
+
+// Name returns the provider name
+func (d *Driver) Name() string {
+	return "remote-cluster"
+}
+
+// Upload writes the certificate into a TLS Secret in the remote cluster.
+// The identifier of the result is the Secret as "namespace/name".
+func (d *Driver) Upload(ctx context.Context, certData drivertypes.CertificateData) (drivertypes.UploadResult, error) {
+	log := logf.FromContext(ctx)
+
+	remote, err := d.getRemoteClient(ctx)
+	if err != nil {
+		return drivertypes.UploadResult{}, err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      d.secretName,
+			Namespace: d.targetNamespace,
+		},
+	}
+
+	var op controllerutil.OperationResult
+	err = d.backoff.Do(ctx, func(ctx context.Context) error {
+		var writeErr error
+		op, writeErr = controllerutil.CreateOrUpdate(ctx, remote, secret, func() error {
+			// Never take over a Secret the operator didn't create
+			if secret.ResourceVersion != "" && secret.Labels[managedByLabel] != managedByValue {
+				return fmt.Errorf("secret %s/%s exists and is not managed by %s", secret.Namespace, secret.Name, managedByValue)
+			}
+			if secret.Labels == nil {
+				secret.Labels = make(map[string]string)
+			}
+			secret.Labels[managedByLabel] = managedByValue
+			secret.Type = corev1.SecretTypeTLS
+			secret.Data = map[string][]byte{
+				corev1.TLSCertKey:       certData.FullChain(),
+				corev1.TLSPrivateKeyKey: certData.PrivateKey,
+			}
+			return nil
+		})
+		return classifyError(writeErr)
+	})
+	if err != nil {
+		return drivertypes.UploadResult{}, fmt.Errorf("failed to write secret to remote cluster: %w", err)
+	}
+
+	identifier := secret.Namespace + "/" + secret.Name
+	log.V(1).Info("Replicated TLS secret to remote cluster", "secret", identifier, "operation", op)
+	return drivertypes.UploadResult{
+		Identifier: identifier,
+	}, nil
+}
+
+// Delete deletes the replicated Secret, identified as "namespace/name", from the remote cluster
+func (d *Driver) Delete(ctx context.Context, identifier string) error {
+	namespace, name, ok := strings.Cut(identifier, "/")
+	if !ok || namespace == "" || name == "" {
+		return fmt.Errorf("invalid remote secret reference %q, expected namespace/name", identifier)
+	}
+
+	remote, err := d.getRemoteClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	if err := remote.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete secret from remote cluster: %w", classifyError(err))
+	}
+
+	return nil
+}
+
+// getRemoteClient creates a client for the remote cluster from the kubeconfig Secret
+func (d *Driver) getRemoteClient(ctx context.Context) (client.Client, error) {
+	kubeconfigSecret := &corev1.Secret{}
+	if err := d.client.Get(ctx, types.NamespacedName{
+		Name:      d.kubeconfigSecretRef,
+		Namespace: d.namespace,
+	}, kubeconfigSecret); err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig secret: %w", err)
+	}
+
+	kubeconfig := kubeconfigSecret.Data[kubeconfigKey]
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("%s not found in kubeconfig secret", kubeconfigKey)
+	}
+
+	remote, err := d.newRemoteClient(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote cluster client: %w", err)
+	}
+
+	return remote, nil
+}
+
+// newClientFromKubeconfig creates a client for the cluster described by a kubeconfig. The
+// kubeconfig comes from a user-supplied Secret, so it may only carry inline credentials.
+func newClientFromKubeconfig(kubeconfig []byte) (client.Client, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	if err := validateKubeconfig(config); err != nil {
+		return nil, err
+	}
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	return client.New(restConfig, client.Options{})
+}
+
+// validateKubeconfig rejects kubeconfigs that run commands or read files in the operator
+// pod, such as exec plugins or a tokenFile pointing at the operator's own ServiceAccount
+// token. Only inline tokens, basic auth, and certificate data are allowed.
+func validateKubeconfig(config *clientcmdapi.Config) error {
+	for name, authInfo := range config.AuthInfos {
+		switch {
+		case authInfo.Exec != nil:
+			return fmt.Errorf("kubeconfig user %q: exec credential plugins are not allowed", name)
+		case authInfo.AuthProvider != nil:
+			return fmt.Errorf("kubeconfig user %q: auth providers are not allowed", name)
+		case authInfo.TokenFile != "":
+			return fmt.Errorf("kubeconfig user %q: tokenFile is not allowed, set token instead", name)
+		case authInfo.ClientCertificate != "":
+			return fmt.Errorf("kubeconfig user %q: client-certificate is not allowed, set client-certificate-data instead", name)
+		case authInfo.ClientKey != "":
+			return fmt.Errorf("kubeconfig user %q: client-key is not allowed, set client-key-data instead", name)
+		}
+	}
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return fmt.Errorf("kubeconfig cluster %q: certificate-authority is not allowed, set certificate-authority-data instead", name)
+		}
+	}
+	return nil
+}
+
+// classifyError marks throttling and server-side API server failures as retriable
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if apierrors.IsTooManyRequests(err) || apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) || apierrors.IsInternalError(err) || apierrors.IsServiceUnavailable(err) {
+		return drivertypes.NewRetriableError(err)
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotecluster
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+var _ = Describe("Driver", func() {
+	var (
+		ctx         = context.Background()
+		localClient client.Client
+		remote      client.Client
+		kubeconfigs [][]byte
+	)
+
+	BeforeEach(func() {
+		localClient = fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "edge-kubeconfig", Namespace: "default"},
+			Data:       map[string][]byte{"kubeconfig": []byte("edge")},
+		}).Build()
+		remote = fake.NewClientBuilder().Build()
+		kubeconfigs = nil
+	})
+
+	newTestDriver := func(kubeconfigSecretRef string) *Driver {
+		d := NewDriver(Config{
+			Client:              localClient,
+			KubeconfigSecretRef: kubeconfigSecretRef,
+			Namespace:           "default",
+			TargetNamespace:     "ingress",
+			SecretName:          "example-tls",
+		})
+		d.newRemoteClient = func(kubeconfig []byte) (client.Client, error) {
+			kubeconfigs = append(kubeconfigs, kubeconfig)
+			return remote, nil
+		}
+		return d
+	}
+
+	getRemoteSecret := func() (*corev1.Secret, error) {
+		secret := &corev1.Secret{}
+		err := remote.Get(ctx, client.ObjectKey{Namespace: "ingress", Name: "example-tls"}, secret)
+		return secret, err
+	}
+
+	certData := drivertypes.CertificateData{
+		Domain:      "example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	}
+
+	It("should write the TLS secret into the remote cluster", func() {
+		result, err := newTestDriver("edge-kubeconfig").Upload(ctx, certData)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Identifier).To(Equal("ingress/example-tls"))
+		Expect(kubeconfigs).To(Equal([][]byte{[]byte("edge")}))
+
+		secret, err := getRemoteSecret()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+		Expect(secret.Labels).To(HaveKeyWithValue(managedByLabel, managedByValue))
+		Expect(secret.Data).To(Equal(map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")}))
+	})
+
+	It("should write the leaf followed by the chain to tls.crt", func() {
+		bundled := certData
+		bundled.Chain = []byte("-intermediate")
+		_, err := newTestDriver("edge-kubeconfig").Upload(ctx, bundled)
+		Expect(err).NotTo(HaveOccurred())
+
+		secret, err := getRemoteSecret()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data["tls.crt"]).To(Equal([]byte("cert-intermediate")))
+	})
+
+	It("should update the remote secret on renewal", func() {
+		d := newTestDriver("edge-kubeconfig")
+		_, err := d.Upload(ctx, certData)
+		Expect(err).NotTo(HaveOccurred())
+
+		renewed := certData
+		renewed.Certificate = []byte("renewed-cert")
+		renewed.ExistingID = "ingress/example-tls"
+		_, err = d.Upload(ctx, renewed)
+		Expect(err).NotTo(HaveOccurred())
+
+		secret, err := getRemoteSecret()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data["tls.crt"]).To(Equal([]byte("renewed-cert")))
+	})
+
+	It("should not overwrite a remote secret it doesn't manage", func() {
+		Expect(remote.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-tls", Namespace: "ingress"},
+			Data:       map[string][]byte{"tls.crt": []byte("foreign")},
+		})).To(Succeed())
+
+		_, err := newTestDriver("edge-kubeconfig").Upload(ctx, certData)
+		Expect(err).To(MatchError(ContainSubstring("not managed by certificate-operator")))
+
+		secret, err := getRemoteSecret()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data["tls.crt"]).To(Equal([]byte("foreign")))
+	})
+
+	It("should fail when the kubeconfig secret is missing", func() {
+		_, err := newTestDriver("missing").Upload(ctx, certData)
+		Expect(err).To(MatchError(ContainSubstring("failed to get kubeconfig secret")))
+		Expect(kubeconfigs).To(BeEmpty())
+	})
+
+	It("should delete the remote secret", func() {
+		d := newTestDriver("edge-kubeconfig")
+		result, err := d.Upload(ctx, certData)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(d.Delete(ctx, result.Identifier)).To(Succeed())
+		_, err = getRemoteSecret()
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("ignoring a secret that is already gone")
+		Expect(d.Delete(ctx, result.Identifier)).To(Succeed())
+	})
+
+	It("should reject a malformed secret reference on delete", func() {
+		Expect(newTestDriver("edge-kubeconfig").Delete(ctx, "example-tls")).To(MatchError(ContainSubstring("expected namespace/name")))
+	})
+
+	It("should classify throttling as retriable", func() {
+		Expect(drivertypes.IsRetriable(classifyError(apierrors.NewTooManyRequests("slow down", 1)))).To(BeTrue())
+		Expect(drivertypes.IsRetriable(classifyError(apierrors.NewForbidden(corev1.Resource("secrets"), "example-tls", nil)))).To(BeFalse())
+		Expect(classifyError(nil)).To(BeNil())
+	})
+})
+
+var _ = Describe("newClientFromKubeconfig", func() {
+	kubeconfig := func(user, cluster string) []byte {
+		return []byte(`apiVersion: v1
+kind: Config
+current-context: edge
+contexts:
+- name: edge
+  context:
+    cluster: edge
+    user: edge
+clusters:
+- name: edge
+  cluster:
+    server: https://edge.example.com:6443
+` + cluster + `
+users:
+- name: edge
+  user:
+` + user)
+	}
+
+	It("should accept inline credentials", func() {
+		_, err := newClientFromKubeconfig(kubeconfig("    token: remote-token\n", ""))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should refuse an exec credential plugin", func() {
+		_, err := newClientFromKubeconfig(kubeconfig(`    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: /bin/sh
+      args: ["-c", "cat /var/run/secrets/kubernetes.io/serviceaccount/token"]
+`, ""))
+		Expect(err).To(MatchError(ContainSubstring("exec credential plugins are not allowed")))
+	})
+
+	DescribeTable("should refuse credentials read from files in the operator pod",
+		func(user, cluster, message string) {
+			_, err := newClientFromKubeconfig(kubeconfig(user, cluster))
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("auth provider", "    auth-provider:\n      name: oidc\n", "", "auth providers are not allowed"),
+		Entry("token file", "    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token\n", "", "tokenFile is not allowed"),
+		Entry("client certificate", "    client-certificate: /etc/tls/tls.crt\n", "", "client-certificate is not allowed"),
+		Entry("client key", "    client-key: /etc/tls/tls.key\n", "", "client-key is not allowed"),
+		Entry("certificate authority", "    token: remote-token\n",
+			"    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt", "certificate-authority is not allowed"),
+	)
+})
@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"crypto/x509"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// renewalUploadDelay returns how long the re-upload of the renewed certificate in certPEM
+// is held back. Each certificate is assigned an offset within window after it became valid,
+// derived from its expiry and the Certificate's name, so certificates renewed together are
+// re-uploaded spread over the window. The offset doesn't change between reconciles. It
+// returns zero when window is zero, the offset has passed, or the certificate can't be parsed.
+func renewalUploadDelay(cert *certificatev1alpha1.Certificate, certPEM []byte, window time.Duration, now time.Time) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+	der := leafCertificateDER(certPEM)
+	if der == nil {
+		return 0
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return 0
+	}
+
+	// Certificates from one batch share their expiry, the name tells them apart
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(cert.Namespace + "/" + cert.Name + "/" + strconv.FormatInt(leaf.NotAfter.Unix(), 10)))
+	offset := time.Duration(h.Sum64() % uint64(window))
+
+	return max(leaf.NotBefore.Add(offset).Sub(now), 0)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+const (
+	// DefaultBaseDelay is the backoff before the first retry
+	DefaultBaseDelay = 500 * time.Millisecond
+
+	// DefaultMaxDelay caps the backoff between retries
+	DefaultMaxDelay = 10 * time.Second
+)
+
+// Backoff configures a bounded retry with capped, jittered exponential backoff.
+// Only errors marked with drivertypes.RetriableError are retried.
+type Backoff struct {
+	// MaxRetries is the number of retries after the first attempt. Zero disables retries.
+	MaxRetries int
+
+	// BaseDelay is the backoff before the first retry. Defaults to DefaultBaseDelay.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff between retries. Defaults to DefaultMaxDelay.
+	MaxDelay time.Duration
+}
+
+// Do runs op until it succeeds, returns a non-retriable error, or the retries are exhausted
+func (b Backoff) Do(ctx context.Context, op func(ctx context.Context) error) error {
+	log := logf.FromContext(ctx)
+
+	for attempt := 0; ; attempt++ {
+		err := op(ctx)
+		if err == nil || !drivertypes.IsRetriable(err) || attempt >= b.MaxRetries {
+			return err
+		}
+
+		delay := b.delay(attempt)
+		log.V(1).Info("Retrying provider call after retriable error",
+			"attempt", attempt+1, "maxRetries", b.MaxRetries, "delay", delay, "error", err.Error())
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns a full-jitter backoff for the given attempt
+func (b Backoff) delay(attempt int) time.Duration {
+	base := b.BaseDelay
+	if base <= 0 {
+		base = DefaultBaseDelay
+	}
+	maxDelay := b.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxDelay
+	}
+
+	backoff := base << min(attempt, 30)
+	if backoff <= 0 || backoff > maxDelay {
+		backoff = maxDelay
+	}
+	return time.Duration(rand.Int64N(int64(backoff)) + 1)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	awsdriver "github.com/tae2089/certificate-operator/internal/driver/aws"
+	"github.com/tae2089/certificate-operator/internal/driver/retry"
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// Names of the objects written under the key prefix
+const (
+	certObjectName  = "cert.pem"
+	keyObjectName   = "key.pem"
+	chainObjectName = "chain.pem"
+)
+
+// pemContentType is the content type of the written objects
+const pemContentType = "application/x-pem-file"
+
+// pemObject is a PEM file written to the bucket
+type pemObject struct {
+	name string
+	body []byte
+}
+
+// s3API is the subset of the S3 client used by the driver
+type s3API interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// Driver implements the CloudProvider interface by writing the certificate, private key,
+// and chain as PEM files to an S3 bucket
+type Driver struct {
+	credentials          awsdriver.Credentials
+	bucket               string
+	prefix               string
+	region               string
+	serverSideEncryption string
+	kmsKeyID             string
+	backoff              retry.Backoff
+
+	// newS3Client creates the S3 client, overridable for testing
+	newS3Client func(cfg aws.Config) s3API
+}
+
+// Config holds S3 driver configuration
+type Config struct {
+	Client               client.Client
+	CredentialType       string
+	SecretRef            string // Empty string means use IRSA/Instance Profile
+	Namespace            string
+	Bucket               string
+	Prefix               string // Key prefix of the objects, without a trailing slash
+	Region               string // Bucket region, overrides the region of the credentials
+	ServerSideEncryption string // AES256 or aws:kms, empty for the bucket's default encryption
+	KMSKeyID             string // KMS key for aws:kms encryption, empty for the AWS managed key
+	MaxRetries           int    // Retries for throttled or failed (5xx) writes
+	DefaultRegion        string // Region used when neither Region, the Secret, nor the environment sets one
+}
+
+// NewDriver creates a new S3 driver
+func NewDriver(cfg Config) *Driver {
+	return &Driver{
+		credentials: awsdriver.Credentials{
+			Client:         cfg.Client,
+			CredentialType: cfg.CredentialType,
+			SecretRef:      cfg.SecretRef,
+			Namespace:      cfg.Namespace,
+			DefaultRegion:  cfg.DefaultRegion,
+		},
+		bucket:               cfg.Bucket,
+		prefix:               cfg.Prefix,
+		region:               cfg.Region,
+		serverSideEncryption: cfg.ServerSideEncryption,
+		kmsKeyID:             cfg.KMSKeyID,
+		backoff:              retry.Backoff{MaxRetries: cfg.MaxRetries},
+		newS3Client: func(cfg aws.Config) s3API {
+			return s3.NewFromConfig(cfg)
+		},
+	}
+}
+
+// Name returns the provider name
+func (d *Driver) Name() string {
+	return "s3"
+}
+
+// Upload writes cert.pem, key.pem, and, when the certificate has intermediates, chain.pem
+// under the key prefix. The identifier of the result is the prefix as an s3:// URL and
+// ObjectKeys lists the written keys.
+func (d *Driver) Upload(ctx context.Context, certData drivertypes.CertificateData) (drivertypes.UploadResult, error) {
+	log := logf.FromContext(ctx)
+
+	s3Client, err := d.client(ctx)
+	if err != nil {
+		return drivertypes.UploadResult{}, err
+	}
+
+	objects := []pemObject{
+		{name: certObjectName, body: certData.Certificate},
+		{name: keyObjectName, body: certData.PrivateKey},
+	}
+	if len(certData.Chain) > 0 {
+		objects = append(objects, pemObject{name: chainObjectName, body: certData.Chain})
+	}
+
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		key := path.Join(d.prefix, object.name)
+		input := &s3.PutObjectInput{
+			Bucket:      aws.String(d.bucket),
+			Key:         aws.String(key),
+			ContentType: aws.String(pemContentType),
+		}
+		if d.serverSideEncryption != "" {
+			input.ServerSideEncryption = s3types.ServerSideEncryption(d.serverSideEncryption)
+		}
+		if d.kmsKeyID != "" {
+			input.SSEKMSKeyId = aws.String(d.kmsKeyID)
+		}
+
+		// Retry throttled and server-side failures with backoff
+		err := d.backoff.Do(ctx, func(ctx context.Context) error {
+			// A failed attempt consumes the body, so every attempt gets a fresh reader
+			input.Body = bytes.NewReader(object.body)
+			_, putErr := s3Client.PutObject(ctx, input)
+			return awsdriver.ClassifyError(putErr)
+		})
+		if err != nil {
+			return drivertypes.UploadResult{}, fmt.Errorf("failed to write s3://%s/%s: %w", d.bucket, key, err)
+		}
+		keys = append(keys, key)
+	}
+
+	log.V(1).Info("Wrote certificate objects to S3", "bucket", d.bucket, "keys", keys)
+	return drivertypes.UploadResult{
+		Identifier: fmt.Sprintf("s3://%s/%s", d.bucket, d.prefix),
+		ObjectKeys: keys,
+	}, nil
+}
+
+// Delete deletes the object with the identifier as key from the bucket
+func (d *Driver) Delete(ctx context.Context, identifier string) error {
+	s3Client, err := d.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(identifier),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete s3://%s/%s: %w", d.bucket, identifier, awsdriver.ClassifyError(err))
+	}
+	return nil
+}
+
+// client creates an S3 client with the configured credentials and region
+func (d *Driver) client(ctx context.Context) (s3API, error) {
+	cfg, err := awsdriver.LoadConfig(ctx, d.credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	if d.region != "" {
+		cfg.Region = d.region
+	}
+	if cfg.Region == "" {
+		return nil, awsdriver.RegionNotSetError(d.credentials, "spec.s3.region")
+	}
+	return d.newS3Client(cfg), nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	drivertypes "github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// putObject is an object written to the fake S3 client
+type putObject struct {
+	body                 string
+	contentType          string
+	serverSideEncryption s3types.ServerSideEncryption
+	kmsKeyID             string
+}
+
+// fakeS3 stores written objects by bucket and key, returning the queued put errors in order first.
+type fakeS3 struct {
+	objects  map[string]putObject
+	deleted  []string
+	putErrs  []error
+	putCalls int
+	region   string
+}
+
+func (f *fakeS3) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.putCalls++
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.putErrs) > 0 {
+		err := f.putErrs[0]
+		f.putErrs = f.putErrs[1:]
+		return nil, err
+	}
+	f.objects[aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key)] = putObject{
+		body:                 string(body),
+		contentType:          aws.ToString(params.ContentType),
+		serverSideEncryption: params.ServerSideEncryption,
+		kmsKeyID:             aws.ToString(params.SSEKMSKeyId),
+	}
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) DeleteObject(_ context.Context, params *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	key := aws.ToString(params.Bucket) + "/" + aws.ToString(params.Key)
+	delete(f.objects, key)
+	f.deleted = append(f.deleted, key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+// testPEM encodes data as a PEM certificate block
+func testPEM(data string) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte(data)})
+}
+
+var _ = Describe("Driver", func() {
+	var (
+		ctx = context.Background()
+		api *fakeS3
+	)
+
+	newTestDriver := func(cfg Config) *Driver {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials", Namespace: "default"},
+			Data: map[string][]byte{
+				"access-key-id":     []byte("AKIAEXAMPLE"),
+				"secret-access-key": []byte("secret"),
+				"region":            []byte("us-east-1"),
+			},
+		}
+		cfg.Client = fake.NewClientBuilder().WithObjects(secret).Build()
+		cfg.CredentialType = "access-key"
+		cfg.SecretRef = "aws-credentials"
+		cfg.Namespace = "default"
+		if cfg.Bucket == "" {
+			cfg.Bucket = "certificates"
+		}
+
+		d := NewDriver(cfg)
+		d.backoff.BaseDelay = time.Millisecond
+		d.newS3Client = func(cfg aws.Config) s3API {
+			api.region = cfg.Region
+			return api
+		}
+		return d
+	}
+
+	BeforeEach(func() {
+		api = &fakeS3{objects: map[string]putObject{}}
+	})
+
+	It("should write the leaf, key, and chain under the prefix", func() {
+		leaf, intermediate, root := testPEM("leaf"), testPEM("intermediate"), testPEM("root")
+
+		result, err := newTestDriver(Config{Prefix: "default/example"}).Upload(ctx, drivertypes.CertificateData{
+			Domain:      "example.com",
+			Certificate: leaf,
+			Chain:       append(append([]byte{}, intermediate...), root...),
+			PrivateKey:  []byte("private-key"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Identifier).To(Equal("s3://certificates/default/example"))
+		Expect(result.ObjectKeys).To(Equal([]string{
+			"default/example/cert.pem", "default/example/key.pem", "default/example/chain.pem",
+		}))
+
+		Expect(api.objects).To(HaveLen(3))
+		Expect(api.objects["certificates/default/example/cert.pem"].body).To(Equal(string(leaf)))
+		Expect(api.objects["certificates/default/example/key.pem"].body).To(Equal("private-key"))
+		Expect(api.objects["certificates/default/example/chain.pem"].body).To(Equal(string(intermediate) + string(root)))
+		Expect(api.objects["certificates/default/example/cert.pem"].contentType).To(Equal(pemContentType))
+		Expect(api.objects["certificates/default/example/key.pem"].serverSideEncryption).To(BeEmpty())
+		Expect(api.region).To(Equal("us-east-1"))
+	})
+
+	It("should not write a chain for a certificate without intermediates", func() {
+		result, err := newTestDriver(Config{Prefix: "certs"}).Upload(ctx, drivertypes.CertificateData{
+			Certificate: testPEM("leaf"),
+			PrivateKey:  []byte("private-key"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ObjectKeys).To(ConsistOf("certs/cert.pem", "certs/key.pem"))
+		Expect(api.objects).NotTo(HaveKey("certificates/certs/chain.pem"))
+	})
+
+	It("should request server-side encryption with the KMS key on every object", func() {
+		_, err := newTestDriver(Config{
+			Prefix:               "certs",
+			Region:               "eu-central-1",
+			ServerSideEncryption: "aws:kms",
+			KMSKeyID:             "arn:aws:kms:eu-central-1:123456789012:key/test",
+		}).Upload(ctx, drivertypes.CertificateData{
+			Certificate: testPEM("leaf"),
+			Chain:       testPEM("intermediate"),
+			PrivateKey:  []byte("private-key"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(api.objects).To(HaveLen(3))
+		for key, object := range api.objects {
+			Expect(object.serverSideEncryption).To(Equal(s3types.ServerSideEncryptionAwsKms), key)
+			Expect(object.kmsKeyID).To(Equal("arn:aws:kms:eu-central-1:123456789012:key/test"), key)
+		}
+		Expect(api.region).To(Equal("eu-central-1"))
+	})
+
+	It("should retry throttled writes with the full body", func() {
+		api.putErrs = []error{&smithy.GenericAPIError{Code: "SlowDown", Message: "Please reduce your request rate"}}
+
+		_, err := newTestDriver(Config{Prefix: "certs", MaxRetries: 2}).Upload(ctx, drivertypes.CertificateData{
+			Certificate: testPEM("leaf"),
+			PrivateKey:  []byte("private-key"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(api.putCalls).To(Equal(3))
+		Expect(api.objects["certificates/certs/cert.pem"].body).To(Equal(string(testPEM("leaf"))))
+	})
+
+	It("should delete an object by key", func() {
+		Expect(newTestDriver(Config{}).Delete(ctx, "certs/key.pem")).To(Succeed())
+		Expect(api.deleted).To(ConsistOf("certificates/certs/key.pem"))
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// leafSANs returns the DNS and IP SANs of the leaf certificate in certPEM.
+// It returns false when the bundle has no parsable certificate, so unparseable data is
+// left for the providers to reject.
+func leafSANs(certPEM []byte) ([]string, bool) {
+	der := leafCertificateDER(certPEM)
+	if der == nil {
+		return nil, false
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, false
+	}
+
+	sans := append([]string{}, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans, true
+}
+
+// sansInclude reports whether sans contains domain. DNS names are compared case-insensitively
+// and literally, so a wildcard domain requires the same wildcard SAN.
+func sansInclude(sans []string, domain string) bool {
+	domainIP := net.ParseIP(domain)
+	for _, san := range sans {
+		if domainIP != nil {
+			if sanIP := net.ParseIP(san); sanIP != nil && sanIP.Equal(domainIP) {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(strings.TrimSuffix(san, "."), strings.TrimSuffix(domain, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// setSANMismatchCondition records whether the leaf SANs include spec.domain in the
+// SANMismatch condition and reports whether the condition changed
+func setSANMismatchCondition(cert *certificatev1alpha1.Certificate, sans []string, matched bool) bool {
+	condition := metav1.Condition{
+		Type:               certificatev1alpha1.ConditionSANMismatch,
+		Status:             metav1.ConditionFalse,
+		Reason:             certificatev1alpha1.ReasonSANsMatch,
+		Message:            fmt.Sprintf("Certificate SANs include %s", cert.Spec.Domain),
+		ObservedGeneration: cert.Generation,
+	}
+	if !matched {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = certificatev1alpha1.ReasonDomainNotInSANs
+		condition.Message = fmt.Sprintf("Certificate SANs [%s] don't include %s, upload skipped",
+			strings.Join(sans, ", "), cert.Spec.Domain)
+	}
+	return meta.SetStatusCondition(&cert.Status.Conditions, condition)
+}
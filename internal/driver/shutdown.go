@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// upload uploads the certificate to provider. The upload is not cancelled with ctx when the
+// operator shuts down, so it can finish within the shutdown grace period instead of leaving
+// the provider half-written. It first waits for a slot when the provider's concurrent uploads
+// are capped.
+func (m *CertificateManager) upload(ctx context.Context, provider types.CloudProvider, certData types.CertificateData) (types.UploadResult, error) {
+	release, err := m.acquireUploadSlot(ctx, provider.Name())
+	if err != nil {
+		return types.UploadResult{}, err
+	}
+	defer release()
+
+	m.uploadsMu.Lock()
+	if m.shuttingDown {
+		m.uploadsMu.Unlock()
+		return provider.Upload(ctx, certData)
+	}
+	m.uploads.Add(1)
+	m.uploadsMu.Unlock()
+	defer m.uploads.Done()
+
+	uploadCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(m.uploadCtx, cancel)
+	defer stop()
+
+	return provider.Upload(uploadCtx, certData)
+}
+
+// acquireUploadSlot waits until an upload to the provider is allowed by its concurrency
+// limit and returns the function releasing the slot
+func (m *CertificateManager) acquireUploadSlot(ctx context.Context, provider string) (func(), error) {
+	slots, ok := m.uploadSlots[provider]
+	if !ok {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	default:
+	}
+
+	logf.FromContext(ctx).V(1).Info("Waiting for a provider upload slot", "provider", provider, "limit", cap(slots))
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a %s upload slot: %w", provider, ctx.Err())
+	}
+}
+
+// Start implements manager.Runnable. It blocks until ctx is cancelled, then waits up to the
+// shutdown grace period for in-flight uploads to finish before cancelling them.
+func (m *CertificateManager) Start(ctx context.Context) error {
+	<-ctx.Done()
+	log := logf.FromContext(ctx)
+
+	m.uploadsMu.Lock()
+	m.shuttingDown = true
+	m.uploadsMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.uploads.Wait()
+		close(done)
+	}()
+
+	log.Info("Waiting for in-flight uploads to finish", "gracePeriod", m.shutdownGracePeriod)
+	timer := time.NewTimer(m.shutdownGracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		log.Info("In-flight uploads finished")
+	case <-timer.C:
+		log.Info("Shutdown grace period expired, cancelling in-flight uploads")
+	}
+	m.cancelUploads()
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+const (
+	// maxStatusMessageLength caps free-form status messages such as issuance details and
+	// replication errors, which may embed arbitrarily long upstream error bodies
+	maxStatusMessageLength = 1024
+
+	// MaxStatusSize is the largest serialized Certificate status the operator writes.
+	// It keeps the object well below etcd's request size limit.
+	MaxStatusSize = 64 * 1024
+)
+
+// truncationSuffix marks a status message that was shortened
+const truncationSuffix = "... (truncated)"
+
+// BoundStatus truncates oversized messages in status and rejects the status if its
+// serialized size still exceeds MaxStatusSize. Certificate data is never stored in
+// status, only hashes and fingerprints, so the remaining fields are small.
+func BoundStatus(status *certificatev1alpha1.CertificateStatus) error {
+	status.IssuanceDetail = truncateMessage(status.IssuanceDetail)
+	for i := range status.RemoteClusters {
+		status.RemoteClusters[i].Error = truncateMessage(status.RemoteClusters[i].Error)
+	}
+	for provider, providerStatus := range status.Providers {
+		providerStatus.LastError = truncateMessage(providerStatus.LastError)
+		status.Providers[provider] = providerStatus
+	}
+	if status.Finalization != nil {
+		for i := range status.Finalization.Failed {
+			status.Finalization.Failed[i].Error = truncateMessage(status.Finalization.Failed[i].Error)
+		}
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to serialize status: %w", err)
+	}
+	if len(data) > MaxStatusSize {
+		return fmt.Errorf("status size %d bytes exceeds the limit of %d bytes", len(data), MaxStatusSize)
+	}
+	return nil
+}
+
+// truncateMessage shortens message to maxStatusMessageLength bytes without splitting a UTF-8 rune
+func truncateMessage(message string) string {
+	if len(message) <= maxStatusMessageLength {
+		return message
+	}
+
+	end := maxStatusMessageLength - len(truncationSuffix)
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end] + truncationSuffix
+}
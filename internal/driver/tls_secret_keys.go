@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// tlsSecretKeys returns the data keys the certificate and private key are read from in the
+// TLS Secret. The CRD rejects invalid keys on admission, this catches Certificates created
+// before the rules existed.
+func tlsSecretKeys(cert *certificatev1alpha1.Certificate) (types.TLSSecretKeys, error) {
+	spec := cert.EffectiveSpec()
+	keys := types.TLSSecretKeys{Certificate: spec.CertDataKey, PrivateKey: spec.KeyDataKey}
+
+	if errs := validation.IsConfigMapKey(keys.Certificate); len(errs) > 0 {
+		return types.TLSSecretKeys{}, fmt.Errorf("invalid certDataKey %q: %s", keys.Certificate, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsConfigMapKey(keys.PrivateKey); len(errs) > 0 {
+		return types.TLSSecretKeys{}, fmt.Errorf("invalid keyDataKey %q: %s", keys.PrivateKey, strings.Join(errs, "; "))
+	}
+	if keys.Certificate == keys.PrivateKey {
+		return types.TLSSecretKeys{}, fmt.Errorf("certDataKey and keyDataKey must differ, both are %q", keys.Certificate)
+	}
+	return keys, nil
+}
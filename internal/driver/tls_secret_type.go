@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// tlsSecretTypeMismatch returns the type of the TLS Secret when it holds the certificate and
+// key under tls.crt and tls.key but isn't of type kubernetes.io/tls, so ingress controllers
+// reject it. Secrets read with other keys, a separate key Secret, or a PKCS#12 keystore are
+// meant for other consumers and are not checked.
+func tlsSecretTypeMismatch(cert *certificatev1alpha1.Certificate, tlsSecret *types.TLSSecret, keys types.TLSSecretKeys) (corev1.SecretType, bool) {
+	if cert.Spec.KeySecretRef != "" || len(tlsSecret.PKCS12) > 0 ||
+		keys.Certificate != corev1.TLSCertKey || keys.PrivateKey != corev1.TLSPrivateKeyKey {
+		return "", false
+	}
+	secretType := tlsSecret.Secret.Type
+	if secretType == "" {
+		// The API server defaults an unset type to Opaque
+		secretType = corev1.SecretTypeOpaque
+	}
+	return secretType, secretType != corev1.SecretTypeTLS
+}
+
+// setTLSSecretTypeCondition records a TLS Secret of the wrong type in the TLSSecretTypeMismatch
+// condition, removing it when the type is right, and reports whether the conditions changed
+func setTLSSecretTypeCondition(cert *certificatev1alpha1.Certificate, secretName string, secretType corev1.SecretType, mismatch bool) bool {
+	if !mismatch {
+		return meta.RemoveStatusCondition(&cert.Status.Conditions, certificatev1alpha1.ConditionTLSSecretTypeMismatch)
+	}
+	return meta.SetStatusCondition(&cert.Status.Conditions, metav1.Condition{
+		Type:   certificatev1alpha1.ConditionTLSSecretTypeMismatch,
+		Status: metav1.ConditionTrue,
+		Reason: certificatev1alpha1.ReasonNotKubernetesTLS,
+		Message: fmt.Sprintf("TLS Secret %s is of type %s instead of %s, ingress controllers may reject it. "+
+			"The type can't be changed, recreate the Secret to fix it", secretName, secretType, corev1.SecretTypeTLS),
+		ObservedGeneration: cert.Generation,
+	})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// trustedCAKey is the key holding the PEM CA bundle in spec.trustedCASecretRef
+const trustedCAKey = "ca.crt"
+
+// trustedCAPool reads the CA bundle from spec.trustedCASecretRef
+func (m *CertificateManager) trustedCAPool(ctx context.Context, cert *certificatev1alpha1.Certificate) (*x509.CertPool, error) {
+	secret := &corev1.Secret{}
+	if err := m.k8sClient.Get(ctx, client.ObjectKey{
+		Name:      cert.Spec.TrustedCASecretRef,
+		Namespace: cert.Namespace,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get trusted CA secret: %w", err)
+	}
+
+	caPEM, ok := secret.Data[trustedCAKey]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in trusted CA secret %s", trustedCAKey, cert.Spec.TrustedCASecretRef)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s of trusted CA secret %s", trustedCAKey, cert.Spec.TrustedCASecretRef)
+	}
+	return pool, nil
+}
+
+// verifyTrustedChain verifies that the leaf certificate in certPEM chains to the CA in
+// spec.trustedCASecretRef, using the other certificates in certPEM as intermediates
+func (m *CertificateManager) verifyTrustedChain(ctx context.Context, cert *certificatev1alpha1.Certificate, certPEM []byte) error {
+	roots, err := m.trustedCAPool(ctx, cert)
+	if err != nil {
+		return err
+	}
+
+	var leaf *x509.Certificate
+	intermediates := x509.NewCertPool()
+	for rest := certPEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		parsed, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		if leaf == nil {
+			leaf = parsed
+			continue
+		}
+		intermediates.AddCert(parsed)
+	}
+	if leaf == nil {
+		return errors.New("no certificate found in the TLS Secret")
+	}
+
+	// Key usages are left to the providers, only the issuer is checked here
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+// setChainVerificationCondition records the result of verifyTrustedChain in the
+// ChainVerificationFailed condition and reports whether the condition changed
+func setChainVerificationCondition(cert *certificatev1alpha1.Certificate, verifyErr error) bool {
+	condition := metav1.Condition{
+		Type:               certificatev1alpha1.ConditionChainVerificationFailed,
+		Status:             metav1.ConditionFalse,
+		Reason:             certificatev1alpha1.ReasonChainTrusted,
+		Message:            fmt.Sprintf("Certificate chains to the CA in %s", cert.Spec.TrustedCASecretRef),
+		ObservedGeneration: cert.Generation,
+	}
+	if verifyErr != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = certificatev1alpha1.ReasonUntrustedChain
+		condition.Message = fmt.Sprintf("Certificate doesn't chain to the CA in %s, upload skipped: %v",
+			cert.Spec.TrustedCASecretRef, verifyErr)
+	}
+	return meta.SetStatusCondition(&cert.Status.Conditions, condition)
+}
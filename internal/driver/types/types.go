@@ -18,6 +18,11 @@ package types
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"time"
 
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -37,50 +42,211 @@ type CloudProvider interface {
 	Name() string
 }
 
+// ErrVerificationMismatch is returned by Verifier.Verify when the provider's copy of the
+// certificate differs from the uploaded one
+var ErrVerificationMismatch = errors.New("provider copy of the certificate does not match the upload")
+
+// ErrUploadPending is wrapped by the error CloudProvider.Upload returns when the provider
+// accepted the upload but needs time to finish it. The returned UploadResult identifies
+// what was created so far, and the next upload continues it.
+var ErrUploadPending = errors.New("upload is pending at the provider")
+
+// Verifier is implemented by cloud providers that can read an uploaded certificate back
+type Verifier interface {
+	// Verify compares the certificate stored under identifier with the uploaded certData and
+	// returns an error wrapping ErrVerificationMismatch when they differ
+	Verify(ctx context.Context, identifier string, certData CertificateData) error
+}
+
+// ErrSecretConflict is returned by CertManager.EnsureCertificate when another cert-manager
+// Certificate already issues into the requested Secret
+var ErrSecretConflict = errors.New("secret is the target of another cert-manager Certificate")
+
+// ErrNameConflict is returned by CertManager.EnsureCertificate when a cert-manager
+// Certificate or Secret with the requested name exists and wasn't created for the owner
+var ErrNameConflict = errors.New("name is taken by a resource the operator does not manage")
+
 // CertManager manages cert-manager resources in Kubernetes
 type CertManager interface {
 	// EnsureCertificate creates or updates a cert-manager Certificate
 	EnsureCertificate(ctx context.Context, spec CertSpec) (*CertResult, error)
 
 	// GetTLSSecret retrieves and validates a TLS Secret
-	GetTLSSecret(ctx context.Context, name, namespace string) (*TLSSecret, error)
+	GetTLSSecret(ctx context.Context, name, namespace string, keys TLSSecretKeys) (*TLSSecret, error)
 
-	// WaitForReadiness checks if Certificate is ready
-	WaitForReadiness(ctx context.Context, certName, namespace string) (ctrl.Result, error)
+	// WaitForReadiness checks if Certificate is ready and describes pending issuance progress
+	WaitForReadiness(ctx context.Context, certName, namespace string) (ctrl.Result, string, error)
 }
 
 // CertificateData holds certificate information for upload
 type CertificateData struct {
 	Domain      string
-	Certificate []byte
+	Certificate []byte // PEM leaf certificate
+	Chain       []byte // PEM intermediates following the leaf, nil when there are none
 	PrivateKey  []byte
-	ExistingID  string // For renewals (ARN for AWS, ID for Cloudflare)
+	ExistingID  string            // For renewals (ARN for AWS, ID for Cloudflare)
+	Tags        map[string]string // Extra tags, for providers that support tagging
+}
+
+// FullChain returns the leaf followed by the intermediates, as cert-manager writes tls.crt
+func (c CertificateData) FullChain() []byte {
+	return joinChain(c.Certificate, c.Chain)
 }
 
 // UploadResult contains cloud provider upload results
 type UploadResult struct {
-	Identifier string // ARN for AWS, certificate ID for Cloudflare
+	Identifier string   // ARN for AWS, certificate ID for Cloudflare
+	ObjectKeys []string // Keys of the objects written by object storage providers such as S3
+
+	// ACM is the state AWS ACM reports for the imported certificate, nil for other providers
+	// or when it couldn't be read
+	ACM *ACMCertificateDetails
+}
+
+// ACMCertificateDetails is the state AWS ACM reports for an imported certificate
+type ACMCertificateDetails struct {
+	Status             string // e.g. ISSUED or EXPIRED
+	Type               string // IMPORTED for certificates uploaded by the operator
+	RenewalEligibility string // INELIGIBLE for imported certificates, ACM doesn't renew them
+	InUseByCount       int    // Number of AWS resources using the certificate, e.g. load balancers
+	ImportedAt         time.Time
+	FailureReason      string // Why ACM rejected the certificate, empty unless Status is FAILED
 }
 
 // CertSpec contains specification for creating a Certificate
 type CertSpec struct {
-	Name              string
-	Namespace         string
-	Domain            string
-	ClusterIssuerName string
-	SecretName        string
-	OwnerReferences   []metav1.OwnerReference
+	Name            string
+	Namespace       string
+	Domain          string
+	IssuerKind      string // ClusterIssuer or Issuer, defaults to ClusterIssuer
+	IssuerName      string
+	SecretName      string
+	OwnerReferences []metav1.OwnerReference
+
+	// PrivateKeyRotationPolicy is Never or Always, empty for cert-manager's default
+	PrivateKeyRotationPolicy string
+	// RevisionHistoryLimit is how many CertificateRequests cert-manager keeps, nil for its default
+	RevisionHistoryLimit *int32
+	// Subject is the X.509 subject of the certificate, nil for none
+	Subject *certmanagerv1.X509Subject
+	// AdditionalOutputFormats are the extra formats written to the Secret, nil for none
+	AdditionalOutputFormats []certmanagerv1.CertificateAdditionalOutputFormat
+	// ManagedBy is the app.kubernetes.io/managed-by label value, defaults to certificate-operator
+	ManagedBy string
+	// Labels and Annotations are set on the Certificate, the ones set by a previous call and
+	// no longer listed are removed
+	Labels      map[string]string
+	Annotations map[string]string
 }
 
 // CertResult contains the result of Certificate creation
 type CertResult struct {
 	Certificate *certmanagerv1.Certificate
 	Name        string
+
+	// IssuerChanged is true when an existing Certificate was switched to another issuer
+	// and reissuance was triggered
+	IssuerChanged bool
+	// SubjectChanged is true when the subject of an existing Certificate was changed
+	// and reissuance was triggered
+	SubjectChanged bool
+}
+
+// TLSSecretKeys are the data keys of the certificate and private key in a TLS Secret
+type TLSSecretKeys struct {
+	Certificate string // Defaults to tls.crt
+	PrivateKey  string // Defaults to tls.key
 }
 
 // TLSSecret holds TLS certificate and key data
 type TLSSecret struct {
 	Secret      *corev1.Secret
-	Certificate []byte
+	Certificate []byte // PEM leaf certificate
+	Chain       []byte // PEM intermediates following the leaf, nil when there are none
 	PrivateKey  []byte
+	PKCS12      []byte // PKCS#12 keystore, set instead of Certificate/PrivateKey when the secret has no PEM data
+}
+
+// FullChain returns the leaf followed by the intermediates, as cert-manager writes tls.crt
+func (s *TLSSecret) FullChain() []byte {
+	return joinChain(s.Certificate, s.Chain)
+}
+
+// SplitCertificateChain splits a PEM bundle into the leaf certificate and the certificates
+// after it. Bytes that don't contain a certificate are returned as the leaf unchanged.
+func SplitCertificateChain(certPEM []byte) (leaf, chain []byte) {
+	rest := certPEM
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if leaf == nil {
+			leaf = pem.EncodeToMemory(block)
+		} else {
+			chain = append(chain, pem.EncodeToMemory(block)...)
+		}
+	}
+	if leaf == nil {
+		return certPEM, nil
+	}
+	return leaf, chain
+}
+
+// joinChain returns a new PEM bundle of the leaf followed by the chain
+func joinChain(leaf, chain []byte) []byte {
+	return append(append([]byte{}, leaf...), chain...)
+}
+
+// RetriableError marks a provider failure that is expected to succeed when retried later,
+// such as rate limiting or a transient server-side error.
+type RetriableError struct {
+	Err error
+}
+
+// NewRetriableError wraps err as a RetriableError
+func NewRetriableError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RetriableError{Err: err}
+}
+
+// Error returns the wrapped error message
+func (e *RetriableError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *RetriableError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetriable reports whether err, or any error it wraps, is a RetriableError
+func IsRetriable(err error) bool {
+	var retriableErr *RetriableError
+	return errors.As(err, &retriableErr)
+}
+
+// CertificateFingerprints returns the hex SHA-256 fingerprint of every certificate in a PEM
+// bundle, in bundle order
+func CertificateFingerprints(certPEM []byte) []string {
+	var fingerprints []string
+	rest := certPEM
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return fingerprints
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		sum := sha256.Sum256(block.Bytes)
+		fingerprints = append(fingerprints, hex.EncodeToString(sum[:]))
+	}
 }
@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// postUploadWebhookAnnotation is a URL the manager POSTs an uploadEvent to after the
+// certificate was uploaded, e.g. to invalidate a CDN cache
+const postUploadWebhookAnnotation = "certificate.println.kr/post-upload-webhook"
+
+// defaultPostUploadWebhookTimeout bounds a post-upload webhook call, which runs within the reconcile
+const defaultPostUploadWebhookTimeout = 10 * time.Second
+
+// uploadEvent is the JSON body POSTed to the post-upload webhook
+type uploadEvent struct {
+	Namespace                 string            `json:"namespace"`
+	Name                      string            `json:"name"`
+	Domain                    string            `json:"domain"`
+	CertHash                  string            `json:"certHash"`
+	UploadedAt                time.Time         `json:"uploadedAt"`
+	CloudflareCertificateID   string            `json:"cloudflareCertificateID,omitempty"`
+	AWSCertificateARN         string            `json:"awsCertificateARN,omitempty"`
+	AWSAccountCertificateARNs map[string]string `json:"awsAccountCertificateARNs,omitempty"`
+	S3ObjectKeys              []string          `json:"s3ObjectKeys,omitempty"`
+}
+
+// notifyPostUploadWebhook POSTs the upload result to the Certificate's post-upload webhook, if
+// any. It is best-effort: failures are logged and never fail the reconcile.
+func (m *CertificateManager) notifyPostUploadWebhook(ctx context.Context, cert *certificatev1alpha1.Certificate) {
+	hookURL, ok := cert.Annotations[postUploadWebhookAnnotation]
+	if !ok {
+		return
+	}
+	log := logf.FromContext(ctx).WithValues("webhook", hookURL)
+
+	if err := m.postUploadEvent(ctx, hookURL, newUploadEvent(cert)); err != nil {
+		log.Error(err, "Post-upload webhook failed")
+		return
+	}
+	log.Info("Notified post-upload webhook")
+}
+
+// newUploadEvent describes the last upload recorded in the Certificate's status
+func newUploadEvent(cert *certificatev1alpha1.Certificate) uploadEvent {
+	event := uploadEvent{
+		Namespace:                 cert.Namespace,
+		Name:                      cert.Name,
+		Domain:                    cert.Spec.Domain,
+		CertHash:                  cert.Status.LastUploadedCertHash,
+		AWSAccountCertificateARNs: cert.Status.AWSAccountCertificateARNs,
+		S3ObjectKeys:              cert.Status.S3ObjectKeys,
+	}
+	if cert.Status.LastUploadedTime != nil {
+		event.UploadedAt = cert.Status.LastUploadedTime.UTC()
+	}
+	if cert.Status.CloudflareUploaded {
+		event.CloudflareCertificateID = cert.Status.CloudflareCertificateID
+	}
+	if cert.Status.AWSUploaded {
+		event.AWSCertificateARN = cert.Status.AWSCertificateARN
+	}
+	return event
+}
+
+// postUploadEvent sends event to hookURL and expects a 2xx response
+func (m *CertificateManager) postUploadEvent(ctx context.Context, hookURL string, event uploadEvent) error {
+	parsed, err := url.Parse(hookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q, an absolute http or https URL is required", hookURL)
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode upload event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.postUploadWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, parsed.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "certificate-operator")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
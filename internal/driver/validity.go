@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+)
+
+// maxNotYetValidRequeue caps how long an upload of a not-yet-valid certificate is deferred
+// before the certificate is checked again
+const maxNotYetValidRequeue = time.Hour
+
+// timeUntilValid returns how long until the leaf certificate in certPEM becomes valid.
+// It returns zero when the certificate is already valid or can't be parsed, so
+// unparseable data is left for the providers to reject.
+func timeUntilValid(certPEM []byte, now time.Time) time.Duration {
+	rest := certPEM
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return 0
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		leaf, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || !leaf.NotBefore.After(now) {
+			return 0
+		}
+		return leaf.NotBefore.Sub(now)
+	}
+}
+
+// leafNotAfter returns when the leaf certificate in certPEM expires. It returns false when
+// the bundle has no parsable certificate.
+func leafNotAfter(certPEM []byte) (time.Time, bool) {
+	der := leafCertificateDER(certPEM)
+	if der == nil {
+		return time.Time{}, false
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return leaf.NotAfter, true
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver/types"
+)
+
+// uploadVerification collects the outcome of verifying the uploads of one reconcile
+type uploadVerification struct {
+	// ran is true once a provider's copy was compared with the upload
+	ran bool
+
+	// mismatches describes each provider copy that differs from the upload
+	mismatches []string
+}
+
+// verifyUpload reads the certificate uploaded under identifier back from the provider and
+// compares it with certData. It returns false only when the copy differs; verification
+// that is disabled, unsupported by the provider, or fails to run counts as a match.
+func (m *CertificateManager) verifyUpload(
+	ctx context.Context,
+	provider types.CloudProvider,
+	identifier string,
+	certData types.CertificateData,
+	verification *uploadVerification,
+) bool {
+	if !m.verifyUploads {
+		return true
+	}
+	verifier, ok := provider.(types.Verifier)
+	if !ok {
+		return true
+	}
+	log := logf.FromContext(ctx)
+
+	err := verifier.Verify(ctx, identifier, certData)
+	switch {
+	case err == nil:
+		verification.ran = true
+		log.V(1).Info("Verified uploaded certificate", "provider", provider.Name(), "id", identifier)
+		return true
+	case errors.Is(err, types.ErrVerificationMismatch):
+		verification.ran = true
+		verification.mismatches = append(verification.mismatches, provider.Name()+": "+err.Error())
+		log.Error(err, "Uploaded certificate does not match the provider copy", "provider", provider.Name(), "id", identifier)
+		return false
+	default:
+		log.Error(err, "Failed to verify uploaded certificate", "provider", provider.Name(), "id", identifier)
+		return true
+	}
+}
+
+// setVerificationFailedCondition records the verification mismatches in the
+// VerificationFailed condition and reports whether the condition changed
+func setVerificationFailedCondition(cert *certificatev1alpha1.Certificate, mismatches []string) bool {
+	condition := metav1.Condition{
+		Type:               certificatev1alpha1.ConditionVerificationFailed,
+		Status:             metav1.ConditionFalse,
+		Reason:             certificatev1alpha1.ReasonVerified,
+		Message:            "Uploaded certificates match the provider copies",
+		ObservedGeneration: cert.Generation,
+	}
+	if len(mismatches) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = certificatev1alpha1.ReasonMismatch
+		condition.Message = strings.Join(mismatches, "; ")
+	}
+	return meta.SetStatusCondition(&cert.Status.Conditions, condition)
+}
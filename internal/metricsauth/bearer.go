@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metricsauth protects the metrics endpoint with a static bearer token, for
+// scrapers that cannot authenticate against the Kubernetes API.
+package metricsauth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/rest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// BearerTokenFilterProvider returns a metrics server FilterProvider that rejects requests
+// whose Authorization header does not carry token
+func BearerTokenFilterProvider(token string) func(*rest.Config, *http.Client) (metricsserver.Filter, error) {
+	return func(_ *rest.Config, _ *http.Client) (metricsserver.Filter, error) {
+		if token == "" {
+			return nil, fmt.Errorf("metrics bearer token must not be empty")
+		}
+		return func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !hasBearerToken(r, token) {
+					log.V(1).Info("Rejected metrics request without a valid bearer token", "remoteAddr", r.RemoteAddr)
+					w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+				handler.ServeHTTP(w, r)
+			}), nil
+		}, nil
+	}
+}
+
+// ReadBearerToken reads the token from path, ignoring surrounding whitespace
+func ReadBearerToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read bearer token: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("bearer token file %s is empty", path)
+	}
+	return token, nil
+}
+
+// hasBearerToken compares the request's bearer token to token in constant time
+func hasBearerToken(r *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsauth
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+var _ = Describe("BearerTokenFilterProvider", func() {
+	var metricsURL string
+
+	BeforeEach(func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		addr := listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+
+		server, err := metricsserver.NewServer(metricsserver.Options{
+			BindAddress:    addr,
+			FilterProvider: BearerTokenFilterProvider("s3cr3t"),
+		}, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		go func() {
+			defer GinkgoRecover()
+			Expect(server.Start(ctx)).To(Succeed())
+		}()
+
+		metricsURL = "http://" + addr + "/metrics"
+		Eventually(func() error {
+			resp, err := http.Get(metricsURL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			return err
+		}).Should(Succeed())
+	})
+
+	scrape := func(authorization string) int {
+		req, err := http.NewRequest(http.MethodGet, metricsURL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		return resp.StatusCode
+	}
+
+	It("should reject scrapes without the bearer token", func() {
+		Expect(scrape("")).To(Equal(http.StatusUnauthorized))
+		Expect(scrape("Bearer wrong")).To(Equal(http.StatusUnauthorized))
+		Expect(scrape("Basic czNjcjN0")).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should serve metrics with the bearer token", func() {
+		Expect(scrape("Bearer s3cr3t")).To(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("ReadBearerToken", func() {
+	It("should trim surrounding whitespace", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(path, []byte("s3cr3t\n"), 0o600)).To(Succeed())
+
+		token, err := ReadBearerToken(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("s3cr3t"))
+	})
+
+	It("should reject an empty token file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(path, []byte("\n"), 0o600)).To(Succeed())
+
+		_, err := ReadBearerToken(path)
+		Expect(err).To(MatchError(ContainSubstring("is empty")))
+	})
+})
@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reconcileonce runs the Certificate processing of the operator once against a
+// single Certificate, outside the controller loop, for troubleshooting.
+package reconcileonce
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/controller"
+)
+
+// CommandName is the first argument that selects the reconcile-once subcommand
+const CommandName = "reconcile-once"
+
+// Options selects the Certificate to reconcile
+type Options struct {
+	Namespace string
+	Name      string
+}
+
+// BindFlags registers the reconcile-once flags on fs
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Namespace, "namespace", "default", "Namespace of the Certificate to reconcile.")
+	fs.StringVar(&o.Name, "name", "", "Name of the Certificate to reconcile.")
+}
+
+// Validate checks that a Certificate is selected
+func (o Options) Validate() error {
+	if o.Namespace == "" || o.Name == "" {
+		return fmt.Errorf("--namespace and --name are required")
+	}
+	return nil
+}
+
+// Run processes the selected Certificate once and writes the status changes it would make
+// to out. The status is not written back. processor should be built with driver.WithDryRun,
+// so cert-manager resources and provider uploads aren't changed either and a running
+// operator isn't raced.
+func Run(ctx context.Context, c client.Client, processor controller.CertificateProcessor, opts Options, out io.Writer) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	var cert certificatev1alpha1.Certificate
+	key := types.NamespacedName{Namespace: opts.Namespace, Name: opts.Name}
+	if err := c.Get(ctx, key, &cert); err != nil {
+		return fmt.Errorf("failed to get Certificate %s: %w", key, err)
+	}
+	if !cert.DeletionTimestamp.IsZero() {
+		return fmt.Errorf("certificate %s is being deleted", key)
+	}
+
+	stored := cert.Status.DeepCopy()
+	result, statusUpdated, processErr := processor.ProcessCertificate(ctx, &cert)
+
+	_, _ = fmt.Fprintf(out, "Certificate %s\n", key)
+	changes, err := statusChanges(stored, &cert.Status)
+	if err != nil {
+		return err
+	}
+	if !statusUpdated || len(changes) == 0 {
+		_, _ = fmt.Fprintln(out, "No status changes")
+	} else {
+		_, _ = fmt.Fprintln(out, "Status changes:")
+		for _, change := range changes {
+			_, _ = fmt.Fprintf(out, "  %s\n", change)
+		}
+	}
+	if result.RequeueAfter > 0 {
+		_, _ = fmt.Fprintf(out, "Requeue after: %s\n", result.RequeueAfter)
+	}
+
+	if processErr != nil {
+		return fmt.Errorf("failed to process Certificate %s: %w", key, processErr)
+	}
+	return nil
+}
+
+// statusChanges describes the top-level status fields that differ, one "field: old -> new"
+// line per field sorted by name, with values in their JSON encoding
+func statusChanges(before, after *certificatev1alpha1.CertificateStatus) ([]string, error) {
+	beforeFields, err := statusFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := statusFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	maps.Copy(fields, beforeFields)
+	maps.Copy(fields, afterFields)
+
+	var changes []string
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		oldValue, newValue := beforeFields[field], afterFields[field]
+		if string(oldValue) == string(newValue) {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", field, displayValue(oldValue), displayValue(newValue)))
+	}
+	return changes, nil
+}
+
+// statusFields returns the JSON encoding of each set status field
+func statusFields(status *certificatev1alpha1.CertificateStatus) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Certificate status: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode Certificate status: %w", err)
+	}
+	return fields, nil
+}
+
+// displayValue returns the JSON value, or <unset> for a field that is not set
+func displayValue(value json.RawMessage) string {
+	if value == nil {
+		return "<unset>"
+	}
+	return string(value)
+}
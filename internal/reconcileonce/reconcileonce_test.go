@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcileonce
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver"
+)
+
+// failingProcessor sets the CloudflareUploaded status and fails
+type failingProcessor struct{}
+
+func (failingProcessor) ProcessCertificate(_ context.Context, cert *certificatev1alpha1.Certificate) (ctrl.Result, bool, error) {
+	cert.Status.CloudflareUploaded = true
+	return ctrl.Result{RequeueAfter: time.Minute}, true, errors.New("cloudflare unavailable")
+}
+
+func (failingProcessor) Finalize(context.Context, *certificatev1alpha1.Certificate) error {
+	return nil
+}
+
+var _ = Describe("Run", func() {
+	var (
+		ctx       = context.Background()
+		k8sClient client.Client
+		out       *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithObjects(&certificatev1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default", UID: "example-uid"},
+				Spec:       certificatev1alpha1.CertificateSpec{Domain: "example.com"},
+			}).
+			WithStatusSubresource(&certificatev1alpha1.Certificate{}, &certmanagerv1.Certificate{}).
+			Build()
+		out = &bytes.Buffer{}
+	})
+
+	parseOptions := func(args ...string) Options {
+		var opts Options
+		fs := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+		opts.BindFlags(fs)
+		Expect(fs.Parse(args)).To(Succeed())
+		return opts
+	}
+
+	It("should print the status changes without writing them", func() {
+		manager := driver.NewCertificateManager(k8sClient, testScheme, driver.WithDryRun())
+
+		err := Run(ctx, k8sClient, manager, parseOptions("--namespace", "default", "--name", "example"), out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(ContainSubstring("Certificate default/example"))
+		Expect(out.String()).To(ContainSubstring(`certificateRef: <unset> -> "example-cert"`))
+		Expect(out.String()).To(ContainSubstring(`secretName: <unset> -> "example-tls"`))
+
+		stored := &certificatev1alpha1.Certificate{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example"}, stored)).To(Succeed())
+		Expect(stored.Status.CertificateRef).To(BeEmpty())
+
+		// The cert-manager Certificate is only created as a dry run
+		cmCert := &certmanagerv1.Certificate{}
+		err = k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "example-cert"}, cmCert)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should print the status changes and the requeue before a processing error", func() {
+		err := Run(ctx, k8sClient, failingProcessor{}, parseOptions("--name", "example"), out)
+		Expect(err).To(MatchError(ContainSubstring("cloudflare unavailable")))
+		Expect(out.String()).To(ContainSubstring("cloudflareUploaded: <unset> -> true"))
+		Expect(out.String()).To(ContainSubstring("Requeue after: 1m0s"))
+	})
+
+	It("should fail for a Certificate that does not exist", func() {
+		err := Run(ctx, k8sClient, failingProcessor{}, parseOptions("--name", "missing"), out)
+		Expect(err).To(MatchError(ContainSubstring("failed to get Certificate default/missing")))
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("should require a name", func() {
+		err := Run(ctx, k8sClient, failingProcessor{}, parseOptions(), out)
+		Expect(err).To(MatchError(ContainSubstring("--name are required")))
+	})
+})
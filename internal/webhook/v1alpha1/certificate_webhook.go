@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+	"github.com/tae2089/certificate-operator/internal/driver"
+)
+
+const (
+	// conversionPath is the path the CRD conversion webhook is served on
+	conversionPath = "/convert"
+	// validationPath is the path the Certificate validating webhook is served on
+	validationPath = "/validate-certificate-println-kr-v1alpha1-certificate"
+)
+
+// +kubebuilder:webhook:path=/validate-certificate-println-kr-v1alpha1-certificate,mutating=false,failurePolicy=fail,sideEffects=None,groups=certificate.println.kr,resources=certificates,verbs=create;update,versions=v1alpha1,name=vcertificate-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SetupCertificateWebhookWithManager registers the Certificate conversion and validating webhooks
+// with the manager. Conversion goes through the v1alpha1 hub. The handler is registered explicitly
+// because the builder only enables conversion once more than one version is in the scheme; while
+// v1alpha1 is the only version the API server never calls it. credentialsNamespace is the
+// operator credentials namespace, empty for none.
+func SetupCertificateWebhookWithManager(mgr ctrl.Manager, credentialsNamespace string) error {
+	mgr.GetWebhookServer().Register(conversionPath, conversion.NewWebhookHandler(mgr.GetScheme()))
+	mgr.GetWebhookServer().Register(validationPath, admission.WithCustomValidator(mgr.GetScheme(),
+		&certificatev1alpha1.Certificate{}, &CertificateCustomValidator{CredentialsNamespace: credentialsNamespace}))
+	return nil
+}
+
+// CertificateCustomValidator rejects Certificates whose spec.credentialsNamespace would read
+// the credential Secrets of another namespace. The operator ignores such a namespace anyway,
+// the webhook reports it when the Certificate is applied.
+type CertificateCustomValidator struct {
+	// CredentialsNamespace is the operator credentials namespace, empty for none
+	CredentialsNamespace string
+}
+
+var _ admission.CustomValidator = &CertificateCustomValidator{}
+
+// ValidateCreate validates a new Certificate
+func (v *CertificateCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(obj)
+}
+
+// ValidateUpdate validates an updated Certificate
+func (v *CertificateCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(newObj)
+}
+
+// ValidateDelete allows every deletion
+func (v *CertificateCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *CertificateCustomValidator) validate(obj runtime.Object) error {
+	cert, ok := obj.(*certificatev1alpha1.Certificate)
+	if !ok {
+		return fmt.Errorf("expected a Certificate but got %T", obj)
+	}
+	return driver.ValidateCredentialsNamespace(cert, v.CredentialsNamespace)
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	apix "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	certificatev1alpha1 "github.com/tae2089/certificate-operator/api/v1alpha1"
+)
+
+// spokeVersion is a test-only API version converted through the v1alpha1 hub
+var spokeVersion = certificatev1alpha1.GroupVersion.Group + "/v1beta1"
+
+// certificateSpoke mirrors the Certificate schema under spokeVersion
+type certificateSpoke struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   certificatev1alpha1.CertificateSpec   `json:"spec,omitempty"`
+	Status certificatev1alpha1.CertificateStatus `json:"status,omitempty"`
+}
+
+func (in *certificateSpoke) DeepCopyObject() runtime.Object {
+	out := &certificateSpoke{TypeMeta: in.TypeMeta}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return out
+}
+
+// ConvertTo is an identity conversion to the hub
+func (in *certificateSpoke) ConvertTo(hub ctrlconversion.Hub) error {
+	dst := hub.(*certificatev1alpha1.Certificate)
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	in.Spec.DeepCopyInto(&dst.Spec)
+	in.Status.DeepCopyInto(&dst.Status)
+	return nil
+}
+
+// ConvertFrom is an identity conversion from the hub
+func (in *certificateSpoke) ConvertFrom(hub ctrlconversion.Hub) error {
+	src := hub.(*certificatev1alpha1.Certificate)
+	src.ObjectMeta.DeepCopyInto(&in.ObjectMeta)
+	src.Spec.DeepCopyInto(&in.Spec)
+	src.Status.DeepCopyInto(&in.Status)
+	return nil
+}
+
+// fakeManager serves only the scheme and webhook server used by the webhook setup
+type fakeManager struct {
+	manager.Manager
+	scheme *runtime.Scheme
+	server webhook.Server
+}
+
+func (m *fakeManager) GetScheme() *runtime.Scheme       { return m.scheme }
+func (m *fakeManager) GetWebhookServer() webhook.Server { return m.server }
+
+var _ = Describe("Certificate conversion webhook", func() {
+	var (
+		scheme *runtime.Scheme
+		mgr    *fakeManager
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		utilruntime.Must(certificatev1alpha1.AddToScheme(scheme))
+		spokeGV := certificatev1alpha1.GroupVersion
+		spokeGV.Version = "v1beta1"
+		scheme.AddKnownTypeWithName(spokeGV.WithKind("Certificate"), &certificateSpoke{})
+
+		mgr = &fakeManager{scheme: scheme, server: webhook.NewServer(webhook.Options{})}
+		Expect(SetupCertificateWebhookWithManager(mgr, "credentials")).To(Succeed())
+	})
+
+	// convert sends a ConversionReview for obj to the registered webhook and returns the converted object
+	convert := func(obj runtime.Object, desiredAPIVersion string) runtime.RawExtension {
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		review := apix.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+			Request: &apix.ConversionRequest{
+				UID:               types.UID("review"),
+				DesiredAPIVersion: desiredAPIVersion,
+				Objects:           []runtime.RawExtension{{Raw: raw}},
+			},
+		}
+		body, err := json.Marshal(review)
+		Expect(err).NotTo(HaveOccurred())
+
+		req := httptest.NewRequest(http.MethodPost, conversionPath, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		mgr.server.WebhookMux().ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var resp apix.ConversionReview
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Response.Result.Status).To(Equal(metav1.StatusSuccess), resp.Response.Result.Message)
+		Expect(resp.Response.ConvertedObjects).To(HaveLen(1))
+		return resp.Response.ConvertedObjects[0]
+	}
+
+	It("treats v1alpha1 as the conversion hub", func() {
+		ok, err := conversion.IsConvertible(scheme, &certificatev1alpha1.Certificate{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("round-trips a Certificate through another version unchanged", func() {
+		original := &certificatev1alpha1.Certificate{
+			TypeMeta: metav1.TypeMeta{APIVersion: certificatev1alpha1.GroupVersion.String(), Kind: "Certificate"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "default",
+				Labels:    map[string]string{"team": "web"},
+			},
+			Spec: certificatev1alpha1.CertificateSpec{
+				Domain:              "example.com",
+				ClusterIssuerName:   "letsencrypt-prod",
+				CloudflareSecretRef: "cloudflare-credentials",
+				CloudflareZoneID:    "zone",
+				CloudflareEnabled:   ptr.To(true),
+				Priority:            5,
+				AWS:                 &certificatev1alpha1.AWS{CredentialType: "access-key", SecretRef: "aws-credentials"},
+			},
+			Status: certificatev1alpha1.CertificateStatus{
+				CertificateRef:            "example-cert",
+				AWSUploaded:               true,
+				AWSCertificateARN:         "arn:aws:acm:us-east-1:123456789012:certificate/abc",
+				AWSAccountCertificateARNs: map[string]string{"210987654321": "arn:aws:acm:us-east-1:210987654321:certificate/def"},
+			},
+		}
+
+		spokeRaw := convert(original, spokeVersion)
+		var spoke certificateSpoke
+		Expect(json.Unmarshal(spokeRaw.Raw, &spoke)).To(Succeed())
+		Expect(spoke.APIVersion).To(Equal(spokeVersion))
+		Expect(spoke.Spec).To(Equal(original.Spec))
+
+		hubRaw := convert(&spoke, certificatev1alpha1.GroupVersion.String())
+		var roundTripped certificatev1alpha1.Certificate
+		Expect(json.Unmarshal(hubRaw.Raw, &roundTripped)).To(Succeed())
+		Expect(roundTripped.TypeMeta).To(Equal(original.TypeMeta))
+		Expect(roundTripped.ObjectMeta).To(Equal(original.ObjectMeta))
+		Expect(roundTripped.Spec).To(Equal(original.Spec))
+		Expect(roundTripped.Status).To(Equal(original.Status))
+	})
+})
+
+var _ = Describe("Certificate validating webhook", func() {
+	var mgr *fakeManager
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(certificatev1alpha1.AddToScheme(scheme))
+		mgr = &fakeManager{scheme: scheme, server: webhook.NewServer(webhook.Options{})}
+		Expect(SetupCertificateWebhookWithManager(mgr, "credentials")).To(Succeed())
+	})
+
+	// validate sends an AdmissionReview creating a Certificate with credentialsNamespace in
+	// the default namespace and returns the response
+	validate := func(credentialsNamespace string) *admissionv1.AdmissionResponse {
+		cert := &certificatev1alpha1.Certificate{
+			TypeMeta:   metav1.TypeMeta{APIVersion: certificatev1alpha1.GroupVersion.String(), Kind: "Certificate"},
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: certificatev1alpha1.CertificateSpec{
+				Domain:               "example.com",
+				CloudflareSecretRef:  "cloudflare-credentials",
+				CredentialsNamespace: credentialsNamespace,
+			},
+		}
+		raw, err := json.Marshal(cert)
+		Expect(err).NotTo(HaveOccurred())
+		review := admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request: &admissionv1.AdmissionRequest{
+				UID:       types.UID("review"),
+				Kind:      metav1.GroupVersionKind{Group: certificatev1alpha1.GroupVersion.Group, Version: "v1alpha1", Kind: "Certificate"},
+				Operation: admissionv1.Create,
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+		body, err := json.Marshal(review)
+		Expect(err).NotTo(HaveOccurred())
+
+		req := httptest.NewRequest(http.MethodPost, validationPath, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		mgr.server.WebhookMux().ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var resp admissionv1.AdmissionReview
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.Response).NotTo(BeNil())
+		return resp.Response
+	}
+
+	It("allows the Certificate's own namespace and the operator credentials namespace", func() {
+		Expect(validate("").Allowed).To(BeTrue())
+		Expect(validate("default").Allowed).To(BeTrue())
+		Expect(validate("credentials").Allowed).To(BeTrue())
+	})
+
+	It("rejects the namespace of another tenant", func() {
+		resp := validate("other-team")
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring(`credentialsNamespace "other-team"`))
+	})
+})